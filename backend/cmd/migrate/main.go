@@ -56,27 +56,61 @@ func main() {
 
 	fmt.Printf("\nFound %d migration files\n", len(sqlFiles))
 
-	// Run each migration
+	// Record each migration once it is applied so it never runs twice
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			filename VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		log.Fatalf("Failed to create schema_migrations table: %v", err)
+	}
+
+	applied := make(map[string]bool)
+	rows, err := db.Query("SELECT filename FROM schema_migrations")
+	if err != nil {
+		log.Fatalf("Failed to read applied migrations: %v", err)
+	}
+	for rows.Next() {
+		var filename string
+		if err := rows.Scan(&filename); err != nil {
+			log.Fatalf("Failed to read applied migrations: %v", err)
+		}
+		applied[filename] = true
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatalf("Failed to read applied migrations: %v", err)
+	}
+	rows.Close()
+
+	// Run each migration that hasn't been applied
 	for _, filename := range sqlFiles {
+		if applied[filename] {
+			continue
+		}
+
 		filepath := filepath.Join(migrationsDir, filename)
 		fmt.Printf("\nRunning migration: %s\n", filename)
 		
 		// Read file
 		content, err := ioutil.ReadFile(filepath)
 		if err != nil {
-			log.Printf("Failed to read %s: %v", filename, err)
-			continue
+			log.Fatalf("Failed to read %s: %v", filename, err)
 		}
 
-		// Execute SQL
-		_, err = db.Exec(string(content))
+		// Execute SQL and record it in one transaction
+		err = applyMigration(db, filename, string(content))
 		if err != nil {
-			// Check if it's a "already exists" error
-			if strings.Contains(err.Error(), "already exists") {
-				fmt.Printf("  → Already applied (skipping)\n")
-			} else {
-				log.Printf("  → Error: %v", err)
+			// Databases migrated before migrations were recorded already
+			// have the objects that aren't created IF NOT EXISTS
+			if !strings.Contains(err.Error(), "already exists") {
+				log.Fatalf("  → Error: %v", err)
+			}
+			if _, err := db.Exec("INSERT INTO schema_migrations (filename) VALUES ($1)", filename); err != nil {
+				log.Fatalf("  → Failed to record %s: %v", filename, err)
 			}
+			fmt.Printf("  → Already applied (skipping)\n")
 		} else {
 			fmt.Printf("  → Success\n")
 		}
@@ -118,3 +152,21 @@ func main() {
 	
 	fmt.Println("\nMigration check complete!")
 }
+
+// applyMigration runs a migration file and records it as applied, rolling
+// both back if any statement fails
+func applyMigration(db *sql.DB, filename, content string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(content); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (filename) VALUES ($1)", filename); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
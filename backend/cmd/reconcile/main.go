@@ -0,0 +1,74 @@
+// Command reconcile finds jobs whose stored total_amount no longer matches the
+// total of their items, markups and discounts, and approved change orders. By
+// default it only reports them; pass -fix to store the recomputed totals.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/lib/pq"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "store the recomputed totals instead of only reporting them")
+	flag.Parse()
+
+	// Get database URL from environment or use default
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		dbURL = "postgres://postgres@localhost/bremray_dev?sslmode=disable"
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	ctx := context.Background()
+	jobRepo := repository.NewJobRepository(db)
+
+	drifts, err := jobRepo.FindTotalDrift(ctx)
+	if err != nil {
+		log.Fatalf("Failed to check job totals: %v", err)
+	}
+
+	if len(drifts) == 0 {
+		fmt.Println("All job totals match their items, adjustments and approved change orders")
+		return
+	}
+
+	for _, drift := range drifts {
+		fmt.Printf("Job %s: stored %s, computed %s (off by %s)\n",
+			drift.JobID, drift.StoredTotal, drift.ComputedTotal, drift.Difference)
+	}
+
+	if !*fix {
+		fmt.Printf("%d job(s) have drifted totals; run with -fix to correct them\n", len(drifts))
+		return
+	}
+
+	fixed := 0
+	for _, drift := range drifts {
+		if err := jobRepo.RefreshTotal(ctx, drift.JobID); err != nil {
+			log.Printf("Failed to fix job %s: %v", drift.JobID, err)
+			continue
+		}
+		fixed++
+	}
+
+	fmt.Printf("Fixed %d of %d job total(s)\n", fixed, len(drifts))
+	if fixed < len(drifts) {
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -62,16 +63,44 @@ func main() {
 	templateRepo := repository.NewJobTemplateRepository(db)
 	jobRepo := repository.NewJobRepository(db)
 	companyRepo := repository.NewCompanyRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	estimateRepo := repository.NewEstimateRepository(db)
+	changeOrderRepo := repository.NewChangeOrderRepository(db)
+	taxRepo := repository.NewTaxRepository(db)
+	adjustmentRepo := repository.NewAdjustmentRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	laborRepo := repository.NewLaborRepository(db)
+	loadCalculationRepo := repository.NewLoadCalculationRepository(db)
+	wireItemRepo := repository.NewWireItemRepository(db)
+	conduitFillRepo := repository.NewConduitFillRepository(db)
+	uow := repository.NewUnitOfWork(db)
 
 	// Initialize services
-	itemService := services.NewItemService(itemRepo)
+	itemService := services.NewItemService(itemRepo, uow)
+	authService := services.NewAuthService(userRepo, getEnvAsDuration("SESSION_TTL", services.DefaultSessionTTL))
+
+	// Create the first admin account on a fresh install
+	if err := authService.EnsureAdmin(context.Background(), os.Getenv("ADMIN_EMAIL"), os.Getenv("ADMIN_PASSWORD")); err != nil {
+		log.Fatalf("Failed to create initial admin user: %v", err)
+	}
 
 	// Initialize handlers
 	itemHandler := handlers.NewItemHandler(itemService)
 	customerHandler := handlers.NewCustomerHandler(customerRepo)
-	templateHandler := handlers.NewTemplateHandler(templateRepo, itemRepo)
-	jobHandler := handlers.NewJobHandler(jobRepo, customerRepo, templateRepo, itemRepo)
+	templateHandler := handlers.NewTemplateHandler(uow, templateRepo, itemRepo)
+	jobHandler := handlers.NewJobHandler(uow, jobRepo, customerRepo, templateRepo, itemRepo, taxRepo, adjustmentRepo, laborRepo)
+	changeOrderHandler := handlers.NewChangeOrderHandler(changeOrderRepo, jobRepo, itemRepo)
+	estimateHandler := handlers.NewEstimateHandler(uow, estimateRepo, customerRepo, templateRepo, itemRepo)
 	companyHandler := handlers.NewCompanyHandler(companyRepo)
+	taxHandler := handlers.NewTaxHandler(taxRepo)
+	adjustmentHandler := handlers.NewAdjustmentHandler(adjustmentRepo, customerRepo)
+	supplierHandler := handlers.NewSupplierHandler(uow, supplierRepo, itemRepo)
+	laborHandler := handlers.NewLaborHandler(laborRepo, jobRepo, templateRepo, itemRepo)
+	loadCalculationHandler := handlers.NewLoadCalculationHandler(loadCalculationRepo, jobRepo)
+	voltageDropHandler := handlers.NewVoltageDropHandler(uow, wireItemRepo, jobRepo, itemRepo)
+	conduitFillHandler := handlers.NewConduitFillHandler(conduitFillRepo, jobRepo, templateRepo, itemRepo)
+	authHandler := handlers.NewAuthHandler(authService)
+	userHandler := handlers.NewUserHandler(userRepo)
 
 	// Setup routes
 	router := mux.NewRouter()
@@ -84,41 +113,73 @@ func main() {
 	api.Use(middleware.JSONContentType)
 	api.Use(middleware.Logger)
 
+	// Health check (public)
+	api.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	}).Methods("GET")
+
+	// Login (public)
+	authHandler.RegisterPublicRoutes(api)
+
+	// Everything else requires an authenticated user; each handler declares
+	// which roles may call its routes
+	protected := api.NewRoute().Subrouter()
+	protected.Use(middleware.RequireAuth(authService))
+
+	// Auth and user routes
+	authHandler.RegisterRoutes(protected)
+	userHandler.RegisterRoutes(protected)
+
 	// Item routes
-	api.HandleFunc("/items", itemHandler.Create).Methods("POST")
-	api.HandleFunc("/items", itemHandler.List).Methods("GET")
-	api.HandleFunc("/items/{id}", itemHandler.GetByID).Methods("GET")
-	api.HandleFunc("/items/{id}", itemHandler.Update).Methods("PUT")
-	api.HandleFunc("/items/{id}", itemHandler.Delete).Methods("DELETE")
+	itemHandler.RegisterRoutes(protected)
 	
 	// Customer routes
-	customerHandler.RegisterRoutes(api)
+	customerHandler.RegisterRoutes(protected)
 	
 	// Template routes
-	templateHandler.RegisterRoutes(api)
+	templateHandler.RegisterRoutes(protected)
 	
 	// Job routes
-	jobHandler.RegisterRoutes(api)
+	jobHandler.RegisterRoutes(protected)
+	changeOrderHandler.RegisterRoutes(protected)
+	
+	// Estimate routes
+	estimateHandler.RegisterRoutes(protected)
 	
 	// Company routes
-	companyHandler.RegisterRoutes(api)
+	companyHandler.RegisterRoutes(protected)
+	
+	// Tax routes
+	taxHandler.RegisterRoutes(protected)
+	
+	// Markup and discount routes
+	adjustmentHandler.RegisterRoutes(protected)
+	
+	// Supplier routes
+	supplierHandler.RegisterRoutes(protected)
+	
+	// Labor rate and labor estimate routes
+	laborHandler.RegisterRoutes(protected)
 	
+	// Service load calculation routes
+	loadCalculationHandler.RegisterRoutes(protected)
+	
+	// Voltage drop and wire item routes
+	voltageDropHandler.RegisterRoutes(protected)
+	
+	// Conduit fill reference table and sizing routes
+	conduitFillHandler.RegisterRoutes(protected)
+	
+	// Service health checks
+	healthHandler := handlers.NewHealthHandler()
+	healthHandler.RegisterRoutes(protected)
+
 	// Handle OPTIONS for all routes
 	api.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	}).Methods("OPTIONS")
 
-	// Health check
-	api.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok"}`))
-	}).Methods("GET")
-	
-	// Service health checks
-	healthHandler := handlers.NewHealthHandler()
-	api.HandleFunc("/health/wave", healthHandler.CheckWave).Methods("GET")
-	api.HandleFunc("/health/cloudflare", healthHandler.CheckCloudflare).Methods("GET")
-
 	// Start server
 	addr := fmt.Sprintf(":%s", port)
 	log.Printf("Server starting on %s", addr)
@@ -0,0 +1,102 @@
+// Package conduitfill finds the smallest conduit that holds a set of
+// conductors within the fill limits of NEC (2020) Chapter 9, Table 1. The
+// conductor and conduit areas come from the caller, who keeps them as
+// reference tables.
+package conduitfill
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Conductor is a number of identical conductors and the area of each in
+// square inches (Chapter 9, Table 5)
+type Conductor struct {
+	Description string  `json:"description"`
+	AreaSqIn    float64 `json:"areaSqIn"`
+	Count       int     `json:"count"`
+}
+
+// Conduit is a trade size of one conduit type and its total internal area in
+// square inches (Chapter 9, Table 4)
+type Conduit struct {
+	TradeSize string  `json:"tradeSize"`
+	AreaSqIn  float64 `json:"areaSqIn"`
+}
+
+// Result is the smallest conduit that holds the conductors, how full they
+// make it and how full it may be
+type Result struct {
+	TradeSize         string  `json:"tradeSize"`
+	ConduitAreaSqIn   float64 `json:"conduitAreaSqIn"`
+	ConductorAreaSqIn float64 `json:"conductorAreaSqIn"`
+	Conductors        int     `json:"conductors"`
+	FillPercent       float64 `json:"fillPercent"`
+	MaxFillPercent    float64 `json:"maxFillPercent"`
+}
+
+// MaxFillPercent is how much of a conduit's area conductors may fill: 53% for
+// one conductor, 31% for two and 40% for more (Chapter 9, Table 1)
+func MaxFillPercent(conductors int) float64 {
+	switch conductors {
+	case 1:
+		return 53
+	case 2:
+		return 31
+	}
+	return 40
+}
+
+// Size finds the smallest of conduits, which may be in any order, that holds
+// the conductors
+func Size(conductors []Conductor, conduits []Conduit) (*Result, error) {
+	if len(conductors) == 0 {
+		return nil, errors.New("at least one conductor is required")
+	}
+
+	var count int
+	var area float64
+	for i, conductor := range conductors {
+		if conductor.Count <= 0 {
+			return nil, fmt.Errorf("conductor %s must have a positive count", describe(conductor, i))
+		}
+		if conductor.AreaSqIn <= 0 {
+			return nil, fmt.Errorf("conductor %s must have a positive area", describe(conductor, i))
+		}
+		count += conductor.Count
+		area += conductor.AreaSqIn * float64(conductor.Count)
+	}
+	area = math.Round(area*10000) / 10000
+
+	sorted := make([]Conduit, len(conduits))
+	copy(sorted, conduits)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].AreaSqIn < sorted[j].AreaSqIn })
+
+	maxFill := MaxFillPercent(count)
+	for _, conduit := range sorted {
+		if conduit.AreaSqIn <= 0 || area > conduit.AreaSqIn*maxFill/100 {
+			continue
+		}
+		return &Result{
+			TradeSize:         conduit.TradeSize,
+			ConduitAreaSqIn:   conduit.AreaSqIn,
+			ConductorAreaSqIn: area,
+			Conductors:        count,
+			FillPercent:       math.Round(area/conduit.AreaSqIn*10000) / 100,
+			MaxFillPercent:    maxFill,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%d conductors of %g sq in don't fit any conduit at %g%% fill", count, area, maxFill)
+}
+
+// describe names a conductor for messages, by its position when it has no
+// description
+func describe(conductor Conductor, i int) string {
+	if conductor.Description != "" {
+		return conductor.Description
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
@@ -0,0 +1,126 @@
+package conduitfill
+
+import (
+	"strings"
+	"testing"
+)
+
+// EMT from Chapter 9, Table 4, listed out of order
+var emt = []Conduit{
+	{"1", 0.864},
+	{"1/2", 0.304},
+	{"3/4", 0.533},
+	{"1-1/4", 1.496},
+	{"1-1/2", 2.036},
+	{"2", 3.356},
+	{"2-1/2", 5.858},
+	{"3", 8.846},
+	{"3-1/2", 11.545},
+	{"4", 14.753},
+}
+
+// THHN areas from Chapter 9, Table 5
+const (
+	thhn12  = 0.0133
+	thhn6   = 0.0507
+	thhn2   = 0.1158
+	thhn10  = 0.1855
+	thhn40  = 0.3237
+	thhn500 = 0.7073
+)
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		conductors []Conductor
+		tradeSize  string
+		area       float64
+		fill       float64
+		maxFill    float64
+	}{
+		{
+			name:       "one conductor",
+			conductors: []Conductor{{"6 AWG THHN", thhn6, 1}},
+			tradeSize:  "1/2",
+			area:       0.0507,
+			fill:       16.68,
+			maxFill:    53,
+		},
+		{
+			// 0.371 sq in over 31% needs 1.197 sq in; 1 in EMT has 0.864
+			name:       "two conductors",
+			conductors: []Conductor{{"1/0 AWG THHN", thhn10, 2}},
+			tradeSize:  "1-1/4",
+			area:       0.371,
+			fill:       24.8,
+			maxFill:    31,
+		},
+		{
+			name:       "branch circuit",
+			conductors: []Conductor{{"12 AWG THHN", thhn12, 4}},
+			tradeSize:  "1/2",
+			area:       0.0532,
+			fill:       17.5,
+			maxFill:    40,
+		},
+		{
+			// 1.0869 sq in over 40% needs 2.717 sq in; 1-1/2 in EMT has 2.036
+			name:       "200 A feeder",
+			conductors: []Conductor{{"4/0 AWG THHN", thhn40, 3}, {"2 AWG THHN", thhn2, 1}},
+			tradeSize:  "2",
+			area:       1.0869,
+			fill:       32.39,
+			maxFill:    40,
+		},
+		{
+			name:       "400 A feeder",
+			conductors: []Conductor{{"500 kcmil THHN", thhn500, 4}},
+			tradeSize:  "3",
+			area:       2.8292,
+			fill:       31.98,
+			maxFill:    40,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Size(tt.conductors, emt)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.TradeSize != tt.tradeSize || result.ConductorAreaSqIn != tt.area || result.FillPercent != tt.fill || result.MaxFillPercent != tt.maxFill {
+				t.Errorf("expected %s in with %g sq in at %g%% of %g%%, got %+v", tt.tradeSize, tt.area, tt.fill, tt.maxFill, result)
+			}
+		})
+	}
+}
+
+func TestSize_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		conductors []Conductor
+		errMsg     string
+	}{
+		{"no conductors", nil, "at least one conductor is required"},
+		{"no count", []Conductor{{"12 AWG THHN", thhn12, 0}}, "conductor 12 AWG THHN must have a positive count"},
+		{"no area", []Conductor{{AreaSqIn: 0, Count: 3}}, "conductor #1 must have a positive area"},
+		{"too many", []Conductor{{"500 kcmil THHN", thhn500, 9}}, "9 conductors of 6.3657 sq in don't fit any conduit at 40% fill"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Size(tt.conductors, emt)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestMaxFillPercent(t *testing.T) {
+	for conductors, percent := range map[int]float64{1: 53, 2: 31, 3: 40, 12: 40} {
+		if got := MaxFillPercent(conductors); got != percent {
+			t.Errorf("MaxFillPercent(%d) = %g, want %g", conductors, got, percent)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// AdjustmentHandler handles HTTP requests for default markups and discounts
+type AdjustmentHandler struct {
+	adjustmentRepo repository.AdjustmentRepository
+	customerRepo   repository.CustomerRepository
+}
+
+// NewAdjustmentHandler creates a new adjustment handler
+func NewAdjustmentHandler(adjustmentRepo repository.AdjustmentRepository, customerRepo repository.CustomerRepository) *AdjustmentHandler {
+	return &AdjustmentHandler{
+		adjustmentRepo: adjustmentRepo,
+		customerRepo:   customerRepo,
+	}
+}
+
+// RegisterRoutes registers all adjustment routes
+func (h *AdjustmentHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the adjustment endpoints; pricing is admin-only
+func (h *AdjustmentHandler) routes() []route {
+	return []route{
+		{"GET", "/adjustments", h.List, adminOnly},
+		{"POST", "/adjustments", h.Create, adminOnly},
+		{"GET", "/adjustments/{id}", h.Get, adminOnly},
+		{"PUT", "/adjustments/{id}", h.Update, adminOnly},
+		{"DELETE", "/adjustments/{id}", h.Delete, adminOnly},
+	}
+}
+
+// adjustmentRequest is a requested markup or discount. Give either percent
+// or amount.
+type adjustmentRequest struct {
+	CustomerID string                 `json:"customerId"`
+	Name       string                 `json:"name"`
+	Type       models.AdjustmentType  `json:"type"`
+	Scope      models.AdjustmentScope `json:"scope"`
+	ItemID     string                 `json:"itemId"`
+	Category   string                 `json:"category"`
+	Percent    float64                `json:"percent"`
+	Amount     models.Money           `json:"amount"`
+}
+
+// adjustment creates the requested adjustment
+func (req *adjustmentRequest) adjustment() (*models.PriceAdjustment, error) {
+	return models.NewPriceAdjustment(req.Name, req.Type, req.Scope, req.ItemID, req.Category, req.Percent, req.Amount)
+}
+
+// List handles GET /api/adjustments. With ?customerId= it lists the defaults a
+// new job for that customer would get.
+func (h *AdjustmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	var adjustments []models.PriceAdjustment
+	var err error
+	if customerID := r.URL.Query().Get("customerId"); customerID != "" {
+		adjustments, err = h.adjustmentRepo.ListForCustomer(r.Context(), customerID)
+	} else {
+		adjustments, err = h.adjustmentRepo.List(r.Context())
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list adjustments")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, adjustments)
+}
+
+// Get handles GET /api/adjustments/:id
+func (h *AdjustmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	adjustment, ok := h.getAdjustment(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, adjustment)
+}
+
+// Create handles POST /api/adjustments
+func (h *AdjustmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req adjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	adjustment, err := req.adjustment()
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !h.setCustomer(w, r, adjustment, req.CustomerID) {
+		return
+	}
+
+	if err := h.adjustmentRepo.Create(r.Context(), adjustment); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create adjustment")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, adjustment)
+}
+
+// Update handles PUT /api/adjustments/:id. Jobs already created keep the
+// adjustment as it was.
+func (h *AdjustmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	adjustment, ok := h.getAdjustment(w, r)
+	if !ok {
+		return
+	}
+
+	var req adjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := adjustment.Update(req.Name, req.Type, req.Scope, req.ItemID, req.Category, req.Percent, req.Amount); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !h.setCustomer(w, r, adjustment, req.CustomerID) {
+		return
+	}
+
+	if err := h.adjustmentRepo.Update(r.Context(), adjustment); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update adjustment")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, adjustment)
+}
+
+// Delete handles DELETE /api/adjustments/:id
+func (h *AdjustmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.adjustmentRepo.Delete(r.Context(), mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Adjustment not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete adjustment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getAdjustment loads the adjustment named in the URL, writing the error
+// response when it cannot
+func (h *AdjustmentHandler) getAdjustment(w http.ResponseWriter, r *http.Request) (*models.PriceAdjustment, bool) {
+	adjustment, err := h.adjustmentRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Adjustment not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get adjustment")
+		return nil, false
+	}
+
+	return adjustment, true
+}
+
+// setCustomer limits the adjustment to a customer's jobs, or to every job when
+// customerID is empty, writing the error response when the customer is unknown
+func (h *AdjustmentHandler) setCustomer(w http.ResponseWriter, r *http.Request, adjustment *models.PriceAdjustment, customerID string) bool {
+	if customerID != "" {
+		if _, err := h.customerRepo.GetByID(r.Context(), customerID); err != nil {
+			if err.Error() == "customer not found" {
+				respondWithError(w, http.StatusBadRequest, "Customer not found")
+				return false
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to get customer")
+			return false
+		}
+	}
+
+	adjustment.CustomerID = customerID
+	return true
+}
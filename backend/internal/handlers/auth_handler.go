@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/services"
+)
+
+// AuthService defines the interface for authentication business logic
+type AuthService interface {
+	Login(ctx context.Context, email, password string) (string, *models.User, time.Time, error)
+	Logout(ctx context.Context, token string) error
+	ChangePassword(ctx context.Context, user *models.User, currentPassword, newPassword string) error
+}
+
+// AuthHandler handles HTTP requests for signing in and out
+type AuthHandler struct {
+	service AuthService
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(service AuthService) *AuthHandler {
+	return &AuthHandler{
+		service: service,
+	}
+}
+
+// RegisterPublicRoutes registers the routes that do not require a session
+func (h *AuthHandler) RegisterPublicRoutes(router *mux.Router) {
+	router.HandleFunc("/auth/login", h.Login).Methods("POST", "OPTIONS")
+}
+
+// RegisterRoutes registers the routes that require a session
+func (h *AuthHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the session endpoints; every signed-in user may call them
+func (h *AuthHandler) routes() []route {
+	return []route{
+		{"POST", "/auth/logout", h.Logout, anyRole},
+		{"GET", "/auth/me", h.Me, anyRole},
+		{"PUT", "/auth/password", h.ChangePassword, anyRole},
+	}
+}
+
+// Login handles POST /api/auth/login
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Email == "" || req.Password == "" {
+		respondWithError(w, http.StatusBadRequest, "Email and password are required")
+		return
+	}
+
+	token, user, expiresAt, err := h.service.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			respondWithError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to sign in")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"token":     token,
+		"expiresAt": expiresAt,
+		"user":      user,
+	})
+}
+
+// Logout handles POST /api/auth/logout
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Logout(r.Context(), middleware.BearerToken(r)); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to sign out")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me handles GET /api/auth/me
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// ChangePassword handles PUT /api/auth/password
+func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.ChangePassword(r.Context(), user, req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			respondWithError(w, http.StatusBadRequest, "Current password is incorrect")
+			return
+		}
+		if err.Error() == "password must be at least 8 characters" {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to change password")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/services"
+)
+
+// MockAuthService implements the auth service interface for testing
+type MockAuthService struct {
+	users        map[string]*models.User
+	loggedOut    string
+	passwordSets int
+}
+
+func NewMockAuthService() *MockAuthService {
+	user, _ := models.NewUser("tech@bremray.com", "Tech", models.UserRoleTech, "supersecret")
+	return &MockAuthService{
+		users: map[string]*models.User{user.Email: user},
+	}
+}
+
+func (m *MockAuthService) Login(ctx context.Context, email, password string) (string, *models.User, time.Time, error) {
+	user, ok := m.users[models.NormalizeEmail(email)]
+	if !ok || !user.CheckPassword(password) {
+		return "", nil, time.Time{}, services.ErrInvalidCredentials
+	}
+	return "token-123", user, time.Now().Add(time.Hour), nil
+}
+
+func (m *MockAuthService) Logout(ctx context.Context, token string) error {
+	m.loggedOut = token
+	return nil
+}
+
+func (m *MockAuthService) ChangePassword(ctx context.Context, user *models.User, currentPassword, newPassword string) error {
+	if !user.CheckPassword(currentPassword) {
+		return services.ErrInvalidCredentials
+	}
+	m.passwordSets++
+	return user.SetPassword(newPassword)
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         interface{}
+		expectedCode int
+	}{
+		{
+			name:         "valid credentials",
+			body:         map[string]string{"email": "Tech@Bremray.com", "password": "supersecret"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "wrong password",
+			body:         map[string]string{"email": "tech@bremray.com", "password": "wrongpassword"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown user",
+			body:         map[string]string{"email": "nobody@bremray.com", "password": "supersecret"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "missing password",
+			body:         map[string]string{"email": "tech@bremray.com"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid JSON",
+			body:         "invalid json",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuthHandler(NewMockAuthService())
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			handler.Login(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if w.Code == http.StatusOK {
+				var response map[string]interface{}
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if response["token"] != "token-123" {
+					t.Errorf("expected token in response, got %v", response["token"])
+				}
+				user, _ := response["user"].(map[string]interface{})
+				if _, leaked := user["passwordHash"]; leaked {
+					t.Error("password hash must not be serialized")
+				}
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	service := NewMockAuthService()
+	handler := NewAuthHandler(service)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer token-123")
+	w := httptest.NewRecorder()
+
+	handler.Logout(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status code %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if service.loggedOut != "token-123" {
+		t.Errorf("expected session token-123 to be ended, got %q", service.loggedOut)
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         map[string]string
+		withUser     bool
+		expectedCode int
+	}{
+		{
+			name:         "valid change",
+			body:         map[string]string{"currentPassword": "supersecret", "newPassword": "newsecret1"},
+			withUser:     true,
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "wrong current password",
+			body:         map[string]string{"currentPassword": "nope", "newPassword": "newsecret1"},
+			withUser:     true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "new password too short",
+			body:         map[string]string{"currentPassword": "supersecret", "newPassword": "short"},
+			withUser:     true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "no user on context",
+			body:         map[string]string{"currentPassword": "supersecret", "newPassword": "newsecret1"},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewMockAuthService()
+			handler := NewAuthHandler(service)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPut, "/api/auth/password", bytes.NewReader(body))
+			if tt.withUser {
+				req = req.WithContext(middleware.WithUser(req.Context(), service.users["tech@bremray.com"]))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ChangePassword(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d. Response: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
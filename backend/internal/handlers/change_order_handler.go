@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+	"github.com/masterbrent/electrical-bidding-app/internal/services"
+)
+
+// ChangeOrderHandler handles HTTP requests for job change orders
+type ChangeOrderHandler struct {
+	changeOrderRepo repository.ChangeOrderRepository
+	jobRepo         repository.JobRepository
+	itemRepo        repository.ItemRepository
+}
+
+// NewChangeOrderHandler creates a new change order handler
+func NewChangeOrderHandler(
+	changeOrderRepo repository.ChangeOrderRepository,
+	jobRepo repository.JobRepository,
+	itemRepo repository.ItemRepository,
+) *ChangeOrderHandler {
+	return &ChangeOrderHandler{
+		changeOrderRepo: changeOrderRepo,
+		jobRepo:         jobRepo,
+		itemRepo:        itemRepo,
+	}
+}
+
+// RegisterRoutes registers all change order routes
+func (h *ChangeOrderHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the change order endpoints. Techs can raise change orders
+// and collect the customer's sign-off in the field; deciding, editing and
+// deleting them is admin-only.
+func (h *ChangeOrderHandler) routes() []route {
+	return []route{
+		{"GET", "/jobs/{id}/change-orders", h.List, anyRole},
+		{"POST", "/jobs/{id}/change-orders", h.Create, anyRole},
+		{"GET", "/jobs/{id}/change-orders/{changeOrderId}", h.Get, anyRole},
+		{"PUT", "/jobs/{id}/change-orders/{changeOrderId}", h.Update, adminOnly},
+		{"DELETE", "/jobs/{id}/change-orders/{changeOrderId}", h.Delete, adminOnly},
+		{"POST", "/jobs/{id}/change-orders/{changeOrderId}/approve", h.Approve, adminOnly},
+		{"POST", "/jobs/{id}/change-orders/{changeOrderId}/reject", h.Reject, adminOnly},
+		{"POST", "/jobs/{id}/change-orders/{changeOrderId}/sign-off", h.SignOff, anyRole},
+	}
+}
+
+// changeOrderItemRequest is a requested change order line; a negative
+// quantity removes work
+type changeOrderItemRequest struct {
+	ItemID   string  `json:"itemId"`
+	Quantity float64 `json:"quantity"`
+}
+
+// List handles GET /api/jobs/:id/change-orders
+func (h *ChangeOrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, job.ChangeOrders)
+}
+
+// Get handles GET /api/jobs/:id/change-orders/:changeOrderId
+func (h *ChangeOrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	changeOrder, ok := h.getChangeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, changeOrder)
+}
+
+// Create handles POST /api/jobs/:id/change-orders
+func (h *ChangeOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string                   `json:"reason"`
+		Items  []changeOrderItemRequest `json:"items"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	items, ok := h.priceItems(w, r, req.Items)
+	if !ok {
+		return
+	}
+
+	changeOrder, err := models.NewChangeOrder(job.ID, req.Reason, items)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.changeOrderRepo.Create(r.Context(), changeOrder); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create change order")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, changeOrder)
+}
+
+// Update handles PUT /api/jobs/:id/change-orders/:changeOrderId. Only pending
+// change orders can be edited.
+func (h *ChangeOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	changeOrder, ok := h.getChangeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason *string                  `json:"reason"`
+		Items  []changeOrderItemRequest `json:"items"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if changeOrder.Status != models.ChangeOrderStatusPending {
+		respondWithError(w, http.StatusConflict, "only pending change orders can be edited")
+		return
+	}
+
+	if req.Reason != nil {
+		if *req.Reason == "" {
+			respondWithError(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+		changeOrder.Reason = *req.Reason
+	}
+
+	if req.Items != nil {
+		items, ok := h.priceItems(w, r, req.Items)
+		if !ok {
+			return
+		}
+		if err := changeOrder.SetItems(items); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.save(w, r, changeOrder, models.ChangeOrderStatusPending)
+}
+
+// Delete handles DELETE /api/jobs/:id/change-orders/:changeOrderId. Approved
+// change orders are part of the job's scope and cannot be deleted.
+func (h *ChangeOrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	changeOrder, ok := h.getChangeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	if changeOrder.IsApproved() {
+		respondWithError(w, http.StatusConflict, "Approved change orders cannot be deleted")
+		return
+	}
+
+	if err := h.changeOrderRepo.Delete(r.Context(), changeOrder.ID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete change order")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Approve handles POST /api/jobs/:id/change-orders/:changeOrderId/approve and
+// adds the change order to the job's total
+func (h *ChangeOrderHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	if job.WaveInvoiceID != "" {
+		respondWithError(w, http.StatusConflict, "Job has already been invoiced")
+		return
+	}
+
+	changeOrder, ok := h.getChangeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	from := changeOrder.Status
+	if err := changeOrder.Approve(); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	// Saving the approval also adds the change order to the job total
+	h.save(w, r, changeOrder, from)
+}
+
+// Reject handles POST /api/jobs/:id/change-orders/:changeOrderId/reject
+func (h *ChangeOrderHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	changeOrder, ok := h.getChangeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	from := changeOrder.Status
+	if err := changeOrder.Reject(); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	h.save(w, r, changeOrder, from)
+}
+
+// SignOff handles POST /api/jobs/:id/change-orders/:changeOrderId/sign-off and
+// records the customer's signature
+func (h *ChangeOrderHandler) SignOff(w http.ResponseWriter, r *http.Request) {
+	changeOrder, ok := h.getChangeOrder(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		SignedBy string `json:"signedBy"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := changeOrder.SignOff(req.SignedBy); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.save(w, r, changeOrder, changeOrder.Status)
+}
+
+// save persists a change made to the change order in the given status, so a
+// request racing another decision or signature gets a conflict instead of
+// overwriting it, and writes the change order as the response
+func (h *ChangeOrderHandler) save(w http.ResponseWriter, r *http.Request, changeOrder *models.ChangeOrder, from models.ChangeOrderStatus) {
+	err := h.changeOrderRepo.UpdateFromStatus(r.Context(), changeOrder, from)
+	if errors.Is(err, repository.ErrConflict) {
+		respondWithError(w, http.StatusConflict, "Change order was changed by another request")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update change order")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, changeOrder)
+}
+
+// priceItems turns requested lines into change order lines priced at each
+// item's current unit price. It writes an error response and returns false on
+// failure.
+func (h *ChangeOrderHandler) priceItems(w http.ResponseWriter, r *http.Request, requested []changeOrderItemRequest) ([]models.ChangeOrderItem, bool) {
+	items := make([]models.ChangeOrderItem, 0, len(requested))
+	for _, req := range requested {
+		item, err := h.itemRepo.GetByID(r.Context(), req.ItemID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Item %s not found", req.ItemID))
+				return nil, false
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to get item")
+			return nil, false
+		}
+
+		items = append(items, models.ChangeOrderItem{
+			ItemID:   item.ID,
+			Name:     item.Name,
+			Quantity: req.Quantity,
+			Price:    item.UnitPrice,
+			Cost:     item.UnitCost,
+		})
+	}
+
+	return items, true
+}
+
+// getJob loads the job named in the URL. It writes an error response and
+// returns false on failure.
+func (h *ChangeOrderHandler) getJob(w http.ResponseWriter, r *http.Request) (*models.Job, bool) {
+	job, err := h.jobRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			respondWithError(w, http.StatusNotFound, "Job not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job")
+		return nil, false
+	}
+
+	return job, true
+}
+
+// getChangeOrder loads the change order named in the URL, checking that it
+// belongs to the job. It writes an error response and returns false on failure.
+func (h *ChangeOrderHandler) getChangeOrder(w http.ResponseWriter, r *http.Request) (*models.ChangeOrder, bool) {
+	vars := mux.Vars(r)
+
+	changeOrder, err := h.changeOrderRepo.GetByID(r.Context(), vars["changeOrderId"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			respondWithError(w, http.StatusNotFound, "Change order not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get change order")
+		return nil, false
+	}
+
+	if changeOrder.JobID != vars["id"] {
+		respondWithError(w, http.StatusNotFound, "Change order not found")
+		return nil, false
+	}
+
+	return changeOrder, true
+}
+
+// changeOrderLineItems builds the invoice lines for a job's approved change
+// orders. Each change order's lines carry its label so they read as a group on
+// the invoice; removed work is billed as a credit.
+func changeOrderLineItems(job *models.Job) []services.LineItem {
+	var lineItems []services.LineItem
+
+	for i := range job.ChangeOrders {
+		changeOrder := &job.ChangeOrders[i]
+		if !changeOrder.IsApproved() {
+			continue
+		}
+
+		for _, item := range changeOrder.Items {
+			quantity, price := item.Quantity, item.Price
+			if quantity < 0 {
+				quantity, price = -quantity, -price
+			}
+
+			lineItems = append(lineItems, services.LineItem{
+				ItemID:      item.ItemID,
+				ProductName: item.Name,
+				Description: changeOrder.InvoiceLabel(),
+				Quantity:    quantity,
+				Price:       price,
+				Total:       item.Total,
+			})
+		}
+	}
+
+	return lineItems
+}
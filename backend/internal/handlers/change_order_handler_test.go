@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+func TestChangeOrderLineItems(t *testing.T) {
+	job := &models.Job{
+		ChangeOrders: []models.ChangeOrder{
+			{
+				Number: 1,
+				Reason: "Add subpanel",
+				Status: models.ChangeOrderStatusApproved,
+				Items: []models.ChangeOrderItem{
+					{Name: "Subpanel", Quantity: 1, Price: models.Cents(85000), Total: models.Cents(85000)},
+					{Name: "Recessed Light", Quantity: -2, Price: models.Cents(4500), Total: models.Cents(-9000)},
+					{Name: "12/2 Wire", Quantity: 62.5, Price: models.Cents(89), Total: models.Cents(5563)},
+				},
+			},
+			{
+				Number: 2,
+				Reason: "Extra outlets",
+				Status: models.ChangeOrderStatusPending,
+				Items:  []models.ChangeOrderItem{{Name: "Outlet", Quantity: 4, Price: models.Cents(1500), Total: models.Cents(6000)}},
+			},
+			{
+				Number: 3,
+				Reason: "Hot tub circuit",
+				Status: models.ChangeOrderStatusRejected,
+				Items:  []models.ChangeOrderItem{{Name: "GFCI Breaker", Quantity: 1, Price: models.Cents(12000), Total: models.Cents(12000)}},
+			},
+		},
+	}
+
+	lineItems := changeOrderLineItems(job)
+
+	if len(lineItems) != 3 {
+		t.Fatalf("expected 3 lines from the approved change order, got %d", len(lineItems))
+	}
+
+	for _, line := range lineItems {
+		if line.Description != "Change Order #1: Add subpanel" {
+			t.Errorf("expected change order label, got %q", line.Description)
+		}
+	}
+
+	credit := lineItems[1]
+	if credit.Quantity != 2 || credit.Price != models.Cents(-4500) || credit.Total != models.Cents(-9000) {
+		t.Errorf("expected removed work to be a credit, got %+v", credit)
+	}
+
+	wire := lineItems[2]
+	if wire.Quantity != 62.5 {
+		t.Errorf("expected fractional quantity 62.5 to be kept, got %v", wire.Quantity)
+	}
+	if wire.InvoiceTotal() != wire.Total {
+		t.Errorf("expected Wave to bill the local line total %s, got %s", wire.Total, wire.InvoiceTotal())
+	}
+}
+
+// raceChangeOrderRepository serves a pending change order that another request
+// decides before this one saves it
+type raceChangeOrderRepository struct {
+	repository.ChangeOrderRepository
+	from models.ChangeOrderStatus
+}
+
+func (r *raceChangeOrderRepository) GetByID(ctx context.Context, id string) (*models.ChangeOrder, error) {
+	return &models.ChangeOrder{ID: id, JobID: "job1", Reason: "Extra outlet", Status: models.ChangeOrderStatusPending}, nil
+}
+
+func (r *raceChangeOrderRepository) UpdateFromStatus(ctx context.Context, changeOrder *models.ChangeOrder, from models.ChangeOrderStatus) error {
+	r.from = from
+	return repository.ErrConflict
+}
+
+func TestChangeOrderHandler_Reject_ConflictsWithConcurrentDecision(t *testing.T) {
+	repo := &raceChangeOrderRepository{}
+	handler := NewChangeOrderHandler(repo, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/jobs/job1/change-orders/co1/reject", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "job1", "changeOrderId": "co1"})
+	w := httptest.NewRecorder()
+	handler.Reject(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	if repo.from != models.ChangeOrderStatusPending {
+		t.Errorf("expected the save to require the pending status it was read in, got %q", repo.from)
+	}
+}
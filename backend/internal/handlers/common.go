@@ -33,4 +33,13 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 // respondWithError sends an error response
 func respondWithError(w http.ResponseWriter, code int, message string) {
 	respondWithJSON(w, code, map[string]string{"error": message})
-}
\ No newline at end of file
+}
+// validationError reports a problem with the request's data rather than a
+// storage failure, so handlers can answer 400 instead of 500
+type validationError struct {
+	msg string
+}
+
+func (e *validationError) Error() string {
+	return e.msg
+}
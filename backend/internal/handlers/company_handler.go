@@ -22,8 +22,15 @@ func NewCompanyHandler(companyRepo repository.CompanyRepository) *CompanyHandler
 
 // RegisterRoutes registers all company routes
 func (h *CompanyHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/company", h.Get).Methods("GET", "OPTIONS")
-	router.HandleFunc("/company", h.Update).Methods("PUT", "OPTIONS")
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the company endpoints and who may call them
+func (h *CompanyHandler) routes() []route {
+	return []route{
+		{"GET", "/company", h.Get, anyRole},
+		{"PUT", "/company", h.Update, adminOnly},
+	}
 }
 
 // Get returns the company settings
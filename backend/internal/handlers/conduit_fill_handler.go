@@ -0,0 +1,474 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/conduitfill"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// ConduitFillHandler handles HTTP requests for the conductor and conduit area
+// tables, sizing conduit for a set of conductors, and adding the conduit to
+// jobs and templates
+type ConduitFillHandler struct {
+	fillRepo     repository.ConduitFillRepository
+	jobRepo      repository.JobRepository
+	templateRepo repository.JobTemplateRepository
+	itemRepo     repository.ItemRepository
+}
+
+// NewConduitFillHandler creates a new conduit fill handler
+func NewConduitFillHandler(
+	fillRepo repository.ConduitFillRepository,
+	jobRepo repository.JobRepository,
+	templateRepo repository.JobTemplateRepository,
+	itemRepo repository.ItemRepository,
+) *ConduitFillHandler {
+	return &ConduitFillHandler{
+		fillRepo:     fillRepo,
+		jobRepo:      jobRepo,
+		templateRepo: templateRepo,
+		itemRepo:     itemRepo,
+	}
+}
+
+// RegisterRoutes registers all conduit fill routes
+func (h *ConduitFillHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the conduit fill endpoints; anyone may read the tables and
+// size conduit, but only admins edit the tables or add conduit to jobs and
+// templates
+func (h *ConduitFillHandler) routes() []route {
+	return []route{
+		{"POST", "/conduit-fill", h.Size, anyRole},
+
+		{"GET", "/conduit-fill/conductors", h.ListConductors, anyRole},
+		{"POST", "/conduit-fill/conductors", h.CreateConductor, adminOnly},
+		{"PUT", "/conduit-fill/conductors/{id}", h.UpdateConductor, adminOnly},
+		{"DELETE", "/conduit-fill/conductors/{id}", h.DeleteConductor, adminOnly},
+
+		{"GET", "/conduit-fill/conduits", h.ListConduits, anyRole},
+		{"POST", "/conduit-fill/conduits", h.CreateConduit, adminOnly},
+		{"PUT", "/conduit-fill/conduits/{id}", h.UpdateConduit, adminOnly},
+		{"DELETE", "/conduit-fill/conduits/{id}", h.DeleteConduit, adminOnly},
+
+		{"POST", "/jobs/{id}/conduit-fill", h.AddToJob, adminOnly},
+		{"POST", "/templates/{id}/conduit-fill", h.AddToTemplate, adminOnly},
+	}
+}
+
+// conductorAreaRequest is a requested conductor area
+type conductorAreaRequest struct {
+	Insulation string  `json:"insulation"`
+	Size       string  `json:"size"`
+	AreaSqIn   float64 `json:"areaSqIn"`
+}
+
+// conduitSizeRequest is a requested conduit size
+type conduitSizeRequest struct {
+	ConduitType string  `json:"conduitType"`
+	TradeSize   string  `json:"tradeSize"`
+	AreaSqIn    float64 `json:"areaSqIn"`
+	ItemID      string  `json:"itemId"`
+}
+
+// conduitFillRequest is a set of conductors to pull in a conduit type and,
+// when the conduit is added to a job or template, how much of it
+type conduitFillRequest struct {
+	ConduitType string                        `json:"conduitType"`
+	Conductors  []models.ConduitFillConductor `json:"conductors"`
+	Quantity    float64                       `json:"quantity"`
+}
+
+// conduitFillResponse is the smallest conduit that holds the conductors and,
+// when it was added, the job or template line
+type conduitFillResponse struct {
+	Result       *conduitfill.Result  `json:"result"`
+	Conduit      *models.ConduitSize  `json:"conduit"`
+	JobItem      *models.JobItem      `json:"jobItem,omitempty"`
+	TemplateItem *models.TemplateItem `json:"templateItem,omitempty"`
+}
+
+// Size handles POST /api/conduit-fill, finding the smallest trade size of the
+// conduit type that holds the conductors
+func (h *ConduitFillHandler) Size(w http.ResponseWriter, r *http.Request) {
+	var req conduitFillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, ok := h.size(w, r, req)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// AddToJob handles POST /api/jobs/:id/conduit-fill: it sizes the conduit and
+// budgets quantity of its item on the job
+func (h *ConduitFillHandler) AddToJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			respondWithError(w, http.StatusNotFound, "Job not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job")
+		return
+	}
+
+	var req conduitFillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Quantity <= 0 {
+		respondWithError(w, http.StatusBadRequest, "quantity must be positive")
+		return
+	}
+
+	response, ok := h.size(w, r, req)
+	if !ok {
+		return
+	}
+	item, ok := h.conduitItem(w, r, response.Conduit)
+	if !ok {
+		return
+	}
+
+	line := models.BudgetJobItem(job.ID, item, req.Quantity)
+	// The repository sets the line total and refreshes the job total
+	if err := h.jobRepo.AddJobItem(ctx, job.ID, &line); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to add job item")
+		return
+	}
+
+	response.JobItem = &line
+	respondWithJSON(w, http.StatusCreated, response)
+}
+
+// AddToTemplate handles POST /api/templates/:id/conduit-fill: it sizes the
+// conduit and adds quantity of its item to the template
+func (h *ConduitFillHandler) AddToTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	template, err := h.templateRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "template not found" {
+			respondWithError(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get template")
+		return
+	}
+
+	var req conduitFillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Quantity <= 0 {
+		respondWithError(w, http.StatusBadRequest, "quantity must be positive")
+		return
+	}
+
+	response, ok := h.size(w, r, req)
+	if !ok {
+		return
+	}
+	item, ok := h.conduitItem(w, r, response.Conduit)
+	if !ok {
+		return
+	}
+
+	templateItem := &models.TemplateItem{
+		ID:              uuid.New().String(),
+		TemplateID:      template.ID,
+		ItemID:          item.ID,
+		DefaultQuantity: req.Quantity,
+	}
+	if err := h.templateRepo.AddTemplateItem(ctx, template.ID, templateItem); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to add template item")
+		return
+	}
+
+	response.TemplateItem = templateItem
+	respondWithJSON(w, http.StatusCreated, response)
+}
+
+// ListConductors handles GET /api/conduit-fill/conductors
+func (h *ConduitFillHandler) ListConductors(w http.ResponseWriter, r *http.Request) {
+	conductors, err := h.fillRepo.ListConductors(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list conductor areas")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, conductors)
+}
+
+// CreateConductor handles POST /api/conduit-fill/conductors
+func (h *ConduitFillHandler) CreateConductor(w http.ResponseWriter, r *http.Request) {
+	var req conductorAreaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	conductor, err := models.NewConductorArea(req.Insulation, req.Size, req.AreaSqIn)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.fillRepo.CreateConductor(r.Context(), conductor); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "This conductor already has an area")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create conductor area")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, conductor)
+}
+
+// UpdateConductor handles PUT /api/conduit-fill/conductors/:id
+func (h *ConduitFillHandler) UpdateConductor(w http.ResponseWriter, r *http.Request) {
+	conductor, err := h.fillRepo.GetConductor(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Conductor area not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get conductor area")
+		return
+	}
+
+	var req conductorAreaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := conductor.Update(req.Insulation, req.Size, req.AreaSqIn); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.fillRepo.UpdateConductor(r.Context(), conductor); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "This conductor already has an area")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update conductor area")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, conductor)
+}
+
+// DeleteConductor handles DELETE /api/conduit-fill/conductors/:id
+func (h *ConduitFillHandler) DeleteConductor(w http.ResponseWriter, r *http.Request) {
+	if err := h.fillRepo.DeleteConductor(r.Context(), mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Conductor area not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete conductor area")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListConduits handles GET /api/conduit-fill/conduits, of one ?type= or all
+func (h *ConduitFillHandler) ListConduits(w http.ResponseWriter, r *http.Request) {
+	conduits, err := h.fillRepo.ListConduits(r.Context(), r.URL.Query().Get("type"))
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list conduit sizes")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, conduits)
+}
+
+// CreateConduit handles POST /api/conduit-fill/conduits
+func (h *ConduitFillHandler) CreateConduit(w http.ResponseWriter, r *http.Request) {
+	var req conduitSizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	conduit, err := models.NewConduitSize(req.ConduitType, req.TradeSize, req.AreaSqIn, req.ItemID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.fillRepo.CreateConduit(r.Context(), conduit); err != nil {
+		h.conduitSaveError(w, err, req.ItemID, "Failed to create conduit size")
+		return
+	}
+
+	h.respondWithConduit(w, r, conduit.ID, http.StatusCreated)
+}
+
+// UpdateConduit handles PUT /api/conduit-fill/conduits/:id
+func (h *ConduitFillHandler) UpdateConduit(w http.ResponseWriter, r *http.Request) {
+	conduit, err := h.fillRepo.GetConduit(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Conduit size not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get conduit size")
+		return
+	}
+
+	var req conduitSizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := conduit.Update(req.ConduitType, req.TradeSize, req.AreaSqIn, req.ItemID); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.fillRepo.UpdateConduit(r.Context(), conduit); err != nil {
+		h.conduitSaveError(w, err, req.ItemID, "Failed to update conduit size")
+		return
+	}
+
+	h.respondWithConduit(w, r, conduit.ID, http.StatusOK)
+}
+
+// DeleteConduit handles DELETE /api/conduit-fill/conduits/:id
+func (h *ConduitFillHandler) DeleteConduit(w http.ResponseWriter, r *http.Request) {
+	if err := h.fillRepo.DeleteConduit(r.Context(), mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Conduit size not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete conduit size")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// size finds the smallest conduit of the requested type that holds the
+// requested conductors, writing the error response when it cannot
+func (h *ConduitFillHandler) size(w http.ResponseWriter, r *http.Request, req conduitFillRequest) (*conduitFillResponse, bool) {
+	response, err := sizeConduit(r.Context(), h.fillRepo, req)
+	if err != nil {
+		var validationErr *validationError
+		if errors.As(err, &validationErr) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to load conduit fill tables")
+		return nil, false
+	}
+
+	return response, true
+}
+
+// conduitItem loads the catalog item that sells a conduit size, writing the
+// error response when it cannot
+func (h *ConduitFillHandler) conduitItem(w http.ResponseWriter, r *http.Request, conduit *models.ConduitSize) (*models.Item, bool) {
+	if conduit.ItemID == "" {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("No item is linked to %s %s", conduit.TradeSize, conduit.ConduitType))
+		return nil, false
+	}
+
+	item, err := h.itemRepo.GetByID(r.Context(), conduit.ItemID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get conduit item")
+		return nil, false
+	}
+
+	return item, true
+}
+
+// conduitSaveError writes the response for a conduit size that couldn't be
+// saved
+func (h *ConduitFillHandler) conduitSaveError(w http.ResponseWriter, err error, itemID, message string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		respondWithError(w, http.StatusNotFound, "Conduit size not found")
+	case errors.Is(err, repository.ErrDuplicate):
+		respondWithError(w, http.StatusConflict, "This conduit size already has an area")
+	case errors.Is(err, repository.ErrInvalidInput):
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Item %s not found", itemID))
+	default:
+		respondWithError(w, http.StatusInternalServerError, message)
+	}
+}
+
+// respondWithConduit writes a saved conduit size with its item's name
+func (h *ConduitFillHandler) respondWithConduit(w http.ResponseWriter, r *http.Request, id string, status int) {
+	conduit, err := h.fillRepo.GetConduit(r.Context(), id)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get conduit size")
+		return
+	}
+
+	respondWithJSON(w, status, conduit)
+}
+
+// sizeConduit finds the smallest conduit of the requested type that holds the
+// requested conductors. Requests that can't be sized return a
+// validationError.
+func sizeConduit(ctx context.Context, fillRepo repository.ConduitFillRepository, req conduitFillRequest) (*conduitFillResponse, error) {
+	if req.ConduitType == "" {
+		return nil, &validationError{"conduit type is required"}
+	}
+
+	conduits, err := fillRepo.ListConduits(ctx, req.ConduitType)
+	if err != nil {
+		return nil, err
+	}
+	if len(conduits) == 0 {
+		return nil, &validationError{fmt.Sprintf("unknown conduit type %q", req.ConduitType)}
+	}
+
+	areas, err := fillRepo.ListConductors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conductors, err := models.FillConductors(req.Conductors, areas)
+	if err != nil {
+		return nil, &validationError{err.Error()}
+	}
+
+	result, err := conduitfill.Size(conductors, models.FillConduits(conduits))
+	if err != nil {
+		return nil, &validationError{err.Error()}
+	}
+
+	response := &conduitFillResponse{Result: result}
+	for i := range conduits {
+		if conduits[i].TradeSize == result.TradeSize {
+			response.Conduit = &conduits[i]
+			break
+		}
+	}
+	return response, nil
+}
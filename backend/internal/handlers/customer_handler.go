@@ -24,11 +24,18 @@ func NewCustomerHandler(customerRepo repository.CustomerRepository) *CustomerHan
 
 // RegisterRoutes registers all customer routes
 func (h *CustomerHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/customers", h.List).Methods("GET", "OPTIONS")
-	router.HandleFunc("/customers", h.Create).Methods("POST", "OPTIONS")
-	router.HandleFunc("/customers/{id}", h.Get).Methods("GET", "OPTIONS")
-	router.HandleFunc("/customers/{id}", h.Update).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/customers/{id}", h.Delete).Methods("DELETE", "OPTIONS")
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the customer endpoints and who may call them
+func (h *CustomerHandler) routes() []route {
+	return []route{
+		{"GET", "/customers", h.List, anyRole},
+		{"POST", "/customers", h.Create, adminOnly},
+		{"GET", "/customers/{id}", h.Get, anyRole},
+		{"PUT", "/customers/{id}", h.Update, adminOnly},
+		{"DELETE", "/customers/{id}", h.Delete, adminOnly},
+	}
 }
 
 // List returns all customers
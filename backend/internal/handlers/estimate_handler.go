@@ -0,0 +1,541 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// EstimateHandler handles HTTP requests for estimates
+type EstimateHandler struct {
+	uow          repository.UnitOfWork
+	estimateRepo repository.EstimateRepository
+	customerRepo repository.CustomerRepository
+	templateRepo repository.JobTemplateRepository
+	itemRepo     repository.ItemRepository
+}
+
+// NewEstimateHandler creates a new estimate handler
+func NewEstimateHandler(
+	uow repository.UnitOfWork,
+	estimateRepo repository.EstimateRepository,
+	customerRepo repository.CustomerRepository,
+	templateRepo repository.JobTemplateRepository,
+	itemRepo repository.ItemRepository,
+) *EstimateHandler {
+	return &EstimateHandler{
+		uow:          uow,
+		estimateRepo: estimateRepo,
+		customerRepo: customerRepo,
+		templateRepo: templateRepo,
+		itemRepo:     itemRepo,
+	}
+}
+
+// RegisterRoutes registers all estimate routes
+func (h *EstimateHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the estimate endpoints; bidding is admin-only
+func (h *EstimateHandler) routes() []route {
+	return []route{
+		{"GET", "/estimates", h.List, adminOnly},
+		{"POST", "/estimates", h.Create, adminOnly},
+		{"GET", "/estimates/{id}", h.Get, adminOnly},
+		{"PUT", "/estimates/{id}", h.Update, adminOnly},
+		{"DELETE", "/estimates/{id}", h.Delete, adminOnly},
+
+		// Approval workflow
+		{"POST", "/estimates/{id}/send", h.Send, adminOnly},
+		{"POST", "/estimates/{id}/accept", h.Accept, adminOnly},
+		{"POST", "/estimates/{id}/reject", h.Reject, adminOnly},
+		{"POST", "/estimates/{id}/revise", h.Revise, adminOnly},
+	}
+}
+
+// estimateItemRequest is a requested estimate line
+type estimateItemRequest struct {
+	ItemID   string  `json:"itemId"`
+	Quantity float64 `json:"quantity"`
+}
+
+// List handles GET /api/estimates
+func (h *EstimateHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := 50
+	offset := 0
+
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	var estimates []*models.Estimate
+	var err error
+
+	switch {
+	case r.URL.Query().Get("status") != "":
+		estimates, err = h.estimateRepo.GetByStatus(ctx, models.EstimateStatus(r.URL.Query().Get("status")))
+	case r.URL.Query().Get("customerId") != "":
+		estimates, err = h.estimateRepo.GetByCustomerID(ctx, r.URL.Query().Get("customerId"))
+	default:
+		estimates, err = h.estimateRepo.List(ctx, limit, offset)
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list estimates")
+		return
+	}
+
+	for _, estimate := range estimates {
+		if err := h.expireIfPastDue(ctx, estimate); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to update estimate")
+			return
+		}
+	}
+
+	if estimates == nil {
+		estimates = []*models.Estimate{}
+	}
+
+	respondWithJSON(w, http.StatusOK, estimates)
+}
+
+// Get handles GET /api/estimates/:id
+func (h *EstimateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, estimate)
+}
+
+// Create handles POST /api/estimates. Lines default to the template's items
+// at their default quantities, priced at each item's current unit price.
+func (h *EstimateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req struct {
+		CustomerID string                `json:"customerId"`
+		TemplateID string                `json:"templateId"`
+		Address    string                `json:"address"`
+		ValidUntil *time.Time            `json:"validUntil"`
+		Notes      string                `json:"notes"`
+		Items      []estimateItemRequest `json:"items"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.ValidUntil == nil {
+		respondWithError(w, http.StatusBadRequest, "Valid until date is required")
+		return
+	}
+
+	estimate, err := models.NewEstimate(req.CustomerID, req.TemplateID, req.Address, *req.ValidUntil)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	estimate.Notes = req.Notes
+
+	if _, err := h.customerRepo.GetByID(ctx, req.CustomerID); err != nil {
+		if err.Error() == "customer not found" {
+			respondWithError(w, http.StatusBadRequest, "Customer not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get customer")
+		return
+	}
+
+	template, err := h.templateRepo.GetByID(ctx, req.TemplateID)
+	if err != nil {
+		if err.Error() == "template not found" {
+			respondWithError(w, http.StatusBadRequest, "Template not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get template")
+		return
+	}
+
+	requested := req.Items
+	if requested == nil {
+		for _, templateItem := range template.Items {
+			requested = append(requested, estimateItemRequest{
+				ItemID:   templateItem.ItemID,
+				Quantity: templateItem.DefaultQuantity,
+			})
+		}
+	}
+
+	if !h.setItems(w, r, estimate, requested) {
+		return
+	}
+
+	if err := h.estimateRepo.Create(ctx, estimate); err != nil {
+		log.Printf("Error creating estimate: %v", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create estimate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, estimate)
+}
+
+// Update handles PUT /api/estimates/:id. Only drafts can be edited.
+func (h *EstimateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Address    *string               `json:"address"`
+		ValidUntil *time.Time            `json:"validUntil"`
+		Notes      *string               `json:"notes"`
+		Items      []estimateItemRequest `json:"items"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if estimate.Status != models.EstimateStatusDraft {
+		respondWithError(w, http.StatusConflict, "only draft estimates can be edited")
+		return
+	}
+
+	if req.Address != nil {
+		if *req.Address == "" {
+			respondWithError(w, http.StatusBadRequest, "address is required")
+			return
+		}
+		estimate.Address = *req.Address
+	}
+
+	if req.ValidUntil != nil {
+		if !req.ValidUntil.After(time.Now()) {
+			respondWithError(w, http.StatusBadRequest, "valid until date must be in the future")
+			return
+		}
+		estimate.ValidUntil = *req.ValidUntil
+	}
+
+	if req.Notes != nil {
+		estimate.Notes = *req.Notes
+	}
+
+	if req.Items != nil {
+		if !h.setItems(w, r, estimate, req.Items) {
+			return
+		}
+	}
+
+	estimate.UpdatedAt = time.Now()
+	if err := h.estimateRepo.Update(ctx, estimate); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update estimate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, estimate)
+}
+
+// Delete handles DELETE /api/estimates/:id. Accepted estimates are kept as the
+// record behind their job.
+func (h *EstimateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	if estimate.Status == models.EstimateStatusAccepted {
+		respondWithError(w, http.StatusConflict, "Accepted estimates cannot be deleted")
+		return
+	}
+
+	if err := h.estimateRepo.Delete(r.Context(), estimate.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Estimate not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete estimate")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Send handles POST /api/estimates/:id/send
+func (h *EstimateHandler) Send(w http.ResponseWriter, r *http.Request) {
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	from := estimate.Status
+	if err := estimate.Send(); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	h.save(w, r, estimate, from)
+}
+
+// Accept handles POST /api/estimates/:id/accept. The customer's approval
+// creates a scheduled job whose budget is the estimated quantities.
+func (h *EstimateHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ScheduledDate *time.Time `json:"scheduledDate,omitempty"`
+	}
+
+	// The body is optional
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	from := estimate.Status
+	if err := estimate.Accept(); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	// Default to tomorrow like job creation does
+	scheduledDate := time.Now().Add(24 * time.Hour)
+	if req.ScheduledDate != nil {
+		scheduledDate = *req.ScheduledDate
+	}
+
+	job, created, err := h.buildJob(ctx, estimate, scheduledDate)
+	if err != nil {
+		var validationErr *validationError
+		if errors.As(err, &validationErr) {
+			respondWithError(w, http.StatusBadRequest, validationErr.Error())
+			return
+		}
+		log.Printf("Error building job from estimate %s: %v", estimate.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create job from estimate")
+		return
+	}
+	estimate.SetJob(job.ID)
+
+	// The job and the accepted estimate are saved together, and only if no
+	// other request accepted or withdrew the estimate in the meantime
+	err = h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Estimates.UpdateFromStatus(ctx, estimate, from); err != nil {
+			return err
+		}
+		return createJob(ctx, repos.Jobs, job, created)
+	})
+	if errors.Is(err, repository.ErrConflict) {
+		respondWithError(w, http.StatusConflict, "Estimate was changed by another request")
+		return
+	}
+	if err != nil {
+		log.Printf("Error accepting estimate %s: %v", estimate.ID, err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create job from estimate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"estimate": estimate,
+		"job":      job,
+	})
+}
+
+// Reject handles POST /api/estimates/:id/reject
+func (h *EstimateHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	from := estimate.Status
+	if err := estimate.Reject(); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	h.save(w, r, estimate, from)
+}
+
+// Revise handles POST /api/estimates/:id/revise
+func (h *EstimateHandler) Revise(w http.ResponseWriter, r *http.Request) {
+	estimate, ok := h.getEstimate(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ValidUntil *time.Time `json:"validUntil"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.ValidUntil == nil {
+		respondWithError(w, http.StatusBadRequest, "Valid until date is required")
+		return
+	}
+
+	from := estimate.Status
+	if err := estimate.Revise(*req.ValidUntil); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	h.save(w, r, estimate, from)
+}
+
+// buildJob prepares the job for an accepted estimate and the first entry of
+// its status timeline. Installed quantities start at zero and the estimated
+// quantities become the job's budget. The job is billed at the price the
+// customer accepted, so default markups and discounts are not added to it.
+func (h *EstimateHandler) buildJob(ctx context.Context, estimate *models.Estimate, scheduledDate time.Time) (*models.Job, *models.JobStatusChange, error) {
+	job, err := models.NewJob(estimate.CustomerID, estimate.TemplateID, models.JobStatusScheduled, scheduledDate)
+	if err != nil {
+		return nil, nil, &validationError{msg: err.Error()}
+	}
+
+	job.Address = estimate.Address
+	job.Notes = estimate.Notes
+
+	template, err := h.templateRepo.GetByID(ctx, estimate.TemplateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	job.TemplateVersion = template.Version
+	job.StartPhases(template.Phases)
+
+	for _, line := range estimate.Items {
+		item, err := h.itemRepo.GetByID(ctx, line.ItemID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get item %s: %w", line.ItemID, err)
+		}
+
+		job.Items = append(job.Items, models.JobItem{
+			ID:               uuid.New().String(),
+			JobID:            job.ID,
+			ItemID:           line.ItemID,
+			Name:             line.Name,
+			Category:         item.Category,
+			Quantity:         0,
+			BudgetedQuantity: line.Quantity,
+			Price:            line.Price,
+			Cost:             item.UnitCost,
+			Total:            0,
+		})
+	}
+
+	job.CalculateTotal()
+
+	user, _ := middleware.UserFromContext(ctx)
+	created := models.NewJobStatusChange(job.ID, nil, job.Status, user, fmt.Sprintf("Created from estimate revision %d", estimate.Revision))
+	return job, created, nil
+}
+
+// setItems prices the requested lines at each item's current unit price and
+// replaces the estimate's lines. It writes an error response and returns false
+// on failure.
+func (h *EstimateHandler) setItems(w http.ResponseWriter, r *http.Request, estimate *models.Estimate, requested []estimateItemRequest) bool {
+	lines := make([]models.EstimateItem, 0, len(requested))
+	for _, req := range requested {
+		item, err := h.itemRepo.GetByID(r.Context(), req.ItemID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+				respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Item %s not found", req.ItemID))
+				return false
+			}
+			respondWithError(w, http.StatusInternalServerError, "Failed to get item")
+			return false
+		}
+		lines = append(lines, models.NewEstimateItem(item, req.Quantity))
+	}
+
+	if err := estimate.SetItems(lines); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// getEstimate loads the estimate named in the URL, expiring it if it is past
+// due. It writes an error response and returns false on failure.
+func (h *EstimateHandler) getEstimate(w http.ResponseWriter, r *http.Request) (*models.Estimate, bool) {
+	estimate, err := h.estimateRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			respondWithError(w, http.StatusNotFound, "Estimate not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get estimate")
+		return nil, false
+	}
+
+	if err := h.expireIfPastDue(r.Context(), estimate); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update estimate")
+		return nil, false
+	}
+
+	return estimate, true
+}
+
+// expireIfPastDue persists the expired status of a sent estimate past its validity date
+func (h *EstimateHandler) expireIfPastDue(ctx context.Context, estimate *models.Estimate) error {
+	if !estimate.ExpireIfPastDue(time.Now()) {
+		return nil
+	}
+	return h.estimateRepo.Update(ctx, estimate)
+}
+
+// save persists a status change made from the given status and writes the
+// estimate as the response
+func (h *EstimateHandler) save(w http.ResponseWriter, r *http.Request, estimate *models.Estimate, from models.EstimateStatus) {
+	err := h.estimateRepo.UpdateFromStatus(r.Context(), estimate, from)
+	if errors.Is(err, repository.ErrConflict) {
+		respondWithError(w, http.StatusConflict, "Estimate was changed by another request")
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update estimate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, estimate)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+func TestEstimateHandler_BuildJob_BudgetsQuotedTotal(t *testing.T) {
+	estimate, err := models.NewEstimate("cust", "tmpl", "1 Main St", time.Now().Add(30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outlet := &models.Item{ID: "outlet", Name: "Outlet", UnitPrice: models.Cents(4500)}
+	if err := estimate.SetItems([]models.EstimateItem{models.NewEstimateItem(outlet, 4)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handler := NewEstimateHandler(nil, nil, &stubCustomerRepository{},
+		&stubTemplateRepository{template: &models.JobTemplate{ID: "tmpl", Version: 2}},
+		&stubItemRepository{})
+
+	job, _, err := handler.buildJob(context.Background(), estimate, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The customer accepted 180.00; default markups would bill them more
+	if len(job.Adjustments) != 0 {
+		t.Errorf("expected no default adjustments on a job from an estimate, got %+v", job.Adjustments)
+	}
+	if job.BudgetedTotal() != estimate.TotalAmount {
+		t.Errorf("expected the job to budget the quoted %s, got %s", estimate.TotalAmount, job.BudgetedTotal())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/masterbrent/electrical-bidding-app/internal/services"
 )
 
@@ -15,6 +16,19 @@ func NewHealthHandler() *HealthHandler {
 	return &HealthHandler{}
 }
 
+// RegisterRoutes registers the service health check routes
+func (h *HealthHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the health check endpoints and who may call them
+func (h *HealthHandler) routes() []route {
+	return []route{
+		{"GET", "/health/wave", h.CheckWave, anyRole},
+		{"GET", "/health/cloudflare", h.CheckCloudflare, anyRole},
+	}
+}
+
 // CheckWave checks Wave API connectivity
 func (h *HealthHandler) CheckWave(w http.ResponseWriter, r *http.Request) {
 	// For now, we'll simulate the check since Wave service isn't implemented yet
@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
@@ -13,13 +18,23 @@ import (
 
 // ItemService defines the interface for item business logic
 type ItemService interface {
-	Create(ctx context.Context, name, unit string, unitPrice float64, category string) (*models.Item, error)
+	Create(ctx context.Context, name, unit string, unitPrice models.Money, category string) (*models.Item, error)
 	GetByID(ctx context.Context, id string) (*models.Item, error)
 	List(ctx context.Context) ([]*models.Item, error)
 	Update(ctx context.Context, id string, updates map[string]interface{}) (*models.Item, error)
 	Delete(ctx context.Context, id string) error
+	AddCost(ctx context.Context, itemID, supplier string, cost models.Money, effectiveFrom time.Time) (*models.ItemCost, error)
+	ListCosts(ctx context.Context, itemID string) ([]models.ItemCost, error)
+	ListPriceHistory(ctx context.Context, itemID string) ([]models.ItemPriceChange, error)
+	Export(ctx context.Context, w io.Writer) error
+	Import(ctx context.Context, r io.Reader, apply bool) (*models.ItemImport, error)
+	CreateAssembly(ctx context.Context, draft *models.Item) (*models.Item, error)
+	UpdateAssembly(ctx context.Context, id string, draft *models.Item) (*models.Item, error)
 }
 
+// maxItemImportSize caps the size of an uploaded catalog CSV
+const maxItemImportSize = 10 << 20
+
 // ItemHandler handles HTTP requests for items
 type ItemHandler struct {
 	service ItemService
@@ -32,13 +47,76 @@ func NewItemHandler(service ItemService) *ItemHandler {
 	}
 }
 
+// RegisterRoutes registers all item routes
+func (h *ItemHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the item endpoints and who may call them. Pricing is admin-only.
+func (h *ItemHandler) routes() []route {
+	return []route{
+		{"POST", "/items", h.Create, adminOnly},
+		{"GET", "/items", h.List, anyRole},
+		{"GET", "/items/export", h.Export, adminOnly},
+		{"POST", "/items/import", h.Import, adminOnly},
+		{"GET", "/items/{id}", h.GetByID, anyRole},
+		{"PUT", "/items/{id}", h.Update, adminOnly},
+		{"DELETE", "/items/{id}", h.Delete, adminOnly},
+		{"GET", "/items/{id}/costs", h.ListCosts, adminOnly},
+		{"POST", "/items/{id}/costs", h.AddCost, adminOnly},
+		{"GET", "/items/{id}/price-history", h.PriceHistory, adminOnly},
+
+		{"GET", "/assemblies", h.ListAssemblies, anyRole},
+		{"POST", "/assemblies", h.CreateAssembly, adminOnly},
+		{"GET", "/assemblies/{id}", h.GetAssembly, anyRole},
+		{"PUT", "/assemblies/{id}", h.UpdateAssembly, adminOnly},
+	}
+}
+
+// assemblyRequest is a requested assembly: its details, how many of each
+// item go into one unit of it, and optionally the price to charge instead of
+// the sum of its parts
+type assemblyRequest struct {
+	Name            string        `json:"name"`
+	Nickname        string        `json:"nickname"`
+	Unit            string        `json:"unit"`
+	Category        string        `json:"category"`
+	PriceOverride   *models.Money `json:"priceOverride"`
+	ExpandOnInvoice bool          `json:"expandOnInvoice"`
+	Components      []struct {
+		ItemID   string  `json:"itemId"`
+		Quantity float64 `json:"quantity"`
+	} `json:"components"`
+}
+
+// draft is the assembly the request describes, for the service to fill in
+func (req *assemblyRequest) draft() *models.Item {
+	draft := &models.Item{
+		Name:            req.Name,
+		Nickname:        req.Nickname,
+		Unit:            req.Unit,
+		Category:        req.Category,
+		PriceOverride:   req.PriceOverride,
+		ExpandOnInvoice: req.ExpandOnInvoice,
+	}
+	for _, component := range req.Components {
+		draft.Components = append(draft.Components, models.AssemblyComponent{
+			ItemID:   component.ItemID,
+			Quantity: component.Quantity,
+		})
+	}
+	return draft
+}
+
 // Create handles POST /api/items
 func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Name      string  `json:"name"`
 		Nickname  string  `json:"nickname"`
 		Unit      string  `json:"unit"`
-		UnitPrice float64 `json:"unitPrice"`
+		UnitPrice models.Money `json:"unitPrice"`
+		UnitCost  models.Money `json:"unitCost"`
+		LaborHours float64 `json:"laborHours"`
 		Category  string  `json:"category"`
 	}
 
@@ -46,6 +124,10 @@ func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if req.LaborHours < 0 {
+		respondWithError(w, http.StatusBadRequest, "labor hours cannot be negative")
+		return
+	}
 
 	item, err := h.service.Create(r.Context(), req.Name, req.Unit, req.UnitPrice, req.Category)
 	if err != nil {
@@ -58,18 +140,26 @@ func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// If nickname was provided, update the item
+	// If nickname, cost or labor hours were provided, update the item
+	updates := map[string]interface{}{}
 	if req.Nickname != "" {
-		updates := map[string]interface{}{
-			"nickname": req.Nickname,
-		}
-		item, err = h.service.Update(r.Context(), item.ID, updates)
+		updates["nickname"] = req.Nickname
+	}
+	if req.UnitCost != 0 {
+		updates["unit_cost"] = req.UnitCost
+	}
+	if req.LaborHours != 0 {
+		updates["labor_hours"] = req.LaborHours
+	}
+	if len(updates) > 0 {
+		updated, err := h.service.Update(r.Context(), item.ID, updates)
 		if err != nil {
-			// Item was created but nickname update failed - log but don't fail the request
-			// The item is still usable without a nickname
+			// Item was created but the update failed - don't fail the request
+			// The item is still usable without a nickname, cost or labor hours
 			respondWithJSON(w, http.StatusCreated, item)
 			return
 		}
+		item = updated
 	}
 
 	respondWithJSON(w, http.StatusCreated, item)
@@ -109,6 +199,89 @@ func (h *ItemHandler) List(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, items)
 }
 
+// ListAssemblies handles GET /api/assemblies
+func (h *ItemHandler) ListAssemblies(w http.ResponseWriter, r *http.Request) {
+	items, err := h.service.List(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list assemblies")
+		return
+	}
+
+	assemblies := make([]*models.Item, 0)
+	for _, item := range items {
+		if item.IsAssembly() {
+			assemblies = append(assemblies, item)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, assemblies)
+}
+
+// GetAssembly handles GET /api/assemblies/:id
+func (h *ItemHandler) GetAssembly(w http.ResponseWriter, r *http.Request) {
+	item, err := h.service.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err == nil && !item.IsAssembly() {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Assembly not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get assembly")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, item)
+}
+
+// CreateAssembly handles POST /api/assemblies. The assembly is an item, so
+// templates and jobs use it by its ID like any other.
+func (h *ItemHandler) CreateAssembly(w http.ResponseWriter, r *http.Request) {
+	var req assemblyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	item, err := h.service.CreateAssembly(r.Context(), req.draft())
+	if err != nil {
+		if errors.Is(err, models.ErrValidation) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create assembly")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, item)
+}
+
+// UpdateAssembly handles PUT /api/assemblies/:id, replacing the assembly's
+// details and components
+func (h *ItemHandler) UpdateAssembly(w http.ResponseWriter, r *http.Request) {
+	var req assemblyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	item, err := h.service.UpdateAssembly(r.Context(), mux.Vars(r)["id"], req.draft())
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, "Assembly not found")
+		case errors.Is(err, models.ErrValidation):
+			respondWithError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to update assembly")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, item)
+}
+
 // Update handles PUT /api/items/:id
 func (h *ItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -119,8 +292,11 @@ func (h *ItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Keep numbers as json.Number so prices are parsed exactly
 	var updates map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&updates); err != nil {
 		respondWithError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
@@ -131,10 +307,20 @@ func (h *ItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 			respondWithError(w, http.StatusNotFound, "Item not found")
 			return
 		}
+		if errors.Is(err, models.ErrAssemblyPriced) {
+			respondWithError(w, http.StatusBadRequest, models.ErrAssemblyPriced.Error())
+			return
+		}
+		if errors.Is(err, models.ErrAssemblyLabor) {
+			respondWithError(w, http.StatusBadRequest, models.ErrAssemblyLabor.Error())
+			return
+		}
 		// Check for validation errors
 		errMsg := err.Error()
 		if errMsg == "item name is required" || errMsg == "unit is required" || 
-		   errMsg == "unit price must be positive" || errMsg == "unit price cannot be negative" {
+		   errMsg == "unit price must be positive" || errMsg == "unit price cannot be negative" ||
+		   errMsg == "unit cost cannot be negative" || errMsg == "labor hours cannot be negative" ||
+		   errMsg == "labor hours must be a number" {
 			respondWithError(w, http.StatusBadRequest, errMsg)
 			return
 		}
@@ -145,7 +331,7 @@ func (h *ItemHandler) Update(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, item)
 }
 
-// Delete handles DELETE /api/items/:id
+// Delete handles DELETE /api/items/:id, which also deletes assemblies
 func (h *ItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -161,6 +347,10 @@ func (h *ItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			respondWithError(w, http.StatusNotFound, "Item not found")
 			return
 		}
+		if errors.Is(err, repository.ErrInUse) {
+			respondWithError(w, http.StatusConflict, "Item is a component of an assembly; remove it from the assembly first")
+			return
+		}
 		respondWithError(w, http.StatusInternalServerError, "Failed to delete item")
 		return
 	}
@@ -168,3 +358,141 @@ func (h *ItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ListCosts handles GET /api/items/:id/costs
+func (h *ItemHandler) ListCosts(w http.ResponseWriter, r *http.Request) {
+	costs, err := h.service.ListCosts(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Item not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to list item costs")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, costs)
+}
+
+// PriceHistory handles GET /api/items/:id/price-history
+func (h *ItemHandler) PriceHistory(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.service.ListPriceHistory(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Item not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to list item price history")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, changes)
+}
+
+// AddCost handles POST /api/items/:id/costs. The cost takes effect from
+// effectiveFrom, or today when it is omitted.
+func (h *ItemHandler) AddCost(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Supplier      string       `json:"supplier"`
+		Cost          models.Money `json:"cost"`
+		EffectiveFrom string       `json:"effectiveFrom"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	effectiveFrom := time.Now()
+	if req.EffectiveFrom != "" {
+		parsed, err := parseDate(req.EffectiveFrom)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "effective from must be a date like 2006-01-02")
+			return
+		}
+		effectiveFrom = parsed
+	}
+
+	cost, err := h.service.AddCost(r.Context(), mux.Vars(r)["id"], req.Supplier, req.Cost, effectiveFrom)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Item not found")
+			return
+		}
+		if err.Error() == "unit cost cannot be negative" {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to add item cost")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cost)
+}
+
+// Export handles GET /api/items/export?format=csv
+func (h *ItemHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if format := r.URL.Query().Get("format"); format != "" && format != "csv" {
+		respondWithError(w, http.StatusBadRequest, "format must be csv")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.service.Export(r.Context(), &buf); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to export items")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="items.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// Import handles POST /api/items/import. The CSV is the request body or the
+// "file" field of a multipart form. Without ?apply=true it only reports what
+// each row would do; with it, every row must be valid and all are saved
+// together.
+func (h *ItemHandler) Import(w http.ResponseWriter, r *http.Request) {
+	apply := false
+	if value := r.URL.Query().Get("apply"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "apply must be true or false")
+			return
+		}
+		apply = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxItemImportSize)
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, _, err := r.FormFile("file")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Upload the CSV as the file field")
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	plan, err := h.service.Import(r.Context(), file, apply)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		var csvErr *models.CSVError
+		switch {
+		case errors.As(err, &tooLarge):
+			respondWithError(w, http.StatusRequestEntityTooLarge, "The file is too large")
+		case errors.As(err, &csvErr):
+			respondWithError(w, http.StatusBadRequest, csvErr.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to import items")
+		}
+		return
+	}
+
+	if apply && !plan.Applied {
+		respondWithJSON(w, http.StatusUnprocessableEntity, plan)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, plan)
+}
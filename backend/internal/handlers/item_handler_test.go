@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
@@ -16,17 +20,21 @@ import (
 // MockItemService implements the item service interface for testing
 type MockItemService struct {
 	items    map[string]*models.Item
+	costs    map[string][]models.ItemCost
+	prices   map[string][]models.ItemPriceChange
 	err      error
 	lastCall string
 }
 
 func NewMockItemService() *MockItemService {
 	return &MockItemService{
-		items: make(map[string]*models.Item),
+		items:  make(map[string]*models.Item),
+		costs:  make(map[string][]models.ItemCost),
+		prices: make(map[string][]models.ItemPriceChange),
 	}
 }
 
-func (m *MockItemService) Create(ctx context.Context, name, unit string, unitPrice float64, category string) (*models.Item, error) {
+func (m *MockItemService) Create(ctx context.Context, name, unit string, unitPrice models.Money, category string) (*models.Item, error) {
 	m.lastCall = "Create"
 	if m.err != nil {
 		return nil, m.err
@@ -85,6 +93,14 @@ func (m *MockItemService) Update(ctx context.Context, id string, updates map[str
 	}
 	
 	// Only update the stored item if validation passed
+	if itemCopy.UnitPrice != item.UnitPrice {
+		m.prices[id] = append([]models.ItemPriceChange{{
+			ItemID:    id,
+			OldPrice:  item.UnitPrice,
+			NewPrice:  itemCopy.UnitPrice,
+			ChangedAt: itemCopy.UpdatedAt,
+		}}, m.prices[id]...)
+	}
 	m.items[id] = &itemCopy
 	return &itemCopy, nil
 }
@@ -103,6 +119,146 @@ func (m *MockItemService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (m *MockItemService) AddCost(ctx context.Context, itemID, supplier string, cost models.Money, effectiveFrom time.Time) (*models.ItemCost, error) {
+	m.lastCall = "AddCost"
+	if m.err != nil {
+		return nil, m.err
+	}
+	
+	if _, exists := m.items[itemID]; !exists {
+		return nil, repository.ErrNotFound
+	}
+	
+	itemCost, err := models.NewItemCost(itemID, supplier, cost, effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	
+	m.costs[itemID] = append(m.costs[itemID], *itemCost)
+	return itemCost, nil
+}
+
+func (m *MockItemService) ListCosts(ctx context.Context, itemID string) ([]models.ItemCost, error) {
+	m.lastCall = "ListCosts"
+	if m.err != nil {
+		return nil, m.err
+	}
+	
+	if _, exists := m.items[itemID]; !exists {
+		return nil, repository.ErrNotFound
+	}
+	return m.costs[itemID], nil
+}
+
+func (m *MockItemService) ListPriceHistory(ctx context.Context, itemID string) ([]models.ItemPriceChange, error) {
+	m.lastCall = "ListPriceHistory"
+	if m.err != nil {
+		return nil, m.err
+	}
+	
+	if _, exists := m.items[itemID]; !exists {
+		return nil, repository.ErrNotFound
+	}
+	return m.prices[itemID], nil
+}
+
+func (m *MockItemService) Export(ctx context.Context, w io.Writer) error {
+	m.lastCall = "Export"
+	if m.err != nil {
+		return m.err
+	}
+	
+	items, _ := m.List(ctx)
+	return models.WriteItemsCSV(w, items)
+}
+
+func (m *MockItemService) Import(ctx context.Context, r io.Reader, apply bool) (*models.ItemImport, error) {
+	m.lastCall = "Import"
+	if m.err != nil {
+		return nil, m.err
+	}
+	
+	existing := make([]*models.Item, 0, len(m.items))
+	for _, item := range m.items {
+		existing = append(existing, item)
+	}
+	plan, err := models.PlanItemImport(r, existing)
+	if err != nil || !apply || plan.Invalid > 0 {
+		return plan, err
+	}
+	
+	for _, row := range plan.Rows {
+		switch row.Action {
+		case models.ItemImportCreate:
+			m.items[row.ItemID] = row.Item
+		case models.ItemImportUpdate:
+			m.items[row.ItemID].Update(row.Updates)
+		}
+	}
+	plan.Applied = true
+	return plan, nil
+}
+
+func (m *MockItemService) CreateAssembly(ctx context.Context, draft *models.Item) (*models.Item, error) {
+	m.lastCall = "CreateAssembly"
+	if m.err != nil {
+		return nil, m.err
+	}
+	
+	components, err := m.assemblyComponents(draft.Components)
+	if err != nil {
+		return nil, err
+	}
+	item, err := models.NewAssembly(draft.Name, draft.Unit, draft.Category, components, draft.PriceOverride, draft.ExpandOnInvoice)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+	
+	m.items[item.ID] = item
+	return item, nil
+}
+
+func (m *MockItemService) UpdateAssembly(ctx context.Context, id string, draft *models.Item) (*models.Item, error) {
+	m.lastCall = "UpdateAssembly"
+	if m.err != nil {
+		return nil, m.err
+	}
+	
+	item, exists := m.items[id]
+	if !exists || !item.IsAssembly() {
+		return nil, repository.ErrNotFound
+	}
+	components, err := m.assemblyComponents(draft.Components)
+	if err != nil {
+		return nil, err
+	}
+	itemCopy := *item
+	if err := itemCopy.SetComponents(components, draft.PriceOverride); err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+	itemCopy.Name, itemCopy.Unit, itemCopy.Category = draft.Name, draft.Unit, draft.Category
+	itemCopy.ExpandOnInvoice = draft.ExpandOnInvoice
+	
+	m.items[id] = &itemCopy
+	return &itemCopy, nil
+}
+
+func (m *MockItemService) assemblyComponents(drafts []models.AssemblyComponent) ([]models.AssemblyComponent, error) {
+	var components []models.AssemblyComponent
+	for _, draft := range drafts {
+		item, exists := m.items[draft.ItemID]
+		if !exists {
+			return nil, fmt.Errorf("%w: component item %q not found", models.ErrValidation, draft.ItemID)
+		}
+		component, err := models.NewAssemblyComponent(item, draft.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", models.ErrValidation, err)
+		}
+		components = append(components, component)
+	}
+	return components, nil
+}
+
 func TestItemHandler_Create(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -191,7 +347,7 @@ func TestItemHandler_GetByID(t *testing.T) {
 		ID:        "test-id",
 		Name:      "Test Item",
 		Unit:      "each",
-		UnitPrice: 15.50,
+		UnitPrice: models.Cents(1550),
 		Category:  "Electrical",
 	}
 
@@ -278,8 +434,8 @@ func TestItemHandler_List(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedLen:  2,
 			setupMock: func(m *MockItemService) {
-				item1, _ := models.NewItem("Item 1", "each", 10.00, "Test")
-				item2, _ := models.NewItem("Item 2", "box", 20.00, "Test")
+				item1, _ := models.NewItem("Item 1", "each", models.Cents(1000), "Test")
+				item2, _ := models.NewItem("Item 2", "box", models.Cents(2000), "Test")
 				m.items[item1.ID] = item1
 				m.items[item2.ID] = item2
 			},
@@ -340,7 +496,7 @@ func TestItemHandler_Update(t *testing.T) {
 			},
 			expectedCode: http.StatusOK,
 			setupMock: func(m *MockItemService) {
-				item, _ := models.NewItem("Original Item", "each", 15.00, "Test")
+				item, _ := models.NewItem("Original Item", "each", models.Cents(1500), "Test")
 				item.ID = "test-id"
 				m.items["test-id"] = item
 			},
@@ -361,7 +517,7 @@ func TestItemHandler_Update(t *testing.T) {
 			},
 			expectedCode: http.StatusBadRequest,
 			setupMock: func(m *MockItemService) {
-				item, _ := models.NewItem("Item", "each", 15.00, "Test")
+				item, _ := models.NewItem("Item", "each", models.Cents(1500), "Test")
 				item.ID = "test-id"
 				m.items["test-id"] = item
 			},
@@ -411,7 +567,7 @@ func TestItemHandler_Delete(t *testing.T) {
 			id:           "test-id",
 			expectedCode: http.StatusNoContent,
 			setupMock: func(m *MockItemService) {
-				item, _ := models.NewItem("Item", "each", 15.00, "Test")
+				item, _ := models.NewItem("Item", "each", models.Cents(1500), "Test")
 				item.ID = "test-id"
 				m.items["test-id"] = item
 			},
@@ -454,4 +610,301 @@ func TestItemHandler_Delete(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestItemHandler_AddCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		body         interface{}
+		expectedCode int
+	}{
+		{
+			name: "dated supplier cost",
+			id:   "test-id",
+			body: map[string]interface{}{
+				"supplier":      "City Electric",
+				"cost":          7.25,
+				"effectiveFrom": "2026-03-01",
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "cost from today",
+			id:           "test-id",
+			body:         map[string]interface{}{"cost": 7.25},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "negative cost",
+			id:           "test-id",
+			body:         map[string]interface{}{"cost": -1},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid date",
+			id:           "test-id",
+			body:         map[string]interface{}{"cost": 7.25, "effectiveFrom": "March 1"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "non-existing item",
+			id:           "non-existing",
+			body:         map[string]interface{}{"cost": 7.25},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewMockItemService()
+			item, _ := models.NewItem("Outlet", "each", models.Cents(1500), "Devices")
+			item.ID = "test-id"
+			service.items["test-id"] = item
+			
+			handler := NewItemHandler(service)
+			
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/items/"+tt.id+"/costs", bytes.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+			
+			handler.AddCost(w, req)
+			
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d. Response: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusCreated {
+				var cost models.ItemCost
+				if err := json.NewDecoder(w.Body).Decode(&cost); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if cost.Cost != models.Cents(725) || cost.ItemID != "test-id" {
+					t.Errorf("expected a 7.25 cost for the item, got %+v", cost)
+				}
+			}
+		})
+	}
+}
+
+func TestItemHandler_PriceHistory(t *testing.T) {
+	service := NewMockItemService()
+	item, _ := models.NewItem("Outlet", "each", models.Cents(1500), "Devices")
+	item.ID = "test-id"
+	service.items["test-id"] = item
+	
+	handler := NewItemHandler(service)
+	
+	// Two price changes and a rename
+	for _, update := range []map[string]interface{}{
+		{"unit_price": "16.00"},
+		{"name": "Duplex Outlet"},
+		{"unit_price": "17.25"},
+	} {
+		body, _ := json.Marshal(update)
+		req := httptest.NewRequest(http.MethodPut, "/api/items/test-id", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": "test-id"})
+		w := httptest.NewRecorder()
+		handler.Update(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("update failed with %d: %s", w.Code, w.Body.String())
+		}
+	}
+	
+	req := httptest.NewRequest(http.MethodGet, "/api/items/test-id/price-history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "test-id"})
+	w := httptest.NewRecorder()
+	handler.PriceHistory(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	var changes []models.ItemPriceChange
+	if err := json.NewDecoder(w.Body).Decode(&changes); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(changes) != 2 || changes[0].OldPrice != models.Cents(1600) || changes[0].NewPrice != models.Cents(1725) {
+		t.Errorf("expected the two price changes newest first, got %+v", changes)
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/api/items/non-existing/price-history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "non-existing"})
+	w = httptest.NewRecorder()
+	handler.PriceHistory(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestItemHandler_Export(t *testing.T) {
+	service := NewMockItemService()
+	item, _ := models.NewItem("Outlet", "each", models.Cents(1500), "Devices")
+	item.ID = "outlet"
+	service.items["outlet"] = item
+	
+	handler := NewItemHandler(service)
+	
+	req := httptest.NewRequest(http.MethodGet, "/api/items/export?format=csv", nil)
+	w := httptest.NewRecorder()
+	handler.Export(w, req)
+	
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("expected a CSV, got %s", w.Header().Get("Content-Type"))
+	}
+	want := "id,name,nickname,unit,unit_price,unit_cost,category,labor_hours\noutlet,Outlet,,each,15.00,0.00,Devices,0\n"
+	if w.Body.String() != want {
+		t.Errorf("expected %q, got %q", want, w.Body.String())
+	}
+	
+	req = httptest.NewRequest(http.MethodGet, "/api/items/export?format=xlsx", nil)
+	w = httptest.NewRecorder()
+	handler.Export(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d for xlsx, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestItemHandler_Import(t *testing.T) {
+	valid := "name,unit,unit_price\nOutlet,each,16.00\nSwitch,each,8.50\n"
+	invalid := "name,unit,unit_price\nOutlet,each,16.00\nSwitch,,8.50\n"
+	
+	multipartBody := func(file string) (*bytes.Buffer, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "items.csv")
+		part.Write([]byte(file))
+		writer.Close()
+		return &body, writer.FormDataContentType()
+	}
+	
+	tests := []struct {
+		name         string
+		query        string
+		file         string
+		multipart    bool
+		expectedCode int
+		wantItems    int
+		wantPrice    models.Money
+	}{
+		{name: "dry run", file: valid, expectedCode: http.StatusOK, wantItems: 1, wantPrice: models.Cents(1500)},
+		{name: "apply", query: "?apply=true", file: valid, expectedCode: http.StatusOK, wantItems: 2, wantPrice: models.Cents(1600)},
+		{name: "apply upload", query: "?apply=true", file: valid, multipart: true, expectedCode: http.StatusOK, wantItems: 2, wantPrice: models.Cents(1600)},
+		{name: "apply with invalid rows", query: "?apply=true", file: invalid, expectedCode: http.StatusUnprocessableEntity, wantItems: 1, wantPrice: models.Cents(1500)},
+		{name: "bad header", file: "title\nOutlet\n", expectedCode: http.StatusBadRequest, wantItems: 1, wantPrice: models.Cents(1500)},
+		{name: "bad apply flag", query: "?apply=maybe", file: valid, expectedCode: http.StatusBadRequest, wantItems: 1, wantPrice: models.Cents(1500)},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewMockItemService()
+			item, _ := models.NewItem("Outlet", "each", models.Cents(1500), "Devices")
+			item.ID = "outlet"
+			service.items["outlet"] = item
+			
+			handler := NewItemHandler(service)
+			
+			var req *http.Request
+			if tt.multipart {
+				body, contentType := multipartBody(tt.file)
+				req = httptest.NewRequest(http.MethodPost, "/api/items/import"+tt.query, body)
+				req.Header.Set("Content-Type", contentType)
+			} else {
+				req = httptest.NewRequest(http.MethodPost, "/api/items/import"+tt.query, bytes.NewBufferString(tt.file))
+				req.Header.Set("Content-Type", "text/csv")
+			}
+			w := httptest.NewRecorder()
+			
+			handler.Import(w, req)
+			
+			if w.Code != tt.expectedCode {
+				t.Fatalf("expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if len(service.items) != tt.wantItems || service.items["outlet"].UnitPrice != tt.wantPrice {
+				t.Errorf("expected %d items with the outlet at %s, got %d at %s",
+					tt.wantItems, tt.wantPrice, len(service.items), service.items["outlet"].UnitPrice)
+			}
+			if w.Code == http.StatusOK || w.Code == http.StatusUnprocessableEntity {
+				var plan models.ItemImport
+				if err := json.NewDecoder(w.Body).Decode(&plan); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if len(plan.Rows) != 2 {
+					t.Errorf("expected a result for each row, got %+v", plan.Rows)
+				}
+			}
+		})
+	}
+}
+
+func TestItemHandler_Assemblies(t *testing.T) {
+	service := NewMockItemService()
+	for _, item := range []*models.Item{
+		{ID: "box", Name: "Box", Unit: "each", UnitPrice: models.Cents(300), UnitCost: models.Cents(100)},
+		{ID: "receptacle", Name: "Receptacle", Unit: "each", UnitPrice: models.Cents(400), UnitCost: models.Cents(150)},
+		{ID: "wire", Name: "12/2 Wire", Unit: "ft", UnitPrice: models.Cents(90), UnitCost: models.Cents(40)},
+	} {
+		service.items[item.ID] = item
+	}
+	handler := NewItemHandler(service)
+	
+	send := func(handle http.HandlerFunc, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/assemblies", bytes.NewBufferString(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		w := httptest.NewRecorder()
+		handle(w, req)
+		return w
+	}
+	
+	w := send(handler.CreateAssembly, "", `{"name": "Outlet Install", "unit": "each", "category": "Devices",
+		"components": [{"itemId": "box", "quantity": 1}, {"itemId": "receptacle", "quantity": 1}, {"itemId": "wire", "quantity": 15}]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var assembly models.Item
+	json.Unmarshal(w.Body.Bytes(), &assembly)
+	if assembly.UnitPrice != models.Cents(2050) || assembly.UnitCost != models.Cents(850) || len(assembly.Components) != 3 {
+		t.Fatalf("expected an assembly priced at 20.50 costing 8.50, got %+v", assembly)
+	}
+	
+	w = send(handler.UpdateAssembly, assembly.ID, `{"name": "Outlet Install", "unit": "each", "priceOverride": "25.00",
+		"expandOnInvoice": true, "components": [{"itemId": "box", "quantity": 1}, {"itemId": "receptacle", "quantity": 1}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got := service.items[assembly.ID]; got.UnitPrice != models.Cents(2500) || got.PartsPrice() != models.Cents(700) || !got.ExpandOnInvoice {
+		t.Errorf("expected the override price with two parts, got %+v", got)
+	}
+	
+	w = send(handler.ListAssemblies, "", "")
+	var assemblies []models.Item
+	json.Unmarshal(w.Body.Bytes(), &assemblies)
+	if w.Code != http.StatusOK || len(assemblies) != 1 || assemblies[0].ID != assembly.ID {
+		t.Errorf("expected only the assembly to be listed, got %d: %s", w.Code, w.Body.String())
+	}
+	
+	tests := []struct {
+		name         string
+		handle       http.HandlerFunc
+		id           string
+		body         string
+		expectedCode int
+	}{
+		{"no components", handler.CreateAssembly, "", `{"name": "Empty", "unit": "each"}`, http.StatusBadRequest},
+		{"unknown component", handler.CreateAssembly, "", `{"name": "Kit", "unit": "each", "components": [{"itemId": "missing", "quantity": 1}]}`, http.StatusBadRequest},
+		{"nested assembly", handler.CreateAssembly, "", `{"name": "Kit", "unit": "each", "components": [{"itemId": "` + assembly.ID + `", "quantity": 1}]}`, http.StatusBadRequest},
+		{"zero quantity", handler.CreateAssembly, "", `{"name": "Kit", "unit": "each", "components": [{"itemId": "box", "quantity": 0}]}`, http.StatusBadRequest},
+		{"plain item is not an assembly", handler.GetAssembly, "box", "", http.StatusNotFound},
+		{"update plain item", handler.UpdateAssembly, "box", `{"name": "Box", "unit": "each", "components": [{"itemId": "wire", "quantity": 1}]}`, http.StatusNotFound},
+		{"set assembly price", handler.Update, assembly.ID, `{"unit_price": "30.00"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := send(tt.handle, tt.id, tt.body); w.Code != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
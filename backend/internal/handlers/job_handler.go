@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -12,6 +15,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
 	"github.com/masterbrent/electrical-bidding-app/internal/repository"
 	"github.com/masterbrent/electrical-bidding-app/internal/services"
@@ -19,47 +23,91 @@ import (
 
 // JobHandler handles HTTP requests for jobs
 type JobHandler struct {
-	jobRepo      repository.JobRepository
-	customerRepo repository.CustomerRepository
-	templateRepo repository.JobTemplateRepository
-	itemRepo     repository.ItemRepository
-	r2Service    *services.R2Service
+	uow            repository.UnitOfWork
+	jobRepo        repository.JobRepository
+	customerRepo   repository.CustomerRepository
+	templateRepo   repository.JobTemplateRepository
+	itemRepo       repository.ItemRepository
+	taxRepo        repository.TaxRepository
+	adjustmentRepo repository.AdjustmentRepository
+	laborRepo      repository.LaborRepository
+	r2Service      *services.R2Service
 }
 
 // NewJobHandler creates a new job handler
 func NewJobHandler(
+	uow repository.UnitOfWork,
 	jobRepo repository.JobRepository,
 	customerRepo repository.CustomerRepository,
 	templateRepo repository.JobTemplateRepository,
 	itemRepo repository.ItemRepository,
+	taxRepo repository.TaxRepository,
+	adjustmentRepo repository.AdjustmentRepository,
+	laborRepo repository.LaborRepository,
 ) *JobHandler {
 	return &JobHandler{
-		jobRepo:      jobRepo,
-		customerRepo: customerRepo,
-		templateRepo: templateRepo,
-		itemRepo:     itemRepo,
+		uow:            uow,
+		jobRepo:        jobRepo,
+		customerRepo:   customerRepo,
+		templateRepo:   templateRepo,
+		itemRepo:       itemRepo,
+		taxRepo:        taxRepo,
+		adjustmentRepo: adjustmentRepo,
+		laborRepo:      laborRepo,
 	}
 }
 
 // RegisterRoutes registers all job routes
 func (h *JobHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/jobs", h.List).Methods("GET", "OPTIONS")
-	router.HandleFunc("/jobs", h.Create).Methods("POST", "OPTIONS")
-	router.HandleFunc("/jobs/{id}", h.Get).Methods("GET", "OPTIONS")
-	router.HandleFunc("/jobs/{id}", h.Update).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/jobs/{id}", h.Delete).Methods("DELETE", "OPTIONS")
-	
-	// Job items
-	router.HandleFunc("/jobs/{id}/items", h.AddItem).Methods("POST", "OPTIONS")
-	router.HandleFunc("/jobs/{id}/items/{itemId}", h.UpdateItem).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/jobs/{id}/items/{itemId}", h.RemoveItem).Methods("DELETE", "OPTIONS")
-	
-	// Job photos
-	router.HandleFunc("/jobs/{id}/photos", h.AddPhoto).Methods("POST", "OPTIONS")
-	router.HandleFunc("/jobs/{id}/photos/{photoId}", h.RemovePhoto).Methods("DELETE", "OPTIONS")
-	
-	// Wave integration
-	router.HandleFunc("/jobs/{id}/send-to-wave", h.SendToWave).Methods("POST", "OPTIONS")
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the job endpoints and who may call them. Techs can read jobs,
+// count installed quantities, add photos and advance phases; everything else is
+// admin-only.
+func (h *JobHandler) routes() []route {
+	return []route{
+		{"GET", "/jobs", h.List, anyRole},
+		{"POST", "/jobs", h.Create, adminOnly},
+		{"GET", "/jobs/{id}", h.Get, anyRole},
+		{"PUT", "/jobs/{id}", h.Update, adminOnly},
+		{"DELETE", "/jobs/{id}", h.Delete, adminOnly},
+		{"PUT", "/jobs/{id}/phase", h.UpdatePhase, anyRole},
+		{"GET", "/jobs/{id}/phases", h.ListPhases, anyRole},
+		{"POST", "/jobs/{id}/phases/{phaseId}/complete", h.CompletePhase, anyRole},
+		{"POST", "/jobs/{id}/phases/{phaseId}/reopen", h.ReopenPhase, adminOnly},
+		{"PUT", "/jobs/{id}/status", h.UpdateStatus, adminOnly},
+		{"GET", "/jobs/{id}/status-history", h.StatusHistory, anyRole},
+
+		// Job items
+		{"POST", "/jobs/{id}/items", h.AddItem, adminOnly},
+		{"PUT", "/jobs/{id}/items/{itemId}", h.UpdateItem, anyRole},
+		{"DELETE", "/jobs/{id}/items/{itemId}", h.RemoveItem, adminOnly},
+		{"POST", "/jobs/reprice", h.Reprice, adminOnly},
+
+		// Job markups and discounts
+		{"GET", "/jobs/{id}/adjustments", h.ListAdjustments, anyRole},
+		{"POST", "/jobs/{id}/adjustments", h.AddAdjustment, adminOnly},
+		{"DELETE", "/jobs/{id}/adjustments/{adjustmentId}", h.RemoveAdjustment, adminOnly},
+
+		{"GET", "/jobs/{id}/variance", h.Variance, adminOnly},
+		{"GET", "/jobs/{id}/tax", h.Tax, anyRole},
+		{"GET", "/jobs/{id}/profitability", h.Profitability, adminOnly},
+		{"GET", "/reports/template-profitability", h.TemplateProfitability, adminOnly},
+
+		// Job revisions
+		{"GET", "/jobs/{id}/revisions", h.ListRevisions, anyRole},
+		{"POST", "/jobs/{id}/revisions", h.CreateRevision, adminOnly},
+		{"GET", "/jobs/{id}/revisions/diff", h.DiffRevisions, anyRole},
+		{"GET", "/jobs/{id}/revisions/{number}", h.GetRevision, anyRole},
+
+		// Job photos
+		{"POST", "/jobs/{id}/photos", h.AddPhoto, anyRole},
+		{"DELETE", "/jobs/{id}/photos/{photoId}", h.RemovePhoto, adminOnly},
+
+		// Wave integration
+		{"POST", "/jobs/{id}/send-to-wave", h.SendToWave, adminOnly},
+	}
 }
 
 // List returns all jobs
@@ -202,71 +250,768 @@ func (h *JobHandler) Create(w http.ResponseWriter, r *http.Request) {
 	
 	job.Address = req.Address
 	job.Notes = req.Notes
+	job.TemplateVersion = template.Version
 	
-	// Set initial phase to the first phase if template has phases
-	if len(template.Phases) > 0 {
-		// Find the phase with the lowest order number
-		var firstPhase *models.TemplatePhase
-		for i := range template.Phases {
-			if firstPhase == nil || template.Phases[i].Order < firstPhase.Order {
-				firstPhase = &template.Phases[i]
+	// Track the template's phases on the job, starting with the first
+	job.StartPhases(template.Phases)
+	
+	// Build the job's items from the template
+	for _, templateItem := range template.Items {
+		item, err := h.itemRepo.GetByID(ctx, templateItem.ItemID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				http.Error(w, "Template item "+templateItem.ItemID+" not found", http.StatusBadRequest)
+				return
 			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		
+		job.Items = append(job.Items, models.JobItem{
+			ID:               uuid.New().String(),
+			JobID:            job.ID,
+			ItemID:           item.ID,
+			Name:             item.Name,
+			Category:         item.Category,
+			Quantity:         0, // Always start with 0 quantity - techs will increment as they install
+			BudgetedQuantity: templateItem.DefaultQuantity,
+			Price:            item.UnitPrice,
+			Cost:             item.UnitCost,
+			Total:            0, // Total is 0 since quantity is 0
+		})
+	}
+	
+	// Price the job with the contractor's and the customer's default adjustments
+	job.Adjustments, err = defaultAdjustments(ctx, h.adjustmentRepo, job)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	job.CalculateTotal()
+	
+	user, _ := middleware.UserFromContext(ctx)
+	created := models.NewJobStatusChange(job.ID, nil, job.Status, user, "Job created")
+	
+	// Save the job, its phases, items and first status entry together
+	err = h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		return createJob(ctx, repos.Jobs, job, created)
+	})
+	if err != nil {
+		log.Printf("Error creating job: %v", err)
+		http.Error(w, "Failed to create job", http.StatusInternalServerError)
+		return
+	}
+	
+	w.WriteHeader(http.StatusCreated)
+	respondJSON(w, job)
+}
+
+// defaultAdjustments copies the default markups and discounts for the job's
+// customer onto the job
+func defaultAdjustments(ctx context.Context, adjustmentRepo repository.AdjustmentRepository, job *models.Job) ([]models.PriceAdjustment, error) {
+	defaults, err := adjustmentRepo.ListForCustomer(ctx, job.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default adjustments: %w", err)
+	}
+	
+	adjustments := make([]models.PriceAdjustment, 0, len(defaults))
+	for i := range defaults {
+		adjustments = append(adjustments, defaults[i].ForJob(job.ID))
+	}
+	return adjustments, nil
+}
+
+// createJob stores a new job with its phases, items, adjustments and the first
+// entry of its status timeline. Callers run it in a unit of work so a failure
+// part way leaves nothing behind.
+func createJob(ctx context.Context, jobs repository.JobRepository, job *models.Job, created *models.JobStatusChange) error {
+	if err := jobs.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	
+	if err := jobs.SavePhases(ctx, job.ID, job.Phases); err != nil {
+		return fmt.Errorf("failed to save job phases: %w", err)
+	}
+	
+	for i := range job.Items {
+		if err := jobs.AddJobItem(ctx, job.ID, &job.Items[i]); err != nil {
+			return fmt.Errorf("failed to add job item %s: %w", job.Items[i].ItemID, err)
 		}
-		if firstPhase != nil {
-			job.CurrentPhaseID = &firstPhase.ID
+	}
+	
+	for i := range job.Adjustments {
+		if err := jobs.AddAdjustment(ctx, job.ID, &job.Adjustments[i]); err != nil {
+			return fmt.Errorf("failed to add job adjustment %s: %w", job.Adjustments[i].Name, err)
 		}
 	}
 	
-	// Create job in database
-	if err := h.jobRepo.Create(ctx, job); err != nil {
+	if err := jobs.AddStatusChange(ctx, created); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	
+	return nil
+}
+
+// Update updates an existing job
+func (h *JobHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	// Get existing job
+	job, err := h.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Add items from template
-	for _, templateItem := range template.Items {
-		// Get item details
-		item, err := h.itemRepo.GetByID(ctx, templateItem.ItemID)
+	var req struct {
+		Address        string     `json:"address"`
+		Status         string     `json:"status"`
+		CurrentPhaseID *string    `json:"currentPhaseId"`
+		ScheduledDate  *time.Time `json:"scheduledDate"`
+		PermitRequired *bool      `json:"permitRequired"`
+		PermitNumber   string     `json:"permitNumber"`
+		Notes          string     `json:"notes"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	// Update fields
+	if req.Address != "" {
+		job.Address = req.Address
+	}
+	
+	if req.CurrentPhaseID != nil {
+		if err := job.UpdatePhase(*req.CurrentPhaseID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	
+	if req.ScheduledDate != nil {
+		job.ScheduledDate = *req.ScheduledDate
+	}
+	
+	if req.PermitRequired != nil {
+		job.PermitRequired = *req.PermitRequired
+	}
+	
+	if req.PermitNumber != "" {
+		job.PermitNumber = req.PermitNumber
+	}
+	
+	if req.Notes != "" {
+		job.Notes = req.Notes
+	}
+	
+	// Apply the status last so guards see the other changes, such as a permit number
+	var statusChange *models.JobStatusChange
+	if req.Status != "" && models.JobStatus(req.Status) != job.Status {
+		statusChange, err = h.changeStatus(ctx, job, models.JobStatus(req.Status), "")
 		if err != nil {
-			continue // Skip if item not found
+			respondStatusError(w, err)
+			return
 		}
-		
-		jobItem := &models.JobItem{
-			ID:       uuid.New().String(),
-			JobID:    job.ID,
-			ItemID:   item.ID,
-			Name:     item.Name,
-			Quantity: 0, // Always start with 0 quantity - techs will increment as they install
-			Price:    item.UnitPrice,
-			Total:    0, // Total is 0 since quantity is 0
+	}
+	
+	job.UpdatedAt = time.Now()
+	
+	// Save the job, its phases and any status entry together
+	err = h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Jobs.Update(ctx, job); err != nil {
+			return fmt.Errorf("failed to update job: %w", err)
 		}
-		
-		if err := h.jobRepo.AddJobItem(ctx, job.ID, jobItem); err != nil {
-			// Log error but continue
+		if err := repos.Jobs.SavePhases(ctx, job.ID, job.Phases); err != nil {
+			return fmt.Errorf("failed to save job phases: %w", err)
+		}
+		if statusChange != nil {
+			if err := repos.Jobs.AddStatusChange(ctx, statusChange); err != nil {
+				return fmt.Errorf("failed to record status change: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error updating job: %v", err)
+		http.Error(w, "Failed to update job", http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, job)
+}
+
+// UpdateStatus moves a job to another status with an optional note
+func (h *JobHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	var req struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	statusChange, err := h.changeStatus(ctx, job, models.JobStatus(req.Status), req.Note)
+	if err != nil {
+		respondStatusError(w, err)
+		return
+	}
+	
+	// Save the job and its status entry together
+	err = h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Jobs.Update(ctx, job); err != nil {
+			return fmt.Errorf("failed to update job: %w", err)
+		}
+		if err := repos.Jobs.AddStatusChange(ctx, statusChange); err != nil {
+			return fmt.Errorf("failed to record status change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error updating job status: %v", err)
+		http.Error(w, "Failed to update job status", http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, job)
+}
+
+// StatusHistory returns the job's status timeline, oldest first
+func (h *JobHandler) StatusHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := mux.Vars(r)["id"]
+	
+	if _, err := h.jobRepo.GetByID(ctx, jobID); err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	history, err := h.jobRepo.GetStatusHistory(ctx, jobID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, history)
+}
+
+// changeStatus moves the job to another status, checking the allowed
+// transitions and guards, and returns the history entry to record once the job
+// is saved. Rule violations are returned as validation errors.
+func (h *JobHandler) changeStatus(ctx context.Context, job *models.Job, to models.JobStatus, note string) (*models.JobStatusChange, error) {
+	from := job.Status
+	if err := job.UpdateStatus(to); err != nil {
+		return nil, &validationError{msg: err.Error()}
+	}
+	
+	user, _ := middleware.UserFromContext(ctx)
+	return models.NewJobStatusChange(job.ID, &from, to, user, note), nil
+}
+
+// respondStatusError answers a failed status change: 400 for rule violations,
+// 500 otherwise
+func respondStatusError(w http.ResponseWriter, err error) {
+	var validationErr *validationError
+	if errors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// UpdatePhase moves a job to another phase
+func (h *JobHandler) UpdatePhase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	job, err := h.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req struct {
+		PhaseID string `json:"phaseId"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := job.UpdatePhase(req.PhaseID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.savePhases(w, r, job)
+}
+
+// jobPhaseResponse is a phase record with its duration so far
+type jobPhaseResponse struct {
+	models.JobPhase
+	IsCurrent     bool    `json:"isCurrent"`
+	DurationHours float64 `json:"durationHours"`
+}
+
+// ListPhases returns the job's phases in order with how long each has taken
+func (h *JobHandler) ListPhases(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	phases := make([]jobPhaseResponse, 0, len(job.Phases))
+	for _, phase := range job.Phases {
+		phases = append(phases, jobPhaseResponse{
+			JobPhase:      phase,
+			IsCurrent:     job.CurrentPhaseID != nil && *job.CurrentPhaseID == phase.ID,
+			DurationHours: phase.Duration(now).Hours(),
+		})
+	}
+
+	respondJSON(w, phases)
+}
+
+// CompletePhase marks one of the job's phases done and advances the job to
+// the next open phase
+func (h *JobHandler) CompletePhase(w http.ResponseWriter, r *http.Request) {
+	h.changePhase(w, r, (*models.Job).CompletePhase)
+}
+
+// ReopenPhase marks a completed phase as open again
+func (h *JobHandler) ReopenPhase(w http.ResponseWriter, r *http.Request) {
+	h.changePhase(w, r, (*models.Job).ReopenPhase)
+}
+
+// changePhase applies a phase change to the phase named in the URL and saves the job
+func (h *JobHandler) changePhase(w http.ResponseWriter, r *http.Request, apply func(*models.Job, string) error) {
+	vars := mux.Vars(r)
+
+	job, err := h.jobRepo.GetByID(r.Context(), vars["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := apply(job, vars["phaseId"]); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.savePhases(w, r, job)
+}
+
+// savePhases persists the job and its phase records together and writes the
+// job as the response
+func (h *JobHandler) savePhases(w http.ResponseWriter, r *http.Request, job *models.Job) {
+	ctx := r.Context()
+
+	err := h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Jobs.Update(ctx, job); err != nil {
+			return fmt.Errorf("failed to update job: %w", err)
+		}
+		if err := repos.Jobs.SavePhases(ctx, job.ID, job.Phases); err != nil {
+			return fmt.Errorf("failed to save job phases: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error saving job phases: %v", err)
+		http.Error(w, "Failed to save job phases", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, job)
+}
+
+// Delete deletes a job
+func (h *JobHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	if err := h.jobRepo.Delete(ctx, id); err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddItem adds an item to a job
+func (h *JobHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	jobID := vars["id"]
+	
+	// Verify job exists
+	if _, err := h.jobRepo.GetByID(ctx, jobID); err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	var req struct {
+		ItemID           string  `json:"itemId"`
+		Quantity         float64 `json:"quantity"`
+		BudgetedQuantity float64 `json:"budgetedQuantity"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	// Get item details
+	item, err := h.itemRepo.GetByID(ctx, req.ItemID)
+	if err != nil {
+		if err.Error() == "item not found" {
+			http.Error(w, "Item not found", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	// Create job item
+	jobItem := &models.JobItem{
+		ID:               uuid.New().String(),
+		JobID:            jobID,
+		ItemID:           item.ID,
+		Name:             item.Name,
+		Category:         item.Category,
+		Quantity:         req.Quantity,
+		BudgetedQuantity: req.BudgetedQuantity,
+		Price:            item.UnitPrice,
+		Cost:             item.UnitCost,
+	}
+	
+	// The repository sets the line total and refreshes the job total
+	if err := h.jobRepo.AddJobItem(ctx, jobID, jobItem); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.WriteHeader(http.StatusCreated)
+	respondJSON(w, jobItem)
+}
+
+// UpdateItem updates a job item
+func (h *JobHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	jobID := vars["id"]
+	itemID := vars["itemId"]
+	
+	// Get job to verify it exists
+	job, err := h.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	var req struct {
+		Quantity float64 `json:"quantity"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	// Find and update the item
+	var updatedItem *models.JobItem
+	for i := range job.Items {
+		if job.Items[i].ID == itemID {
+			job.Items[i].Quantity = req.Quantity
+			updatedItem = &job.Items[i]
+			break
+		}
+	}
+	
+	if updatedItem == nil {
+		http.Error(w, "Job item not found", http.StatusNotFound)
+		return
+	}
+	
+	// Only the quantity changes; the repository totals the line at its stored
+	// price and refreshes the job total
+	if err := h.jobRepo.UpdateJobItemQuantity(ctx, jobID, updatedItem); err != nil {
+		if err.Error() == "job item not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, updatedItem)
+}
+
+// RemoveItem removes an item from a job
+func (h *JobHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	jobID := vars["id"]
+	itemID := vars["itemId"]
+	
+	// Get job to verify it exists
+	if _, err := h.jobRepo.GetByID(ctx, jobID); err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	// The repository refreshes the job total
+	if err := h.jobRepo.RemoveJobItem(ctx, jobID, itemID); err != nil {
+		if err.Error() == "job item not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reprice moves the lines of jobs that have not been invoiced to current item
+// prices. It previews the change to each job unless the request sets
+// "apply": true. Without "jobIds" it covers every job that has not been
+// invoiced or cancelled; jobs whose prices are already current are left out.
+func (h *JobHandler) Reprice(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	
+	var req struct {
+		JobIDs []string `json:"jobIds"`
+		Apply  bool     `json:"apply"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	jobs, status, err := h.repriceableJobs(ctx, req.JobIDs)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	
+	items, err := h.itemRepo.List(ctx, map[string]interface{}{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	prices := make(map[string]models.Money, len(items))
+	for _, item := range items {
+		prices[item.ID] = item.UnitPrice
+	}
+	
+	repriced := make([]*models.Job, 0)
+	repricings := make([]models.JobRepricing, 0)
+	var totalDelta models.Money
+	for _, job := range jobs {
+		repricing := job.Reprice(prices)
+		if len(repricing.Lines) == 0 {
 			continue
 		}
-		
-		job.Items = append(job.Items, *jobItem)
+		repriced = append(repriced, job)
+		repricings = append(repricings, repricing)
+		totalDelta += repricing.TotalDelta
+	}
+	
+	if req.Apply {
+		// Reprice every job or none; each saved line refreshes its job's total
+		err := h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+			for i, job := range repriced {
+				for _, line := range repricings[i].Lines {
+					for j := range job.Items {
+						if job.Items[j].ID != line.JobItemID {
+							continue
+						}
+						if err := repos.Jobs.UpdateJobItem(ctx, &job.Items[j]); err != nil {
+							return fmt.Errorf("failed to reprice job %s: %w", job.ID, err)
+						}
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Error repricing jobs: %v", err)
+			http.Error(w, "Failed to reprice jobs", http.StatusInternalServerError)
+			return
+		}
+	}
+	
+	respondJSON(w, map[string]interface{}{
+		"applied":    req.Apply,
+		"jobs":       repricings,
+		"totalDelta": totalDelta,
+	})
+}
+
+// repriceableJobs loads the chosen jobs, refusing invoiced and cancelled ones,
+// or every job that has not been invoiced or cancelled when none are chosen
+func (h *JobHandler) repriceableJobs(ctx context.Context, jobIDs []string) ([]*models.Job, int, error) {
+	if len(jobIDs) == 0 {
+		jobs := make([]*models.Job, 0)
+		const pageSize = 100
+		for offset := 0; ; offset += pageSize {
+			page, err := h.jobRepo.List(ctx, pageSize, offset)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+			for _, job := range page {
+				if job.WaveInvoiceID == "" && job.Status != models.JobStatusCancelled {
+					jobs = append(jobs, job)
+				}
+			}
+			if len(page) < pageSize {
+				return jobs, 0, nil
+			}
+		}
+	}
+	
+	jobs := make([]*models.Job, 0, len(jobIDs))
+	for _, id := range jobIDs {
+		job, err := h.jobRepo.GetByID(ctx, id)
+		if err != nil {
+			if err.Error() == "job not found" {
+				return nil, http.StatusBadRequest, fmt.Errorf("job %s not found", id)
+			}
+			return nil, http.StatusInternalServerError, err
+		}
+		if job.WaveInvoiceID != "" {
+			return nil, http.StatusConflict, fmt.Errorf("job %s has already been invoiced", id)
+		}
+		if job.Status == models.JobStatusCancelled {
+			return nil, http.StatusConflict, fmt.Errorf("job %s is cancelled", id)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, 0, nil
+}
+
+// ListAdjustments returns a job's markups and discounts and the lines they
+// add to the job, in the order they are applied
+func (h *JobHandler) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, map[string]interface{}{
+		"adjustments": job.Adjustments,
+		"lines":       job.AdjustmentLines,
+		"total":       models.AdjustmentsTotal(job.AdjustmentLines),
+	})
+}
+
+// AddAdjustment adds an ad-hoc markup or discount to a job
+func (h *JobHandler) AddAdjustment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := mux.Vars(r)["id"]
+	
+	if _, err := h.jobRepo.GetByID(ctx, jobID); err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	var req adjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
+	adjustment, err := req.adjustment()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	
+	// The repository refreshes the job total
+	if err := h.jobRepo.AddAdjustment(ctx, jobID, adjustment); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 	
-	// Calculate total
-	job.CalculateTotal()
-	h.jobRepo.Update(ctx, job)
-	
 	w.WriteHeader(http.StatusCreated)
-	respondJSON(w, job)
+	respondJSON(w, adjustment)
 }
 
-// Update updates an existing job
-func (h *JobHandler) Update(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// RemoveAdjustment removes a markup or discount from a job
+func (h *JobHandler) RemoveAdjustment(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	id := vars["id"]
 	
-	// Get existing job
-	job, err := h.jobRepo.GetByID(ctx, id)
-	if err != nil {
-		if err.Error() == "job not found" {
+	// The repository refreshes the job total
+	if err := h.jobRepo.RemoveAdjustment(r.Context(), vars["id"], vars["adjustmentId"]); err != nil {
+		if err.Error() == "job adjustment not found" {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
@@ -274,70 +1019,113 @@ func (h *JobHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	var req struct {
-		Address        string     `json:"address"`
-		Status         string     `json:"status"`
-		CurrentPhaseID *string    `json:"currentPhaseId"`
-		ScheduledDate  *time.Time `json:"scheduledDate"`
-		PermitRequired *bool      `json:"permitRequired"`
-		PermitNumber   string     `json:"permitNumber"`
-		Notes          string     `json:"notes"`
-	}
-	
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Variance returns the budgeted vs installed report for a job
+func (h *JobHandler) Variance(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Update fields
-	if req.Address != "" {
-		job.Address = req.Address
-	}
+	respondJSON(w, job.VarianceReport())
+}
+
+// Tax returns the sales tax on a job at its address on ?date= (default today)
+func (h *JobHandler) Tax(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	
-	if req.Status != "" {
-		if err := job.UpdateStatus(models.JobStatus(req.Status)); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+	on := time.Now()
+	if date := r.URL.Query().Get("date"); date != "" {
+		parsed, err := parseDate(date)
+		if err != nil {
+			http.Error(w, "date must be a date like 2006-01-02", http.StatusBadRequest)
 			return
 		}
+		on = parsed
 	}
 	
-	if req.CurrentPhaseID != nil {
-		job.UpdatePhase(*req.CurrentPhaseID)
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 	
-	if req.ScheduledDate != nil {
-		job.ScheduledDate = *req.ScheduledDate
+	settings, err := h.loadTaxSettings(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 	
-	if req.PermitRequired != nil {
-		job.PermitRequired = *req.PermitRequired
+	respondJSON(w, settings.CalculateJobTax(job, on))
+}
+
+// adjustmentDescription describes one of the job's adjustments for the invoice
+func adjustmentDescription(job *models.Job, adjustmentID string) string {
+	for _, line := range job.AdjustmentLines {
+		if line.AdjustmentID == adjustmentID {
+			return line.Description()
+		}
 	}
-	
-	if req.PermitNumber != "" {
-		job.PermitNumber = req.PermitNumber
+	return ""
+}
+
+// loadTaxSettings loads the tax rates, category taxability and the category
+// of every item
+func (h *JobHandler) loadTaxSettings(ctx context.Context) (*models.TaxSettings, error) {
+	rates, err := h.taxRepo.ListRates(ctx)
+	if err != nil {
+		return nil, err
 	}
 	
-	if req.Notes != "" {
-		job.Notes = req.Notes
+	categories, err := h.taxRepo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
 	}
 	
-	job.UpdatedAt = time.Now()
+	itemCategories, err := h.loadItemCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
 	
-	if err := h.jobRepo.Update(ctx, job); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	return &models.TaxSettings{
+		Rates:          rates,
+		Categories:     categories,
+		ItemCategories: itemCategories,
+	}, nil
+}
+
+// loadItemCategories maps every item to its category
+func (h *JobHandler) loadItemCategories(ctx context.Context) (map[string]string, error) {
+	items, err := h.itemRepo.List(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, err
 	}
 	
-	respondJSON(w, job)
+	itemCategories := make(map[string]string, len(items))
+	for _, item := range items {
+		itemCategories[item.ID] = item.Category
+	}
+	return itemCategories, nil
 }
 
-// Delete deletes a job
-func (h *JobHandler) Delete(w http.ResponseWriter, r *http.Request) {
+// Profitability returns a job's revenue, material cost and gross margin,
+// overall and per category
+func (h *JobHandler) Profitability(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	vars := mux.Vars(r)
-	id := vars["id"]
 	
-	if err := h.jobRepo.Delete(ctx, id); err != nil {
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
 		if err.Error() == "job not found" {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
@@ -346,80 +1134,107 @@ func (h *JobHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	w.WriteHeader(http.StatusNoContent)
+	itemCategories, err := h.loadItemCategories(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, job.Profitability(itemCategories))
 }
 
-// AddItem adds an item to a job
-func (h *JobHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+// TemplateProfitability sums job profitability by template, best margin
+// first. It covers the jobs with ?status=, or every job that was not cancelled.
+func (h *JobHandler) TemplateProfitability(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	vars := mux.Vars(r)
-	jobID := vars["id"]
 	
-	// Verify job exists
-	job, err := h.jobRepo.GetByID(ctx, jobID)
-	if err != nil {
-		if err.Error() == "job not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+	var jobs []*models.Job
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !models.ValidateJobStatus(models.JobStatus(status)) {
+			http.Error(w, "invalid job status", http.StatusBadRequest)
+			return
+		}
+		found, err := h.jobRepo.GetByStatus(ctx, models.JobStatus(status))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		jobs = found
+	} else {
+		const pageSize = 100
+		for offset := 0; ; offset += pageSize {
+			page, err := h.jobRepo.List(ctx, pageSize, offset)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			for _, job := range page {
+				if job.Status != models.JobStatusCancelled {
+					jobs = append(jobs, job)
+				}
+			}
+			if len(page) < pageSize {
+				break
+			}
+		}
+	}
+	
+	itemCategories, err := h.loadItemCategories(ctx)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	var req struct {
-		ItemID   string  `json:"itemId"`
-		Quantity float64 `json:"quantity"`
+	reports := make([]*models.JobProfitability, 0, len(jobs))
+	for _, job := range jobs {
+		reports = append(reports, job.Profitability(itemCategories))
 	}
 	
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+	summaries := models.SummarizeTemplateProfitability(reports)
+	for i := range summaries {
+		template, err := h.templateRepo.GetByID(ctx, summaries[i].TemplateID)
+		if err != nil {
+			if err.Error() == "template not found" {
+				continue
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		summaries[i].TemplateName = template.Name
 	}
 	
-	// Get item details
-	item, err := h.itemRepo.GetByID(ctx, req.ItemID)
-	if err != nil {
-		if err.Error() == "item not found" {
-			http.Error(w, "Item not found", http.StatusBadRequest)
+	respondJSON(w, summaries)
+}
+
+// ListRevisions returns every revision of a job, oldest first
+func (h *JobHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := mux.Vars(r)["id"]
+	
+	if _, err := h.jobRepo.GetByID(ctx, jobID); err != nil {
+		if err.Error() == "job not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Create job item
-	jobItem := &models.JobItem{
-		ID:       uuid.New().String(),
-		JobID:    jobID,
-		ItemID:   item.ID,
-		Name:     item.Name,
-		Quantity: req.Quantity,
-		Price:    item.UnitPrice,
-		Total:    req.Quantity * item.UnitPrice,
-	}
-	
-	if err := h.jobRepo.AddJobItem(ctx, jobID, jobItem); err != nil {
+	revisions, err := h.jobRepo.GetRevisions(ctx, jobID)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Update job total
-	job.Items = append(job.Items, *jobItem)
-	job.CalculateTotal()
-	h.jobRepo.Update(ctx, job)
-	
-	w.WriteHeader(http.StatusCreated)
-	respondJSON(w, jobItem)
+	respondJSON(w, revisions)
 }
 
-// UpdateItem updates a job item
-func (h *JobHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+// CreateRevision snapshots the job's current line items and budgeted total as a
+// named revision authored by the signed-in user
+func (h *JobHandler) CreateRevision(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	vars := mux.Vars(r)
-	jobID := vars["id"]
-	itemID := vars["itemId"]
+	jobID := mux.Vars(r)["id"]
 	
-	// Get job to verify it exists
 	job, err := h.jobRepo.GetByID(ctx, jobID)
 	if err != nil {
 		if err.Error() == "job not found" {
@@ -431,7 +1246,8 @@ func (h *JobHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	var req struct {
-		Quantity float64 `json:"quantity"`
+		Name string `json:"name"`
+		Note string `json:"note"`
 	}
 	
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -439,73 +1255,111 @@ func (h *JobHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Find and update the item
-	var updatedItem *models.JobItem
-	for i := range job.Items {
-		if job.Items[i].ID == itemID {
-			job.Items[i].Quantity = req.Quantity
-			job.Items[i].Total = req.Quantity * job.Items[i].Price
-			updatedItem = &job.Items[i]
-			break
-		}
-	}
-	
-	if updatedItem == nil {
-		http.Error(w, "Job item not found", http.StatusNotFound)
+	author, _ := middleware.UserFromContext(ctx)
+	revision, err := models.NewJobRevision(job, req.Name, req.Note, author)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 	
-	if err := h.jobRepo.UpdateJobItem(ctx, updatedItem); err != nil {
+	if err := h.jobRepo.CreateRevision(ctx, revision); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	
-	// Update job total
-	job.CalculateTotal()
-	h.jobRepo.Update(ctx, job)
+	w.WriteHeader(http.StatusCreated)
+	respondJSON(w, revision)
+}
+
+// GetRevision returns a single revision by its number
+func (h *JobHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
 	
-	respondJSON(w, updatedItem)
+	revision, ok := h.loadRevision(w, r, vars["id"], vars["number"])
+	if !ok {
+		return
+	}
+	
+	respondJSON(w, revision)
 }
 
-// RemoveItem removes an item from a job
-func (h *JobHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+// revisionSummary identifies one side of a revision diff with its budgeted
+// total, including markups, discounts and approved change orders
+type revisionSummary struct {
+	Number      int          `json:"number,omitempty"`
+	Name        string       `json:"name"`
+	TotalAmount models.Money `json:"totalAmount"`
+}
+
+// DiffRevisions returns the line-level diff of the budgeted scope between two
+// revisions, given as ?from=N&to=M. When to is omitted the job's current lines
+// are used.
+func (h *JobHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	vars := mux.Vars(r)
-	jobID := vars["id"]
-	itemID := vars["itemId"]
+	jobID := mux.Vars(r)["id"]
+	query := r.URL.Query()
 	
-	// Get job
-	job, err := h.jobRepo.GetByID(ctx, jobID)
-	if err != nil {
-		if err.Error() == "job not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if query.Get("from") == "" {
+		http.Error(w, "from revision is required", http.StatusBadRequest)
 		return
 	}
 	
-	if err := h.jobRepo.RemoveJobItem(ctx, jobID, itemID); err != nil {
-		if err.Error() == "job item not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+	from, ok := h.loadRevision(w, r, jobID, query.Get("from"))
+	if !ok {
+		return
+	}
+	
+	var toItems []models.JobItem
+	toSummary := revisionSummary{Name: "Current"}
+	
+	if query.Get("to") != "" {
+		to, ok := h.loadRevision(w, r, jobID, query.Get("to"))
+		if !ok {
 			return
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		toItems = to.Items
+		toSummary = revisionSummary{Number: to.Number, Name: to.Name, TotalAmount: to.TotalAmount}
+	} else {
+		job, err := h.jobRepo.GetByID(ctx, jobID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		toItems = job.Items
+		toSummary.TotalAmount = job.BudgetedTotal()
+	}
+	
+	respondJSON(w, struct {
+		From revisionSummary `json:"from"`
+		To   revisionSummary `json:"to"`
+		models.JobItemDiff
+	}{
+		From:        revisionSummary{Number: from.Number, Name: from.Name, TotalAmount: from.TotalAmount},
+		To:          toSummary,
+		JobItemDiff: models.DiffJobItems(from.Items, toItems),
+	})
+}
+
+// loadRevision fetches a job revision by its number. It writes an error
+// response and returns false on failure.
+func (h *JobHandler) loadRevision(w http.ResponseWriter, r *http.Request, jobID, number string) (*models.JobRevision, bool) {
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		http.Error(w, "Invalid revision number", http.StatusBadRequest)
+		return nil, false
 	}
 	
-	// Update job items and total
-	newItems := make([]models.JobItem, 0, len(job.Items)-1)
-	for _, item := range job.Items {
-		if item.ID != itemID {
-			newItems = append(newItems, item)
+	revision, err := h.jobRepo.GetRevision(r.Context(), jobID, n)
+	if err != nil {
+		if err.Error() == "revision not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return nil, false
 		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
 	}
-	job.Items = newItems
-	job.CalculateTotal()
-	h.jobRepo.Update(ctx, job)
 	
-	w.WriteHeader(http.StatusNoContent)
+	return revision, true
 }
 
 // AddPhoto handles photo uploads for a job
@@ -645,12 +1499,22 @@ func (h *JobHandler) RemovePhoto(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// SendToWave sends a job to Wave for invoicing
+// SendToWave sends a job to Wave for invoicing. When the invoice lines don't
+// add up to the job it answers 409 with the reconciliation and bills nothing,
+// unless the request body sets "override": true.
 func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
 	jobID := vars["id"]
 	
+	var req struct {
+		Override bool `json:"override"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	
 	// Get the job with all related data
 	job, err := h.jobRepo.GetByID(ctx, jobID)
 	if err != nil {
@@ -701,6 +1565,38 @@ func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 		skyviewCustomerID = skyviewCustomer.ID
 	}
 	
+	// Charge sales tax on taxable lines at the job's address as of today
+	settings, err := h.loadTaxSettings(ctx)
+	if err != nil {
+		http.Error(w, "Failed to load tax settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tax := settings.CalculateJobTax(job, time.Now())
+	salesTaxIDs := tax.WaveSalesTaxIDs()
+	
+	// Jobs set to split labor out bill it on lines of its own
+	invoiceLines := services.ExpandAssemblyLine
+	labor, err := getJobLabor(ctx, h.laborRepo, job.ID)
+	if err != nil {
+		http.Error(w, "Failed to load job labor: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if labor.SplitOnInvoice {
+		rate, difficulty, err := loadLaborPricing(ctx, h.laborRepo, labor.RateID, labor.DifficultyID)
+		if err != nil {
+			var validationErr *validationError
+			if errors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "Failed to load labor rates: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		invoiceLines = func(line services.LineItem, item *models.Item) []services.LineItem {
+			return services.InvoiceLaborLines(line, item, rate.HourlyRate, difficulty)
+		}
+	}
+	
 	// Prepare line items
 	var lineItems []services.LineItem
 	
@@ -710,27 +1606,66 @@ func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 		
-		// Get the full item details
+		// Get the full item details; a line that can't be priced must not
+		// silently drop off the invoice
 		item, err := h.itemRepo.GetByID(ctx, jobItem.ItemID)
 		if err != nil {
-			continue
+			http.Error(w, fmt.Sprintf("Failed to load item %s for line %s: %v", jobItem.ItemID, jobItem.Name, err), http.StatusInternalServerError)
+			return
 		}
 		
 		lineItem := services.LineItem{
+			ItemID:      item.ID,
 			ProductName: item.Name,
 			Description: "", // Items don't have descriptions
-			Quantity:    int(jobItem.Quantity),
+			Quantity:    jobItem.Quantity,
 			Price:       jobItem.Price,
 			Total:       jobItem.Total,
 		}
+		if settings.IsTaxableItem(item.ID) {
+			lineItem.SalesTaxIDs = salesTaxIDs
+		}
 		
+		// Assemblies set to expand on invoices are billed as their parts
+		lineItems = append(lineItems, invoiceLines(lineItem, item)...)
+	}
+	
+	// Markups and discounts follow the lines they adjust, each as its own line
+	for _, line := range tax.AdjustmentLines() {
+		lineItem := services.LineItem{
+			ItemID:      line.ItemID,
+			ProductName: line.Name,
+			Description: adjustmentDescription(job, line.AdjustmentID),
+			Quantity:    1,
+			Price:       line.Amount,
+			Total:       line.Amount,
+		}
+		if line.Taxable {
+			lineItem.SalesTaxIDs = salesTaxIDs
+		}
 		lineItems = append(lineItems, lineItem)
 	}
 	
+	// Approved change orders follow the base scope as labelled groups
+	for _, lineItem := range changeOrderLineItems(job) {
+		if settings.IsTaxableItem(lineItem.ItemID) {
+			lineItem.SalesTaxIDs = salesTaxIDs
+		}
+		item, err := h.itemRepo.GetByID(ctx, lineItem.ItemID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to load item %s for change order line %s: %v", lineItem.ItemID, lineItem.ProductName, err), http.StatusInternalServerError)
+			return
+		}
+		lineItems = append(lineItems, invoiceLines(lineItem, item)...)
+	}
+	
+	// Tax and the untaxed permit are billed on top of the job total
+	localTotal := job.TotalAmount + tax.TaxAmount
+	
 	// Add permit if required
 	if job.PermitRequired {
 		// Try to find permit price from items table
-		permitPrice := 250.00 // Default
+		permitPrice := models.Cents(25000) // Default
 		
 		// Search for electrical permit in items
 		items, _ := h.itemRepo.List(ctx, map[string]interface{}{})
@@ -748,6 +1683,7 @@ func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 			Price:       permitPrice,
 			Total:       permitPrice,
 		})
+		localTotal += permitPrice
 	}
 	
 	if len(lineItems) == 0 {
@@ -755,6 +1691,16 @@ func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Check the lines add up to the job before billing them
+	reconciliation := services.ReconcileInvoice(lineItems, tax.WaveTaxAmount(), localTotal)
+	if !reconciliation.Matches() && !req.Override {
+		respondWithJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":          reconciliation.Warning(),
+			"reconciliation": reconciliation,
+		})
+		return
+	}
+	
 	// Format PO number
 	poNumber := services.FormatPONumber(customer.Name, job.Address)
 	
@@ -765,6 +1711,10 @@ func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	if invoice.Total != nil {
+		reconciliation.SetWaveTotal(*invoice.Total)
+	}
+	
 	// Update job with Wave invoice info
 	job.WaveInvoiceID = invoice.InvoiceNumber
 	job.WaveInvoiceURL = invoice.ViewURL
@@ -774,10 +1724,15 @@ func (h *JobHandler) SendToWave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Return the invoice info
-	respondJSON(w, map[string]interface{}{
-		"invoiceNumber": invoice.InvoiceNumber,
-		"invoiceUrl":    invoice.ViewURL,
-		"message":       "Invoice created successfully in Wave",
-	})
+	// Return the invoice info, with a warning when Wave bills a different total
+	response := map[string]interface{}{
+		"invoiceNumber":  invoice.InvoiceNumber,
+		"invoiceUrl":     invoice.ViewURL,
+		"message":        "Invoice created successfully in Wave",
+		"reconciliation": reconciliation,
+	}
+	if warning := reconciliation.Warning(); warning != "" {
+		response["warning"] = warning
+	}
+	respondJSON(w, response)
 }
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// recordingJobRepository records job writes and fails the one named by failOn.
+// Methods it doesn't override panic through the nil embedded interface.
+type recordingJobRepository struct {
+	repository.JobRepository
+	failOn string
+	writes []string
+}
+
+func (r *recordingJobRepository) write(name string) error {
+	if name == r.failOn {
+		return errors.New("injected failure")
+	}
+	r.writes = append(r.writes, name)
+	return nil
+}
+
+func (r *recordingJobRepository) Create(ctx context.Context, job *models.Job) error {
+	return r.write("Create")
+}
+
+func (r *recordingJobRepository) Update(ctx context.Context, job *models.Job) error {
+	return r.write("Update")
+}
+
+func (r *recordingJobRepository) SavePhases(ctx context.Context, jobID string, phases []models.JobPhase) error {
+	return r.write("SavePhases")
+}
+
+func (r *recordingJobRepository) AddJobItem(ctx context.Context, jobID string, item *models.JobItem) error {
+	return r.write("AddJobItem " + item.ItemID)
+}
+
+func (r *recordingJobRepository) AddAdjustment(ctx context.Context, jobID string, adjustment *models.PriceAdjustment) error {
+	return r.write("AddAdjustment " + adjustment.Name)
+}
+
+func (r *recordingJobRepository) UpdateJobItem(ctx context.Context, item *models.JobItem) error {
+	return r.write("UpdateJobItem " + item.ItemID)
+}
+
+func (r *recordingJobRepository) AddStatusChange(ctx context.Context, change *models.JobStatusChange) error {
+	return r.write("AddStatusChange")
+}
+
+// stagingUnitOfWork hands out a recording job repository and keeps its writes
+// only when the work succeeds, like a committed transaction
+type stagingUnitOfWork struct {
+	failOn    string
+	committed []string
+}
+
+func (u *stagingUnitOfWork) WithTx(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	jobs := &recordingJobRepository{failOn: u.failOn}
+	if err := fn(repository.Repositories{Jobs: jobs}); err != nil {
+		return err
+	}
+	u.committed = append(u.committed, jobs.writes...)
+	return nil
+}
+
+// stubJobRepository serves a fixed set of jobs
+type stubJobRepository struct {
+	repository.JobRepository
+	jobs []*models.Job
+}
+
+func (r *stubJobRepository) GetByID(ctx context.Context, id string) (*models.Job, error) {
+	for _, job := range r.jobs {
+		if job.ID == id {
+			return job, nil
+		}
+	}
+	return nil, errors.New("job not found")
+}
+
+func (r *stubJobRepository) List(ctx context.Context, limit, offset int) ([]*models.Job, error) {
+	if offset >= len(r.jobs) {
+		return []*models.Job{}, nil
+	}
+	end := offset + limit
+	if end > len(r.jobs) {
+		end = len(r.jobs)
+	}
+	return r.jobs[offset:end], nil
+}
+
+type stubCustomerRepository struct {
+	repository.CustomerRepository
+}
+
+func (r *stubCustomerRepository) GetByID(ctx context.Context, id string) (*models.Customer, error) {
+	return &models.Customer{ID: id, Name: "Test Customer"}, nil
+}
+
+type stubTemplateRepository struct {
+	repository.JobTemplateRepository
+	template *models.JobTemplate
+}
+
+func (r *stubTemplateRepository) GetByID(ctx context.Context, id string) (*models.JobTemplate, error) {
+	return r.template, nil
+}
+
+type stubItemRepository struct {
+	repository.ItemRepository
+	items []*models.Item
+}
+
+func (r *stubItemRepository) GetByID(ctx context.Context, id string) (*models.Item, error) {
+	return &models.Item{ID: id, Name: id, UnitPrice: models.Cents(1000)}, nil
+}
+
+func (r *stubItemRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.Item, error) {
+	return r.items, nil
+}
+
+type stubAdjustmentRepository struct {
+	repository.AdjustmentRepository
+	defaults []models.PriceAdjustment
+}
+
+func (r *stubAdjustmentRepository) ListForCustomer(ctx context.Context, customerID string) ([]models.PriceAdjustment, error) {
+	return r.defaults, nil
+}
+
+func TestJobHandler_Create_IsAllOrNothing(t *testing.T) {
+	template := &models.JobTemplate{
+		ID:      "tmpl",
+		Version: 3,
+		Items: []models.TemplateItem{
+			{ItemID: "outlet", DefaultQuantity: 6},
+			{ItemID: "switch", DefaultQuantity: 2},
+		},
+		Phases: []models.TemplatePhase{{ID: "rough", Name: "Rough", Order: 1}},
+	}
+
+	markup, _ := models.NewPriceAdjustment("Materials markup", models.AdjustmentMarkup, models.AdjustmentScopeCategory, "", "Materials", 20, 0)
+
+	tests := []struct {
+		name          string
+		failOn        string
+		wantStatus    int
+		wantCommitted int
+	}{
+		{name: "all writes succeed", wantStatus: http.StatusCreated, wantCommitted: 6},
+		{name: "second item fails", failOn: "AddJobItem switch", wantStatus: http.StatusInternalServerError},
+		{name: "adjustment fails", failOn: "AddAdjustment Materials markup", wantStatus: http.StatusInternalServerError},
+		{name: "phases fail", failOn: "SavePhases", wantStatus: http.StatusInternalServerError},
+		{name: "status history fails", failOn: "AddStatusChange", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := &stagingUnitOfWork{failOn: tt.failOn}
+			handler := NewJobHandler(uow, nil, &stubCustomerRepository{},
+				&stubTemplateRepository{template: template}, &stubItemRepository{}, nil,
+				&stubAdjustmentRepository{defaults: []models.PriceAdjustment{*markup}}, nil)
+
+			body := bytes.NewBufferString(`{"customerId": "cust", "templateId": "tmpl", "address": "1 Main St"}`)
+			req := httptest.NewRequest(http.MethodPost, "/jobs", body)
+			rr := httptest.NewRecorder()
+
+			handler.Create(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if len(uow.committed) != tt.wantCommitted {
+				t.Errorf("expected %d committed writes, got %v", tt.wantCommitted, uow.committed)
+			}
+		})
+	}
+}
+
+func TestJobHandler_UpdateStatus_IsAllOrNothing(t *testing.T) {
+	tests := []struct {
+		name          string
+		failOn        string
+		wantStatus    int
+		wantCommitted int
+	}{
+		{name: "all writes succeed", wantStatus: http.StatusOK, wantCommitted: 2},
+		{name: "status history fails after the job row", failOn: "AddStatusChange", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &models.Job{ID: "job1", Status: models.JobStatusScheduled}
+			uow := &stagingUnitOfWork{failOn: tt.failOn}
+			handler := NewJobHandler(uow, &stubJobRepository{jobs: []*models.Job{job}}, nil, nil, nil, nil, nil, nil)
+
+			body := bytes.NewBufferString(`{"status": "in_progress"}`)
+			req := httptest.NewRequest(http.MethodPut, "/jobs/job1/status", body)
+			req = mux.SetURLVars(req, map[string]string{"id": "job1"})
+			rr := httptest.NewRecorder()
+
+			handler.UpdateStatus(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if len(uow.committed) != tt.wantCommitted {
+				t.Errorf("expected %d committed writes, got %v", tt.wantCommitted, uow.committed)
+			}
+		})
+	}
+}
+
+func TestJobHandler_CompletePhase_IsAllOrNothing(t *testing.T) {
+	tests := []struct {
+		name          string
+		failOn        string
+		wantStatus    int
+		wantCommitted int
+	}{
+		{name: "all writes succeed", wantStatus: http.StatusOK, wantCommitted: 2},
+		{name: "phases fail after the job row", failOn: "SavePhases", wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rough := "rough"
+			job := &models.Job{
+				ID:             "job1",
+				Status:         models.JobStatusInProgress,
+				CurrentPhaseID: &rough,
+				Phases: []models.JobPhase{
+					{ID: "rough", Name: "Rough", Order: 1},
+					{ID: "trim", Name: "Trim", Order: 2},
+				},
+			}
+			uow := &stagingUnitOfWork{failOn: tt.failOn}
+			handler := NewJobHandler(uow, &stubJobRepository{jobs: []*models.Job{job}}, nil, nil, nil, nil, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs/job1/phases/rough/complete", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "job1", "phaseId": "rough"})
+			rr := httptest.NewRecorder()
+
+			handler.CompletePhase(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if len(uow.committed) != tt.wantCommitted {
+				t.Errorf("expected %d committed writes, got %v", tt.wantCommitted, uow.committed)
+			}
+		})
+	}
+}
+
+func TestJobHandler_Reprice(t *testing.T) {
+	newJobs := func() []*models.Job {
+		line := func(itemID string, price int64) models.JobItem {
+			return models.JobItem{ID: "line-" + itemID, ItemID: itemID, Name: itemID, Quantity: 2, Price: models.Cents(price)}
+		}
+		return []*models.Job{
+			{ID: "open", Status: models.JobStatusInProgress, Items: []models.JobItem{line("outlet", 1000), line("switch", 800)}},
+			{ID: "invoiced", Status: models.JobStatusCompleted, WaveInvoiceID: "INV-1", Items: []models.JobItem{line("outlet", 1000)}},
+			{ID: "cancelled", Status: models.JobStatusCancelled, Items: []models.JobItem{line("outlet", 1000)}},
+			{ID: "current", Status: models.JobStatusScheduled, Items: []models.JobItem{line("switch", 800)}},
+		}
+	}
+	items := []*models.Item{
+		{ID: "outlet", UnitPrice: models.Cents(1200)},
+		{ID: "switch", UnitPrice: models.Cents(800)},
+	}
+
+	tests := []struct {
+		name          string
+		body          string
+		wantStatus    int
+		wantJobs      []string
+		wantDelta     models.Money
+		wantCommitted []string
+	}{
+		{name: "preview every open job", wantStatus: http.StatusOK, wantJobs: []string{"open"}, wantDelta: models.Cents(400)},
+		{name: "apply", body: `{"apply": true}`, wantStatus: http.StatusOK, wantJobs: []string{"open"}, wantDelta: models.Cents(400), wantCommitted: []string{"UpdateJobItem outlet"}},
+		{name: "chosen jobs", body: `{"jobIds": ["current"]}`, wantStatus: http.StatusOK, wantJobs: []string{}},
+		{name: "invoiced job", body: `{"jobIds": ["open", "invoiced"]}`, wantStatus: http.StatusConflict},
+		{name: "unknown job", body: `{"jobIds": ["missing"]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uow := &stagingUnitOfWork{}
+			handler := NewJobHandler(uow, &stubJobRepository{jobs: newJobs()}, nil, nil,
+				&stubItemRepository{items: items}, nil, nil, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/jobs/reprice", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			handler.Reprice(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if len(uow.committed) != len(tt.wantCommitted) || (len(tt.wantCommitted) > 0 && uow.committed[0] != tt.wantCommitted[0]) {
+				t.Errorf("expected committed writes %v, got %v", tt.wantCommitted, uow.committed)
+			}
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Jobs       []models.JobRepricing `json:"jobs"`
+				TotalDelta models.Money          `json:"totalDelta"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Jobs) != len(tt.wantJobs) {
+				t.Fatalf("expected jobs %v, got %+v", tt.wantJobs, resp.Jobs)
+			}
+			for i, id := range tt.wantJobs {
+				if resp.Jobs[i].JobID != id {
+					t.Errorf("expected job %s, got %s", id, resp.Jobs[i].JobID)
+				}
+			}
+			if resp.TotalDelta != tt.wantDelta {
+				t.Errorf("expected a total change of %s, got %s", tt.wantDelta, resp.TotalDelta)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// LaborHandler handles HTTP requests for labor rates, difficulty multipliers
+// and the labor in jobs and templates
+type LaborHandler struct {
+	laborRepo    repository.LaborRepository
+	jobRepo      repository.JobRepository
+	templateRepo repository.JobTemplateRepository
+	itemRepo     repository.ItemRepository
+}
+
+// NewLaborHandler creates a new labor handler
+func NewLaborHandler(
+	laborRepo repository.LaborRepository,
+	jobRepo repository.JobRepository,
+	templateRepo repository.JobTemplateRepository,
+	itemRepo repository.ItemRepository,
+) *LaborHandler {
+	return &LaborHandler{
+		laborRepo:    laborRepo,
+		jobRepo:      jobRepo,
+		templateRepo: templateRepo,
+		itemRepo:     itemRepo,
+	}
+}
+
+// RegisterRoutes registers all labor routes
+func (h *LaborHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the labor endpoints; labor rates and estimates are
+// admin-only
+func (h *LaborHandler) routes() []route {
+	return []route{
+		{"GET", "/labor/rates", h.ListRates, adminOnly},
+		{"POST", "/labor/rates", h.CreateRate, adminOnly},
+		{"GET", "/labor/rates/{id}", h.GetRate, adminOnly},
+		{"PUT", "/labor/rates/{id}", h.UpdateRate, adminOnly},
+		{"DELETE", "/labor/rates/{id}", h.DeleteRate, adminOnly},
+
+		{"GET", "/labor/difficulties", h.ListDifficulties, adminOnly},
+		{"POST", "/labor/difficulties", h.CreateDifficulty, adminOnly},
+		{"GET", "/labor/difficulties/{id}", h.GetDifficulty, adminOnly},
+		{"PUT", "/labor/difficulties/{id}", h.UpdateDifficulty, adminOnly},
+		{"DELETE", "/labor/difficulties/{id}", h.DeleteDifficulty, adminOnly},
+
+		{"GET", "/jobs/{id}/labor", h.JobLabor, adminOnly},
+		{"PUT", "/jobs/{id}/labor", h.SetJobLabor, adminOnly},
+		{"GET", "/templates/{id}/labor", h.TemplateLabor, adminOnly},
+	}
+}
+
+// laborRateRequest is a requested labor rate
+type laborRateRequest struct {
+	Name       string       `json:"name"`
+	HourlyRate models.Money `json:"hourlyRate"`
+	IsDefault  bool         `json:"isDefault"`
+}
+
+// laborDifficultyRequest is a requested difficulty multiplier
+type laborDifficultyRequest struct {
+	Name       string  `json:"name"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// jobLaborRequest is how a job's labor should be priced
+type jobLaborRequest struct {
+	RateID         string `json:"rateId"`
+	DifficultyID   string `json:"difficultyId"`
+	SplitOnInvoice bool   `json:"splitOnInvoice"`
+}
+
+// jobLaborResponse is how a job's labor is priced and the labor it comes to
+type jobLaborResponse struct {
+	Settings *models.JobLabor      `json:"settings"`
+	Estimate *models.LaborEstimate `json:"estimate"`
+}
+
+// ListRates handles GET /api/labor/rates
+func (h *LaborHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.laborRepo.ListRates(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list labor rates")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rates)
+}
+
+// GetRate handles GET /api/labor/rates/:id
+func (h *LaborHandler) GetRate(w http.ResponseWriter, r *http.Request) {
+	rate, ok := h.getRate(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rate)
+}
+
+// CreateRate handles POST /api/labor/rates. A new default rate replaces the
+// old default.
+func (h *LaborHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
+	var req laborRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rate, err := models.NewLaborRate(req.Name, req.HourlyRate, req.IsDefault)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.laborRepo.CreateRate(r.Context(), rate); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A labor rate with this name already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create labor rate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, rate)
+}
+
+// UpdateRate handles PUT /api/labor/rates/:id. Jobs priced at the rate pick
+// up the change.
+func (h *LaborHandler) UpdateRate(w http.ResponseWriter, r *http.Request) {
+	rate, ok := h.getRate(w, r)
+	if !ok {
+		return
+	}
+
+	var req laborRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := rate.Update(req.Name, req.HourlyRate, req.IsDefault); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.laborRepo.UpdateRate(r.Context(), rate); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A labor rate with this name already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update labor rate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rate)
+}
+
+// DeleteRate handles DELETE /api/labor/rates/:id
+func (h *LaborHandler) DeleteRate(w http.ResponseWriter, r *http.Request) {
+	if err := h.laborRepo.DeleteRate(r.Context(), mux.Vars(r)["id"]); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, "Labor rate not found")
+		case errors.Is(err, repository.ErrInUse):
+			respondWithError(w, http.StatusConflict, "Jobs are priced at this labor rate")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to delete labor rate")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDifficulties handles GET /api/labor/difficulties
+func (h *LaborHandler) ListDifficulties(w http.ResponseWriter, r *http.Request) {
+	difficulties, err := h.laborRepo.ListDifficulties(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list labor difficulties")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, difficulties)
+}
+
+// GetDifficulty handles GET /api/labor/difficulties/:id
+func (h *LaborHandler) GetDifficulty(w http.ResponseWriter, r *http.Request) {
+	difficulty, ok := h.getDifficulty(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, difficulty)
+}
+
+// CreateDifficulty handles POST /api/labor/difficulties
+func (h *LaborHandler) CreateDifficulty(w http.ResponseWriter, r *http.Request) {
+	var req laborDifficultyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	difficulty, err := models.NewLaborDifficulty(req.Name, req.Multiplier)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.laborRepo.CreateDifficulty(r.Context(), difficulty); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A difficulty with this name already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create labor difficulty")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, difficulty)
+}
+
+// UpdateDifficulty handles PUT /api/labor/difficulties/:id
+func (h *LaborHandler) UpdateDifficulty(w http.ResponseWriter, r *http.Request) {
+	difficulty, ok := h.getDifficulty(w, r)
+	if !ok {
+		return
+	}
+
+	var req laborDifficultyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := difficulty.Update(req.Name, req.Multiplier); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.laborRepo.UpdateDifficulty(r.Context(), difficulty); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A difficulty with this name already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update labor difficulty")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, difficulty)
+}
+
+// DeleteDifficulty handles DELETE /api/labor/difficulties/:id
+func (h *LaborHandler) DeleteDifficulty(w http.ResponseWriter, r *http.Request) {
+	if err := h.laborRepo.DeleteDifficulty(r.Context(), mux.Vars(r)["id"]); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			respondWithError(w, http.StatusNotFound, "Labor difficulty not found")
+		case errors.Is(err, repository.ErrInUse):
+			respondWithError(w, http.StatusConflict, "Jobs are priced at this difficulty")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to delete labor difficulty")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// JobLabor handles GET /api/jobs/:id/labor: the labor in the job's budgeted
+// lines and approved change orders at the job's rate and difficulty.
+// ?rateId= and ?difficultyId= price it at others without saving them.
+func (h *LaborHandler) JobLabor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			respondWithError(w, http.StatusNotFound, "Job not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job")
+		return
+	}
+
+	settings, err := getJobLabor(ctx, h.laborRepo, job.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job labor")
+		return
+	}
+
+	query := r.URL.Query()
+	rateID, difficultyID := settings.RateID, settings.DifficultyID
+	if query.Has("rateId") {
+		rateID = query.Get("rateId")
+	}
+	if query.Has("difficultyId") {
+		difficultyID = query.Get("difficultyId")
+	}
+
+	rate, difficulty, ok := h.laborPricing(w, r, rateID, difficultyID)
+	if !ok {
+		return
+	}
+
+	items, ok := h.catalog(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, jobLaborResponse{
+		Settings: settings,
+		Estimate: models.EstimateLabor(models.JobLaborLines(job, items), rate, difficulty),
+	})
+}
+
+// SetJobLabor handles PUT /api/jobs/:id/labor, setting the rate and
+// difficulty the job's labor is priced at and whether its invoice bills labor
+// on lines of its own
+func (h *LaborHandler) SetJobLabor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			respondWithError(w, http.StatusNotFound, "Job not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job")
+		return
+	}
+
+	var req jobLaborRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rate, difficulty, ok := h.laborPricing(w, r, req.RateID, req.DifficultyID)
+	if !ok {
+		return
+	}
+
+	settings := &models.JobLabor{
+		JobID:          job.ID,
+		RateID:         req.RateID,
+		DifficultyID:   req.DifficultyID,
+		SplitOnInvoice: req.SplitOnInvoice,
+		UpdatedAt:      time.Now(),
+	}
+	if err := h.laborRepo.SetJobLabor(ctx, settings); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save job labor")
+		return
+	}
+
+	items, ok := h.catalog(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, jobLaborResponse{
+		Settings: settings,
+		Estimate: models.EstimateLabor(models.JobLaborLines(job, items), rate, difficulty),
+	})
+}
+
+// TemplateLabor handles GET /api/templates/:id/labor: the labor in the
+// template's default quantities at ?rateId= (default rate) and ?difficultyId=
+func (h *LaborHandler) TemplateLabor(w http.ResponseWriter, r *http.Request) {
+	template, err := h.templateRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "template not found" {
+			respondWithError(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get template")
+		return
+	}
+
+	query := r.URL.Query()
+	rate, difficulty, ok := h.laborPricing(w, r, query.Get("rateId"), query.Get("difficultyId"))
+	if !ok {
+		return
+	}
+
+	items, ok := h.catalog(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, models.EstimateLabor(models.TemplateLaborLines(template, items), rate, difficulty))
+}
+
+// getRate loads the labor rate named in the URL, writing the error response
+// when it cannot
+func (h *LaborHandler) getRate(w http.ResponseWriter, r *http.Request) (*models.LaborRate, bool) {
+	rate, err := h.laborRepo.GetRate(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Labor rate not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get labor rate")
+		return nil, false
+	}
+
+	return rate, true
+}
+
+// getDifficulty loads the difficulty named in the URL, writing the error
+// response when it cannot
+func (h *LaborHandler) getDifficulty(w http.ResponseWriter, r *http.Request) (*models.LaborDifficulty, bool) {
+	difficulty, err := h.laborRepo.GetDifficulty(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Labor difficulty not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get labor difficulty")
+		return nil, false
+	}
+
+	return difficulty, true
+}
+
+// laborPricing loads a rate and difficulty, writing the error response when
+// it cannot
+func (h *LaborHandler) laborPricing(w http.ResponseWriter, r *http.Request, rateID, difficultyID string) (*models.LaborRate, *models.LaborDifficulty, bool) {
+	rate, difficulty, err := loadLaborPricing(r.Context(), h.laborRepo, rateID, difficultyID)
+	if err != nil {
+		var validationErr *validationError
+		if errors.As(err, &validationErr) {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return nil, nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to load labor rates")
+		return nil, nil, false
+	}
+
+	return rate, difficulty, true
+}
+
+// catalog maps every item by ID, writing the error response when it cannot
+func (h *LaborHandler) catalog(w http.ResponseWriter, r *http.Request) (map[string]*models.Item, bool) {
+	items, err := h.itemRepo.List(r.Context(), map[string]interface{}{})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list items")
+		return nil, false
+	}
+
+	byID := make(map[string]*models.Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID, true
+}
+
+// getJobLabor loads how a job's labor is priced; jobs that have never had it
+// set use the default rate with no difficulty
+func getJobLabor(ctx context.Context, laborRepo repository.LaborRepository, jobID string) (*models.JobLabor, error) {
+	settings, err := laborRepo.GetJobLabor(ctx, jobID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &models.JobLabor{JobID: jobID}, nil
+	}
+	return settings, err
+}
+
+// loadLaborPricing loads the rate, or the default rate without one, and the
+// difficulty, which is nil without one. Rates and difficulties that don't
+// exist are a *validationError.
+func loadLaborPricing(ctx context.Context, laborRepo repository.LaborRepository, rateID, difficultyID string) (*models.LaborRate, *models.LaborDifficulty, error) {
+	var rate *models.LaborRate
+	var err error
+	if rateID != "" {
+		rate, err = laborRepo.GetRate(ctx, rateID)
+	} else {
+		rate, err = laborRepo.GetDefaultRate(ctx)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		if rateID != "" {
+			return nil, nil, &validationError{msg: "labor rate " + rateID + " not found"}
+		}
+		return nil, nil, &validationError{msg: "no labor rate is the default; name a rate or make one the default"}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if difficultyID == "" {
+		return rate, nil, nil
+	}
+	difficulty, err := laborRepo.GetDifficulty(ctx, difficultyID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, &validationError{msg: "labor difficulty " + difficultyID + " not found"}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rate, difficulty, nil
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/loadcalc"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// LoadCalculationHandler handles HTTP requests for dwelling service load
+// calculations and the ones saved against jobs
+type LoadCalculationHandler struct {
+	calculationRepo repository.LoadCalculationRepository
+	jobRepo         repository.JobRepository
+}
+
+// NewLoadCalculationHandler creates a new load calculation handler
+func NewLoadCalculationHandler(calculationRepo repository.LoadCalculationRepository, jobRepo repository.JobRepository) *LoadCalculationHandler {
+	return &LoadCalculationHandler{
+		calculationRepo: calculationRepo,
+		jobRepo:         jobRepo,
+	}
+}
+
+// RegisterRoutes registers all load calculation routes
+func (h *LoadCalculationHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the load calculation endpoints; anyone may work one out or
+// read a job's, but only admins save them to a job or delete them
+func (h *LoadCalculationHandler) routes() []route {
+	return []route{
+		{"POST", "/load-calculations", h.Calculate, anyRole},
+
+		{"GET", "/jobs/{id}/load-calculations", h.List, anyRole},
+		{"POST", "/jobs/{id}/load-calculations", h.Create, adminOnly},
+		{"GET", "/jobs/{id}/load-calculations/{calculationId}", h.Get, anyRole},
+		{"DELETE", "/jobs/{id}/load-calculations/{calculationId}", h.Delete, adminOnly},
+	}
+}
+
+// loadCalculationRequest is a dwelling's loads and, when saved to a job, the
+// calculation's name
+type loadCalculationRequest struct {
+	Name string `json:"name"`
+	loadcalc.Dwelling
+}
+
+// Calculate handles POST /api/load-calculations, working out a dwelling's
+// service load without saving it
+func (h *LoadCalculationHandler) Calculate(w http.ResponseWriter, r *http.Request) {
+	var dwelling loadcalc.Dwelling
+	if err := json.NewDecoder(r.Body).Decode(&dwelling); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := loadcalc.Calculate(dwelling)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// List handles GET /api/jobs/:id/load-calculations
+func (h *LoadCalculationHandler) List(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	calculations, err := h.calculationRepo.ListByJob(r.Context(), job.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list load calculations")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, calculations)
+}
+
+// Create handles POST /api/jobs/:id/load-calculations, working out a
+// dwelling's service load and saving it to the job
+func (h *LoadCalculationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	var req loadCalculationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	calculation, err := models.NewJobLoadCalculation(job.ID, req.Name, req.Dwelling)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.calculationRepo.Create(r.Context(), calculation); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save load calculation")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, calculation)
+}
+
+// Get handles GET /api/jobs/:id/load-calculations/:calculationId
+func (h *LoadCalculationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	calculation, err := h.calculationRepo.GetByID(r.Context(), vars["id"], vars["calculationId"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Load calculation not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get load calculation")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, calculation)
+}
+
+// Delete handles DELETE /api/jobs/:id/load-calculations/:calculationId
+func (h *LoadCalculationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.calculationRepo.Delete(r.Context(), vars["id"], vars["calculationId"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Load calculation not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete load calculation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getJob loads the job named in the URL, writing the error response when it
+// cannot
+func (h *LoadCalculationHandler) getJob(w http.ResponseWriter, r *http.Request) (*models.Job, bool) {
+	job, err := h.jobRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			respondWithError(w, http.StatusNotFound, "Job not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job")
+		return nil, false
+	}
+
+	return job, true
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// Role sets used when declaring routes
+var (
+	anyRole   = []models.UserRole{models.UserRoleAdmin, models.UserRoleTech}
+	adminOnly = []models.UserRole{models.UserRoleAdmin}
+)
+
+// route describes a single endpoint and the roles allowed to call it
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+	roles   []models.UserRole
+}
+
+// registerRoutes adds routes to the router, wrapping each handler in its role check
+func registerRoutes(router *mux.Router, routes []route) {
+	for _, rt := range routes {
+		router.Handle(rt.path, middleware.RequireRole(rt.roles...)(rt.handler)).Methods(rt.method, "OPTIONS")
+	}
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// allRoutes collects the declared routes of every protected handler. Handlers
+// are built without dependencies because only their route tables are used.
+func allRoutes() []route {
+	var routes []route
+	routes = append(routes, NewAuthHandler(nil).routes()...)
+	routes = append(routes, NewUserHandler(nil).routes()...)
+	routes = append(routes, NewItemHandler(nil).routes()...)
+	routes = append(routes, NewCustomerHandler(nil).routes()...)
+	routes = append(routes, NewTemplateHandler(nil, nil, nil).routes()...)
+	routes = append(routes, NewJobHandler(nil, nil, nil, nil, nil, nil, nil, nil).routes()...)
+	routes = append(routes, NewChangeOrderHandler(nil, nil, nil).routes()...)
+	routes = append(routes, NewEstimateHandler(nil, nil, nil, nil, nil).routes()...)
+	routes = append(routes, NewCompanyHandler(nil).routes()...)
+	routes = append(routes, NewTaxHandler(nil).routes()...)
+	routes = append(routes, NewAdjustmentHandler(nil, nil).routes()...)
+	routes = append(routes, NewSupplierHandler(nil, nil, nil).routes()...)
+	routes = append(routes, NewLaborHandler(nil, nil, nil, nil).routes()...)
+	routes = append(routes, NewLoadCalculationHandler(nil, nil).routes()...)
+	routes = append(routes, NewVoltageDropHandler(nil, nil, nil, nil).routes()...)
+	routes = append(routes, NewConduitFillHandler(nil, nil, nil, nil).routes()...)
+	routes = append(routes, NewHealthHandler().routes()...)
+	return routes
+}
+
+var pathVarPattern = regexp.MustCompile(`\{[^}]+\}`)
+
+func TestRoutePermissions(t *testing.T) {
+	// The full route/role matrix. Admins may call every route; tech marks
+	// the routes techs may also call.
+	matrix := []struct {
+		method string
+		path   string
+		tech   bool
+	}{
+		// Auth
+		{"POST", "/auth/logout", true},
+		{"GET", "/auth/me", true},
+		{"PUT", "/auth/password", true},
+
+		// Users
+		{"GET", "/users", false},
+		{"POST", "/users", false},
+		{"GET", "/users/{id}", false},
+		{"PUT", "/users/{id}", false},
+		{"DELETE", "/users/{id}", false},
+
+		// Items
+		{"POST", "/items", false},
+		{"GET", "/items", true},
+		{"GET", "/items/export", false},
+		{"POST", "/items/import", false},
+		{"GET", "/items/{id}", true},
+		{"PUT", "/items/{id}", false},
+		{"DELETE", "/items/{id}", false},
+		{"GET", "/items/{id}/costs", false},
+		{"POST", "/items/{id}/costs", false},
+		{"GET", "/items/{id}/price-history", false},
+
+		// Assemblies
+		{"GET", "/assemblies", true},
+		{"POST", "/assemblies", false},
+		{"GET", "/assemblies/{id}", true},
+		{"PUT", "/assemblies/{id}", false},
+
+		// Customers
+		{"GET", "/customers", true},
+		{"POST", "/customers", false},
+		{"GET", "/customers/{id}", true},
+		{"PUT", "/customers/{id}", false},
+		{"DELETE", "/customers/{id}", false},
+
+		// Templates
+		{"GET", "/templates", true},
+		{"POST", "/templates", false},
+		{"GET", "/templates/{id}", true},
+		{"PUT", "/templates/{id}", false},
+		{"DELETE", "/templates/{id}", false},
+		{"POST", "/templates/{id}/items", false},
+		{"PUT", "/templates/{id}/items/{itemId}", false},
+		{"DELETE", "/templates/{id}/items/{itemId}", false},
+		{"GET", "/templates/{id}/versions", true},
+		{"GET", "/templates/{id}/versions/diff", true},
+		{"GET", "/templates/{id}/versions/{version}", true},
+
+		// Jobs
+		{"GET", "/jobs", true},
+		{"POST", "/jobs", false},
+		{"GET", "/jobs/{id}", true},
+		{"PUT", "/jobs/{id}", false},
+		{"DELETE", "/jobs/{id}", false},
+		{"PUT", "/jobs/{id}/phase", true},
+		{"GET", "/jobs/{id}/phases", true},
+		{"POST", "/jobs/{id}/phases/{phaseId}/complete", true},
+		{"POST", "/jobs/{id}/phases/{phaseId}/reopen", false},
+		{"PUT", "/jobs/{id}/status", false},
+		{"GET", "/jobs/{id}/status-history", true},
+		{"POST", "/jobs/{id}/items", false},
+		{"PUT", "/jobs/{id}/items/{itemId}", true},
+		{"DELETE", "/jobs/{id}/items/{itemId}", false},
+		{"POST", "/jobs/reprice", false},
+		{"GET", "/jobs/{id}/variance", false},
+		{"GET", "/jobs/{id}/tax", true},
+		{"GET", "/jobs/{id}/profitability", false},
+		{"GET", "/reports/template-profitability", false},
+		{"GET", "/jobs/{id}/adjustments", true},
+		{"POST", "/jobs/{id}/adjustments", false},
+		{"DELETE", "/jobs/{id}/adjustments/{adjustmentId}", false},
+		{"GET", "/jobs/{id}/revisions", true},
+		{"POST", "/jobs/{id}/revisions", false},
+		{"GET", "/jobs/{id}/revisions/diff", true},
+		{"GET", "/jobs/{id}/revisions/{number}", true},
+		{"POST", "/jobs/{id}/photos", true},
+		{"DELETE", "/jobs/{id}/photos/{photoId}", false},
+		{"POST", "/jobs/{id}/send-to-wave", false},
+
+		// Change orders
+		{"GET", "/jobs/{id}/change-orders", true},
+		{"POST", "/jobs/{id}/change-orders", true},
+		{"GET", "/jobs/{id}/change-orders/{changeOrderId}", true},
+		{"PUT", "/jobs/{id}/change-orders/{changeOrderId}", false},
+		{"DELETE", "/jobs/{id}/change-orders/{changeOrderId}", false},
+		{"POST", "/jobs/{id}/change-orders/{changeOrderId}/approve", false},
+		{"POST", "/jobs/{id}/change-orders/{changeOrderId}/reject", false},
+		{"POST", "/jobs/{id}/change-orders/{changeOrderId}/sign-off", true},
+
+		// Estimates
+		{"GET", "/estimates", false},
+		{"POST", "/estimates", false},
+		{"GET", "/estimates/{id}", false},
+		{"PUT", "/estimates/{id}", false},
+		{"DELETE", "/estimates/{id}", false},
+		{"POST", "/estimates/{id}/send", false},
+		{"POST", "/estimates/{id}/accept", false},
+		{"POST", "/estimates/{id}/reject", false},
+		{"POST", "/estimates/{id}/revise", false},
+
+		// Company
+		{"GET", "/company", true},
+		{"PUT", "/company", false},
+
+		// Tax
+		{"GET", "/tax/rates", false},
+		{"POST", "/tax/rates", false},
+		{"GET", "/tax/rates/{id}", false},
+		{"PUT", "/tax/rates/{id}", false},
+		{"DELETE", "/tax/rates/{id}", false},
+		{"GET", "/tax/categories", false},
+		{"PUT", "/tax/categories/{category}", false},
+
+		// Adjustments
+		{"GET", "/adjustments", false},
+		{"POST", "/adjustments", false},
+		{"GET", "/adjustments/{id}", false},
+		{"PUT", "/adjustments/{id}", false},
+		{"DELETE", "/adjustments/{id}", false},
+
+		// Suppliers
+		{"GET", "/suppliers", false},
+		{"POST", "/suppliers", false},
+		{"GET", "/suppliers/{id}", false},
+		{"PUT", "/suppliers/{id}", false},
+		{"DELETE", "/suppliers/{id}", false},
+		{"GET", "/suppliers/{id}/items", false},
+		{"POST", "/suppliers/{id}/items", false},
+		{"DELETE", "/suppliers/{id}/items/{linkId}", false},
+		{"GET", "/suppliers/{id}/imports", false},
+		{"POST", "/suppliers/{id}/imports", false},
+		{"GET", "/suppliers/{id}/imports/{importId}", false},
+		{"GET", "/suppliers/{id}/imports/{importId}/changes", false},
+		{"POST", "/suppliers/{id}/imports/{importId}/apply", false},
+
+		// Labor
+		{"GET", "/labor/rates", false},
+		{"POST", "/labor/rates", false},
+		{"GET", "/labor/rates/{id}", false},
+		{"PUT", "/labor/rates/{id}", false},
+		{"DELETE", "/labor/rates/{id}", false},
+		{"GET", "/labor/difficulties", false},
+		{"POST", "/labor/difficulties", false},
+		{"GET", "/labor/difficulties/{id}", false},
+		{"PUT", "/labor/difficulties/{id}", false},
+		{"DELETE", "/labor/difficulties/{id}", false},
+		{"GET", "/jobs/{id}/labor", false},
+		{"PUT", "/jobs/{id}/labor", false},
+		{"GET", "/templates/{id}/labor", false},
+
+		// Load calculations
+		{"POST", "/load-calculations", true},
+		{"GET", "/jobs/{id}/load-calculations", true},
+		{"POST", "/jobs/{id}/load-calculations", false},
+		{"GET", "/jobs/{id}/load-calculations/{calculationId}", true},
+		{"DELETE", "/jobs/{id}/load-calculations/{calculationId}", false},
+
+		// Voltage drop
+		{"POST", "/voltage-drop", true},
+		{"GET", "/wire-items", false},
+		{"PUT", "/wire-items", false},
+		{"DELETE", "/wire-items/{itemId}", false},
+		{"POST", "/jobs/{id}/voltage-drop", false},
+
+		// Conduit fill
+		{"POST", "/conduit-fill", true},
+		{"GET", "/conduit-fill/conductors", true},
+		{"POST", "/conduit-fill/conductors", false},
+		{"PUT", "/conduit-fill/conductors/{id}", false},
+		{"DELETE", "/conduit-fill/conductors/{id}", false},
+		{"GET", "/conduit-fill/conduits", true},
+		{"POST", "/conduit-fill/conduits", false},
+		{"PUT", "/conduit-fill/conduits/{id}", false},
+		{"DELETE", "/conduit-fill/conduits/{id}", false},
+		{"POST", "/jobs/{id}/conduit-fill", false},
+		{"POST", "/templates/{id}/conduit-fill", false},
+
+		// Health
+		{"GET", "/health/wave", true},
+		{"GET", "/health/cloudflare", true},
+	}
+
+	routes := allRoutes()
+
+	// Every declared route must be in the matrix and vice versa
+	declared := make(map[string]bool, len(routes))
+	for _, rt := range routes {
+		key := rt.method + " " + rt.path
+		if declared[key] {
+			t.Errorf("route %s declared more than once", key)
+		}
+		declared[key] = true
+	}
+	expected := make(map[string]bool, len(matrix))
+	for _, m := range matrix {
+		key := m.method + " " + m.path
+		expected[key] = true
+		if !declared[key] {
+			t.Errorf("route %s is in the matrix but not declared", key)
+		}
+	}
+	for key := range declared {
+		if !expected[key] {
+			t.Errorf("route %s is declared but missing from the matrix", key)
+		}
+	}
+
+	// Register every route with a stub handler behind the real role checks
+	router := mux.NewRouter()
+	stubbed := make([]route, 0, len(routes))
+	for _, rt := range routes {
+		rt.handler = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}
+		stubbed = append(stubbed, rt)
+	}
+	registerRoutes(router, stubbed)
+
+	users := map[string]*models.User{
+		"admin": {ID: "admin-id", Role: models.UserRoleAdmin, IsActive: true},
+		"tech":  {ID: "tech-id", Role: models.UserRoleTech, IsActive: true},
+		"none":  nil,
+	}
+
+	for _, m := range matrix {
+		for roleName, user := range users {
+			t.Run(m.method+" "+m.path+" as "+roleName, func(t *testing.T) {
+				url := pathVarPattern.ReplaceAllString(m.path, "x1")
+				req := httptest.NewRequest(m.method, url, nil)
+				if user != nil {
+					req = req.WithContext(middleware.WithUser(req.Context(), user))
+				}
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				expectedCode := http.StatusForbidden
+				switch {
+				case user == nil:
+					expectedCode = http.StatusUnauthorized
+				case user.Role == models.UserRoleAdmin:
+					expectedCode = http.StatusOK
+				case user.Role == models.UserRoleTech && m.tech:
+					expectedCode = http.StatusOK
+				}
+
+				if w.Code != expectedCode {
+					t.Fatalf("expected status code %d, got %d", expectedCode, w.Code)
+				}
+
+				if w.Code == http.StatusForbidden {
+					var response map[string]string
+					if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+						t.Fatalf("failed to decode response: %v", err)
+					}
+					if response["error"] == "" {
+						t.Error("expected error message in response")
+					}
+				}
+			})
+		}
+	}
+}
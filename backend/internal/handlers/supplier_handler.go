@@ -0,0 +1,569 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// maxSupplierPriceFileSize caps the size of an uploaded supplier price file
+const maxSupplierPriceFileSize = 20 << 20
+
+// SupplierHandler handles HTTP requests for suppliers, their SKU links and the
+// price files they send
+type SupplierHandler struct {
+	uow          repository.UnitOfWork
+	supplierRepo repository.SupplierRepository
+	itemRepo     repository.ItemRepository
+}
+
+// NewSupplierHandler creates a new supplier handler
+func NewSupplierHandler(uow repository.UnitOfWork, supplierRepo repository.SupplierRepository, itemRepo repository.ItemRepository) *SupplierHandler {
+	return &SupplierHandler{
+		uow:          uow,
+		supplierRepo: supplierRepo,
+		itemRepo:     itemRepo,
+	}
+}
+
+// RegisterRoutes registers all supplier routes
+func (h *SupplierHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the supplier endpoints; costs are admin-only
+func (h *SupplierHandler) routes() []route {
+	return []route{
+		{"GET", "/suppliers", h.List, adminOnly},
+		{"POST", "/suppliers", h.Create, adminOnly},
+		{"GET", "/suppliers/{id}", h.Get, adminOnly},
+		{"PUT", "/suppliers/{id}", h.Update, adminOnly},
+		{"DELETE", "/suppliers/{id}", h.Delete, adminOnly},
+
+		{"GET", "/suppliers/{id}/items", h.ListLinks, adminOnly},
+		{"POST", "/suppliers/{id}/items", h.AddLink, adminOnly},
+		{"DELETE", "/suppliers/{id}/items/{linkId}", h.RemoveLink, adminOnly},
+
+		{"GET", "/suppliers/{id}/imports", h.ListImports, adminOnly},
+		{"POST", "/suppliers/{id}/imports", h.Import, adminOnly},
+		{"GET", "/suppliers/{id}/imports/{importId}", h.GetImport, adminOnly},
+		{"GET", "/suppliers/{id}/imports/{importId}/changes", h.Changes, adminOnly},
+		{"POST", "/suppliers/{id}/imports/{importId}/apply", h.Apply, adminOnly},
+	}
+}
+
+// supplierRequest is a requested supplier
+type supplierRequest struct {
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Phone         string `json:"phone"`
+	AccountNumber string `json:"accountNumber"`
+}
+
+// supplierImportResponse is a staged price file with the rows of the file
+// that could not be staged
+type supplierImportResponse struct {
+	Import    *models.SupplierPriceImport    `json:"import,omitempty"`
+	RowErrors []models.SupplierPriceRowError `json:"rowErrors"`
+}
+
+// supplierChangesResponse reports the cost changes in a price file against
+// the supplier's previous one
+type supplierChangesResponse struct {
+	ImportID         string                      `json:"importId"`
+	PreviousImportID string                      `json:"previousImportId,omitempty"`
+	Threshold        float64                     `json:"threshold"`
+	MarkupPercent    *float64                    `json:"markupPercent,omitempty"`
+	Changes          []models.SupplierCostChange `json:"changes"`
+}
+
+// supplierApplyRequest is how to apply a price file. With a markup, items
+// whose cost moved by more than the threshold are re-priced at cost plus the
+// markup.
+type supplierApplyRequest struct {
+	Threshold     float64  `json:"threshold"`
+	MarkupPercent *float64 `json:"markupPercent"`
+}
+
+// supplierApplyResponse is the outcome of applying a price file
+type supplierApplyResponse struct {
+	Import        *models.SupplierPriceImport `json:"import"`
+	CostsRecorded int                         `json:"costsRecorded"`
+	Repriced      []models.SupplierCostChange `json:"repriced"`
+}
+
+// List handles GET /api/suppliers
+func (h *SupplierHandler) List(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.supplierRepo.List(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list suppliers")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, suppliers)
+}
+
+// Get handles GET /api/suppliers/:id
+func (h *SupplierHandler) Get(w http.ResponseWriter, r *http.Request) {
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, supplier)
+}
+
+// Create handles POST /api/suppliers
+func (h *SupplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req supplierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	supplier, err := models.NewSupplier(req.Name, req.Email, req.Phone, req.AccountNumber)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.supplierRepo.Create(r.Context(), supplier); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A supplier with this name already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create supplier")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, supplier)
+}
+
+// Update handles PUT /api/suppliers/:id
+func (h *SupplierHandler) Update(w http.ResponseWriter, r *http.Request) {
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	var req supplierRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := supplier.Update(req.Name, req.Email, req.Phone, req.AccountNumber); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.supplierRepo.Update(r.Context(), supplier); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A supplier with this name already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update supplier")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, supplier)
+}
+
+// Delete handles DELETE /api/suppliers/:id. The supplier's SKU links and
+// price files go with it; costs already recorded on items stay.
+func (h *SupplierHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.supplierRepo.Delete(r.Context(), mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Supplier not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete supplier")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListLinks handles GET /api/suppliers/:id/items
+func (h *SupplierHandler) ListLinks(w http.ResponseWriter, r *http.Request) {
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	links, err := h.supplierRepo.ListLinks(r.Context(), supplier.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list supplier items")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, links)
+}
+
+// AddLink handles POST /api/suppliers/:id/items, linking one of the
+// supplier's SKUs to an item
+func (h *SupplierHandler) AddLink(w http.ResponseWriter, r *http.Request) {
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		SKU    string `json:"sku"`
+		ItemID string `json:"itemId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	link, err := models.NewSupplierItem(supplier.ID, req.SKU, req.ItemID)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := h.itemRepo.GetByID(r.Context(), link.ItemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusBadRequest, "Item not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get item")
+		return
+	}
+	link.ItemName = item.Name
+
+	if err := h.supplierRepo.AddLink(r.Context(), link); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "SKU "+link.SKU+" is already linked to an item")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to link supplier item")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, link)
+}
+
+// RemoveLink handles DELETE /api/suppliers/:id/items/:linkId
+func (h *SupplierHandler) RemoveLink(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.supplierRepo.RemoveLink(r.Context(), vars["id"], vars["linkId"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Supplier item not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to remove supplier item")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListImports handles GET /api/suppliers/:id/imports
+func (h *SupplierHandler) ListImports(w http.ResponseWriter, r *http.Request) {
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	imports, err := h.supplierRepo.ListImports(r.Context(), supplier.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list price files")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, imports)
+}
+
+// Import handles POST /api/suppliers/:id/imports. The price file is the
+// request body, named by ?fileName=, or the "file" field of a multipart form.
+// Its valid rows are staged, and the rows that are not are reported; costs
+// only reach items when the import is applied.
+func (h *SupplierHandler) Import(w http.ResponseWriter, r *http.Request) {
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSupplierPriceFileSize)
+	var file io.Reader = r.Body
+	fileName := r.URL.Query().Get("fileName")
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, header, err := r.FormFile("file")
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Upload the price file as the file field")
+			return
+		}
+		defer upload.Close()
+		file, fileName = upload, header.Filename
+	}
+
+	prices, rowErrors, err := models.ParseSupplierPriceFile(file)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		var csvErr *models.CSVError
+		switch {
+		case errors.As(err, &tooLarge):
+			respondWithError(w, http.StatusRequestEntityTooLarge, "The file is too large")
+		case errors.As(err, &csvErr):
+			respondWithError(w, http.StatusBadRequest, csvErr.Error())
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to read the price file")
+		}
+		return
+	}
+	if len(prices) == 0 {
+		respondWithJSON(w, http.StatusUnprocessableEntity, supplierImportResponse{RowErrors: rowErrors})
+		return
+	}
+
+	priceImport := models.NewSupplierPriceImport(supplier.ID, fileName, prices)
+	if err := h.supplierRepo.CreateImport(r.Context(), priceImport); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to stage the price file")
+		return
+	}
+
+	priceImport.Prices = nil
+	respondWithJSON(w, http.StatusCreated, supplierImportResponse{Import: priceImport, RowErrors: rowErrors})
+}
+
+// GetImport handles GET /api/suppliers/:id/imports/:importId
+func (h *SupplierHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	priceImport, ok := h.getImport(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, priceImport)
+}
+
+// Changes handles GET /api/suppliers/:id/imports/:importId/changes: the
+// linked items whose cost moved by more than ?threshold= percent (default 0)
+// since the supplier's previous price file. With ?markup= each change carries
+// the price that marks the new cost up by that percent.
+func (h *SupplierHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var req supplierApplyRequest
+	if value := query.Get("threshold"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 {
+			respondWithError(w, http.StatusBadRequest, "threshold must be a percent of 0 or more")
+			return
+		}
+		req.Threshold = threshold
+	}
+	if value := query.Get("markup"); value != "" {
+		markup, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "markup must be a percent")
+			return
+		}
+		req.MarkupPercent = &markup
+	}
+
+	priceImport, ok := h.getImport(w, r)
+	if !ok {
+		return
+	}
+
+	report, ok := h.costChanges(w, r, priceImport, req)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+// Apply handles POST /api/suppliers/:id/imports/:importId/apply. Every linked
+// SKU whose cost differs from its item's current cost is recorded in the
+// item's cost history under the supplier's name, effective the day the file
+// was imported. With a markupPercent, items whose cost moved by more than the
+// threshold since the previous file are also re-priced at cost plus the
+// markup, which records the change in their price history. It all happens
+// together, and a file can only be applied once.
+func (h *SupplierHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	var req supplierApplyRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			respondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.Threshold < 0 {
+		respondWithError(w, http.StatusBadRequest, "threshold must be a percent of 0 or more")
+		return
+	}
+
+	supplier, ok := h.getSupplier(w, r)
+	if !ok {
+		return
+	}
+	priceImport, ok := h.getImport(w, r)
+	if !ok {
+		return
+	}
+	if priceImport.AppliedAt != nil {
+		respondWithError(w, http.StatusConflict, "This price file has already been applied")
+		return
+	}
+
+	report, ok := h.costChanges(w, r, priceImport, req)
+	if !ok {
+		return
+	}
+
+	links, items, err := h.linkedItems(r, supplier.ID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load supplier items")
+		return
+	}
+	itemsBySKU := make(map[string]*models.Item, len(links))
+	for _, link := range links {
+		itemsBySKU[link.SKU] = items[link.ItemID]
+	}
+
+	result := supplierApplyResponse{Import: priceImport, Repriced: []models.SupplierCostChange{}}
+	now := time.Now()
+	err = h.uow.WithTx(r.Context(), func(repos repository.Repositories) error {
+		for _, price := range priceImport.Prices {
+			item := itemsBySKU[price.SKU]
+			if item == nil || item.UnitCost == price.Cost {
+				continue
+			}
+			cost, err := models.NewItemCost(item.ID, supplier.Name, price.Cost, priceImport.ImportedAt)
+			if err != nil {
+				return &validationError{msg: err.Error()}
+			}
+			if err := repos.Items.AddCost(r.Context(), cost); err != nil {
+				return err
+			}
+			result.CostsRecorded++
+		}
+
+		if req.MarkupPercent != nil {
+			for _, change := range report.Changes {
+				if change.DerivedPrice == nil || *change.DerivedPrice == change.UnitPrice {
+					continue
+				}
+				updates := map[string]interface{}{"unit_price": *change.DerivedPrice}
+				if err := repos.Items.Update(r.Context(), change.ItemID, updates); err != nil {
+					return err
+				}
+				result.Repriced = append(result.Repriced, change)
+			}
+		}
+
+		return repos.Suppliers.MarkImportApplied(r.Context(), priceImport.ID, now)
+	})
+	if err != nil {
+		var validationErr *validationError
+		switch {
+		case errors.As(err, &validationErr):
+			respondWithError(w, http.StatusBadRequest, validationErr.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			respondWithError(w, http.StatusConflict, "This price file has already been applied")
+		default:
+			respondWithError(w, http.StatusInternalServerError, "Failed to apply the price file")
+		}
+		return
+	}
+
+	priceImport.AppliedAt = &now
+	priceImport.Prices = nil
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// costChanges compares a price file with the supplier's previous one, writing
+// the error response when it cannot. A supplier's first file has no changes.
+func (h *SupplierHandler) costChanges(w http.ResponseWriter, r *http.Request, priceImport *models.SupplierPriceImport, req supplierApplyRequest) (*supplierChangesResponse, bool) {
+	report := &supplierChangesResponse{
+		ImportID:      priceImport.ID,
+		Threshold:     req.Threshold,
+		MarkupPercent: req.MarkupPercent,
+		Changes:       []models.SupplierCostChange{},
+	}
+
+	previous, err := h.supplierRepo.GetPreviousImport(r.Context(), priceImport)
+	if errors.Is(err, repository.ErrNotFound) {
+		return report, true
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get the previous price file")
+		return nil, false
+	}
+	report.PreviousImportID = previous.ID
+
+	links, items, err := h.linkedItems(r, priceImport.SupplierID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to load supplier items")
+		return nil, false
+	}
+
+	report.Changes = models.SupplierCostChanges(previous.Prices, priceImport.Prices, links, items, req.Threshold, req.MarkupPercent)
+	return report, true
+}
+
+// linkedItems loads a supplier's SKU links and the items they link to, by ID
+func (h *SupplierHandler) linkedItems(r *http.Request, supplierID string) ([]models.SupplierItem, map[string]*models.Item, error) {
+	links, err := h.supplierRepo.ListLinks(r.Context(), supplierID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make(map[string]*models.Item, len(links))
+	for _, link := range links {
+		if _, ok := items[link.ItemID]; ok {
+			continue
+		}
+		item, err := h.itemRepo.GetByID(r.Context(), link.ItemID)
+		if err != nil {
+			return nil, nil, err
+		}
+		items[item.ID] = item
+	}
+
+	return links, items, nil
+}
+
+// getSupplier loads the supplier named in the URL, writing the error response
+// when it cannot
+func (h *SupplierHandler) getSupplier(w http.ResponseWriter, r *http.Request) (*models.Supplier, bool) {
+	supplier, err := h.supplierRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Supplier not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get supplier")
+		return nil, false
+	}
+
+	return supplier, true
+}
+
+// getImport loads the price file named in the URL, with its prices, writing
+// the error response when it cannot
+func (h *SupplierHandler) getImport(w http.ResponseWriter, r *http.Request) (*models.SupplierPriceImport, bool) {
+	vars := mux.Vars(r)
+	priceImport, err := h.supplierRepo.GetImport(r.Context(), vars["id"], vars["importId"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Price file not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get price file")
+		return nil, false
+	}
+
+	return priceImport, true
+}
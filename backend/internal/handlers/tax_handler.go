@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// TaxHandler handles HTTP requests for sales tax rates and category taxability
+type TaxHandler struct {
+	taxRepo repository.TaxRepository
+}
+
+// NewTaxHandler creates a new tax handler
+func NewTaxHandler(taxRepo repository.TaxRepository) *TaxHandler {
+	return &TaxHandler{
+		taxRepo: taxRepo,
+	}
+}
+
+// RegisterRoutes registers all tax routes
+func (h *TaxHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the tax endpoints; tax settings are admin-only
+func (h *TaxHandler) routes() []route {
+	return []route{
+		{"GET", "/tax/rates", h.ListRates, adminOnly},
+		{"POST", "/tax/rates", h.CreateRate, adminOnly},
+		{"GET", "/tax/rates/{id}", h.GetRate, adminOnly},
+		{"PUT", "/tax/rates/{id}", h.UpdateRate, adminOnly},
+		{"DELETE", "/tax/rates/{id}", h.DeleteRate, adminOnly},
+
+		{"GET", "/tax/categories", h.ListCategories, adminOnly},
+		{"PUT", "/tax/categories/{category}", h.SetCategory, adminOnly},
+	}
+}
+
+// taxRateRequest is a requested tax rate. Dates are YYYY-MM-DD.
+type taxRateRequest struct {
+	Name           string  `json:"name"`
+	State          string  `json:"state"`
+	City           string  `json:"city"`
+	Zip            string  `json:"zip"`
+	Rate           float64 `json:"rate"`
+	WaveSalesTaxID string  `json:"waveSalesTaxId"`
+	EffectiveFrom  string  `json:"effectiveFrom"`
+	EffectiveTo    string  `json:"effectiveTo"`
+}
+
+// dates parses the request's effective dates
+func (req *taxRateRequest) dates() (time.Time, *time.Time, error) {
+	from, err := parseDate(req.EffectiveFrom)
+	if err != nil {
+		return time.Time{}, nil, errors.New("effective from must be a date like 2006-01-02")
+	}
+	if req.EffectiveTo == "" {
+		return from, nil, nil
+	}
+	to, err := parseDate(req.EffectiveTo)
+	if err != nil {
+		return time.Time{}, nil, errors.New("effective to must be a date like 2006-01-02")
+	}
+	return from, &to, nil
+}
+
+// ListRates handles GET /api/tax/rates. With ?address= it lists only the rates
+// that apply to that address on ?date= (default today).
+func (h *TaxHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.taxRepo.ListRates(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list tax rates")
+		return
+	}
+
+	query := r.URL.Query()
+	if address := query.Get("address"); address != "" {
+		on := time.Now()
+		if query.Get("date") != "" {
+			if on, err = parseDate(query.Get("date")); err != nil {
+				respondWithError(w, http.StatusBadRequest, "date must be a date like 2006-01-02")
+				return
+			}
+		}
+		rates = models.ApplicableTaxRates(rates, models.ParseTaxAddress(address), on)
+	}
+
+	respondWithJSON(w, http.StatusOK, rates)
+}
+
+// GetRate handles GET /api/tax/rates/:id
+func (h *TaxHandler) GetRate(w http.ResponseWriter, r *http.Request) {
+	rate, ok := h.getRate(w, r)
+	if !ok {
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rate)
+}
+
+// CreateRate handles POST /api/tax/rates
+func (h *TaxHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
+	var req taxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	from, to, err := req.dates()
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rate, err := models.NewTaxRate(req.Name, req.State, req.City, req.Zip, req.Rate, req.WaveSalesTaxID, from, to)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.checkOverlap(w, r, rate) {
+		return
+	}
+
+	if err := h.taxRepo.CreateRate(r.Context(), rate); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create tax rate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, rate)
+}
+
+// UpdateRate handles PUT /api/tax/rates/:id. To change a rate from a date,
+// end the old rate with effectiveTo and create a new one rather than editing
+// the old rate, so past jobs keep the rate they were charged.
+func (h *TaxHandler) UpdateRate(w http.ResponseWriter, r *http.Request) {
+	rate, ok := h.getRate(w, r)
+	if !ok {
+		return
+	}
+
+	var req taxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	from, to, err := req.dates()
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := rate.Update(req.Name, req.State, req.City, req.Zip, req.Rate, req.WaveSalesTaxID, from, to); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.checkOverlap(w, r, rate) {
+		return
+	}
+
+	if err := h.taxRepo.UpdateRate(r.Context(), rate); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to update tax rate")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, rate)
+}
+
+// DeleteRate handles DELETE /api/tax/rates/:id
+func (h *TaxHandler) DeleteRate(w http.ResponseWriter, r *http.Request) {
+	if err := h.taxRepo.DeleteRate(r.Context(), mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Tax rate not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete tax rate")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCategories handles GET /api/tax/categories
+func (h *TaxHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.taxRepo.ListCategories(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list tax categories")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, categories)
+}
+
+// SetCategory handles PUT /api/tax/categories/:category
+func (h *TaxHandler) SetCategory(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Taxable *bool `json:"taxable"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Taxable == nil {
+		respondWithError(w, http.StatusBadRequest, "taxable is required")
+		return
+	}
+
+	category := &models.TaxCategory{
+		Category:  strings.TrimSpace(mux.Vars(r)["category"]),
+		Taxable:   *req.Taxable,
+		UpdatedAt: time.Now(),
+	}
+	if category.Category == "" {
+		respondWithError(w, http.StatusBadRequest, "category is required")
+		return
+	}
+
+	if err := h.taxRepo.SetCategory(r.Context(), category); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save tax category")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, category)
+}
+
+// getRate loads the tax rate named in the URL, writing the error response
+// when it cannot
+func (h *TaxHandler) getRate(w http.ResponseWriter, r *http.Request) (*models.TaxRate, bool) {
+	rate, err := h.taxRepo.GetRate(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Tax rate not found")
+			return nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get tax rate")
+		return nil, false
+	}
+
+	return rate, true
+}
+
+// checkOverlap rejects a rate that covers the same jurisdiction on the same
+// days as another, which would charge the tax twice
+func (h *TaxHandler) checkOverlap(w http.ResponseWriter, r *http.Request, rate *models.TaxRate) bool {
+	rates, err := h.taxRepo.ListRates(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to check tax rates")
+		return false
+	}
+
+	for i := range rates {
+		if rate.Overlaps(&rates[i]) {
+			respondWithError(w, http.StatusConflict, "overlaps tax rate "+rates[i].Name+"; end it with effectiveTo first")
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseDate parses a YYYY-MM-DD date
+func parseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
@@ -2,11 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -16,13 +16,15 @@ import (
 
 // TemplateHandler handles HTTP requests for job templates
 type TemplateHandler struct {
+	uow          repository.UnitOfWork
 	templateRepo repository.JobTemplateRepository
 	itemRepo     repository.ItemRepository
 }
 
 // NewTemplateHandler creates a new template handler
-func NewTemplateHandler(templateRepo repository.JobTemplateRepository, itemRepo repository.ItemRepository) *TemplateHandler {
+func NewTemplateHandler(uow repository.UnitOfWork, templateRepo repository.JobTemplateRepository, itemRepo repository.ItemRepository) *TemplateHandler {
 	return &TemplateHandler{
+		uow:          uow,
 		templateRepo: templateRepo,
 		itemRepo:     itemRepo,
 	}
@@ -30,16 +32,29 @@ func NewTemplateHandler(templateRepo repository.JobTemplateRepository, itemRepo
 
 // RegisterRoutes registers all template routes
 func (h *TemplateHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/templates", h.List).Methods("GET", "OPTIONS")
-	router.HandleFunc("/templates", h.Create).Methods("POST", "OPTIONS")
-	router.HandleFunc("/templates/{id}", h.Get).Methods("GET", "OPTIONS")
-	router.HandleFunc("/templates/{id}", h.Update).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/templates/{id}", h.Delete).Methods("DELETE", "OPTIONS")
-	
-	// Template items
-	router.HandleFunc("/templates/{id}/items", h.AddItem).Methods("POST", "OPTIONS")
-	router.HandleFunc("/templates/{id}/items/{itemId}", h.UpdateItem).Methods("PUT", "OPTIONS")
-	router.HandleFunc("/templates/{id}/items/{itemId}", h.RemoveItem).Methods("DELETE", "OPTIONS")
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the template endpoints and who may call them. Techs can read
+// templates to show phases; only admins can change them.
+func (h *TemplateHandler) routes() []route {
+	return []route{
+		{"GET", "/templates", h.List, anyRole},
+		{"POST", "/templates", h.Create, adminOnly},
+		{"GET", "/templates/{id}", h.Get, anyRole},
+		{"PUT", "/templates/{id}", h.Update, adminOnly},
+		{"DELETE", "/templates/{id}", h.Delete, adminOnly},
+
+		// Template items
+		{"POST", "/templates/{id}/items", h.AddItem, adminOnly},
+		{"PUT", "/templates/{id}/items/{itemId}", h.UpdateItem, adminOnly},
+		{"DELETE", "/templates/{id}/items/{itemId}", h.RemoveItem, adminOnly},
+
+		// Template versions
+		{"GET", "/templates/{id}/versions", h.ListVersions, anyRole},
+		{"GET", "/templates/{id}/versions/diff", h.DiffVersions, anyRole},
+		{"GET", "/templates/{id}/versions/{version}", h.GetVersion, anyRole},
+	}
 }
 
 // List returns all templates
@@ -177,26 +192,18 @@ func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
 	
 	log.Printf("Updating template %s", id)
 	
-	// Get existing template
-	template, err := h.templateRepo.GetByID(ctx, id)
-	if err != nil {
-		if err.Error() == "template not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	
 	var req struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
 		IsActive    *bool  `json:"isActive"`
 		Phases      []struct {
+			ID          string `json:"id,omitempty"`
 			Name        string `json:"name"`
 			Order       int    `json:"order"`
 			Description string `json:"description,omitempty"`
 		} `json:"phases,omitempty"`
+		// PhaseReassignments maps removed phase IDs to the phase their jobs move to
+		PhaseReassignments map[string]string `json:"phaseReassignments,omitempty"`
 	}
 	
 	// Read body for debugging
@@ -217,53 +224,77 @@ func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
 	
 	log.Printf("Update request: name=%s, phases=%d", req.Name, len(req.Phases))
 	
-	// Update fields
-	if req.Name != "" {
-		template.Name = req.Name
-	}
-	
-	// Allow empty description to clear it
-	template.Description = req.Description
-	
-	if req.IsActive != nil {
-		if *req.IsActive {
-			template.Activate()
-		} else {
-			template.Deactivate()
+	// Read, edit and save the template in one transaction so phase edits are
+	// checked against the phases being replaced
+	var updatedTemplate *models.JobTemplate
+	err = h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		template, err := repos.Templates.GetByID(ctx, id)
+		if err != nil {
+			return err
 		}
-	}
-	
-	// Update phases if provided
-	if req.Phases != nil {
-		log.Printf("Updating template phases: %d phases", len(req.Phases))
-		template.Phases = make([]models.TemplatePhase, 0, len(req.Phases))
-		for i, reqPhase := range req.Phases {
-			phase := models.TemplatePhase{
-				ID:          uuid.New().String(),
-				TemplateID:  template.ID,
-				Name:        reqPhase.Name,
-				Order:       reqPhase.Order,
-				Description: reqPhase.Description,
+		
+		// Update fields
+		if req.Name != "" {
+			template.Name = req.Name
+		}
+		
+		// Allow empty description to clear it
+		template.Description = req.Description
+		
+		if req.IsActive != nil {
+			if *req.IsActive {
+				template.Activate()
+			} else {
+				template.Deactivate()
 			}
-			log.Printf("Adding phase %d: ID=%s, Name=%s, Order=%d", i, phase.ID, phase.Name, phase.Order)
-			template.Phases = append(template.Phases, phase)
 		}
-		template.UpdatedAt = time.Now()
-	} else {
-		log.Printf("No phases in update request")
-	}
-	
-	if err := h.templateRepo.Update(ctx, template); err != nil {
-		log.Printf("Failed to update template: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	
-	// Reload template to get all associated data
-	updatedTemplate, err := h.templateRepo.GetByID(ctx, template.ID)
+		
+		// Update phases if provided. Phases sent with an ID keep their identity
+		// so jobs on them are unaffected.
+		if req.Phases != nil {
+			log.Printf("Updating template phases: %d phases", len(req.Phases))
+			phases := make([]models.TemplatePhase, 0, len(req.Phases))
+			for _, reqPhase := range req.Phases {
+				phases = append(phases, models.TemplatePhase{
+					ID:          reqPhase.ID,
+					Name:        reqPhase.Name,
+					Order:       reqPhase.Order,
+					Description: reqPhase.Description,
+				})
+			}
+			
+			removed, err := template.ReplacePhases(phases)
+			if err != nil {
+				return &validationError{msg: err.Error()}
+			}
+			if err := template.CheckPhaseReassignments(removed, req.PhaseReassignments); err != nil {
+				return &validationError{msg: err.Error()}
+			}
+		} else {
+			log.Printf("No phases in update request")
+		}
+		
+		if err := repos.Templates.Update(ctx, template, req.PhaseReassignments); err != nil {
+			return err
+		}
+		
+		// Reload template to get all associated data
+		updatedTemplate, err = repos.Templates.GetByID(ctx, template.ID)
+		return err
+	})
 	if err != nil {
-		log.Printf("Failed to reload template: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Failed to update template: %v", err)
+		var validationErr *validationError
+		switch {
+		case errors.As(err, &validationErr):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repository.ErrPhaseInUse):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case err.Error() == "template not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 	
@@ -429,4 +460,96 @@ func (h *TemplateHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	w.WriteHeader(http.StatusNoContent)
-}
\ No newline at end of file
+}
+
+// ListVersions returns every saved version of a template, oldest first
+func (h *TemplateHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateID := mux.Vars(r)["id"]
+	
+	if _, err := h.templateRepo.GetByID(ctx, templateID); err != nil {
+		if err.Error() == "template not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	versions, err := h.templateRepo.ListVersions(ctx, templateID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	
+	respondJSON(w, versions)
+}
+
+// GetVersion returns a single template version
+func (h *TemplateHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	
+	version, ok := h.loadVersion(w, r, vars["id"], vars["version"])
+	if !ok {
+		return
+	}
+	
+	respondJSON(w, version)
+}
+
+// DiffVersions returns the item and phase differences between two versions,
+// given as ?from=N&to=M. Omitting to compares against the latest version.
+func (h *TemplateHandler) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateID := mux.Vars(r)["id"]
+	query := r.URL.Query()
+	
+	if query.Get("from") == "" {
+		http.Error(w, "from version is required", http.StatusBadRequest)
+		return
+	}
+	
+	from, ok := h.loadVersion(w, r, templateID, query.Get("from"))
+	if !ok {
+		return
+	}
+	
+	to := query.Get("to")
+	if to == "" {
+		template, err := h.templateRepo.GetByID(ctx, templateID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		to = strconv.Itoa(template.Version)
+	}
+	
+	toVersion, ok := h.loadVersion(w, r, templateID, to)
+	if !ok {
+		return
+	}
+	
+	respondJSON(w, models.DiffTemplateVersions(from, toVersion))
+}
+
+// loadVersion fetches a template version by its number. It writes an error
+// response and returns false on failure.
+func (h *TemplateHandler) loadVersion(w http.ResponseWriter, r *http.Request, templateID, number string) (*models.TemplateVersion, bool) {
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		http.Error(w, "Invalid version number", http.StatusBadRequest)
+		return nil, false
+	}
+	
+	version, err := h.templateRepo.GetVersion(r.Context(), templateID, n)
+	if err != nil {
+		if err.Error() == "template version not found" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	
+	return version, true
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/middleware"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// UserHandler handles HTTP requests for managing user accounts
+type UserHandler struct {
+	userRepo repository.UserRepository
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userRepo repository.UserRepository) *UserHandler {
+	return &UserHandler{
+		userRepo: userRepo,
+	}
+}
+
+// RegisterRoutes registers all user routes
+func (h *UserHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the user management endpoints; all are admin-only
+func (h *UserHandler) routes() []route {
+	return []route{
+		{"GET", "/users", h.List, adminOnly},
+		{"POST", "/users", h.Create, adminOnly},
+		{"GET", "/users/{id}", h.Get, adminOnly},
+		{"PUT", "/users/{id}", h.Update, adminOnly},
+		{"DELETE", "/users/{id}", h.Delete, adminOnly},
+	}
+}
+
+// List handles GET /api/users
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	users, err := h.userRepo.List(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, users)
+}
+
+// Get handles GET /api/users/:id
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, err := h.userRepo.GetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// Create handles POST /api/users
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email"`
+		Name     string `json:"name"`
+		Role     string `json:"role"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Role == "" {
+		req.Role = string(models.UserRoleTech)
+	}
+
+	user, err := models.NewUser(req.Email, req.Name, models.UserRole(req.Role), req.Password)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.userRepo.Create(r.Context(), user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			respondWithError(w, http.StatusConflict, "A user with that email already exists")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to create user")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, user)
+}
+
+// Update handles PUT /api/users/:id
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user, err := h.userRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+
+	var req struct {
+		Name     *string `json:"name"`
+		Role     *string `json:"role"`
+		IsActive *bool   `json:"isActive"`
+		Password *string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+
+	if req.Role != nil {
+		if err := user.SetRole(models.UserRole(*req.Role)); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if req.IsActive != nil {
+		user.IsActive = *req.IsActive
+	}
+
+	if req.Password != nil {
+		if err := user.SetPassword(*req.Password); err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := h.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrLastAdmin) {
+			respondWithError(w, http.StatusConflict, "The last active admin cannot be demoted or deactivated")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to update user")
+		return
+	}
+
+	// Sign the user out everywhere when they are disabled or their password is reset
+	if (req.IsActive != nil && !*req.IsActive) || req.Password != nil {
+		if err := h.userRepo.DeleteUserSessions(ctx, user.ID); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Failed to revoke user sessions")
+			return
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// Delete handles DELETE /api/users/:id
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if current, ok := middleware.UserFromContext(r.Context()); ok && current.ID == id {
+		respondWithError(w, http.StatusBadRequest, "You cannot delete your own account")
+		return
+	}
+
+	if err := h.userRepo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, repository.ErrLastAdmin) {
+			respondWithError(w, http.StatusConflict, "The last active admin cannot be deleted")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+	"github.com/masterbrent/electrical-bidding-app/internal/voltagedrop"
+)
+
+// VoltageDropHandler handles HTTP requests for sizing conductors for voltage
+// drop, the catalog items that sell each conductor, and quoting sized runs on
+// jobs
+type VoltageDropHandler struct {
+	uow      repository.UnitOfWork
+	wireRepo repository.WireItemRepository
+	jobRepo  repository.JobRepository
+	itemRepo repository.ItemRepository
+}
+
+// NewVoltageDropHandler creates a new voltage drop handler
+func NewVoltageDropHandler(
+	uow repository.UnitOfWork,
+	wireRepo repository.WireItemRepository,
+	jobRepo repository.JobRepository,
+	itemRepo repository.ItemRepository,
+) *VoltageDropHandler {
+	return &VoltageDropHandler{
+		uow:      uow,
+		wireRepo: wireRepo,
+		jobRepo:  jobRepo,
+		itemRepo: itemRepo,
+	}
+}
+
+// RegisterRoutes registers all voltage drop routes
+func (h *VoltageDropHandler) RegisterRoutes(router *mux.Router) {
+	registerRoutes(router, h.routes())
+}
+
+// routes declares the voltage drop endpoints; anyone may size a run, but
+// only admins link wire items or quote runs on jobs
+func (h *VoltageDropHandler) routes() []route {
+	return []route{
+		{"POST", "/voltage-drop", h.Size, anyRole},
+
+		{"GET", "/wire-items", h.ListWires, adminOnly},
+		{"PUT", "/wire-items", h.SetWire, adminOnly},
+		{"DELETE", "/wire-items/{itemId}", h.DeleteWire, adminOnly},
+
+		{"POST", "/jobs/{id}/voltage-drop", h.QuoteJob, adminOnly},
+	}
+}
+
+// wireItemRequest links an item to a conductor material and size
+type wireItemRequest struct {
+	ItemID   string `json:"itemId"`
+	Material string `json:"material"`
+	Size     string `json:"size"`
+}
+
+// jobVoltageDropRequest is a run to size and quote on a job. Conductors
+// defaults to the run's current-carrying conductors; JobItemID is the wire
+// line the run replaces, if any.
+type jobVoltageDropRequest struct {
+	voltagedrop.Run
+	Conductors int    `json:"conductors"`
+	JobItemID  string `json:"jobItemId"`
+}
+
+// jobVoltageDropResponse is a sized run and the job line quoting its wire
+type jobVoltageDropResponse struct {
+	Result   *voltagedrop.Result `json:"result"`
+	Footage  float64             `json:"footage"`
+	JobItem  models.JobItem      `json:"jobItem"`
+	Replaced *models.JobItem     `json:"replaced,omitempty"`
+}
+
+// Size handles POST /api/voltage-drop, finding the conductor a run needs
+func (h *VoltageDropHandler) Size(w http.ResponseWriter, r *http.Request) {
+	var run voltagedrop.Run
+	if err := json.NewDecoder(r.Body).Decode(&run); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := voltagedrop.Size(run)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, result)
+}
+
+// ListWires handles GET /api/wire-items
+func (h *VoltageDropHandler) ListWires(w http.ResponseWriter, r *http.Request) {
+	wires, err := h.wireRepo.List(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to list wire items")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, wires)
+}
+
+// SetWire handles PUT /api/wire-items, linking an item to the conductor it
+// sells in place of any item linked to it before
+func (h *VoltageDropHandler) SetWire(w http.ResponseWriter, r *http.Request) {
+	var req wireItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wire, err := models.NewWireItem(req.ItemID, req.Material, req.Size)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.wireRepo.Set(r.Context(), wire); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Item %s not found", req.ItemID))
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to save wire item")
+		return
+	}
+
+	wire, err = h.wireRepo.GetBySize(r.Context(), wire.Material, wire.Size)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get wire item")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, wire)
+}
+
+// DeleteWire handles DELETE /api/wire-items/:itemId
+func (h *VoltageDropHandler) DeleteWire(w http.ResponseWriter, r *http.Request) {
+	if err := h.wireRepo.Delete(r.Context(), mux.Vars(r)["itemId"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Wire item not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete wire item")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// QuoteJob handles POST /api/jobs/:id/voltage-drop: it sizes a run and budgets
+// its footage of the wire item linked to the size on the job, replacing the
+// line named by jobItemId when one is given
+func (h *VoltageDropHandler) QuoteJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	job, err := h.jobRepo.GetByID(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		if err.Error() == "job not found" {
+			respondWithError(w, http.StatusNotFound, "Job not found")
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get job")
+		return
+	}
+
+	var req jobVoltageDropRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Conductors < 0 {
+		respondWithError(w, http.StatusBadRequest, "conductors cannot be negative")
+		return
+	}
+
+	result, err := voltagedrop.Size(req.Run)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var replaced *models.JobItem
+	if req.JobItemID != "" {
+		for i := range job.Items {
+			if job.Items[i].ID == req.JobItemID {
+				replaced = &job.Items[i]
+			}
+		}
+		if replaced == nil {
+			respondWithError(w, http.StatusNotFound, "Job item not found")
+			return
+		}
+	}
+
+	wire, err := h.wireRepo.GetBySize(ctx, result.Material, result.Size)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("No wire item is linked to %s %s", result.Size, result.Material))
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to get wire item")
+		return
+	}
+
+	item, err := h.itemRepo.GetByID(ctx, wire.ItemID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to get wire item")
+		return
+	}
+
+	conductors := req.Conductors
+	if conductors == 0 {
+		conductors = req.Run.Conductors()
+	}
+	footage := voltagedrop.Footage(req.LengthFeet, conductors)
+	line := models.QuoteWire(job.ID, item, footage, replaced)
+
+	// The repository sets the line total and refreshes the job total
+	err = h.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if replaced != nil && replaced.ID == line.ID {
+			return repos.Jobs.UpdateJobItem(ctx, &line)
+		}
+		if replaced != nil {
+			if err := repos.Jobs.RemoveJobItem(ctx, job.ID, replaced.ID); err != nil {
+				return err
+			}
+		}
+		return repos.Jobs.AddJobItem(ctx, job.ID, &line)
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to save job item")
+		return
+	}
+
+	status := http.StatusCreated
+	if replaced != nil {
+		status = http.StatusOK
+	}
+	respondWithJSON(w, status, jobVoltageDropResponse{
+		Result:   result,
+		Footage:  footage,
+		JobItem:  line,
+		Replaced: replaced,
+	})
+}
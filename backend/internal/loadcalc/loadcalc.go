@@ -0,0 +1,405 @@
+// Package loadcalc computes the service load of a single-family dwelling by
+// the standard method of NEC (2020) Article 220, Part III, and the service
+// size it needs.
+package loadcalc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Loads and demand factors from Article 220
+const (
+	// GeneralLightingVAPerSquareFoot is the general lighting and receptacle
+	// load of a dwelling unit (Table 220.12)
+	GeneralLightingVAPerSquareFoot = 3.0
+	// SmallApplianceCircuitVA is the load of each 20 A small-appliance circuit
+	// (220.52(A)); a dwelling has at least two
+	SmallApplianceCircuitVA = 1500.0
+	// LaundryCircuitVA is the load of each laundry circuit (220.52(B)); a
+	// dwelling has at least one
+	LaundryCircuitVA = 1500.0
+	// MinimumDryerVA is the least a household clothes dryer counts for (220.54)
+	MinimumDryerVA = 5000.0
+	// MinimumEVChargerVA is the least an EV charger counts for (220.57)
+	MinimumEVChargerVA = 7200.0
+	// MinimumServiceAmps is the smallest service a one-family dwelling may
+	// have (230.79(C))
+	MinimumServiceAmps = 100
+	// DefaultVoltage is the voltage of a 120/240 V single-phase service
+	DefaultVoltage = 240.0
+)
+
+// StandardServiceSizes are the service ratings recommended, smallest first
+var StandardServiceSizes = []int{100, 125, 150, 175, 200, 225, 250, 300, 350, 400, 600, 800}
+
+// Kinds of cooking appliance. An appliance without a kind counts as a range.
+const (
+	CookingRange    = "range"
+	CookingCooktop  = "cooktop"
+	CookingWallOven = "wall_oven"
+)
+
+// Appliance is one appliance or piece of equipment and its nameplate rating
+// in volt-amperes (watts for resistive loads). Motor marks motor loads, the
+// largest of which adds 25% (220.50, 430.24). Kind tells ranges, cooktops and
+// wall ovens apart among cooking appliances.
+type Appliance struct {
+	Name  string  `json:"name"`
+	VA    float64 `json:"va"`
+	Motor bool    `json:"motor,omitempty"`
+	Kind  string  `json:"kind,omitempty"`
+}
+
+// Dwelling describes the loads of a single-family dwelling. FixedAppliances
+// are those fastened in place other than cooking, dryers, heating, cooling and
+// EV chargers: water heaters, dishwashers, disposals, microwaves and the like.
+// Cooking are ranges, cooktops and ovens. Voltage defaults to 240.
+type Dwelling struct {
+	SquareFeet             float64     `json:"squareFeet"`
+	SmallApplianceCircuits int         `json:"smallApplianceCircuits"`
+	LaundryCircuits        int         `json:"laundryCircuits"`
+	FixedAppliances        []Appliance `json:"fixedAppliances"`
+	Cooking                []Appliance `json:"cooking"`
+	Dryers                 []Appliance `json:"dryers"`
+	Heating                []Appliance `json:"heating"`
+	Cooling                []Appliance `json:"cooling"`
+	EVChargers             []Appliance `json:"evChargers"`
+	Voltage                float64     `json:"voltage"`
+}
+
+// Line is one step of the calculation: a load as connected and as demanded
+// after its demand factor
+type Line struct {
+	Description string  `json:"description"`
+	Reference   string  `json:"reference"`
+	ConnectedVA float64 `json:"connectedVa"`
+	DemandVA    float64 `json:"demandVa"`
+}
+
+// Result is a dwelling's calculated load. Amps is the demand load over the
+// service voltage, to two decimal places; ServiceAmps is the smallest standard
+// service that carries it.
+type Result struct {
+	Lines       []Line  `json:"lines"`
+	ConnectedVA float64 `json:"connectedVa"`
+	DemandVA    float64 `json:"demandVa"`
+	Voltage     float64 `json:"voltage"`
+	Amps        float64 `json:"amps"`
+	ServiceAmps int     `json:"serviceAmps"`
+}
+
+// Validate checks the dwelling's loads
+func (d *Dwelling) Validate() error {
+	if d.SquareFeet <= 0 {
+		return errors.New("square feet must be positive")
+	}
+	if d.SmallApplianceCircuits < 0 || d.LaundryCircuits < 0 {
+		return errors.New("circuit counts cannot be negative")
+	}
+	if d.Voltage < 0 {
+		return errors.New("voltage cannot be negative")
+	}
+
+	groups := []struct {
+		name       string
+		appliances []Appliance
+	}{
+		{"fixed appliance", d.FixedAppliances},
+		{"cooking appliance", d.Cooking},
+		{"dryer", d.Dryers},
+		{"heating", d.Heating},
+		{"cooling", d.Cooling},
+		{"EV charger", d.EVChargers},
+	}
+	for _, group := range groups {
+		for i, appliance := range group.appliances {
+			if appliance.VA <= 0 {
+				return fmt.Errorf("%s %s must have a positive rating", group.name, applianceName(appliance, i))
+			}
+		}
+	}
+	for i, appliance := range d.Cooking {
+		switch appliance.Kind {
+		case "", CookingRange, CookingCooktop, CookingWallOven:
+		default:
+			return fmt.Errorf("cooking appliance %s has unknown kind %q", applianceName(appliance, i), appliance.Kind)
+		}
+	}
+	return nil
+}
+
+// Calculate works out the dwelling's demand load step by step:
+//
+//  1. general lighting at 3 VA per square foot, plus 1500 VA for each
+//     small-appliance and laundry circuit, with the demand factors of Table
+//     220.42: the first 3000 VA at 100%, up to 120,000 VA at 35%, the rest at
+//     25%
+//  2. fixed appliances at 100%, or 75% when there are four or more (220.53)
+//  3. cooking appliances by Table 220.55, Column C, with a cooktop and up to
+//     two wall ovens counted as one range (Note 4)
+//  4. dryers at 5000 VA or their rating, whichever is larger, by Table 220.54
+//  5. the larger of heating and cooling, since they don't run together (220.60)
+//  6. EV chargers at 7200 VA or their rating, whichever is larger (220.57)
+//  7. 25% of the largest motor among the loads counted (220.50, 430.24)
+func Calculate(dwelling Dwelling) (*Result, error) {
+	if err := dwelling.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Lines: []Line{}, Voltage: dwelling.Voltage}
+	if result.Voltage == 0 {
+		result.Voltage = DefaultVoltage
+	}
+	add := func(line Line) {
+		result.Lines = append(result.Lines, line)
+		result.ConnectedVA += line.ConnectedVA
+		result.DemandVA += line.DemandVA
+	}
+
+	// 1. General lighting, small-appliance and laundry circuits
+	smallAppliance := max(dwelling.SmallApplianceCircuits, 2)
+	laundry := max(dwelling.LaundryCircuits, 1)
+	general := dwelling.SquareFeet*GeneralLightingVAPerSquareFoot +
+		float64(smallAppliance)*SmallApplianceCircuitVA +
+		float64(laundry)*LaundryCircuitVA
+	add(Line{
+		Description: fmt.Sprintf("General lighting (%g sq ft at %g VA), %d small-appliance and %d laundry circuits",
+			dwelling.SquareFeet, GeneralLightingVAPerSquareFoot, smallAppliance, laundry),
+		Reference:   "220.12, 220.52, Table 220.42",
+		ConnectedVA: general,
+		DemandVA:    GeneralDemand(general),
+	})
+
+	// 2. Fixed appliances
+	var motors []Appliance
+	if len(dwelling.FixedAppliances) > 0 {
+		connected := totalVA(dwelling.FixedAppliances)
+		line := Line{
+			Description: fmt.Sprintf("%d fixed appliances at 100%%", len(dwelling.FixedAppliances)),
+			Reference:   "220.53",
+			ConnectedVA: connected,
+			DemandVA:    connected,
+		}
+		if len(dwelling.FixedAppliances) >= 4 {
+			line.Description = fmt.Sprintf("%d fixed appliances at 75%%", len(dwelling.FixedAppliances))
+			line.DemandVA = connected * 0.75
+		}
+		add(line)
+		motors = append(motors, dwelling.FixedAppliances...)
+	}
+
+	// 3. Cooking
+	if len(dwelling.Cooking) > 0 {
+		demand, err := CookingDemand(dwelling.Cooking)
+		if err != nil {
+			return nil, err
+		}
+		description := fmt.Sprintf("%d cooking appliances", len(dwelling.Cooking))
+		if units := len(CookingUnits(dwelling.Cooking)); units != len(dwelling.Cooking) {
+			description = fmt.Sprintf("%d cooking appliances counted as %d", len(dwelling.Cooking), units)
+		}
+		add(Line{
+			Description: description,
+			Reference:   "Table 220.55, Column C",
+			ConnectedVA: totalVA(dwelling.Cooking),
+			DemandVA:    demand,
+		})
+	}
+
+	// 4. Dryers
+	if len(dwelling.Dryers) > 0 {
+		demand, err := DryerDemand(dwelling.Dryers)
+		if err != nil {
+			return nil, err
+		}
+		add(Line{
+			Description: fmt.Sprintf("%d dryers at %g VA or their rating", len(dwelling.Dryers), MinimumDryerVA),
+			Reference:   "220.54, Table 220.54",
+			ConnectedVA: totalVA(dwelling.Dryers),
+			DemandVA:    demand,
+		})
+	}
+
+	// 5. Heating or cooling, whichever is larger
+	heating, cooling := totalVA(dwelling.Heating), totalVA(dwelling.Cooling)
+	if heating > 0 || cooling > 0 {
+		line := Line{Reference: "220.51, 220.60"}
+		if heating >= cooling {
+			line.Description = "Heating (the larger of heating and cooling)"
+			line.ConnectedVA, line.DemandVA = heating, heating
+			motors = append(motors, dwelling.Heating...)
+		} else {
+			line.Description = "Cooling (the larger of heating and cooling)"
+			line.ConnectedVA, line.DemandVA = cooling, cooling
+			motors = append(motors, dwelling.Cooling...)
+		}
+		add(line)
+	}
+
+	// 6. EV chargers
+	if len(dwelling.EVChargers) > 0 {
+		var demand float64
+		for _, charger := range dwelling.EVChargers {
+			demand += math.Max(charger.VA, MinimumEVChargerVA)
+		}
+		add(Line{
+			Description: fmt.Sprintf("%d EV chargers at %g VA or their rating", len(dwelling.EVChargers), MinimumEVChargerVA),
+			Reference:   "220.57",
+			ConnectedVA: totalVA(dwelling.EVChargers),
+			DemandVA:    demand,
+		})
+	}
+
+	// 7. Largest motor
+	var largest *Appliance
+	for i, appliance := range motors {
+		if appliance.Motor && (largest == nil || appliance.VA > largest.VA) {
+			largest = &motors[i]
+		}
+	}
+	if largest != nil {
+		add(Line{
+			Description: fmt.Sprintf("25%% of the largest motor (%s)", applianceName(*largest, 0)),
+			Reference:   "220.50, 430.24",
+			DemandVA:    largest.VA * 0.25,
+		})
+	}
+
+	result.Amps = math.Round(result.DemandVA/result.Voltage*100) / 100
+	service, err := ServiceSize(result.Amps)
+	if err != nil {
+		return nil, err
+	}
+	result.ServiceAmps = service
+
+	return result, nil
+}
+
+// GeneralDemand applies the dwelling demand factors of Table 220.42 to the
+// general lighting, small-appliance and laundry load
+func GeneralDemand(va float64) float64 {
+	demand := math.Min(va, 3000)
+	if va > 3000 {
+		demand += (math.Min(va, 120000) - 3000) * 0.35
+	}
+	if va > 120000 {
+		demand += (va - 120000) * 0.25
+	}
+	return demand
+}
+
+// CookingUnits are the ratings Table 220.55 counts for cooking appliances.
+// Each cooktop is counted with up to two wall ovens as one range rated at
+// their sum (Note 4); ranges and any other ovens count on their own.
+func CookingUnits(appliances []Appliance) []float64 {
+	var units, ovens []float64
+	for _, appliance := range appliances {
+		if appliance.Kind == CookingWallOven {
+			ovens = append(ovens, appliance.VA)
+		}
+	}
+
+	for _, appliance := range appliances {
+		switch appliance.Kind {
+		case CookingWallOven:
+		case CookingCooktop:
+			rating := appliance.VA
+			for i := 0; i < 2 && len(ovens) > 0; i++ {
+				rating += ovens[0]
+				ovens = ovens[1:]
+			}
+			units = append(units, rating)
+		default:
+			units = append(units, appliance.VA)
+		}
+	}
+	return append(units, ovens...)
+}
+
+// CookingDemand is the demand of household cooking appliances by Table
+// 220.55, Column C, after counting cooktops with their wall ovens (Note 4).
+// Appliances rated under 12 kW count as 12 kW (Note 2), and the column's demand
+// rises 5% for each kW, or major fraction of one, that their average rating is
+// over 12 kW (Note 1); exactly half a kW is not a major fraction.
+func CookingDemand(appliances []Appliance) (float64, error) {
+	units := CookingUnits(appliances)
+	count := len(units)
+	if count == 0 {
+		return 0, nil
+	}
+	if count > 25 {
+		return 0, errors.New("cooking demand is only worked out for up to 25 appliances")
+	}
+
+	// Column C: 8 kW for one, 3 kW more for each up to five, then 1 kW more
+	// for each up to 25
+	columnC := 8000 + 3000*float64(min(count, 5)-1)
+	if count > 5 {
+		columnC = 15000 + 1000*float64(count)
+	}
+
+	var total float64
+	for _, rating := range units {
+		total += math.Max(rating, 12000)
+	}
+	overVA := total/float64(count) - 12000
+	overKW := math.Floor(overVA / 1000)
+	if overVA-overKW*1000 > 500 {
+		overKW++
+	}
+
+	return columnC * (100 + 5*overKW) / 100, nil
+}
+
+// dryerDemandFactors are the demand factors of Table 220.54 for one to eleven
+// dryers
+var dryerDemandFactors = []float64{1, 1, 1, 1, 0.85, 0.75, 0.65, 0.60, 0.55, 0.50, 0.47}
+
+// DryerDemand is the demand of household clothes dryers, each counted at 5000
+// VA or its rating, whichever is larger, by Table 220.54
+func DryerDemand(dryers []Appliance) (float64, error) {
+	if len(dryers) == 0 {
+		return 0, nil
+	}
+	if len(dryers) > len(dryerDemandFactors) {
+		return 0, fmt.Errorf("dryer demand is only worked out for up to %d dryers", len(dryerDemandFactors))
+	}
+
+	var total float64
+	for _, dryer := range dryers {
+		total += math.Max(dryer.VA, MinimumDryerVA)
+	}
+	return total * dryerDemandFactors[len(dryers)-1], nil
+}
+
+// ServiceSize is the smallest standard service that carries amps, and never
+// less than the 100 A minimum for a dwelling
+func ServiceSize(amps float64) (int, error) {
+	for _, size := range StandardServiceSizes {
+		if size >= MinimumServiceAmps && float64(size) >= amps {
+			return size, nil
+		}
+	}
+	largest := StandardServiceSizes[len(StandardServiceSizes)-1]
+	return 0, fmt.Errorf("a %.2f A load is larger than a %d A service; size it by hand", amps, largest)
+}
+
+func totalVA(appliances []Appliance) float64 {
+	var total float64
+	for _, appliance := range appliances {
+		total += appliance.VA
+	}
+	return total
+}
+
+// applianceName names an appliance for messages, by its position when it has
+// no name
+func applianceName(appliance Appliance, i int) string {
+	if name := strings.TrimSpace(appliance.Name); name != "" {
+		return name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
@@ -0,0 +1,228 @@
+package loadcalc
+
+import (
+	"strings"
+	"testing"
+)
+
+// NEC Annex D, Example D1(a): a 1500 sq ft dwelling with a 12 kW range and a
+// 5.5 kW dryer
+func TestCalculate_AnnexDExampleD1a(t *testing.T) {
+	result, err := Calculate(Dwelling{
+		SquareFeet:             1500,
+		SmallApplianceCircuits: 2,
+		LaundryCircuits:        1,
+		Cooking:                []Appliance{{Name: "Range", VA: 12000}},
+		Dryers:                 []Appliance{{Name: "Dryer", VA: 5500}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 4500 + 3000 + 1500 = 9000 VA; 3000 + 6000 x 35% = 5100 VA
+	want := []float64{5100, 8000, 5500}
+	if len(result.Lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), result.Lines)
+	}
+	for i, demand := range want {
+		if result.Lines[i].DemandVA != demand {
+			t.Errorf("line %d: expected %g VA, got %+v", i, demand, result.Lines[i])
+		}
+	}
+	if result.Lines[0].ConnectedVA != 9000 {
+		t.Errorf("expected 9000 VA of general load, got %g", result.Lines[0].ConnectedVA)
+	}
+	if result.DemandVA != 18600 || result.Voltage != 240 || result.Amps != 77.5 || result.ServiceAmps != 100 {
+		t.Errorf("expected 18,600 VA, 77.5 A at 240 V on a 100 A service, got %+v", result)
+	}
+}
+
+// A 2400 sq ft house with four fixed appliances, a 14 kW range, a small dryer,
+// electric heat with air conditioning, and an EV charger
+func TestCalculate_AllElectricHouse(t *testing.T) {
+	result, err := Calculate(Dwelling{
+		SquareFeet:             2400,
+		SmallApplianceCircuits: 2,
+		LaundryCircuits:        1,
+		FixedAppliances: []Appliance{
+			{Name: "Dishwasher", VA: 1200},
+			{Name: "Disposal", VA: 900, Motor: true},
+			{Name: "Water heater", VA: 4500},
+			{Name: "Microwave", VA: 1500},
+		},
+		Cooking:    []Appliance{{Name: "Range", VA: 14000}},
+		Dryers:     []Appliance{{Name: "Dryer", VA: 4000}},
+		Heating:    []Appliance{{Name: "Baseboard heat", VA: 10000}},
+		Cooling:    []Appliance{{Name: "Air conditioner", VA: 5000, Motor: true}},
+		EVChargers: []Appliance{{Name: "Garage charger", VA: 9600}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		demand    float64
+		reference string
+	}{
+		{6045, "Table 220.42"}, // 3000 + (11,700 - 3000) x 35%
+		{6075, "220.53"},       // 8100 x 75%
+		{8800, "Column C"},     // 8 kW + 5% for each of 2 kW over 12
+		{5000, "Table 220.54"}, // the 5000 VA minimum
+		{10000, "220.60"},      // heating, larger than the cooling
+		{9600, "220.57"},       // nameplate over the 7200 VA minimum
+		{225, "430.24"},        // the disposal; the air conditioner is omitted
+	}
+	if len(result.Lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), result.Lines)
+	}
+	for i, line := range want {
+		if result.Lines[i].DemandVA != line.demand || !strings.Contains(result.Lines[i].Reference, line.reference) {
+			t.Errorf("line %d: expected %g VA by %s, got %+v", i, line.demand, line.reference, result.Lines[i])
+		}
+	}
+	// 45,745 VA / 240 V = 190.60 A
+	if result.DemandVA != 45745 || result.Amps != 190.6 || result.ServiceAmps != 200 {
+		t.Errorf("expected 45,745 VA, 190.6 A on a 200 A service, got %+v", result)
+	}
+}
+
+func TestCalculate_CircuitMinimums(t *testing.T) {
+	result, err := Calculate(Dwelling{SquareFeet: 800, Voltage: 240})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 2400 + 2 x 1500 + 1500 = 6900 VA; 3000 + 3900 x 35% = 4365 VA
+	if result.Lines[0].ConnectedVA != 6900 || result.DemandVA != 4365 {
+		t.Errorf("expected two small-appliance and one laundry circuit at least, got %+v", result.Lines[0])
+	}
+	if result.ServiceAmps != MinimumServiceAmps {
+		t.Errorf("expected the 100 A minimum, got %d", result.ServiceAmps)
+	}
+}
+
+func TestCalculate_CoolingLargerThanHeating(t *testing.T) {
+	result, err := Calculate(Dwelling{
+		SquareFeet: 1000,
+		Heating:    []Appliance{{Name: "Furnace blower", VA: 600, Motor: true}},
+		Cooling:    []Appliance{{Name: "Heat pump", VA: 6000, Motor: true}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := result.Lines[len(result.Lines)-1]
+	if result.Lines[1].DemandVA != 6000 || !strings.HasPrefix(result.Lines[1].Description, "Cooling") {
+		t.Errorf("expected the cooling to count, got %+v", result.Lines[1])
+	}
+	if last.DemandVA != 1500 || !strings.Contains(last.Description, "Heat pump") {
+		t.Errorf("expected 25%% of the heat pump, got %+v", last)
+	}
+}
+
+func TestCalculate_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		dwelling Dwelling
+		errMsg   string
+	}{
+		{"no area", Dwelling{}, "square feet must be positive"},
+		{"negative circuits", Dwelling{SquareFeet: 1000, LaundryCircuits: -1}, "circuit counts cannot be negative"},
+		{"negative voltage", Dwelling{SquareFeet: 1000, Voltage: -240}, "voltage cannot be negative"},
+		{"unrated appliance", Dwelling{SquareFeet: 1000, Cooking: []Appliance{{Name: "Oven"}}}, "cooking appliance Oven must have a positive rating"},
+		{"unknown cooking kind", Dwelling{SquareFeet: 1000, Cooking: []Appliance{{Name: "Grill", VA: 3000, Kind: "grill"}}}, `cooking appliance Grill has unknown kind "grill"`},
+		{"unnamed appliance", Dwelling{SquareFeet: 1000, EVChargers: []Appliance{{VA: 7200}, {}}}, "EV charger #2 must have a positive rating"},
+		{"too large", Dwelling{SquareFeet: 1000, Heating: []Appliance{{VA: 250000}}}, "larger than a 800 A service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Calculate(tt.dwelling)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestGeneralDemand(t *testing.T) {
+	tests := []struct {
+		va, demand float64
+	}{
+		{2000, 2000},
+		{3000, 3000},
+		{13000, 6500},
+		{120000, 43950},
+		{130000, 46450},
+	}
+	for _, tt := range tests {
+		if demand := GeneralDemand(tt.va); demand != tt.demand {
+			t.Errorf("GeneralDemand(%g) = %g, want %g", tt.va, demand, tt.demand)
+		}
+	}
+}
+
+func TestCookingDemand(t *testing.T) {
+	tests := []struct {
+		name       string
+		appliances []Appliance
+		demand     float64
+	}{
+		{"one range under 12 kW", []Appliance{{VA: 9000}}, 8000},
+		{"one 16 kW range", []Appliance{{VA: 16000}}, 9600},
+		{"major fraction of a kW", []Appliance{{VA: 13600}}, 8800},
+		{"exactly half a kW", []Appliance{{VA: 12500}}, 8000},
+		{"cooktop and oven as one range", []Appliance{{VA: 6000, Kind: CookingCooktop}, {VA: 4000, Kind: CookingWallOven}}, 8000},
+		{"cooktop and two ovens over 12 kW", []Appliance{{VA: 7000, Kind: CookingCooktop}, {VA: 4000, Kind: CookingWallOven}, {VA: 4000, Kind: CookingWallOven}}, 9200},
+		{"third oven counts on its own", []Appliance{{VA: 6000, Kind: CookingCooktop}, {VA: 4000, Kind: CookingWallOven}, {VA: 4000, Kind: CookingWallOven}, {VA: 4000, Kind: CookingWallOven}}, 11550},
+		{"range and oven", []Appliance{{VA: 6000}, {VA: 4000}}, 11000},
+		{"five ranges", []Appliance{{VA: 12000}, {VA: 12000}, {VA: 12000}, {VA: 12000}, {VA: 12000}}, 20000},
+		{"average over 12 kW", []Appliance{{VA: 12000}, {VA: 16000}}, 12100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			demand, err := CookingDemand(tt.appliances)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if demand != tt.demand {
+				t.Errorf("expected %g VA, got %g", tt.demand, demand)
+			}
+		})
+	}
+
+	eight := make([]Appliance, 8)
+	for i := range eight {
+		eight[i] = Appliance{VA: 8000}
+	}
+	if demand, _ := CookingDemand(eight); demand != 23000 {
+		t.Errorf("expected 15 kW + 1 kW per range for eight ranges, got %g", demand)
+	}
+}
+
+func TestDryerDemand(t *testing.T) {
+	dryers := []Appliance{{VA: 5500}, {VA: 5500}, {VA: 5500}, {VA: 5500}, {VA: 5500}}
+	// 27,500 VA at 85% for five dryers
+	if demand, err := DryerDemand(dryers); err != nil || demand != 23375 {
+		t.Errorf("expected 23,375 VA, got %g (%v)", demand, err)
+	}
+	if _, err := DryerDemand(make([]Appliance, 12)); err == nil {
+		t.Error("expected twelve dryers to be refused")
+	}
+}
+
+func TestServiceSize(t *testing.T) {
+	tests := []struct {
+		amps    float64
+		service int
+	}{
+		{40, 100},
+		{100, 100},
+		{100.01, 125},
+		{190.6, 200},
+		{401, 600},
+	}
+	for _, tt := range tests {
+		if service, err := ServiceSize(tt.amps); err != nil || service != tt.service {
+			t.Errorf("ServiceSize(%g) = %d, %v; want %d", tt.amps, service, err, tt.service)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// Authenticator resolves a bearer token to the user it belongs to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*models.User, error)
+}
+
+// contextKey is an unexported type for request context keys
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// RequireAuth rejects requests without a valid bearer token and puts the
+// authenticated user on the request context.
+func RequireAuth(auth Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Let CORS preflight requests through
+			if r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := BearerToken(r)
+			if token == "" {
+				writeJSONError(w, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			user, err := auth.Authenticate(r.Context(), token)
+			if err != nil || user == nil {
+				writeJSONError(w, http.StatusUnauthorized, "Invalid or expired session")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the authenticated user, if any
+func UserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*models.User)
+	return user, ok && user != nil
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header
+func BearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// writeJSONError writes an error in the same {"error": "..."} shape the handlers use
+func writeJSONError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
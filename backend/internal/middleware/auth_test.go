@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// mockAuthenticator maps tokens to users for testing
+type mockAuthenticator struct {
+	users map[string]*models.User
+}
+
+func (m *mockAuthenticator) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	user, ok := m.users[token]
+	if !ok {
+		return nil, errors.New("invalid session")
+	}
+	return user, nil
+}
+
+func TestRequireAuth(t *testing.T) {
+	admin := &models.User{ID: "admin-id", Email: "admin@bremray.com", Role: models.UserRoleAdmin, IsActive: true}
+	auth := &mockAuthenticator{users: map[string]*models.User{"good-token": admin}}
+
+	tests := []struct {
+		name         string
+		method       string
+		header       string
+		expectedCode int
+		expectUser   bool
+	}{
+		{
+			name:         "valid token",
+			method:       http.MethodGet,
+			header:       "Bearer good-token",
+			expectedCode: http.StatusOK,
+			expectUser:   true,
+		},
+		{
+			name:         "lowercase scheme",
+			method:       http.MethodGet,
+			header:       "bearer good-token",
+			expectedCode: http.StatusOK,
+			expectUser:   true,
+		},
+		{
+			name:         "missing header",
+			method:       http.MethodGet,
+			header:       "",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "wrong scheme",
+			method:       http.MethodGet,
+			header:       "Basic good-token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown token",
+			method:       http.MethodGet,
+			header:       "Bearer bad-token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "preflight passes through",
+			method:       http.MethodOptions,
+			header:       "",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser *models.User
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser, _ = UserFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/jobs", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			RequireAuth(auth)(next).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if tt.expectUser && (gotUser == nil || gotUser.ID != admin.ID) {
+				t.Errorf("expected user %q on context, got %v", admin.ID, gotUser)
+			}
+
+			if w.Code == http.StatusUnauthorized {
+				var response map[string]string
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if response["error"] == "" {
+					t.Error("expected error message in response")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// RequireRole only lets through callers whose role is one of roles. It must run
+// after RequireAuth so the user is already on the request context.
+func RequireRole(roles ...models.UserRole) func(http.Handler) http.Handler {
+	allowed := make(map[models.UserRole]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Let CORS preflight requests through
+			if r.Method == "OPTIONS" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, ok := UserFromContext(r.Context())
+			if !ok {
+				writeJSONError(w, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+
+			if !allowed[user.Role] {
+				writeJSONError(w, http.StatusForbidden, "You do not have permission to perform this action")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
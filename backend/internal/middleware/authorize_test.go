@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+func TestRequireRole(t *testing.T) {
+	admin := &models.User{ID: "admin-id", Role: models.UserRoleAdmin}
+	tech := &models.User{ID: "tech-id", Role: models.UserRoleTech}
+
+	tests := []struct {
+		name         string
+		roles        []models.UserRole
+		user         *models.User
+		method       string
+		expectedCode int
+	}{
+		{
+			name:         "admin on admin route",
+			roles:        []models.UserRole{models.UserRoleAdmin},
+			user:         admin,
+			method:       http.MethodPut,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "tech on admin route",
+			roles:        []models.UserRole{models.UserRoleAdmin},
+			user:         tech,
+			method:       http.MethodPut,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "tech on shared route",
+			roles:        []models.UserRole{models.UserRoleAdmin, models.UserRoleTech},
+			user:         tech,
+			method:       http.MethodGet,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "no user",
+			roles:        []models.UserRole{models.UserRoleAdmin, models.UserRoleTech},
+			user:         nil,
+			method:       http.MethodGet,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "no roles allows nobody",
+			roles:        nil,
+			user:         admin,
+			method:       http.MethodGet,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "preflight passes through",
+			roles:        []models.UserRole{models.UserRoleAdmin},
+			user:         nil,
+			method:       http.MethodOptions,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/items/1", nil)
+			if tt.user != nil {
+				req = req.WithContext(WithUser(req.Context(), tt.user))
+			}
+			w := httptest.NewRecorder()
+
+			RequireRole(tt.roles...)(next).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, w.Code)
+			}
+
+			if w.Code == http.StatusForbidden {
+				var response map[string]string
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if response["error"] == "" {
+					t.Error("expected error message in response")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdjustmentType says whether an adjustment raises or lowers the price
+type AdjustmentType string
+
+const (
+	AdjustmentMarkup   AdjustmentType = "markup"
+	AdjustmentDiscount AdjustmentType = "discount"
+)
+
+// AdjustmentScope says what an adjustment is charged on
+type AdjustmentScope string
+
+const (
+	// AdjustmentScopeLine adjusts the lines for one item
+	AdjustmentScopeLine AdjustmentScope = "line"
+	// AdjustmentScopeCategory adjusts the lines in one item category
+	AdjustmentScopeCategory AdjustmentScope = "category"
+	// AdjustmentScopeJob adjusts the whole job
+	AdjustmentScopeJob AdjustmentScope = "job"
+)
+
+// PriceAdjustment is a markup or discount, either a percentage or a fixed
+// amount. Defaults with no CustomerID apply to every job (contractor markups);
+// defaults with a CustomerID apply to that customer's jobs. Defaults are copied
+// onto a job when it is created, alongside any ad-hoc adjustments for the job,
+// so later changes to the defaults don't reprice jobs already under way.
+type PriceAdjustment struct {
+	ID         string          `json:"id" db:"id"`
+	JobID      string          `json:"jobId,omitempty" db:"job_id"`
+	CustomerID string          `json:"customerId,omitempty" db:"customer_id"`
+	Name       string          `json:"name" db:"name"`
+	Type       AdjustmentType  `json:"type" db:"type"`
+	Scope      AdjustmentScope `json:"scope" db:"scope"`
+	ItemID     string          `json:"itemId,omitempty" db:"item_id"`
+	Category   string          `json:"category,omitempty" db:"category"`
+	Percent    float64         `json:"percent" db:"percent"`
+	Amount     Money           `json:"amount" db:"amount"`
+	CreatedAt  time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// AdjustmentLine is one adjustment as charged on a job. Amount is positive for
+// markups and negative for discounts; Base is what it was charged on.
+type AdjustmentLine struct {
+	AdjustmentID string          `json:"adjustmentId"`
+	Name         string          `json:"name"`
+	Type         AdjustmentType  `json:"type"`
+	Scope        AdjustmentScope `json:"scope"`
+	ItemID       string          `json:"itemId,omitempty"`
+	Category     string          `json:"category,omitempty"`
+	Percent      float64         `json:"percent,omitempty"`
+	Base         Money           `json:"base"`
+	Amount       Money           `json:"amount"`
+}
+
+// NewPriceAdjustment creates a new PriceAdjustment with validation
+func NewPriceAdjustment(name string, adjustmentType AdjustmentType, scope AdjustmentScope, itemID, category string, percent float64, amount Money) (*PriceAdjustment, error) {
+	now := time.Now()
+	adjustment := &PriceAdjustment{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+	}
+	if err := adjustment.Update(name, adjustmentType, scope, itemID, category, percent, amount); err != nil {
+		return nil, err
+	}
+	return adjustment, nil
+}
+
+// Update changes the adjustment's fields with validation
+func (a *PriceAdjustment) Update(name string, adjustmentType AdjustmentType, scope AdjustmentScope, itemID, category string, percent float64, amount Money) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("adjustment name is required")
+	}
+	if adjustmentType != AdjustmentMarkup && adjustmentType != AdjustmentDiscount {
+		return errors.New("adjustment type must be markup or discount")
+	}
+
+	itemID = strings.TrimSpace(itemID)
+	category = strings.TrimSpace(category)
+	switch scope {
+	case AdjustmentScopeLine:
+		if itemID == "" {
+			return errors.New("a line adjustment needs an item ID")
+		}
+		category = ""
+	case AdjustmentScopeCategory:
+		if category == "" {
+			return errors.New("a category adjustment needs a category")
+		}
+		itemID = ""
+	case AdjustmentScopeJob:
+		itemID, category = "", ""
+	default:
+		return errors.New("adjustment scope must be line, category or job")
+	}
+
+	if (percent != 0) == (amount != 0) {
+		return errors.New("give either a percent or an amount")
+	}
+	if percent < 0 || amount < 0 {
+		return errors.New("percent and amount must be positive; use a discount to lower the price")
+	}
+	if adjustmentType == AdjustmentDiscount && percent > 100 {
+		return errors.New("a discount cannot be more than 100 percent")
+	}
+
+	a.Name = name
+	a.Type = adjustmentType
+	a.Scope = scope
+	a.ItemID = itemID
+	a.Category = category
+	a.Percent = percent
+	a.Amount = amount
+	a.UpdatedAt = time.Now()
+	return nil
+}
+
+// ForJob copies a default adjustment onto a job
+func (a *PriceAdjustment) ForJob(jobID string) PriceAdjustment {
+	now := time.Now()
+	adjustment := *a
+	adjustment.ID = uuid.New().String()
+	adjustment.JobID = jobID
+	adjustment.CreatedAt = now
+	adjustment.UpdatedAt = now
+	return adjustment
+}
+
+// Description describes the adjustment for an invoice, e.g. "20% markup on Materials"
+func (l AdjustmentLine) Description() string {
+	var description string
+	if l.Percent != 0 {
+		description = fmt.Sprintf("%g%% %s", l.Percent, l.Type)
+	} else {
+		amount := l.Amount
+		if amount < 0 {
+			amount = -amount
+		}
+		description = fmt.Sprintf("$%s %s", amount, l.Type)
+	}
+	if l.Scope == AdjustmentScopeCategory {
+		description += " on " + l.Category
+	}
+	return description
+}
+
+// ApplyAdjustments prices the adjustments against the job's lines and returns
+// one line per adjustment that applies, in the order they were applied:
+//
+//  1. line adjustments, each on the total of the lines for its item
+//  2. category adjustments, each on its category's lines plus their line adjustments
+//  3. job adjustments, each on the lines plus every line and category adjustment
+//
+// Within a step every adjustment is charged on the same base, so their order
+// doesn't change the result; they are listed markups first, then by name.
+// Percentages are rounded to the cent, and a discount never takes more than
+// its base. Line and category adjustments that match no line are left out.
+func ApplyAdjustments(items []JobItem, adjustments []PriceAdjustment) []AdjustmentLine {
+	sorted := append([]PriceAdjustment(nil), adjustments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if scopeRank(a.Scope) != scopeRank(b.Scope) {
+			return scopeRank(a.Scope) < scopeRank(b.Scope)
+		}
+		if a.Type != b.Type {
+			return a.Type == AdjustmentMarkup
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+
+	itemTotals := map[string]Money{}
+	itemCategories := map[string]string{}
+	categoryTotals := map[string]Money{}
+	var subtotal Money
+	for _, item := range items {
+		itemTotals[item.ItemID] += item.Total
+		itemCategories[item.ItemID] = item.Category
+		categoryTotals[strings.ToLower(item.Category)] += item.Total
+		subtotal += item.Total
+	}
+
+	lines := []AdjustmentLine{}
+	charge := func(adjustment PriceAdjustment, category string, base Money) Money {
+		line := AdjustmentLine{
+			AdjustmentID: adjustment.ID,
+			Name:         adjustment.Name,
+			Type:         adjustment.Type,
+			Scope:        adjustment.Scope,
+			ItemID:       adjustment.ItemID,
+			Category:     category,
+			Percent:      adjustment.Percent,
+			Base:         base,
+			Amount:       adjustment.Amount,
+		}
+		if adjustment.Percent != 0 {
+			line.Amount = base.Percent(adjustment.Percent)
+		}
+		if adjustment.Type == AdjustmentDiscount {
+			if line.Amount > base {
+				line.Amount = base
+			}
+			if line.Amount < 0 {
+				line.Amount = 0
+			}
+			line.Amount = -line.Amount
+		}
+		lines = append(lines, line)
+		return line.Amount
+	}
+
+	// Each step's bases are taken before any of its adjustments are added
+	categoryBases := map[string]Money{}
+	for category, total := range categoryTotals {
+		categoryBases[category] = total
+	}
+	jobBase := subtotal
+	for _, adjustment := range sorted {
+		if adjustment.Scope != AdjustmentScopeLine {
+			continue
+		}
+		base, ok := itemTotals[adjustment.ItemID]
+		if !ok {
+			continue
+		}
+		category := itemCategories[adjustment.ItemID]
+		amount := charge(adjustment, category, base)
+		categoryBases[strings.ToLower(category)] += amount
+		jobBase += amount
+	}
+
+	for _, adjustment := range sorted {
+		if adjustment.Scope != AdjustmentScopeCategory {
+			continue
+		}
+		key := strings.ToLower(adjustment.Category)
+		if _, ok := categoryTotals[key]; !ok {
+			continue
+		}
+		jobBase += charge(adjustment, adjustment.Category, categoryBases[key])
+	}
+
+	for _, adjustment := range sorted {
+		if adjustment.Scope == AdjustmentScopeJob {
+			charge(adjustment, "", jobBase)
+		}
+	}
+
+	return lines
+}
+
+// AdjustmentsTotal is the sum of the adjustment lines
+func AdjustmentsTotal(lines []AdjustmentLine) Money {
+	var total Money
+	for _, line := range lines {
+		total += line.Amount
+	}
+	return total
+}
+
+func scopeRank(scope AdjustmentScope) int {
+	switch scope {
+	case AdjustmentScopeLine:
+		return 0
+	case AdjustmentScopeCategory:
+		return 1
+	default:
+		return 2
+	}
+}
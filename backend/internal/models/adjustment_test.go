@@ -0,0 +1,171 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPriceAdjustment(t *testing.T) {
+	tests := []struct {
+		name           string
+		adjustmentType AdjustmentType
+		scope          AdjustmentScope
+		itemID         string
+		category       string
+		percent        float64
+		amount         Money
+		errMsg         string
+	}{
+		{name: "category markup", adjustmentType: AdjustmentMarkup, scope: AdjustmentScopeCategory, category: "Materials", percent: 20},
+		{name: "fixed job discount", adjustmentType: AdjustmentDiscount, scope: AdjustmentScopeJob, amount: Cents(5000)},
+		{name: "unknown type", adjustmentType: "rebate", scope: AdjustmentScopeJob, percent: 5, errMsg: "adjustment type must be markup or discount"},
+		{name: "unknown scope", adjustmentType: AdjustmentMarkup, scope: "phase", percent: 5, errMsg: "adjustment scope must be line, category or job"},
+		{name: "line without item", adjustmentType: AdjustmentMarkup, scope: AdjustmentScopeLine, percent: 5, errMsg: "a line adjustment needs an item ID"},
+		{name: "category without category", adjustmentType: AdjustmentMarkup, scope: AdjustmentScopeCategory, percent: 5, errMsg: "a category adjustment needs a category"},
+		{name: "percent and amount", adjustmentType: AdjustmentMarkup, scope: AdjustmentScopeJob, percent: 5, amount: Cents(100), errMsg: "give either a percent or an amount"},
+		{name: "neither percent nor amount", adjustmentType: AdjustmentMarkup, scope: AdjustmentScopeJob, errMsg: "give either a percent or an amount"},
+		{name: "negative percent", adjustmentType: AdjustmentMarkup, scope: AdjustmentScopeJob, percent: -5, errMsg: "percent and amount must be positive; use a discount to lower the price"},
+		{name: "discount over 100 percent", adjustmentType: AdjustmentDiscount, scope: AdjustmentScopeJob, percent: 120, errMsg: "a discount cannot be more than 100 percent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPriceAdjustment("Adjustment", tt.adjustmentType, tt.scope, tt.itemID, tt.category, tt.percent, tt.amount)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("expected error %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+// adjustedJobItems is a job with $1,582.50 of lines across three categories
+func adjustedJobItems() []JobItem {
+	items := []JobItem{
+		{ItemID: "outlet", Name: "Outlet", Category: "Devices", Quantity: 10, Price: Cents(1500)},
+		{ItemID: "wire", Name: "12/2 Wire", Category: "Materials", Quantity: 250, Price: Cents(89)},
+		{ItemID: "panel", Name: "Panel", Category: "Materials", Quantity: 1, Price: Cents(45000)},
+		{ItemID: "labor", Name: "Labor", Category: "Labor", Quantity: 8, Price: Cents(9500)},
+	}
+	for i := range items {
+		items[i].CalculateTotal()
+	}
+	return items
+}
+
+func adjustment(id, name string, adjustmentType AdjustmentType, scope AdjustmentScope, target string, percent float64, amount Money) PriceAdjustment {
+	a := PriceAdjustment{ID: id, Name: name, Type: adjustmentType, Scope: scope, Percent: percent, Amount: amount}
+	switch scope {
+	case AdjustmentScopeLine:
+		a.ItemID = target
+	case AdjustmentScopeCategory:
+		a.Category = target
+	}
+	return a
+}
+
+func TestApplyAdjustments(t *testing.T) {
+	adjustments := []PriceAdjustment{
+		adjustment("a1", "Loyal customer", AdjustmentDiscount, AdjustmentScopeJob, "", 5, 0),
+		adjustment("a2", "Devices discount", AdjustmentDiscount, AdjustmentScopeCategory, "devices", 0, Cents(2000)),
+		adjustment("a3", "Fuel surcharge", AdjustmentMarkup, AdjustmentScopeJob, "", 0, Cents(2500)),
+		adjustment("a4", "Materials markup", AdjustmentMarkup, AdjustmentScopeCategory, "Materials", 20, 0),
+		adjustment("a5", "Panel markup", AdjustmentMarkup, AdjustmentScopeLine, "panel", 10, 0),
+		adjustment("a6", "Fixtures markup", AdjustmentMarkup, AdjustmentScopeCategory, "Fixtures", 15, 0),
+		adjustment("a7", "Meter markup", AdjustmentMarkup, AdjustmentScopeLine, "meter", 15, 0),
+	}
+
+	// Line, then category on the adjusted lines, then job on everything before it
+	want := []struct {
+		id     string
+		base   Money
+		amount Money
+	}{
+		{"a5", Cents(45000), Cents(4500)},
+		{"a4", Cents(71750), Cents(14350)},
+		{"a2", Cents(15000), Cents(-2000)},
+		{"a3", Cents(175100), Cents(2500)},
+		{"a1", Cents(175100), Cents(-8755)},
+	}
+
+	lines := ApplyAdjustments(adjustedJobItems(), adjustments)
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %+v", len(want), lines)
+	}
+	for i, w := range want {
+		if lines[i].AdjustmentID != w.id || lines[i].Base != w.base || lines[i].Amount != w.amount {
+			t.Errorf("line %d: expected %s on %s = %s, got %s on %s = %s",
+				i, w.id, w.base, w.amount, lines[i].AdjustmentID, lines[i].Base, lines[i].Amount)
+		}
+	}
+	if lines[0].Category != "Materials" {
+		t.Errorf("expected the line adjustment to carry its item's category, got %q", lines[0].Category)
+	}
+	if total := AdjustmentsTotal(lines); total != Cents(10595) {
+		t.Errorf("expected adjustments of 105.95, got %s", total)
+	}
+
+	// The same adjustments in any order price the same way
+	reversed := make([]PriceAdjustment, len(adjustments))
+	for i := range adjustments {
+		reversed[len(adjustments)-1-i] = adjustments[i]
+	}
+	if again := ApplyAdjustments(adjustedJobItems(), reversed); !reflect.DeepEqual(lines, again) {
+		t.Errorf("expected the same lines regardless of input order, got %+v", again)
+	}
+}
+
+func TestApplyAdjustments_DiscountNeverExceedsBase(t *testing.T) {
+	adjustments := []PriceAdjustment{
+		adjustment("a1", "Devices credit", AdjustmentDiscount, AdjustmentScopeCategory, "Devices", 0, Cents(50000)),
+	}
+
+	lines := ApplyAdjustments(adjustedJobItems(), adjustments)
+	if len(lines) != 1 || lines[0].Amount != Cents(-15000) {
+		t.Errorf("expected the discount to stop at the 150.00 of devices, got %+v", lines)
+	}
+}
+
+func TestJob_CalculateTotalIncludesAdjustments(t *testing.T) {
+	job := &Job{
+		Items: adjustedJobItems(),
+		Adjustments: []PriceAdjustment{
+			adjustment("a1", "Materials markup", AdjustmentMarkup, AdjustmentScopeCategory, "Materials", 20, 0),
+		},
+		ChangeOrders: []ChangeOrder{
+			{Status: ChangeOrderStatusApproved, TotalAmount: Cents(10000)},
+		},
+	}
+
+	job.CalculateTotal()
+
+	// 1582.50 of lines + 134.50 markup + 100.00 change order
+	if job.TotalAmount != Cents(181700) {
+		t.Errorf("expected total 1817.00, got %s", job.TotalAmount)
+	}
+	if len(job.AdjustmentLines) != 1 {
+		t.Errorf("expected the markup line, got %+v", job.AdjustmentLines)
+	}
+}
+
+func TestAdjustmentLine_Description(t *testing.T) {
+	tests := []struct {
+		line AdjustmentLine
+		want string
+	}{
+		{AdjustmentLine{Type: AdjustmentMarkup, Scope: AdjustmentScopeCategory, Category: "Materials", Percent: 20}, "20% markup on Materials"},
+		{AdjustmentLine{Type: AdjustmentDiscount, Scope: AdjustmentScopeJob, Amount: Cents(-5000)}, "$50.00 discount"},
+		{AdjustmentLine{Type: AdjustmentDiscount, Scope: AdjustmentScopeLine, Category: "Devices", Percent: 2.5}, "2.5% discount"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.line.Description(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAssemblyPriced is returned when an assembly's price or cost is set
+// directly; both come from its components
+var ErrAssemblyPriced = errors.New("an assembly is priced from its components; set its priceOverride instead")
+
+// AssemblyComponent is an item in an assembly and how many of it go into one
+// unit of the assembly. Name, unit, price, cost and labor hours are the
+// item's current ones.
+type AssemblyComponent struct {
+	ItemID    string  `json:"itemId" db:"item_id"`
+	Name      string  `json:"name" db:"name"`
+	Unit      string  `json:"unit" db:"unit"`
+	Quantity  float64 `json:"quantity" db:"quantity"`
+	UnitPrice Money   `json:"unitPrice" db:"unit_price"`
+	UnitCost  Money   `json:"unitCost" db:"unit_cost"`
+
+	LaborHours float64 `json:"laborHours" db:"labor_hours"`
+}
+
+// NewAssemblyComponent puts quantity of item into an assembly. Assemblies are
+// one level deep, so the item cannot be an assembly itself.
+func NewAssemblyComponent(item *Item, quantity float64) (AssemblyComponent, error) {
+	if item.IsAssembly() {
+		return AssemblyComponent{}, fmt.Errorf("%s is an assembly; assemblies cannot contain other assemblies", item.Name)
+	}
+	if quantity <= 0 {
+		return AssemblyComponent{}, fmt.Errorf("quantity of %s must be positive", item.Name)
+	}
+
+	return AssemblyComponent{
+		ItemID:    item.ID,
+		Name:      item.Name,
+		Unit:      item.Unit,
+		Quantity:  quantity,
+		UnitPrice: item.UnitPrice,
+		UnitCost:  item.UnitCost,
+
+		LaborHours: item.LaborHours,
+	}, nil
+}
+
+// NewAssembly creates an item made of components. It is priced as the sum of
+// its parts unless priceOverride is set.
+func NewAssembly(name, unit, category string, components []AssemblyComponent, priceOverride *Money, expandOnInvoice bool) (*Item, error) {
+	item, err := NewItem(name, unit, 0, category)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.SetComponents(components, priceOverride); err != nil {
+		return nil, err
+	}
+	item.ExpandOnInvoice = expandOnInvoice
+
+	return item, nil
+}
+
+// IsAssembly reports whether the item is made of other items
+func (i *Item) IsAssembly() bool {
+	return len(i.Components) > 0
+}
+
+// SetComponents replaces the assembly's components and price override and
+// prices it from them
+func (i *Item) SetComponents(components []AssemblyComponent, priceOverride *Money) error {
+	if len(components) == 0 {
+		return errors.New("an assembly needs at least one component")
+	}
+	if priceOverride != nil && *priceOverride < 0 {
+		return errors.New("price override cannot be negative")
+	}
+
+	seen := make(map[string]bool, len(components))
+	for _, component := range components {
+		if component.ItemID == "" {
+			return errors.New("component item ID is required")
+		}
+		if component.ItemID == i.ID {
+			return errors.New("an assembly cannot contain itself")
+		}
+		if seen[component.ItemID] {
+			return fmt.Errorf("%s is in the assembly twice; combine the quantities", component.Name)
+		}
+		if component.Quantity <= 0 {
+			return fmt.Errorf("quantity of %s must be positive", component.Name)
+		}
+		seen[component.ItemID] = true
+	}
+
+	i.Components = components
+	i.PriceOverride = priceOverride
+	i.PriceAssembly()
+	return nil
+}
+
+// SetComponentPrice moves the price of the assembly's itemID component to price
+// and reprices the assembly. It reports whether the assembly contains the item.
+func (i *Item) SetComponentPrice(itemID string, price Money) bool {
+	found := false
+	for j := range i.Components {
+		if i.Components[j].ItemID == itemID {
+			i.Components[j].UnitPrice = price
+			found = true
+		}
+	}
+	if found {
+		i.PriceAssembly()
+	}
+	return found
+}
+
+// PartsPrice is what the assembly's components sell for, per unit of the
+// assembly
+func (i *Item) PartsPrice() Money {
+	var total Money
+	for _, component := range i.Components {
+		total += component.UnitPrice.MulFineQuantity(component.Quantity)
+	}
+	return total
+}
+
+// PriceAssembly sets an assembly's unit price to its override, or the price
+// of its parts without one, and its unit cost and labor hours to those of its
+// parts
+func (i *Item) PriceAssembly() {
+	if !i.IsAssembly() {
+		return
+	}
+
+	i.UnitPrice = i.PartsPrice()
+	if i.PriceOverride != nil {
+		i.UnitPrice = *i.PriceOverride
+	}
+
+	i.UnitCost = 0
+	i.LaborHours = 0
+	for _, component := range i.Components {
+		i.UnitCost += component.UnitCost.MulFineQuantity(component.Quantity)
+		i.LaborHours += component.LaborHours * component.Quantity
+	}
+	i.LaborHours = roundHours(i.LaborHours)
+}
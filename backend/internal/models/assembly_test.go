@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func outletInstall(t *testing.T, priceOverride *Money) *Item {
+	t.Helper()
+
+	box := &Item{ID: "box", Name: "Box", Unit: "each", UnitPrice: Cents(300), UnitCost: Cents(100), LaborHours: 0.5}
+	wire := &Item{ID: "wire", Name: "12/2 Wire", Unit: "ft", UnitPrice: Cents(89), UnitCost: Cents(37), LaborHours: 0.02}
+
+	var components []AssemblyComponent
+	for _, part := range []struct {
+		item     *Item
+		quantity float64
+	}{{box, 1}, {wire, 12.5}} {
+		component, err := NewAssemblyComponent(part.item, part.quantity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		components = append(components, component)
+	}
+
+	assembly, err := NewAssembly("Outlet Install", "each", "Devices", components, priceOverride, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return assembly
+}
+
+func TestNewAssembly_Pricing(t *testing.T) {
+	assembly := outletInstall(t, nil)
+	if !assembly.IsAssembly() {
+		t.Fatal("expected an assembly")
+	}
+	// 3.00 + 12.5 x 0.89 = 14.13 (11.125 rounds up); cost 1.00 + 12.5 x 0.37 = 5.63
+	if assembly.UnitPrice != Cents(1413) || assembly.UnitCost != Cents(563) {
+		t.Errorf("expected 14.13 costing 5.63, got %s costing %s", assembly.UnitPrice, assembly.UnitCost)
+	}
+	// 0.5 hours for the box and 12.5 x 0.02 for the wire
+	if assembly.LaborHours != 0.75 {
+		t.Errorf("expected 0.75 labor hours, got %g", assembly.LaborHours)
+	}
+
+	override := Cents(1800)
+	assembly = outletInstall(t, &override)
+	if assembly.UnitPrice != Cents(1800) || assembly.PartsPrice() != Cents(1413) || assembly.UnitCost != Cents(563) {
+		t.Errorf("expected the override price over 14.13 of parts, got %s over %s", assembly.UnitPrice, assembly.PartsPrice())
+	}
+}
+
+func TestNewAssembly_Validation(t *testing.T) {
+	assembly := outletInstall(t, nil)
+	box := assembly.Components[0]
+	negative := Cents(-1)
+
+	tests := []struct {
+		name       string
+		components []AssemblyComponent
+		override   *Money
+	}{
+		{"no components", nil, nil},
+		{"duplicate component", []AssemblyComponent{box, box}, nil},
+		{"zero quantity", []AssemblyComponent{{ItemID: "box", Name: "Box"}}, nil},
+		{"missing item", []AssemblyComponent{{Quantity: 1}}, nil},
+		{"negative override", []AssemblyComponent{box}, &negative},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAssembly("Kit", "each", "", tt.components, tt.override, false); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := NewAssemblyComponent(assembly, 1); err == nil {
+		t.Error("expected an error nesting an assembly")
+	}
+	self := AssemblyComponent{ItemID: assembly.ID, Name: assembly.Name, Quantity: 1}
+	if err := assembly.SetComponents([]AssemblyComponent{self}, nil); err == nil {
+		t.Error("expected an error for an assembly containing itself")
+	}
+}
+
+func TestItem_UpdateAssemblyPrice(t *testing.T) {
+	assembly := outletInstall(t, nil)
+
+	if err := assembly.Update(map[string]interface{}{"unit_price": Cents(2000)}); !errors.Is(err, ErrAssemblyPriced) {
+		t.Errorf("expected ErrAssemblyPriced, got %v", err)
+	}
+	if err := assembly.Update(map[string]interface{}{"labor_hours": 2.0}); !errors.Is(err, ErrAssemblyLabor) {
+		t.Errorf("expected ErrAssemblyLabor, got %v", err)
+	}
+	if err := assembly.Update(map[string]interface{}{"unit_price": assembly.UnitPrice, "name": "Duplex Install"}); err != nil {
+		t.Errorf("expected an unchanged price to be accepted, got %v", err)
+	}
+	if assembly.Name != "Duplex Install" {
+		t.Errorf("expected the name to change, got %s", assembly.Name)
+	}
+}
+
+func TestItem_SetComponentPrice(t *testing.T) {
+	assembly := outletInstall(t, nil)
+
+	// 3.00 + 12.5 × 0.99
+	if !assembly.SetComponentPrice("wire", Cents(99)) {
+		t.Fatal("expected the assembly to contain wire")
+	}
+	if assembly.UnitPrice != Cents(1538) {
+		t.Errorf("expected the assembly to be repriced to 15.38, got %s", assembly.UnitPrice)
+	}
+	if assembly.SetComponentPrice("switch", Cents(500)) {
+		t.Error("expected the assembly not to contain switch")
+	}
+
+	override := Cents(2500)
+	overridden := outletInstall(t, &override)
+	overridden.SetComponentPrice("wire", Cents(99))
+	if overridden.UnitPrice != override {
+		t.Errorf("expected the override to keep the price at 25.00, got %s", overridden.UnitPrice)
+	}
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeOrderStatus represents where a change order is in the approval workflow
+type ChangeOrderStatus string
+
+const (
+	ChangeOrderStatusPending  ChangeOrderStatus = "pending"
+	ChangeOrderStatusApproved ChangeOrderStatus = "approved"
+	ChangeOrderStatusRejected ChangeOrderStatus = "rejected"
+)
+
+// ChangeOrder records a change in a job's scope. Only approved change orders
+// count toward the job total and the invoice.
+type ChangeOrder struct {
+	ID          string            `json:"id" db:"id"`
+	JobID       string            `json:"jobId" db:"job_id"`
+	Number      int               `json:"number" db:"change_order_number"`
+	Reason      string            `json:"reason" db:"reason"`
+	Status      ChangeOrderStatus `json:"status" db:"status"`
+	Items       []ChangeOrderItem `json:"items"`
+	TotalAmount Money             `json:"totalAmount" db:"total_amount"`
+	SignedBy    string            `json:"signedBy,omitempty" db:"signed_by"`
+	SignedAt    *time.Time        `json:"signedAt,omitempty" db:"signed_at"`
+	DecidedAt   *time.Time        `json:"decidedAt,omitempty" db:"decided_at"`
+	CreatedAt   time.Time         `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updatedAt" db:"updated_at"`
+}
+
+// ChangeOrderItem is a line added to or removed from a job by a change order.
+// A positive quantity adds work; a negative quantity removes it.
+type ChangeOrderItem struct {
+	ID            string  `json:"id" db:"id"`
+	ChangeOrderID string  `json:"changeOrderId" db:"change_order_id"`
+	ItemID        string  `json:"itemId" db:"item_id"`
+	Name          string  `json:"name" db:"name"`
+	Quantity      float64 `json:"quantity" db:"quantity"`
+	Price         Money   `json:"price" db:"price"`
+	Cost          Money   `json:"cost" db:"cost"`
+	Total         Money   `json:"total" db:"total"`
+}
+
+// ValidateChangeOrderStatus checks if a change order status is valid
+func ValidateChangeOrderStatus(status ChangeOrderStatus) bool {
+	switch status {
+	case ChangeOrderStatusPending, ChangeOrderStatusApproved, ChangeOrderStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewChangeOrder creates a new pending ChangeOrder with validation
+func NewChangeOrder(jobID, reason string, items []ChangeOrderItem) (*ChangeOrder, error) {
+	if jobID == "" {
+		return nil, errors.New("job ID is required")
+	}
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	now := time.Now()
+	changeOrder := &ChangeOrder{
+		ID:        uuid.New().String(),
+		JobID:     jobID,
+		Reason:    reason,
+		Status:    ChangeOrderStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := changeOrder.SetItems(items); err != nil {
+		return nil, err
+	}
+
+	return changeOrder, nil
+}
+
+// SetItems replaces the change order's lines. Lines can only change while the
+// change order is pending.
+func (c *ChangeOrder) SetItems(items []ChangeOrderItem) error {
+	if c.Status != ChangeOrderStatusPending {
+		return errors.New("only pending change orders can be edited")
+	}
+	if len(items) == 0 {
+		return errors.New("change order must have at least one item")
+	}
+
+	for i := range items {
+		if items[i].Quantity == 0 {
+			return errors.New("quantity cannot be zero")
+		}
+		if items[i].ID == "" {
+			items[i].ID = uuid.New().String()
+		}
+		items[i].ChangeOrderID = c.ID
+	}
+
+	c.Items = items
+	c.CalculateTotal()
+	return nil
+}
+
+// CalculateTotal recalculates each line total and the change order total
+func (c *ChangeOrder) CalculateTotal() {
+	var total Money
+	for i := range c.Items {
+		c.Items[i].Total = c.Items[i].Price.MulQuantity(c.Items[i].Quantity)
+		total += c.Items[i].Total
+	}
+	c.TotalAmount = total
+	c.UpdatedAt = time.Now()
+}
+
+// Approve accepts a pending change order so it counts toward the job
+func (c *ChangeOrder) Approve() error {
+	if c.Status != ChangeOrderStatusPending {
+		return errors.New("only pending change orders can be approved")
+	}
+
+	now := time.Now()
+	c.Status = ChangeOrderStatusApproved
+	c.DecidedAt = &now
+	c.UpdatedAt = now
+	return nil
+}
+
+// Reject declines a pending change order
+func (c *ChangeOrder) Reject() error {
+	if c.Status != ChangeOrderStatusPending {
+		return errors.New("only pending change orders can be rejected")
+	}
+
+	now := time.Now()
+	c.Status = ChangeOrderStatusRejected
+	c.DecidedAt = &now
+	c.UpdatedAt = now
+	return nil
+}
+
+// SignOff records the customer's signature on a pending or approved change order
+func (c *ChangeOrder) SignOff(signedBy string) error {
+	if signedBy == "" {
+		return errors.New("signer name is required")
+	}
+	if c.Status == ChangeOrderStatusRejected {
+		return errors.New("rejected change orders cannot be signed")
+	}
+	if c.SignedAt != nil {
+		return errors.New("change order has already been signed")
+	}
+
+	now := time.Now()
+	c.SignedBy = signedBy
+	c.SignedAt = &now
+	c.UpdatedAt = now
+	return nil
+}
+
+// IsApproved reports whether the change order counts toward the job
+func (c *ChangeOrder) IsApproved() bool {
+	return c.Status == ChangeOrderStatusApproved
+}
+
+// InvoiceLabel is the heading used for the change order's group of invoice lines
+func (c *ChangeOrder) InvoiceLabel() string {
+	return fmt.Sprintf("Change Order #%d: %s", c.Number, c.Reason)
+}
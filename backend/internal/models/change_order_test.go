@@ -0,0 +1,153 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNewChangeOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobID   string
+		reason  string
+		items   []ChangeOrderItem
+		wantErr bool
+		errMsg  string
+		total   Money
+	}{
+		{
+			name:   "added and removed work",
+			jobID:  "job123",
+			reason: "Customer added a subpanel and dropped two lights",
+			items: []ChangeOrderItem{
+				{ItemID: "subpanel", Name: "Subpanel", Quantity: 1, Price: Cents(85000)},
+				{ItemID: "can-light", Name: "Recessed Light", Quantity: -2, Price: Cents(4500)},
+			},
+			total: Cents(76000),
+		},
+		{
+			name:    "empty job ID",
+			reason:  "Extra outlet",
+			items:   []ChangeOrderItem{{ItemID: "outlet", Quantity: 1, Price: Cents(1500)}},
+			wantErr: true,
+			errMsg:  "job ID is required",
+		},
+		{
+			name:    "empty reason",
+			jobID:   "job123",
+			items:   []ChangeOrderItem{{ItemID: "outlet", Quantity: 1, Price: Cents(1500)}},
+			wantErr: true,
+			errMsg:  "reason is required",
+		},
+		{
+			name:    "no items",
+			jobID:   "job123",
+			reason:  "Nothing",
+			wantErr: true,
+			errMsg:  "change order must have at least one item",
+		},
+		{
+			name:    "zero quantity",
+			jobID:   "job123",
+			reason:  "Extra outlet",
+			items:   []ChangeOrderItem{{ItemID: "outlet", Quantity: 0, Price: Cents(1500)}},
+			wantErr: true,
+			errMsg:  "quantity cannot be zero",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeOrder, err := NewChangeOrder(tt.jobID, tt.reason, tt.items)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("expected error %q but got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changeOrder.Status != ChangeOrderStatusPending {
+				t.Errorf("expected status pending, got %s", changeOrder.Status)
+			}
+			if changeOrder.TotalAmount != tt.total {
+				t.Errorf("expected total %s, got %s", tt.total, changeOrder.TotalAmount)
+			}
+			for _, item := range changeOrder.Items {
+				if item.ChangeOrderID != changeOrder.ID {
+					t.Errorf("expected change order ID %s, got %s", changeOrder.ID, item.ChangeOrderID)
+				}
+			}
+		})
+	}
+}
+
+func TestChangeOrder_Decisions(t *testing.T) {
+	newChangeOrder := func() *ChangeOrder {
+		changeOrder, _ := NewChangeOrder("job123", "Extra outlet", []ChangeOrderItem{
+			{ItemID: "outlet", Quantity: 1, Price: Cents(1500)},
+		})
+		return changeOrder
+	}
+
+	t.Run("approve", func(t *testing.T) {
+		changeOrder := newChangeOrder()
+		if err := changeOrder.Approve(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !changeOrder.IsApproved() || changeOrder.DecidedAt == nil {
+			t.Error("expected change order to be approved with a decision date")
+		}
+		if err := changeOrder.Reject(); err == nil {
+			t.Error("expected error rejecting an approved change order")
+		}
+		if err := changeOrder.SetItems([]ChangeOrderItem{{ItemID: "outlet", Quantity: 2}}); err == nil {
+			t.Error("expected error editing an approved change order")
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		changeOrder := newChangeOrder()
+		if err := changeOrder.Reject(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if changeOrder.Status != ChangeOrderStatusRejected {
+			t.Errorf("expected status rejected, got %s", changeOrder.Status)
+		}
+		if err := changeOrder.Approve(); err == nil {
+			t.Error("expected error approving a rejected change order")
+		}
+		if err := changeOrder.SignOff("Pat Customer"); err == nil {
+			t.Error("expected error signing a rejected change order")
+		}
+	})
+
+	t.Run("sign off", func(t *testing.T) {
+		changeOrder := newChangeOrder()
+		if err := changeOrder.SignOff(""); err == nil {
+			t.Error("expected error for missing signer")
+		}
+		if err := changeOrder.SignOff("Pat Customer"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if changeOrder.SignedBy != "Pat Customer" || changeOrder.SignedAt == nil {
+			t.Error("expected signature to be recorded")
+		}
+		if err := changeOrder.SignOff("Someone Else"); err == nil {
+			t.Error("expected error signing twice")
+		}
+	})
+}
+
+func TestChangeOrder_InvoiceLabel(t *testing.T) {
+	changeOrder := &ChangeOrder{Number: 2, Reason: "Add subpanel"}
+
+	if got := changeOrder.InvoiceLabel(); got != "Change Order #2: Add subpanel" {
+		t.Errorf("unexpected label %q", got)
+	}
+}
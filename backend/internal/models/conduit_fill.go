@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/masterbrent/electrical-bidding-app/internal/conduitfill"
+)
+
+// ConductorArea is the cross-sectional area of an insulated conductor, such
+// as 12 AWG THHN at 0.0133 sq in
+type ConductorArea struct {
+	ID         string    `json:"id" db:"id"`
+	Insulation string    `json:"insulation" db:"insulation"`
+	Size       string    `json:"size" db:"size"`
+	AreaSqIn   float64   `json:"areaSqIn" db:"area_sq_in"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ConduitSize is the total internal area of a trade size of a conduit type,
+// such as 1/2 EMT at 0.304 sq in, and the catalog item that sells it, if any
+type ConduitSize struct {
+	ID          string    `json:"id" db:"id"`
+	ConduitType string    `json:"conduitType" db:"conduit_type"`
+	TradeSize   string    `json:"tradeSize" db:"trade_size"`
+	AreaSqIn    float64   `json:"areaSqIn" db:"area_sq_in"`
+	ItemID      string    `json:"itemId,omitempty" db:"item_id"`
+	ItemName    string    `json:"itemName,omitempty" db:"item_name"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// ConduitFillConductor is a number of conductors of one insulation and size
+// to pull in a conduit
+type ConduitFillConductor struct {
+	Insulation string `json:"insulation"`
+	Size       string `json:"size"`
+	Count      int    `json:"count"`
+}
+
+// NewConductorArea creates a new ConductorArea with validation
+func NewConductorArea(insulation, size string, areaSqIn float64) (*ConductorArea, error) {
+	conductor := &ConductorArea{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	if err := conductor.Update(insulation, size, areaSqIn); err != nil {
+		return nil, err
+	}
+	return conductor, nil
+}
+
+// Update changes the conductor's fields with validation
+func (c *ConductorArea) Update(insulation, size string, areaSqIn float64) error {
+	insulation, size = strings.TrimSpace(insulation), strings.TrimSpace(size)
+	if insulation == "" {
+		return errors.New("insulation is required")
+	}
+	if size == "" {
+		return errors.New("conductor size is required")
+	}
+	if areaSqIn <= 0 {
+		return errors.New("area must be positive")
+	}
+
+	c.Insulation = insulation
+	c.Size = size
+	c.AreaSqIn = areaSqIn
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// NewConduitSize creates a new ConduitSize with validation
+func NewConduitSize(conduitType, tradeSize string, areaSqIn float64, itemID string) (*ConduitSize, error) {
+	conduit := &ConduitSize{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	if err := conduit.Update(conduitType, tradeSize, areaSqIn, itemID); err != nil {
+		return nil, err
+	}
+	return conduit, nil
+}
+
+// Update changes the conduit size's fields with validation. An empty itemID
+// unlinks its item.
+func (c *ConduitSize) Update(conduitType, tradeSize string, areaSqIn float64, itemID string) error {
+	conduitType, tradeSize = strings.TrimSpace(conduitType), strings.TrimSpace(tradeSize)
+	if conduitType == "" {
+		return errors.New("conduit type is required")
+	}
+	if tradeSize == "" {
+		return errors.New("trade size is required")
+	}
+	if areaSqIn <= 0 {
+		return errors.New("area must be positive")
+	}
+
+	c.ConduitType = conduitType
+	c.TradeSize = tradeSize
+	c.AreaSqIn = areaSqIn
+	c.ItemID = strings.TrimSpace(itemID)
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// FillConductors looks up the area of each requested conductor among areas,
+// matching insulation and size without regard to case
+func FillConductors(requested []ConduitFillConductor, areas []ConductorArea) ([]conduitfill.Conductor, error) {
+	conductors := make([]conduitfill.Conductor, 0, len(requested))
+	for _, req := range requested {
+		var found *ConductorArea
+		for i := range areas {
+			if strings.EqualFold(areas[i].Insulation, strings.TrimSpace(req.Insulation)) &&
+				strings.EqualFold(areas[i].Size, strings.TrimSpace(req.Size)) {
+				found = &areas[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no area is known for %s %s", strings.TrimSpace(req.Size), strings.TrimSpace(req.Insulation))
+		}
+
+		conductors = append(conductors, conduitfill.Conductor{
+			Description: found.Size + " " + found.Insulation,
+			AreaSqIn:    found.AreaSqIn,
+			Count:       req.Count,
+		})
+	}
+	return conductors, nil
+}
+
+// FillConduits lists the trade sizes of a conduit type for sizing
+func FillConduits(sizes []ConduitSize) []conduitfill.Conduit {
+	conduits := make([]conduitfill.Conduit, 0, len(sizes))
+	for _, size := range sizes {
+		conduits = append(conduits, conduitfill.Conduit{TradeSize: size.TradeSize, AreaSqIn: size.AreaSqIn})
+	}
+	return conduits
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/conduitfill"
+)
+
+func TestNewConductorArea_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		insulation string
+		size       string
+		area       float64
+		errMsg     string
+	}{
+		{"valid", " XHHW ", " 12 AWG ", 0.0181, ""},
+		{"missing insulation", "", "12 AWG", 0.0181, "insulation is required"},
+		{"missing size", "XHHW", " ", 0.0181, "conductor size is required"},
+		{"no area", "XHHW", "12 AWG", 0, "area must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conductor, err := NewConductorArea(tt.insulation, tt.size, tt.area)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("expected error %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conductor.ID == "" || conductor.Insulation != "XHHW" || conductor.Size != "12 AWG" {
+				t.Errorf("unexpected conductor %+v", conductor)
+			}
+		})
+	}
+}
+
+func TestNewConduitSize_Validation(t *testing.T) {
+	if _, err := NewConduitSize("", "1/2", 0.304, ""); err == nil || err.Error() != "conduit type is required" {
+		t.Errorf("expected a missing type to be rejected, got %v", err)
+	}
+	if _, err := NewConduitSize("EMT", "", 0.304, ""); err == nil || err.Error() != "trade size is required" {
+		t.Errorf("expected a missing trade size to be rejected, got %v", err)
+	}
+	if _, err := NewConduitSize("EMT", "1/2", -1, ""); err == nil || err.Error() != "area must be positive" {
+		t.Errorf("expected a negative area to be rejected, got %v", err)
+	}
+
+	conduit, err := NewConduitSize(" EMT", "1/2 ", 0.304, " emt-half ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conduit.ConduitType != "EMT" || conduit.TradeSize != "1/2" || conduit.ItemID != "emt-half" {
+		t.Errorf("unexpected conduit %+v", conduit)
+	}
+}
+
+func TestFillConductors(t *testing.T) {
+	areas := []ConductorArea{
+		{Insulation: "THHN", Size: "12 AWG", AreaSqIn: 0.0133},
+		{Insulation: "THHN", Size: "10 AWG", AreaSqIn: 0.0211},
+	}
+
+	conductors, err := FillConductors([]ConduitFillConductor{
+		{Insulation: "thhn", Size: "12 awg", Count: 3},
+		{Insulation: "THHN", Size: "10 AWG", Count: 1},
+	}, areas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []conduitfill.Conductor{
+		{Description: "12 AWG THHN", AreaSqIn: 0.0133, Count: 3},
+		{Description: "10 AWG THHN", AreaSqIn: 0.0211, Count: 1},
+	}
+	if len(conductors) != len(want) || conductors[0] != want[0] || conductors[1] != want[1] {
+		t.Errorf("expected %+v, got %+v", want, conductors)
+	}
+
+	if _, err := FillConductors([]ConduitFillConductor{{Insulation: "XHHW", Size: "12 AWG", Count: 1}}, areas); err == nil || err.Error() != "no area is known for 12 AWG XHHW" {
+		t.Errorf("expected an unknown conductor to be rejected, got %v", err)
+	}
+}
+
+func TestBudgetJobItem(t *testing.T) {
+	emt := &Item{ID: "emt-2", Name: `2" EMT`, Category: "Conduit", UnitPrice: Cents(650), UnitCost: Cents(410)}
+
+	line := BudgetJobItem("job-1", emt, 80)
+	if line.ID == "" || line.JobID != "job-1" || line.ItemID != "emt-2" || line.Category != "Conduit" {
+		t.Errorf("unexpected line %+v", line)
+	}
+	if line.Quantity != 0 || line.BudgetedQuantity != 80 || line.Price != Cents(650) || line.Cost != Cents(410) {
+		t.Errorf("expected 80 budgeted and none installed, got %+v", line)
+	}
+}
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EstimateStatus represents where an estimate is in the approval workflow
+type EstimateStatus string
+
+const (
+	EstimateStatusDraft    EstimateStatus = "draft"
+	EstimateStatusSent     EstimateStatus = "sent"
+	EstimateStatusAccepted EstimateStatus = "accepted"
+	EstimateStatusRejected EstimateStatus = "rejected"
+	EstimateStatusExpired  EstimateStatus = "expired"
+)
+
+// Estimate represents a bid sent to a customer before any work is scheduled
+type Estimate struct {
+	ID          string         `json:"id" db:"id"`
+	CustomerID  string         `json:"customerId" db:"customer_id"`
+	TemplateID  string         `json:"templateId" db:"template_id"`
+	Address     string         `json:"address" db:"address"`
+	Status      EstimateStatus `json:"status" db:"status"`
+	Revision    int            `json:"revision" db:"revision"`
+	ValidUntil  time.Time      `json:"validUntil" db:"valid_until"`
+	Items       []EstimateItem `json:"items"`
+	TotalAmount Money          `json:"totalAmount" db:"total_amount"`
+	Notes       string         `json:"notes,omitempty" db:"notes"`
+	JobID       *string        `json:"jobId,omitempty" db:"job_id"`
+	SentAt      *time.Time     `json:"sentAt,omitempty" db:"sent_at"`
+	DecidedAt   *time.Time     `json:"decidedAt,omitempty" db:"decided_at"`
+	CreatedAt   time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// EstimateItem represents a priced line on an estimate
+type EstimateItem struct {
+	ID         string  `json:"id" db:"id"`
+	EstimateID string  `json:"estimateId" db:"estimate_id"`
+	ItemID     string  `json:"itemId" db:"item_id"`
+	Name       string  `json:"name" db:"name"`
+	Quantity   float64 `json:"quantity" db:"quantity"`
+	Price      Money   `json:"price" db:"price"`
+	Total      Money   `json:"total" db:"total"`
+}
+
+// ValidateEstimateStatus checks if an estimate status is valid
+func ValidateEstimateStatus(status EstimateStatus) bool {
+	switch status {
+	case EstimateStatusDraft, EstimateStatusSent, EstimateStatusAccepted,
+		EstimateStatusRejected, EstimateStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewEstimate creates a new draft Estimate with validation
+func NewEstimate(customerID, templateID, address string, validUntil time.Time) (*Estimate, error) {
+	if customerID == "" {
+		return nil, errors.New("customer ID is required")
+	}
+	if templateID == "" {
+		return nil, errors.New("template ID is required")
+	}
+	if address == "" {
+		return nil, errors.New("address is required")
+	}
+	if !validUntil.After(time.Now()) {
+		return nil, errors.New("valid until date must be in the future")
+	}
+
+	now := time.Now()
+	return &Estimate{
+		ID:         uuid.New().String(),
+		CustomerID: customerID,
+		TemplateID: templateID,
+		Address:    address,
+		Status:     EstimateStatusDraft,
+		Revision:   1,
+		ValidUntil: validUntil,
+		Items:      []EstimateItem{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// NewEstimateItem creates an estimate line priced at the item's current unit price
+func NewEstimateItem(item *Item, quantity float64) EstimateItem {
+	return EstimateItem{
+		ID:       uuid.New().String(),
+		ItemID:   item.ID,
+		Name:     item.Name,
+		Quantity: quantity,
+		Price:    item.UnitPrice,
+		Total:    item.UnitPrice.MulQuantity(quantity),
+	}
+}
+
+// SetItems replaces the estimate's lines. Lines can only change while the
+// estimate is a draft.
+func (e *Estimate) SetItems(items []EstimateItem) error {
+	if e.Status != EstimateStatusDraft {
+		return errors.New("only draft estimates can be edited")
+	}
+
+	for i := range items {
+		if items[i].Quantity < 0 {
+			return errors.New("quantity cannot be negative")
+		}
+		if items[i].ID == "" {
+			items[i].ID = uuid.New().String()
+		}
+		items[i].EstimateID = e.ID
+	}
+
+	e.Items = items
+	e.CalculateTotal()
+	return nil
+}
+
+// CalculateTotal recalculates each line total and the estimate total
+func (e *Estimate) CalculateTotal() {
+	var total Money
+	for i := range e.Items {
+		e.Items[i].Total = e.Items[i].Price.MulQuantity(e.Items[i].Quantity)
+		total += e.Items[i].Total
+	}
+	e.TotalAmount = total
+	e.UpdatedAt = time.Now()
+}
+
+// Send marks a draft estimate as sent to the customer
+func (e *Estimate) Send() error {
+	if e.Status != EstimateStatusDraft {
+		return errors.New("only draft estimates can be sent")
+	}
+	if len(e.Items) == 0 {
+		return errors.New("estimate must have at least one item")
+	}
+	if !e.ValidUntil.After(time.Now()) {
+		return errors.New("valid until date must be in the future")
+	}
+
+	now := time.Now()
+	e.Status = EstimateStatusSent
+	e.SentAt = &now
+	e.UpdatedAt = now
+	return nil
+}
+
+// Accept records the customer's approval. The caller is responsible for
+// creating the job and linking it with SetJob.
+func (e *Estimate) Accept() error {
+	if e.ExpireIfPastDue(time.Now()) || e.Status == EstimateStatusExpired {
+		return errors.New("estimate has expired")
+	}
+	if e.Status != EstimateStatusSent {
+		return errors.New("only sent estimates can be accepted")
+	}
+
+	now := time.Now()
+	e.Status = EstimateStatusAccepted
+	e.DecidedAt = &now
+	e.UpdatedAt = now
+	return nil
+}
+
+// Reject records that the customer declined the estimate
+func (e *Estimate) Reject() error {
+	if e.Status != EstimateStatusSent {
+		return errors.New("only sent estimates can be rejected")
+	}
+
+	now := time.Now()
+	e.Status = EstimateStatusRejected
+	e.DecidedAt = &now
+	e.UpdatedAt = now
+	return nil
+}
+
+// Revise reopens a sent, rejected or expired estimate as a new draft revision
+func (e *Estimate) Revise(validUntil time.Time) error {
+	switch e.Status {
+	case EstimateStatusSent, EstimateStatusRejected, EstimateStatusExpired:
+	default:
+		return errors.New("only sent, rejected or expired estimates can be revised")
+	}
+	if !validUntil.After(time.Now()) {
+		return errors.New("valid until date must be in the future")
+	}
+
+	e.Status = EstimateStatusDraft
+	e.Revision++
+	e.ValidUntil = validUntil
+	e.SentAt = nil
+	e.DecidedAt = nil
+	e.UpdatedAt = time.Now()
+	return nil
+}
+
+// ExpireIfPastDue moves a sent estimate to expired once its validity date has
+// passed. It reports whether the status changed.
+func (e *Estimate) ExpireIfPastDue(now time.Time) bool {
+	if e.Status != EstimateStatusSent || now.Before(e.ValidUntil) {
+		return false
+	}
+
+	e.Status = EstimateStatusExpired
+	e.UpdatedAt = now
+	return true
+}
+
+// SetJob links the estimate to the job created when it was accepted
+func (e *Estimate) SetJob(jobID string) {
+	e.JobID = &jobID
+	e.UpdatedAt = time.Now()
+}
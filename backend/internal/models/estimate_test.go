@@ -0,0 +1,276 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func newSentEstimate(t *testing.T) *Estimate {
+	t.Helper()
+	estimate, err := NewEstimate("cust123", "template123", "123 Main St", time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := estimate.SetItems([]EstimateItem{{ItemID: "item1", Name: "Outlet", Quantity: 2, Price: Cents(1000)}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := estimate.Send(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return estimate
+}
+
+func TestNewEstimate(t *testing.T) {
+	tests := []struct {
+		name       string
+		customerID string
+		templateID string
+		address    string
+		validUntil time.Time
+		wantErr    bool
+		errMsg     string
+	}{
+		{
+			name:       "valid estimate",
+			customerID: "cust123",
+			templateID: "template123",
+			address:    "123 Main St",
+			validUntil: time.Now().Add(24 * time.Hour),
+			wantErr:    false,
+		},
+		{
+			name:       "empty customer ID",
+			templateID: "template123",
+			address:    "123 Main St",
+			validUntil: time.Now().Add(24 * time.Hour),
+			wantErr:    true,
+			errMsg:     "customer ID is required",
+		},
+		{
+			name:       "empty template ID",
+			customerID: "cust123",
+			address:    "123 Main St",
+			validUntil: time.Now().Add(24 * time.Hour),
+			wantErr:    true,
+			errMsg:     "template ID is required",
+		},
+		{
+			name:       "empty address",
+			customerID: "cust123",
+			templateID: "template123",
+			validUntil: time.Now().Add(24 * time.Hour),
+			wantErr:    true,
+			errMsg:     "address is required",
+		},
+		{
+			name:       "valid until in the past",
+			customerID: "cust123",
+			templateID: "template123",
+			address:    "123 Main St",
+			validUntil: time.Now().Add(-24 * time.Hour),
+			wantErr:    true,
+			errMsg:     "valid until date must be in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, err := NewEstimate(tt.customerID, tt.templateID, tt.address, tt.validUntil)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("expected error %q but got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if estimate.Status != EstimateStatusDraft {
+				t.Errorf("expected status draft, got %s", estimate.Status)
+			}
+			if estimate.Revision != 1 {
+				t.Errorf("expected revision 1, got %d", estimate.Revision)
+			}
+		})
+	}
+}
+
+func TestEstimate_SetItems(t *testing.T) {
+	estimate, _ := NewEstimate("cust123", "template123", "123 Main St", time.Now().Add(24*time.Hour))
+
+	err := estimate.SetItems([]EstimateItem{
+		{ItemID: "item1", Name: "Outlet", Quantity: 4, Price: Cents(1250)},
+		{ItemID: "item2", Name: "Switch", Quantity: 2, Price: Cents(800)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if estimate.TotalAmount != Cents(6600) {
+		t.Errorf("expected total 66.00, got %s", estimate.TotalAmount)
+	}
+	for _, item := range estimate.Items {
+		if item.ID == "" {
+			t.Error("expected item ID to be assigned")
+		}
+		if item.EstimateID != estimate.ID {
+			t.Errorf("expected estimate ID %s, got %s", estimate.ID, item.EstimateID)
+		}
+	}
+	if estimate.Items[0].Total != Cents(5000) {
+		t.Errorf("expected line total 50.00, got %s", estimate.Items[0].Total)
+	}
+
+	if err := estimate.SetItems([]EstimateItem{{ItemID: "item1", Quantity: -1}}); err == nil {
+		t.Error("expected error for negative quantity")
+	}
+
+	estimate.Status = EstimateStatusSent
+	if err := estimate.SetItems(nil); err == nil {
+		t.Error("expected error editing a sent estimate")
+	}
+}
+
+func TestEstimate_Send(t *testing.T) {
+	estimate, _ := NewEstimate("cust123", "template123", "123 Main St", time.Now().Add(24*time.Hour))
+
+	if err := estimate.Send(); err == nil || err.Error() != "estimate must have at least one item" {
+		t.Errorf("expected empty estimate error, got %v", err)
+	}
+
+	estimate.SetItems([]EstimateItem{{ItemID: "item1", Quantity: 1, Price: Cents(500)}})
+	if err := estimate.Send(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate.Status != EstimateStatusSent || estimate.SentAt == nil {
+		t.Error("expected estimate to be sent with a sent date")
+	}
+
+	if err := estimate.Send(); err == nil {
+		t.Error("expected error sending an estimate twice")
+	}
+}
+
+func TestEstimate_Decisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(e *Estimate)
+		decide     func(e *Estimate) error
+		wantStatus EstimateStatus
+		wantErr    bool
+	}{
+		{
+			name:       "accept sent estimate",
+			decide:     (*Estimate).Accept,
+			wantStatus: EstimateStatusAccepted,
+		},
+		{
+			name:       "reject sent estimate",
+			decide:     (*Estimate).Reject,
+			wantStatus: EstimateStatusRejected,
+		},
+		{
+			name:       "accept past due estimate",
+			setup:      func(e *Estimate) { e.ValidUntil = time.Now().Add(-time.Hour) },
+			decide:     (*Estimate).Accept,
+			wantStatus: EstimateStatusExpired,
+			wantErr:    true,
+		},
+		{
+			name:       "accept draft estimate",
+			setup:      func(e *Estimate) { e.Status = EstimateStatusDraft },
+			decide:     (*Estimate).Accept,
+			wantStatus: EstimateStatusDraft,
+			wantErr:    true,
+		},
+		{
+			name:       "reject accepted estimate",
+			setup:      func(e *Estimate) { e.Status = EstimateStatusAccepted },
+			decide:     (*Estimate).Reject,
+			wantStatus: EstimateStatusAccepted,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := newSentEstimate(t)
+			if tt.setup != nil {
+				tt.setup(estimate)
+			}
+
+			err := tt.decide(estimate)
+			if tt.wantErr && err == nil {
+				t.Error("expected error but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if estimate.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, estimate.Status)
+			}
+			if !tt.wantErr && estimate.DecidedAt == nil {
+				t.Error("expected decided date to be set")
+			}
+		})
+	}
+}
+
+func TestEstimate_Revise(t *testing.T) {
+	estimate := newSentEstimate(t)
+	estimate.Reject()
+
+	if err := estimate.Revise(time.Now().Add(-time.Hour)); err == nil {
+		t.Error("expected error for past valid until date")
+	}
+
+	validUntil := time.Now().Add(48 * time.Hour)
+	if err := estimate.Revise(validUntil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate.Status != EstimateStatusDraft {
+		t.Errorf("expected status draft, got %s", estimate.Status)
+	}
+	if estimate.Revision != 2 {
+		t.Errorf("expected revision 2, got %d", estimate.Revision)
+	}
+	if estimate.SentAt != nil || estimate.DecidedAt != nil {
+		t.Error("expected sent and decided dates to be cleared")
+	}
+	if !estimate.ValidUntil.Equal(validUntil) {
+		t.Error("expected valid until date to be updated")
+	}
+
+	if err := estimate.Revise(validUntil); err == nil {
+		t.Error("expected error revising a draft")
+	}
+
+	estimate.Status = EstimateStatusAccepted
+	if err := estimate.Revise(validUntil); err == nil {
+		t.Error("expected error revising an accepted estimate")
+	}
+}
+
+func TestEstimate_ExpireIfPastDue(t *testing.T) {
+	estimate := newSentEstimate(t)
+
+	if estimate.ExpireIfPastDue(time.Now()) {
+		t.Error("expected estimate within its validity date not to expire")
+	}
+
+	if !estimate.ExpireIfPastDue(estimate.ValidUntil.Add(time.Minute)) {
+		t.Error("expected past due estimate to expire")
+	}
+	if estimate.Status != EstimateStatusExpired {
+		t.Errorf("expected status expired, got %s", estimate.Status)
+	}
+
+	if estimate.ExpireIfPastDue(estimate.ValidUntil.Add(time.Hour)) {
+		t.Error("expected an expired estimate not to change again")
+	}
+}
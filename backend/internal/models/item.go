@@ -1,7 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,20 +16,82 @@ var (
 	ErrValidation = errors.New("validation error")
 )
 
-// Item represents an inventory item that can be used in jobs
+// Item represents an inventory item that can be used in jobs. UnitCost is what
+// the item costs us: the latest supplier cost already in effect, or the cost
+// set on the item when it has no history. LaborHours are the hours it takes
+// to install one unit (see labor.go). An item with components is an assembly,
+// priced from its parts (see assembly.go).
 type Item struct {
 	ID        string    `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name"`
 	Nickname  string    `json:"nickname,omitempty" db:"nickname"` // Display name for job cards
 	Unit      string    `json:"unit" db:"unit"`
-	UnitPrice float64   `json:"unitPrice" db:"unit_price"`
+	UnitPrice Money     `json:"unitPrice" db:"unit_price"`
+	UnitCost  Money     `json:"unitCost" db:"unit_cost"`
+	LaborHours float64  `json:"laborHours" db:"labor_hours"`
 	Category  string    `json:"category" db:"category"`
 	CreatedAt time.Time `json:"createdAt" db:"created_at"`
 	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+
+	Components      []AssemblyComponent `json:"components,omitempty"`
+	PriceOverride   *Money              `json:"priceOverride,omitempty" db:"price_override"`
+	ExpandOnInvoice bool                `json:"expandOnInvoice,omitempty" db:"expand_on_invoice"`
+}
+
+// ItemCost is what a supplier charges for an item from EffectiveFrom onward
+type ItemCost struct {
+	ID            string    `json:"id" db:"id"`
+	ItemID        string    `json:"itemId" db:"item_id"`
+	Supplier      string    `json:"supplier,omitempty" db:"supplier"`
+	Cost          Money     `json:"cost" db:"cost"`
+	EffectiveFrom time.Time `json:"effectiveFrom" db:"effective_from"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ItemPriceChange records a change to an item's unit price
+type ItemPriceChange struct {
+	ID        string    `json:"id" db:"id"`
+	ItemID    string    `json:"itemId" db:"item_id"`
+	OldPrice  Money     `json:"oldPrice" db:"old_price"`
+	NewPrice  Money     `json:"newPrice" db:"new_price"`
+	ChangedAt time.Time `json:"changedAt" db:"changed_at"`
+}
+
+// NewItemPriceChange records an item's price moving from oldPrice to newPrice
+func NewItemPriceChange(itemID string, oldPrice, newPrice Money, changedAt time.Time) *ItemPriceChange {
+	return &ItemPriceChange{
+		ID:        uuid.New().String(),
+		ItemID:    itemID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		ChangedAt: changedAt,
+	}
+}
+
+// NewItemCost creates a new ItemCost with validation
+func NewItemCost(itemID, supplier string, cost Money, effectiveFrom time.Time) (*ItemCost, error) {
+	if itemID == "" {
+		return nil, errors.New("item ID is required")
+	}
+	if cost < 0 {
+		return nil, errors.New("unit cost cannot be negative")
+	}
+	if effectiveFrom.IsZero() {
+		return nil, errors.New("effective from date is required")
+	}
+
+	return &ItemCost{
+		ID:            uuid.New().String(),
+		ItemID:        itemID,
+		Supplier:      supplier,
+		Cost:          cost,
+		EffectiveFrom: taxDate(effectiveFrom),
+		CreatedAt:     time.Now(),
+	}, nil
 }
 
 // NewItem creates a new Item with validation
-func NewItem(name, unit string, unitPrice float64, category string) (*Item, error) {
+func NewItem(name, unit string, unitPrice Money, category string) (*Item, error) {
 	if err := validateItemFields(name, unit, unitPrice); err != nil {
 		return nil, err
 	}
@@ -48,6 +114,8 @@ func (i *Item) Update(updates map[string]interface{}) error {
 	name := i.Name
 	unit := i.Unit
 	unitPrice := i.UnitPrice
+	unitCost := i.UnitCost
+	laborHours := i.LaborHours
 
 	// Apply updates to temp variables first
 	if val, ok := updates["name"]; ok {
@@ -57,13 +125,43 @@ func (i *Item) Update(updates map[string]interface{}) error {
 		unit = val.(string)
 	}
 	if val, ok := updates["unit_price"]; ok {
-		unitPrice = val.(float64)
+		price, err := moneyUpdate("unit price", val)
+		if err != nil {
+			return err
+		}
+		unitPrice = price
+	}
+	if val, ok := updates["unit_cost"]; ok {
+		cost, err := moneyUpdate("unit cost", val)
+		if err != nil {
+			return err
+		}
+		unitCost = cost
+	}
+	if val, ok := updates["labor_hours"]; ok {
+		hours, err := hoursUpdate(val)
+		if err != nil {
+			return err
+		}
+		laborHours = hours
 	}
 
 	// Validate all fields
 	if err := validateItemFields(name, unit, unitPrice); err != nil {
 		return err
 	}
+	if unitCost < 0 {
+		return errors.New("unit cost cannot be negative")
+	}
+	if laborHours < 0 {
+		return errors.New("labor hours cannot be negative")
+	}
+	if i.IsAssembly() && (unitPrice != i.UnitPrice || unitCost != i.UnitCost) {
+		return ErrAssemblyPriced
+	}
+	if i.IsAssembly() && laborHours != i.LaborHours {
+		return ErrAssemblyLabor
+	}
 
 	// Apply updates if validation passes
 	if val, ok := updates["name"]; ok {
@@ -75,8 +173,14 @@ func (i *Item) Update(updates map[string]interface{}) error {
 	if val, ok := updates["unit"]; ok {
 		i.Unit = val.(string)
 	}
-	if val, ok := updates["unit_price"]; ok {
-		i.UnitPrice = val.(float64)
+	if _, ok := updates["unit_price"]; ok {
+		i.UnitPrice = unitPrice
+	}
+	if _, ok := updates["unit_cost"]; ok {
+		i.UnitCost = unitCost
+	}
+	if _, ok := updates["labor_hours"]; ok {
+		i.LaborHours = laborHours
 	}
 	if val, ok := updates["category"]; ok {
 		i.Category = val.(string)
@@ -86,8 +190,53 @@ func (i *Item) Update(updates map[string]interface{}) error {
 	return nil
 }
 
+// moneyUpdate reads a price from an update map. Decoders that keep numbers
+// as json.Number or strings are parsed exactly; float64 is rounded to the cent.
+func moneyUpdate(field string, val interface{}) (Money, error) {
+	switch v := val.(type) {
+	case Money:
+		return v, nil
+	case json.Number:
+		return ParseMoney(v.String())
+	case string:
+		return ParseMoney(v)
+	case float64:
+		return MoneyFromFloat(v), nil
+	case int:
+		return Cents(int64(v) * 100), nil
+	default:
+		return 0, fmt.Errorf("%s must be a number", field)
+	}
+}
+
+// hoursUpdate reads labor hours from an update map, to four decimal places
+func hoursUpdate(val interface{}) (float64, error) {
+	var hours float64
+	switch v := val.(type) {
+	case float64:
+		hours = v
+	case int:
+		hours = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, errors.New("labor hours must be a number")
+		}
+		hours = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, errors.New("labor hours must be a number")
+		}
+		hours = parsed
+	default:
+		return 0, errors.New("labor hours must be a number")
+	}
+	return roundHours(hours), nil
+}
+
 // validateItemFields validates item fields
-func validateItemFields(name, unit string, unitPrice float64) error {
+func validateItemFields(name, unit string, unitPrice Money) error {
 	if name == "" {
 		return errors.New("item name is required")
 	}
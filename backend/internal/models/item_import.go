@@ -0,0 +1,277 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ItemCSVColumns are the columns of the item catalog CSV, in export order
+var ItemCSVColumns = []string{"id", "name", "nickname", "unit", "unit_price", "unit_cost", "category", "labor_hours"}
+
+// ItemImportAction is what importing a row does to the catalog
+type ItemImportAction string
+
+const (
+	ItemImportCreate    ItemImportAction = "create"
+	ItemImportUpdate    ItemImportAction = "update"
+	ItemImportUnchanged ItemImportAction = "unchanged"
+	ItemImportInvalid   ItemImportAction = "invalid"
+)
+
+// ItemImportRow is one row of an item import. Line is the row's line in the
+// file. Item is the item to create, or Updates the changes to ItemID.
+type ItemImportRow struct {
+	Line    int                    `json:"line"`
+	Action  ItemImportAction       `json:"action"`
+	ItemID  string                 `json:"itemId,omitempty"`
+	Name    string                 `json:"name"`
+	Errors  []string               `json:"errors,omitempty"`
+	Item    *Item                  `json:"-"`
+	Updates map[string]interface{} `json:"-"`
+}
+
+// ItemImport is the outcome of importing a catalog CSV, row by row
+type ItemImport struct {
+	Applied   bool            `json:"applied"`
+	Rows      []ItemImportRow `json:"rows"`
+	Created   int             `json:"created"`
+	Updated   int             `json:"updated"`
+	Unchanged int             `json:"unchanged"`
+	Invalid   int             `json:"invalid"`
+}
+
+// CSVError is a problem with an uploaded CSV file as a whole, as opposed to
+// one of its rows
+type CSVError struct {
+	Err error
+}
+
+func (e *CSVError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+// PlanItemImport reads a catalog CSV and works out what each row does to the
+// existing items. A row matches an item by its id or, without one, by name
+// ignoring case; rows that match nothing create an item. Columns left out of
+// the file are left unchanged on existing items. It returns an error only when
+// the file itself cannot be read, as a *CSVError; problems with a row are
+// reported on the row.
+func PlanItemImport(r io.Reader, existing []*Item) (*ItemImport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, &CSVError{errors.New("the file is empty")}
+	}
+	if err != nil {
+		return nil, &CSVError{fmt.Errorf("failed to read the header: %w", err)}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isItemCSVColumn(name) {
+			return nil, &CSVError{fmt.Errorf("unknown column %q; columns are %s", name, strings.Join(ItemCSVColumns, ", "))}
+		}
+		if _, ok := columns[name]; ok {
+			return nil, &CSVError{fmt.Errorf("column %q appears twice", name)}
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, &CSVError{errors.New("the name column is required")}
+	}
+
+	byID := make(map[string]*Item, len(existing))
+	byName := make(map[string][]*Item, len(existing))
+	for _, item := range existing {
+		byID[item.ID] = item
+		key := strings.ToLower(strings.TrimSpace(item.Name))
+		byName[key] = append(byName[key], item)
+	}
+
+	plan := &ItemImport{Rows: []ItemImportRow{}}
+	matchedOn := make(map[string]int)
+	createdOn := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &CSVError{fmt.Errorf("failed to read the file: %w", err)}
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(column string) (string, bool) {
+			i, ok := columns[column]
+			if !ok {
+				return "", false
+			}
+			return strings.TrimSpace(record[i]), true
+		}
+
+		row := ItemImportRow{Line: line}
+		row.Name, _ = value("name")
+
+		var match *Item
+		if id, _ := value("id"); id != "" {
+			if match = byID[id]; match == nil {
+				row.Errors = append(row.Errors, fmt.Sprintf("no item has ID %s", id))
+			}
+		} else if matches := byName[strings.ToLower(row.Name)]; len(matches) > 1 {
+			row.Errors = append(row.Errors, fmt.Sprintf("%d items are named %s; give the ID of the one to update", len(matches), row.Name))
+		} else if len(matches) == 1 {
+			match = matches[0]
+		}
+		if previous, ok := createdOn[strings.ToLower(row.Name)]; ok && match == nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("line %d already creates an item named %s", previous, row.Name))
+		}
+		if match != nil {
+			row.ItemID = match.ID
+			if previous, ok := matchedOn[match.ID]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("line %d already changes this item", previous))
+			}
+			matchedOn[match.ID] = line
+		}
+
+		// Read the row into the same updates an item edit makes
+		updates := make(map[string]interface{})
+		for _, column := range []string{"name", "nickname", "unit", "category"} {
+			if v, ok := value(column); ok {
+				updates[column] = v
+			}
+		}
+		for _, column := range []string{"unit_price", "unit_cost"} {
+			v, ok := value(column)
+			if !ok {
+				continue
+			}
+			amount := Money(0)
+			if v != "" {
+				parsed, err := ParseMoney(strings.TrimPrefix(v, "$"))
+				if err != nil {
+					row.Errors = append(row.Errors, fmt.Sprintf("%s must be a number", strings.ReplaceAll(column, "_", " ")))
+					continue
+				}
+				amount = parsed
+			}
+			updates[column] = amount
+		}
+		if v, ok := value("labor_hours"); ok {
+			if v == "" {
+				v = "0"
+			}
+			updates["labor_hours"] = v
+		}
+
+		if len(row.Errors) == 0 {
+			if match != nil {
+				planItemUpdate(&row, match, updates)
+			} else {
+				planItemCreate(&row, updates)
+				if row.Action == ItemImportCreate {
+					createdOn[strings.ToLower(row.Name)] = line
+				}
+			}
+		}
+
+		if len(row.Errors) > 0 {
+			row.Action = ItemImportInvalid
+			row.Item, row.Updates = nil, nil
+		}
+
+		switch row.Action {
+		case ItemImportCreate:
+			plan.Created++
+		case ItemImportUpdate:
+			plan.Updated++
+		case ItemImportUnchanged:
+			plan.Unchanged++
+		case ItemImportInvalid:
+			plan.Invalid++
+		}
+		plan.Rows = append(plan.Rows, row)
+	}
+
+	return plan, nil
+}
+
+// planItemUpdate validates the row's changes to an existing item
+func planItemUpdate(row *ItemImportRow, existing *Item, updates map[string]interface{}) {
+	updated := *existing
+	if err := updated.Update(updates); err != nil {
+		row.Errors = append(row.Errors, err.Error())
+		return
+	}
+
+	if updated.Name == existing.Name && updated.Nickname == existing.Nickname &&
+		updated.Unit == existing.Unit && updated.UnitPrice == existing.UnitPrice &&
+		updated.UnitCost == existing.UnitCost && updated.Category == existing.Category &&
+		updated.LaborHours == existing.LaborHours {
+		row.Action = ItemImportUnchanged
+		return
+	}
+	row.Action = ItemImportUpdate
+	row.Updates = updates
+}
+
+// planItemCreate validates the row as a new item
+func planItemCreate(row *ItemImportRow, updates map[string]interface{}) {
+	name, _ := updates["name"].(string)
+	unit, _ := updates["unit"].(string)
+	category, _ := updates["category"].(string)
+	unitPrice, _ := updates["unit_price"].(Money)
+
+	item, err := NewItem(name, unit, unitPrice, category)
+	if err != nil {
+		row.Errors = append(row.Errors, err.Error())
+		return
+	}
+	if err := item.Update(updates); err != nil {
+		row.Errors = append(row.Errors, err.Error())
+		return
+	}
+
+	row.Action = ItemImportCreate
+	row.ItemID = item.ID
+	row.Item = item
+}
+
+// WriteItemsCSV writes items as a catalog CSV that PlanItemImport can read back
+func WriteItemsCSV(w io.Writer, items []*Item) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(ItemCSVColumns); err != nil {
+		return err
+	}
+	for _, item := range items {
+		err := writer.Write([]string{
+			item.ID, item.Name, item.Nickname, item.Unit,
+			item.UnitPrice.String(), item.UnitCost.String(), item.Category,
+			strconv.FormatFloat(item.LaborHours, 'f', -1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func isItemCSVColumn(name string) bool {
+	for _, column := range ItemCSVColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
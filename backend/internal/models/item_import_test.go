@@ -0,0 +1,152 @@
+package models
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func catalog() []*Item {
+	return []*Item{
+		{ID: "outlet", Name: "Outlet", Unit: "each", UnitPrice: Cents(1500), UnitCost: Cents(600), Category: "Devices"},
+		{ID: "wire", Name: "12/2 Wire", Unit: "ft", UnitPrice: Cents(89), Category: "Materials"},
+		{ID: "box-1", Name: "Box", Unit: "each", UnitPrice: Cents(300), Category: "Materials"},
+		{ID: "box-2", Name: "box", Unit: "each", UnitPrice: Cents(450), Category: "Materials"},
+	}
+}
+
+func TestPlanItemImport(t *testing.T) {
+	file := strings.Join([]string{
+		"name,unit,unit_price,id,category",
+		"Outlet,each,15.00,,Devices",    // unchanged, matched by name
+		"12/2 WIRE,ft,$0.95,,Materials", // price change, matched by name ignoring case
+		"GFCI Outlet,each,32.50,,Devices",
+		"Panel,each,-1,,Panels",    // fails validateItemFields
+		"Breaker,,12.00,,Panels",   // fails validateItemFields
+		"Switch,each,abc,,Devices", // price is not a number
+		"Box,each,3.25,,Materials", // two items are named box
+		"Relabelled,each,3.25,box-2,Materials",
+		"Ghost,each,1.00,missing,Misc",    // unknown ID
+		"gfci outlet,each,30.00,,Devices", // created twice
+	}, "\n")
+
+	plan, err := PlanItemImport(strings.NewReader(file), catalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		action ItemImportAction
+		itemID string
+		errors []string
+	}{
+		{ItemImportUnchanged, "outlet", nil},
+		{ItemImportUpdate, "wire", nil},
+		{ItemImportCreate, "", nil},
+		{ItemImportInvalid, "", []string{"unit price must be positive"}},
+		{ItemImportInvalid, "", []string{"unit is required"}},
+		{ItemImportInvalid, "", []string{"unit price must be a number"}},
+		{ItemImportInvalid, "", []string{"2 items are named Box; give the ID of the one to update"}},
+		{ItemImportUpdate, "box-2", nil},
+		{ItemImportInvalid, "", []string{"no item has ID missing"}},
+		{ItemImportInvalid, "", []string{"line 4 already creates an item named gfci outlet"}},
+	}
+	if len(plan.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), plan.Rows)
+	}
+	for i, w := range want {
+		row := plan.Rows[i]
+		if row.Line != i+2 {
+			t.Errorf("row %d: expected line %d, got %d", i, i+2, row.Line)
+		}
+		if row.Action != w.action || !reflect.DeepEqual(row.Errors, w.errors) {
+			t.Errorf("line %d: expected %s %v, got %s %v", row.Line, w.action, w.errors, row.Action, row.Errors)
+		}
+		if w.itemID != "" && row.ItemID != w.itemID {
+			t.Errorf("line %d: expected item %s, got %s", row.Line, w.itemID, row.ItemID)
+		}
+	}
+
+	if plan.Created != 1 || plan.Updated != 2 || plan.Unchanged != 1 || plan.Invalid != 6 {
+		t.Errorf("expected 1 created, 2 updated, 1 unchanged and 6 invalid, got %+v", plan)
+	}
+	if created := plan.Rows[2].Item; created == nil || created.UnitPrice != Cents(3250) || created.Category != "Devices" {
+		t.Errorf("expected a 32.50 GFCI outlet to create, got %+v", created)
+	}
+	if updates := plan.Rows[1].Updates; updates["unit_price"] != Cents(95) {
+		t.Errorf("expected the wire price to change to 0.95, got %+v", updates)
+	}
+}
+
+func TestPlanItemImport_LaborHours(t *testing.T) {
+	file := strings.Join([]string{
+		"id,name,labor_hours",
+		"outlet,Outlet,0.3",
+		"wire,12/2 Wire,",
+		"box-1,Box,-1",
+		"box-2,box,lots",
+	}, "\n")
+
+	plan, err := PlanItemImport(strings.NewReader(file), catalog())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		action ItemImportAction
+		errors []string
+	}{
+		{ItemImportUpdate, nil},
+		{ItemImportUnchanged, nil},
+		{ItemImportInvalid, []string{"labor hours cannot be negative"}},
+		{ItemImportInvalid, []string{"labor hours must be a number"}},
+	}
+	for i, w := range want {
+		row := plan.Rows[i]
+		if row.Action != w.action || !reflect.DeepEqual(row.Errors, w.errors) {
+			t.Errorf("line %d: expected %s %v, got %s %v", row.Line, w.action, w.errors, row.Action, row.Errors)
+		}
+	}
+}
+
+func TestPlanItemImport_BadHeader(t *testing.T) {
+	tests := []struct {
+		file   string
+		errMsg string
+	}{
+		{"", "the file is empty"},
+		{"unit,unit_price\neach,1.00", "the name column is required"},
+		{"name,price\nOutlet,1.00", `unknown column "price"; columns are id, name, nickname, unit, unit_price, unit_cost, category, labor_hours`},
+		{"name,Name\nOutlet,Outlet", `column "name" appears twice`},
+	}
+
+	for _, tt := range tests {
+		if _, err := PlanItemImport(strings.NewReader(tt.file), nil); err == nil || err.Error() != tt.errMsg {
+			t.Errorf("expected error %q, got %v", tt.errMsg, err)
+		}
+	}
+}
+
+func TestWriteItemsCSV_RoundTrips(t *testing.T) {
+	items := catalog()
+	items[0].Nickname = "Duplex, 15A"
+	items[0].LaborHours = 0.25
+
+	var buf bytes.Buffer
+	if err := WriteItemsCSV(&buf, items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "id,name,nickname,unit,unit_price,unit_cost,category,labor_hours\noutlet,Outlet,\"Duplex, 15A\",each,15.00,6.00,Devices,0.25\n") {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+
+	// Importing the export changes nothing
+	plan, err := PlanItemImport(&buf, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Unchanged != len(items) {
+		t.Errorf("expected every row to be unchanged, got %+v", plan.Rows)
+	}
+}
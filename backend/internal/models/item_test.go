@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -10,7 +11,7 @@ func TestNewItem(t *testing.T) {
 		name      string
 		itemName  string
 		unit      string
-		unitPrice float64
+		unitPrice Money
 		category  string
 		wantErr   bool
 		errMsg    string
@@ -19,7 +20,7 @@ func TestNewItem(t *testing.T) {
 			name:      "valid item",
 			itemName:  "Outlet",
 			unit:      "each",
-			unitPrice: 15.50,
+			unitPrice: Cents(1550),
 			category:  "Electrical",
 			wantErr:   false,
 		},
@@ -27,7 +28,7 @@ func TestNewItem(t *testing.T) {
 			name:      "empty name",
 			itemName:  "",
 			unit:      "each",
-			unitPrice: 15.50,
+			unitPrice: Cents(1550),
 			category:  "Electrical",
 			wantErr:   true,
 			errMsg:    "item name is required",
@@ -36,7 +37,7 @@ func TestNewItem(t *testing.T) {
 			name:      "empty unit",
 			itemName:  "Outlet",
 			unit:      "",
-			unitPrice: 15.50,
+			unitPrice: Cents(1550),
 			category:  "Electrical",
 			wantErr:   true,
 			errMsg:    "unit is required",
@@ -45,7 +46,7 @@ func TestNewItem(t *testing.T) {
 			name:      "negative price",
 			itemName:  "Outlet",
 			unit:      "each",
-			unitPrice: -10.00,
+			unitPrice: Cents(-1000),
 			category:  "Electrical",
 			wantErr:   true,
 			errMsg:    "unit price must be positive",
@@ -88,7 +89,7 @@ func TestNewItem(t *testing.T) {
 				}
 				
 				if item.UnitPrice != tt.unitPrice {
-					t.Errorf("expected unit price %f but got %f", tt.unitPrice, item.UnitPrice)
+					t.Errorf("expected unit price %s but got %s", tt.unitPrice, item.UnitPrice)
 				}
 				
 				if item.Category != tt.category {
@@ -112,7 +113,7 @@ func TestNewItem(t *testing.T) {
 }
 
 func TestItem_Update(t *testing.T) {
-	item, err := NewItem("Outlet", "each", 15.50, "Electrical")
+	item, err := NewItem("Outlet", "each", Cents(1550), "Electrical")
 	if err != nil {
 		t.Fatalf("failed to create item: %v", err)
 	}
@@ -165,6 +166,21 @@ func TestItem_Update(t *testing.T) {
 			wantErr: true,
 			errMsg:  "item name is required",
 		},
+		{
+			name: "update cost",
+			updates: map[string]interface{}{
+				"unit_cost": json.Number("7.25"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative cost",
+			updates: map[string]interface{}{
+				"unit_cost": -1.00,
+			},
+			wantErr: true,
+			errMsg:  "unit cost cannot be negative",
+		},
 	}
 	
 	for _, tt := range tests {
@@ -193,8 +209,14 @@ func TestItem_Update(t *testing.T) {
 				}
 				
 				if price, ok := tt.updates["unit_price"]; ok {
-					if testItem.UnitPrice != price.(float64) {
-						t.Errorf("expected price %f but got %f", price, testItem.UnitPrice)
+					if testItem.UnitPrice != MoneyFromFloat(price.(float64)) {
+						t.Errorf("expected price %v but got %s", price, testItem.UnitPrice)
+					}
+				}
+				
+				if cost, ok := tt.updates["unit_cost"]; ok {
+					if want, _ := moneyUpdate("unit cost", cost); testItem.UnitCost != want {
+						t.Errorf("expected cost %v but got %s", cost, testItem.UnitCost)
 					}
 				}
 				
@@ -205,4 +227,23 @@ func TestItem_Update(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestNewItemCost(t *testing.T) {
+	effective := time.Date(2026, 3, 1, 15, 30, 0, 0, time.UTC)
+
+	cost, err := NewItemCost("item-1", "City Electric", Cents(725), effective)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cost.EffectiveFrom.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the cost to take effect from the start of the day, got %v", cost.EffectiveFrom)
+	}
+
+	if _, err := NewItemCost("item-1", "", Cents(-1), effective); err == nil || err.Error() != "unit cost cannot be negative" {
+		t.Errorf("expected a negative cost error, got %v", err)
+	}
+	if _, err := NewItemCost("item-1", "", Cents(725), time.Time{}); err == nil || err.Error() != "effective from date is required" {
+		t.Errorf("expected a missing date error, got %v", err)
+	}
+}
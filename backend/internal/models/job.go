@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +23,7 @@ type Job struct {
 	ID             string      `json:"id" db:"id"`
 	CustomerID     string      `json:"customerId" db:"customer_id"`
 	TemplateID     string      `json:"templateId" db:"template_id"`
+	TemplateVersion int        `json:"templateVersion" db:"template_version"`
 	Address        string      `json:"address" db:"address"`
 	Status         JobStatus   `json:"status" db:"status"`
 	CurrentPhaseID *string     `json:"currentPhaseId,omitempty" db:"current_phase_id"`
@@ -30,9 +32,13 @@ type Job struct {
 	EndDate        *time.Time  `json:"endDate,omitempty" db:"end_date"`
 	PermitRequired bool        `json:"permitRequired" db:"permit_required"`
 	PermitNumber   string      `json:"permitNumber,omitempty" db:"permit_number"`
-	TotalAmount    float64     `json:"totalAmount" db:"total_amount"`
+	TotalAmount    Money       `json:"totalAmount" db:"total_amount"`
 	Items          []JobItem   `json:"items"`
 	Photos         []JobPhoto  `json:"photos"`
+	Phases         []JobPhase  `json:"phases"`
+	ChangeOrders   []ChangeOrder `json:"changeOrders"`
+	Adjustments    []PriceAdjustment `json:"adjustments"`
+	AdjustmentLines []AdjustmentLine `json:"adjustmentLines"`
 	Notes          string      `json:"notes,omitempty" db:"notes"`
 	WaveInvoiceID  string      `json:"waveInvoiceId,omitempty" db:"wave_invoice_id"`
 	WaveInvoiceURL string      `json:"waveInvoiceUrl,omitempty" db:"wave_invoice_url"`
@@ -40,16 +46,139 @@ type Job struct {
 	UpdatedAt      time.Time   `json:"updatedAt" db:"updated_at"`
 }
 
-// JobItem represents an item used in a job
+// JobItem represents an item used in a job. Quantity is what has been
+// installed so far; BudgetedQuantity is what was planned. Price and Cost are
+// the item's unit price and unit cost when it was added to the job.
 type JobItem struct {
-	ID       string  `json:"id" db:"id"`
-	JobID    string  `json:"jobId" db:"job_id"`
-	ItemID   string  `json:"itemId" db:"item_id"`
-	Name     string  `json:"name" db:"name"`
-	Nickname string  `json:"nickname,omitempty" db:"nickname"`
-	Quantity float64 `json:"quantity" db:"quantity"`
-	Price    float64 `json:"price" db:"price"`
-	Total    float64 `json:"total" db:"total"`
+	ID               string  `json:"id" db:"id"`
+	JobID            string  `json:"jobId" db:"job_id"`
+	ItemID           string  `json:"itemId" db:"item_id"`
+	Name             string  `json:"name" db:"name"`
+	Nickname         string  `json:"nickname,omitempty" db:"nickname"`
+	Category         string  `json:"category,omitempty" db:"category"`
+	Quantity         float64 `json:"quantity" db:"quantity"`
+	BudgetedQuantity float64 `json:"budgetedQuantity" db:"budgeted_quantity"`
+	Price            Money   `json:"price" db:"price"`
+	Cost             Money   `json:"cost" db:"cost"`
+	Total            Money   `json:"total" db:"total"`
+}
+
+// JobStatusChange is one entry in a job's status timeline. FromStatus is nil
+// for the entry recorded when the job is created.
+type JobStatusChange struct {
+	ID            string     `json:"id" db:"id"`
+	JobID         string     `json:"jobId" db:"job_id"`
+	FromStatus    *JobStatus `json:"fromStatus" db:"from_status"`
+	ToStatus      JobStatus  `json:"toStatus" db:"to_status"`
+	ChangedBy     string     `json:"changedBy,omitempty" db:"changed_by"`
+	ChangedByName string     `json:"changedByName,omitempty" db:"changed_by_name"`
+	Note          string     `json:"note,omitempty" db:"note"`
+	ChangedAt     time.Time  `json:"changedAt" db:"changed_at"`
+}
+
+// JobRevision is a named, immutable snapshot of a job's line items and total
+type JobRevision struct {
+	ID          string    `json:"id" db:"id"`
+	JobID       string    `json:"jobId" db:"job_id"`
+	Number      int       `json:"number" db:"revision_number"`
+	Name        string    `json:"name" db:"name"`
+	Note        string    `json:"note,omitempty" db:"note"`
+	AuthorID    string    `json:"authorId" db:"author_id"`
+	AuthorName  string    `json:"authorName" db:"author_name"`
+	Items       []JobItem `json:"items"`
+	TotalAmount Money     `json:"totalAmount" db:"total_amount"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// LineItemChangeType describes how a line differs between two sets of job items
+type LineItemChangeType string
+
+const (
+	LineItemAdded   LineItemChangeType = "added"
+	LineItemRemoved LineItemChangeType = "removed"
+	LineItemChanged LineItemChangeType = "changed"
+)
+
+// LineItemChange describes one line whose budgeted scope differs between two
+// sets of job items. The installed quantities are included for reference.
+type LineItemChange struct {
+	JobItemID            string             `json:"jobItemId"`
+	ItemID               string             `json:"itemId"`
+	Name                 string             `json:"name"`
+	Type                 LineItemChangeType `json:"type"`
+	FromBudgetedQuantity float64            `json:"fromBudgetedQuantity"`
+	ToBudgetedQuantity   float64            `json:"toBudgetedQuantity"`
+	FromQuantity         float64            `json:"fromQuantity"`
+	ToQuantity           float64            `json:"toQuantity"`
+	FromPrice            Money              `json:"fromPrice"`
+	ToPrice              Money              `json:"toPrice"`
+	TotalDelta           Money              `json:"totalDelta"`
+}
+
+// JobItemDiff is the line-level difference between two sets of job items,
+// with totals on their budgeted quantities
+type JobItemDiff struct {
+	Added      []LineItemChange `json:"added"`
+	Removed    []LineItemChange `json:"removed"`
+	Changed    []LineItemChange `json:"changed"`
+	FromTotal  Money            `json:"fromTotal"`
+	ToTotal    Money            `json:"toTotal"`
+	TotalDelta Money            `json:"totalDelta"`
+}
+
+// VarianceStatus describes how an item's installed quantity compares to its budget
+type VarianceStatus string
+
+const (
+	VarianceOver     VarianceStatus = "over"
+	VarianceUnder    VarianceStatus = "under"
+	VarianceOnBudget VarianceStatus = "on_budget"
+)
+
+// ItemVariance compares one job item's installed quantity to its budget
+type ItemVariance struct {
+	JobItemID         string         `json:"jobItemId"`
+	ItemID            string         `json:"itemId"`
+	Name              string         `json:"name"`
+	Price             Money          `json:"price"`
+	BudgetedQuantity  float64        `json:"budgetedQuantity"`
+	InstalledQuantity float64        `json:"installedQuantity"`
+	QuantityVariance  float64        `json:"quantityVariance"`
+	BudgetedAmount    Money          `json:"budgetedAmount"`
+	InstalledAmount   Money          `json:"installedAmount"`
+	AmountVariance    Money          `json:"amountVariance"`
+	Status            VarianceStatus `json:"status"`
+}
+
+// JobVarianceReport compares installed quantities to budgeted quantities for
+// every item on a job. Positive variances are overruns.
+type JobVarianceReport struct {
+	JobID          string         `json:"jobId"`
+	Items          []ItemVariance `json:"items"`
+	BudgetedTotal  Money          `json:"budgetedTotal"`
+	InstalledTotal Money          `json:"installedTotal"`
+	AmountVariance Money          `json:"amountVariance"`
+	OverBudget     int            `json:"overBudget"`
+	UnderBudget    int            `json:"underBudget"`
+}
+
+// JobRepricing is what moving a job's lines to current item prices changes:
+// the lines whose price changes and the job total before and after
+type JobRepricing struct {
+	JobID      string           `json:"jobId"`
+	Lines      []LineItemChange `json:"lines"`
+	FromTotal  Money            `json:"fromTotal"`
+	ToTotal    Money            `json:"toTotal"`
+	TotalDelta Money            `json:"totalDelta"`
+}
+
+// JobTotalDrift describes a job whose stored total differs from the total
+// derived from its items, adjustments and approved change orders
+type JobTotalDrift struct {
+	JobID         string  `json:"jobId"`
+	StoredTotal   Money  `json:"storedTotal"`
+	ComputedTotal Money  `json:"computedTotal"`
+	Difference    Money  `json:"difference"`
 }
 
 // JobPhoto represents a photo associated with a job
@@ -97,6 +226,8 @@ func NewJob(customerID, templateID string, status JobStatus, scheduledDate time.
 		TotalAmount:    0,
 		Items:          []JobItem{},
 		Photos:         []JobPhoto{},
+		Phases:         []JobPhase{},
+		ChangeOrders:   []ChangeOrder{},
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}, nil
@@ -109,11 +240,43 @@ func (j *Job) AddPhoto(photo JobPhoto) {
 	j.UpdatedAt = time.Now()
 }
 
-// UpdateStatus updates the job status with validation
+// jobStatusTransitions lists the statuses a job may move to from each status.
+// Completed and cancelled jobs are final.
+var jobStatusTransitions = map[JobStatus][]JobStatus{
+	JobStatusScheduled:  {JobStatusInProgress, JobStatusCancelled},
+	JobStatusInProgress: {JobStatusScheduled, JobStatusCompleted, JobStatusCancelled},
+	JobStatusCompleted:  {},
+	JobStatusCancelled:  {},
+}
+
+// CanTransitionJobStatus reports whether a job may move from one status to another
+func CanTransitionJobStatus(from, to JobStatus) bool {
+	for _, allowed := range jobStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateStatus moves the job to another status, enforcing the allowed
+// transitions and guards. A job can only be completed once every phase is done
+// and, when a permit is required, a permit number is recorded.
 func (j *Job) UpdateStatus(status JobStatus) error {
 	if !ValidateJobStatus(status) {
 		return errors.New("invalid job status")
 	}
+	if !CanTransitionJobStatus(j.Status, status) {
+		return fmt.Errorf("cannot change job status from %s to %s", j.Status, status)
+	}
+	if status == JobStatusCompleted {
+		if err := j.CheckPhasesComplete(); err != nil {
+			return err
+		}
+		if j.PermitRequired && j.PermitNumber == "" {
+			return errors.New("a permit number is required to complete this job")
+		}
+	}
 	
 	j.Status = status
 	j.UpdatedAt = time.Now()
@@ -135,23 +298,263 @@ func (j *Job) UpdateStatus(status JobStatus) error {
 	return nil
 }
 
-// CalculateTotal calculates the total amount for the job based on items
+// NewJobStatusChange records a status change made by a user
+func NewJobStatusChange(jobID string, from *JobStatus, to JobStatus, user *User, note string) *JobStatusChange {
+	change := &JobStatusChange{
+		ID:         uuid.New().String(),
+		JobID:      jobID,
+		FromStatus: from,
+		ToStatus:   to,
+		Note:       note,
+		ChangedAt:  time.Now(),
+	}
+	if user != nil {
+		change.ChangedBy = user.ID
+		change.ChangedByName = user.Name
+	}
+	return change
+}
+
+// CalculateTotal recalculates each line total, the adjustment lines and the
+// job total from the items, adjustments and approved change orders
 func (j *Job) CalculateTotal() {
-	total := 0.0
-	for _, item := range j.Items {
-		item.Total = item.Quantity * item.Price
-		total += item.Total
+	var total Money
+	for i := range j.Items {
+		j.Items[i].CalculateTotal()
+		total += j.Items[i].Total
+	}
+	j.AdjustmentLines = ApplyAdjustments(j.Items, j.Adjustments)
+	total += AdjustmentsTotal(j.AdjustmentLines)
+	for _, changeOrder := range j.ChangeOrders {
+		if changeOrder.IsApproved() {
+			total += changeOrder.TotalAmount
+		}
 	}
 	j.TotalAmount = total
 	j.UpdatedAt = time.Now()
 }
 
-// UpdatePhase updates the current phase of the job
-func (j *Job) UpdatePhase(phaseID string) {
-	if phaseID == "" {
-		j.CurrentPhaseID = nil
-	} else {
-		j.CurrentPhaseID = &phaseID
+// Reprice moves the job's lines to the current prices of their items and
+// recalculates the job total. Lines whose item has no current price keep
+// theirs; change orders keep the prices they were raised at.
+func (j *Job) Reprice(prices map[string]Money) JobRepricing {
+	j.CalculateTotal()
+	fromTotal := j.TotalAmount
+
+	lines := []LineItemChange{}
+	for i := range j.Items {
+		item := &j.Items[i]
+		price, ok := prices[item.ItemID]
+		if !ok || price == item.Price {
+			continue
+		}
+		lines = append(lines, LineItemChange{
+			JobItemID:            item.ID,
+			ItemID:               item.ItemID,
+			Name:                 item.Name,
+			Type:                 LineItemChanged,
+			FromBudgetedQuantity: item.BudgetedQuantity,
+			ToBudgetedQuantity:   item.BudgetedQuantity,
+			FromQuantity:         item.Quantity,
+			ToQuantity:           item.Quantity,
+			FromPrice:            item.Price,
+			ToPrice:              price,
+			TotalDelta:           price.MulQuantity(item.Quantity) - item.Price.MulQuantity(item.Quantity),
+		})
+		item.Price = price
 	}
-	j.UpdatedAt = time.Now()
-}
\ No newline at end of file
+	j.CalculateTotal()
+
+	return JobRepricing{
+		JobID:      j.ID,
+		Lines:      lines,
+		FromTotal:  fromTotal,
+		ToTotal:    j.TotalAmount,
+		TotalDelta: j.TotalAmount - fromTotal,
+	}
+}
+
+// CalculateTotal recalculates the line total from its installed quantity
+func (i *JobItem) CalculateTotal() {
+	i.Total = i.Price.MulQuantity(i.Quantity)
+}
+
+// VarianceReport compares each item's installed quantity to its budgeted
+// quantity, in units and in dollars at the item's price
+func (j *Job) VarianceReport() JobVarianceReport {
+	report := JobVarianceReport{
+		JobID: j.ID,
+		Items: make([]ItemVariance, 0, len(j.Items)),
+	}
+
+	for i := range j.Items {
+		item := &j.Items[i]
+		variance := ItemVariance{
+			JobItemID:         item.ID,
+			ItemID:            item.ItemID,
+			Name:              item.Name,
+			Price:             item.Price,
+			BudgetedQuantity:  item.BudgetedQuantity,
+			InstalledQuantity: item.Quantity,
+			QuantityVariance:  item.Quantity - item.BudgetedQuantity,
+			BudgetedAmount:    item.Price.MulQuantity(item.BudgetedQuantity),
+			InstalledAmount:   item.Price.MulQuantity(item.Quantity),
+			Status:            VarianceOnBudget,
+		}
+		variance.AmountVariance = variance.InstalledAmount - variance.BudgetedAmount
+
+		switch {
+		case variance.QuantityVariance > 0:
+			variance.Status = VarianceOver
+			report.OverBudget++
+		case variance.QuantityVariance < 0:
+			variance.Status = VarianceUnder
+			report.UnderBudget++
+		}
+
+		report.Items = append(report.Items, variance)
+		report.BudgetedTotal += variance.BudgetedAmount
+		report.InstalledTotal += variance.InstalledAmount
+	}
+
+	report.AmountVariance = report.InstalledTotal - report.BudgetedTotal
+	return report
+}
+
+// BudgetedTotal is what the job comes to on its budgeted quantities: the
+// budgeted lines, the markups and discounts on them, and approved change orders.
+// The job itself is left unchanged.
+func (j *Job) BudgetedTotal() Money {
+	items := make([]JobItem, len(j.Items))
+	copy(items, j.Items)
+
+	var total Money
+	for i := range items {
+		items[i].Quantity = items[i].BudgetedQuantity
+		items[i].CalculateTotal()
+		total += items[i].Total
+	}
+	total += AdjustmentsTotal(ApplyAdjustments(items, j.Adjustments))
+	for _, changeOrder := range j.ChangeOrders {
+		if changeOrder.IsApproved() {
+			total += changeOrder.TotalAmount
+		}
+	}
+	return total
+}
+
+// NewJobRevision snapshots the job's current line items and its budgeted total
+func NewJobRevision(job *Job, name, note string, author *User) (*JobRevision, error) {
+	if name == "" {
+		return nil, errors.New("revision name is required")
+	}
+	if author == nil {
+		return nil, errors.New("revision author is required")
+	}
+
+	items := make([]JobItem, len(job.Items))
+	copy(items, job.Items)
+	for i := range items {
+		items[i].CalculateTotal()
+	}
+
+	return &JobRevision{
+		ID:          uuid.New().String(),
+		JobID:       job.ID,
+		Name:        name,
+		Note:        note,
+		AuthorID:    author.ID,
+		AuthorName:  author.Name,
+		Items:       items,
+		TotalAmount: job.BudgetedTotal(),
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// DiffJobItems compares the budgeted scope of two sets of job items line by
+// line. Lines are matched by their job item ID; a line whose budgeted quantity
+// or price differs is reported as changed, while installed progress alone is
+// not a change. Added and changed lines keep the order of to, removed lines the
+// order of from.
+func DiffJobItems(from, to []JobItem) JobItemDiff {
+	diff := JobItemDiff{
+		Added:   []LineItemChange{},
+		Removed: []LineItemChange{},
+		Changed: []LineItemChange{},
+	}
+
+	fromByID := make(map[string]JobItem, len(from))
+	for _, item := range from {
+		fromByID[item.ID] = item
+		diff.FromTotal += item.Price.MulQuantity(item.BudgetedQuantity)
+	}
+
+	toIDs := make(map[string]bool, len(to))
+	for _, item := range to {
+		toIDs[item.ID] = true
+		diff.ToTotal += item.Price.MulQuantity(item.BudgetedQuantity)
+
+		change := LineItemChange{
+			JobItemID:          item.ID,
+			ItemID:             item.ItemID,
+			Name:               item.Name,
+			ToBudgetedQuantity: item.BudgetedQuantity,
+			ToQuantity:         item.Quantity,
+			ToPrice:            item.Price,
+		}
+
+		previous, ok := fromByID[item.ID]
+		if !ok {
+			change.Type = LineItemAdded
+			change.TotalDelta = item.Price.MulQuantity(item.BudgetedQuantity)
+			diff.Added = append(diff.Added, change)
+			continue
+		}
+
+		if previous.BudgetedQuantity == item.BudgetedQuantity && previous.Price == item.Price {
+			continue
+		}
+
+		change.Type = LineItemChanged
+		change.FromBudgetedQuantity = previous.BudgetedQuantity
+		change.FromQuantity = previous.Quantity
+		change.FromPrice = previous.Price
+		change.TotalDelta = item.Price.MulQuantity(item.BudgetedQuantity) - previous.Price.MulQuantity(previous.BudgetedQuantity)
+		diff.Changed = append(diff.Changed, change)
+	}
+
+	for _, item := range from {
+		if toIDs[item.ID] {
+			continue
+		}
+		diff.Removed = append(diff.Removed, LineItemChange{
+			JobItemID:            item.ID,
+			ItemID:               item.ItemID,
+			Name:                 item.Name,
+			Type:                 LineItemRemoved,
+			FromBudgetedQuantity: item.BudgetedQuantity,
+			FromQuantity:         item.Quantity,
+			FromPrice:            item.Price,
+			TotalDelta:           -item.Price.MulQuantity(item.BudgetedQuantity),
+		})
+	}
+
+	diff.TotalDelta = diff.ToTotal - diff.FromTotal
+	return diff
+}
+
+// BudgetJobItem is a new job line budgeting quantity of a catalog item at its
+// current price and cost, with nothing installed yet
+func BudgetJobItem(jobID string, item *Item, quantity float64) JobItem {
+	return JobItem{
+		ID:               uuid.New().String(),
+		JobID:            jobID,
+		ItemID:           item.ID,
+		Name:             item.Name,
+		Category:         item.Category,
+		Quantity:         0,
+		BudgetedQuantity: quantity,
+		Price:            item.UnitPrice,
+		Cost:             item.UnitCost,
+	}
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// JobPhase tracks a job's progress through one of its template's phases. ID is
+// the template phase ID; the name and order are copied so the record outlives
+// later template edits.
+type JobPhase struct {
+	ID          string     `json:"id" db:"phase_id"`
+	JobID       string     `json:"jobId" db:"job_id"`
+	Name        string     `json:"name" db:"name"`
+	Order       int        `json:"order" db:"phase_order"`
+	IsCompleted bool       `json:"isCompleted" db:"is_completed"`
+	StartedAt   *time.Time `json:"startedAt,omitempty" db:"started_at"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" db:"completed_at"`
+}
+
+// Duration is how long the phase has taken: from start to completion, or from
+// start to now while it is still open. It is zero for phases not yet started.
+func (p *JobPhase) Duration(now time.Time) time.Duration {
+	if p.StartedAt == nil {
+		return 0
+	}
+	if p.CompletedAt != nil {
+		return p.CompletedAt.Sub(*p.StartedAt)
+	}
+	return now.Sub(*p.StartedAt)
+}
+
+// StartPhases creates the job's phase records from its template's phases and
+// starts the first one
+func (j *Job) StartPhases(templatePhases []TemplatePhase) {
+	phases := make([]JobPhase, 0, len(templatePhases))
+	for _, phase := range templatePhases {
+		phases = append(phases, JobPhase{
+			ID:    phase.ID,
+			JobID: j.ID,
+			Name:  phase.Name,
+			Order: phase.Order,
+		})
+	}
+	sort.SliceStable(phases, func(a, b int) bool { return phases[a].Order < phases[b].Order })
+
+	j.Phases = phases
+	j.CurrentPhaseID = nil
+	j.advancePhase(time.Now())
+}
+
+// UpdatePhase moves the job to one of its phases. An empty ID clears the
+// current phase.
+func (j *Job) UpdatePhase(phaseID string) error {
+	if phaseID == "" {
+		j.CurrentPhaseID = nil
+		j.UpdatedAt = time.Now()
+		return nil
+	}
+
+	phase := j.findPhase(phaseID)
+	if phase == nil {
+		return errors.New("phase does not belong to this job's template")
+	}
+
+	now := time.Now()
+	if phase.StartedAt == nil {
+		phase.StartedAt = &now
+	}
+	j.CurrentPhaseID = &phase.ID
+	j.UpdatedAt = now
+	return nil
+}
+
+// CompletePhase marks one of the job's phases done and advances the current
+// phase to the next open one
+func (j *Job) CompletePhase(phaseID string) error {
+	phase := j.findPhase(phaseID)
+	if phase == nil {
+		return errors.New("phase does not belong to this job's template")
+	}
+	if phase.IsCompleted {
+		return errors.New("phase is already completed")
+	}
+
+	now := time.Now()
+	if phase.StartedAt == nil {
+		phase.StartedAt = &now
+	}
+	phase.IsCompleted = true
+	phase.CompletedAt = &now
+
+	j.advancePhase(now)
+	j.UpdatedAt = now
+	return nil
+}
+
+// ReopenPhase marks a completed phase as open again and moves the current phase
+// back to the earliest open one
+func (j *Job) ReopenPhase(phaseID string) error {
+	phase := j.findPhase(phaseID)
+	if phase == nil {
+		return errors.New("phase does not belong to this job's template")
+	}
+	if !phase.IsCompleted {
+		return errors.New("phase is not completed")
+	}
+
+	now := time.Now()
+	phase.IsCompleted = false
+	phase.CompletedAt = nil
+
+	j.advancePhase(now)
+	j.UpdatedAt = now
+	return nil
+}
+
+// CheckPhasesComplete reports whether every phase of the job is done, which is
+// required before the job can be completed
+func (j *Job) CheckPhasesComplete() error {
+	for _, phase := range j.Phases {
+		if !phase.IsCompleted {
+			return fmt.Errorf("the %s phase must be completed before the job can be completed", phase.Name)
+		}
+	}
+	return nil
+}
+
+// advancePhase points CurrentPhaseID at the earliest open phase, starting it if
+// needed. Once every phase is done the job stays on its last phase.
+func (j *Job) advancePhase(now time.Time) {
+	if len(j.Phases) == 0 {
+		return
+	}
+
+	for i := range j.Phases {
+		phase := &j.Phases[i]
+		if phase.IsCompleted {
+			continue
+		}
+		if phase.StartedAt == nil {
+			phase.StartedAt = &now
+		}
+		j.CurrentPhaseID = &phase.ID
+		return
+	}
+
+	j.CurrentPhaseID = &j.Phases[len(j.Phases)-1].ID
+}
+
+// findPhase returns the job's record for a phase, or nil if the phase is not
+// one of the job's
+func (j *Job) findPhase(phaseID string) *JobPhase {
+	for i := range j.Phases {
+		if j.Phases[i].ID == phaseID {
+			return &j.Phases[i]
+		}
+	}
+	return nil
+}
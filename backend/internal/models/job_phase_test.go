@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func newPhasedJob(t *testing.T) *Job {
+	t.Helper()
+
+	job, err := NewJob("cust123", "template123", JobStatusScheduled, time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Deliberately out of order to check phases are sorted
+	job.StartPhases([]TemplatePhase{
+		{ID: "trim", Name: "Trim", Order: 3},
+		{ID: "rough", Name: "Rough In", Order: 1},
+		{ID: "wire", Name: "Wire", Order: 2},
+	})
+	return job
+}
+
+func TestJob_StartPhases(t *testing.T) {
+	job := newPhasedJob(t)
+
+	if len(job.Phases) != 3 {
+		t.Fatalf("expected 3 phases, got %d", len(job.Phases))
+	}
+	for i, id := range []string{"rough", "wire", "trim"} {
+		if job.Phases[i].ID != id {
+			t.Errorf("phase %d: expected %s, got %s", i, id, job.Phases[i].ID)
+		}
+		if job.Phases[i].JobID != job.ID {
+			t.Errorf("phase %d: expected job ID %s, got %s", i, job.ID, job.Phases[i].JobID)
+		}
+	}
+
+	if job.CurrentPhaseID == nil || *job.CurrentPhaseID != "rough" {
+		t.Errorf("expected current phase rough, got %v", job.CurrentPhaseID)
+	}
+	if job.Phases[0].StartedAt == nil {
+		t.Error("expected first phase to be started")
+	}
+	if job.Phases[1].StartedAt != nil {
+		t.Error("expected later phases not to be started")
+	}
+}
+
+func TestJob_UpdatePhase(t *testing.T) {
+	tests := []struct {
+		name    string
+		phaseID string
+		wantErr bool
+		want    string
+	}{
+		{"job phase", "wire", false, "wire"},
+		{"phase from another template", "other", true, "rough"},
+		{"clear phase", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newPhasedJob(t)
+
+			err := job.UpdatePhase(tt.phaseID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdatePhase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.want == "" {
+				if job.CurrentPhaseID != nil {
+					t.Errorf("expected no current phase, got %s", *job.CurrentPhaseID)
+				}
+				return
+			}
+			if job.CurrentPhaseID == nil || *job.CurrentPhaseID != tt.want {
+				t.Errorf("expected current phase %s, got %v", tt.want, job.CurrentPhaseID)
+			}
+		})
+	}
+}
+
+func TestJob_CompletePhase(t *testing.T) {
+	job := newPhasedJob(t)
+
+	if err := job.CompletePhase("rough"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !job.Phases[0].IsCompleted || job.Phases[0].CompletedAt == nil {
+		t.Error("expected rough phase to be completed")
+	}
+	if *job.CurrentPhaseID != "wire" || job.Phases[1].StartedAt == nil {
+		t.Errorf("expected wire phase to be current and started, got %s", *job.CurrentPhaseID)
+	}
+
+	if err := job.CompletePhase("rough"); err == nil {
+		t.Error("expected error completing a phase twice")
+	}
+	if err := job.CompletePhase("other"); err == nil {
+		t.Error("expected error completing a phase from another template")
+	}
+
+	// Completing out of order leaves the earliest open phase current
+	if err := job.CompletePhase("trim"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *job.CurrentPhaseID != "wire" {
+		t.Errorf("expected wire phase to stay current, got %s", *job.CurrentPhaseID)
+	}
+
+	if err := job.CompletePhase("wire"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *job.CurrentPhaseID != "trim" {
+		t.Errorf("expected job to stay on its last phase, got %s", *job.CurrentPhaseID)
+	}
+}
+
+func TestJob_ReopenPhase(t *testing.T) {
+	job := newPhasedJob(t)
+	job.CompletePhase("rough")
+	job.CompletePhase("wire")
+
+	if err := job.ReopenPhase("trim"); err == nil {
+		t.Error("expected error reopening an open phase")
+	}
+
+	if err := job.ReopenPhase("rough"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Phases[0].IsCompleted || job.Phases[0].CompletedAt != nil {
+		t.Error("expected rough phase to be open")
+	}
+	if *job.CurrentPhaseID != "rough" {
+		t.Errorf("expected rough phase to be current, got %s", *job.CurrentPhaseID)
+	}
+}
+
+func TestJob_CompleteRequiresPhases(t *testing.T) {
+	job := newPhasedJob(t)
+	job.UpdateStatus(JobStatusInProgress)
+
+	err := job.UpdateStatus(JobStatusCompleted)
+	if err == nil || err.Error() != "the Rough In phase must be completed before the job can be completed" {
+		t.Errorf("expected phase error, got %v", err)
+	}
+
+	for _, id := range []string{"rough", "wire", "trim"} {
+		job.CompletePhase(id)
+	}
+	if err := job.UpdateStatus(JobStatusCompleted); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestJobPhase_Duration(t *testing.T) {
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(6 * time.Hour)
+	now := start.Add(10 * time.Hour)
+
+	tests := []struct {
+		name  string
+		phase JobPhase
+		want  time.Duration
+	}{
+		{"not started", JobPhase{}, 0},
+		{"in progress", JobPhase{StartedAt: &start}, 10 * time.Hour},
+		{"completed", JobPhase{StartedAt: &start, CompletedAt: &end}, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.phase.Duration(now); got != tt.want {
+				t.Errorf("Duration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
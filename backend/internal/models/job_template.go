@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ type JobTemplate struct {
 	Description string           `json:"description" db:"description"`
 	Items       []TemplateItem   `json:"items"`
 	Phases      []TemplatePhase  `json:"phases"`
+	Version     int              `json:"version" db:"version"`
 	IsActive    bool             `json:"isActive" db:"is_active"`
 	CreatedAt   time.Time        `json:"createdAt" db:"created_at"`
 	UpdatedAt   time.Time        `json:"updatedAt" db:"updated_at"`
@@ -97,6 +99,90 @@ func (t *JobTemplate) RemoveItem(itemID string) error {
 	return nil
 }
 
+// FirstPhase returns the phase with the lowest order, or nil if the template has no phases
+func (t *JobTemplate) FirstPhase() *TemplatePhase {
+	var first *TemplatePhase
+	for i := range t.Phases {
+		if first == nil || t.Phases[i].Order < first.Order {
+			first = &t.Phases[i]
+		}
+	}
+	return first
+}
+
+// ReplacePhases applies an edited phase list to the template. Phases that carry
+// the ID of an existing phase keep their identity so jobs on them are
+// unaffected; phases without an ID are new. It returns the IDs of the existing
+// phases that the edit drops.
+func (t *JobTemplate) ReplacePhases(phases []TemplatePhase) ([]string, error) {
+	existing := make(map[string]bool, len(t.Phases))
+	for _, phase := range t.Phases {
+		existing[phase.ID] = true
+	}
+
+	kept := make(map[string]bool, len(phases))
+	orders := make(map[int]bool, len(phases))
+	replaced := make([]TemplatePhase, 0, len(phases))
+	for _, phase := range phases {
+		if phase.Name == "" {
+			return nil, errors.New("phase name is required")
+		}
+		if orders[phase.Order] {
+			return nil, fmt.Errorf("more than one phase has order %d", phase.Order)
+		}
+		orders[phase.Order] = true
+
+		if phase.ID == "" {
+			phase.ID = uuid.New().String()
+		} else if !existing[phase.ID] {
+			return nil, fmt.Errorf("phase %s does not belong to this template", phase.ID)
+		} else if kept[phase.ID] {
+			return nil, fmt.Errorf("phase %s is listed more than once", phase.ID)
+		}
+		kept[phase.ID] = true
+		phase.TemplateID = t.ID
+		replaced = append(replaced, phase)
+	}
+
+	removed := make([]string, 0)
+	for _, phase := range t.Phases {
+		if !kept[phase.ID] {
+			removed = append(removed, phase.ID)
+		}
+	}
+
+	t.Phases = replaced
+	t.UpdatedAt = time.Now()
+	return removed, nil
+}
+
+// CheckPhaseReassignments validates a mapping from removed phase IDs to the
+// phases their jobs should move to. Every key must be a removed phase and
+// every target must be one of the template's remaining phases.
+func (t *JobTemplate) CheckPhaseReassignments(removed []string, reassignments map[string]string) error {
+	isRemoved := make(map[string]bool, len(removed))
+	for _, id := range removed {
+		isRemoved[id] = true
+	}
+
+	for from, to := range reassignments {
+		if !isRemoved[from] {
+			return fmt.Errorf("phase %s is not being removed and cannot be reassigned", from)
+		}
+		found := false
+		for _, phase := range t.Phases {
+			if phase.ID == to {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("cannot reassign jobs to phase %s: it is not one of the template's phases", to)
+		}
+	}
+	return nil
+}
+
 // Deactivate marks the template as inactive
 func (t *JobTemplate) Deactivate() {
 	t.IsActive = false
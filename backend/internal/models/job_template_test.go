@@ -51,7 +51,7 @@ func TestNewJobTemplate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			template, err := NewJobTemplate(tt.templateName, tt.description, tt.items)
+			template, err := NewJobTemplate(tt.templateName, tt.description, tt.items, nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -95,7 +95,7 @@ func TestNewJobTemplate(t *testing.T) {
 func TestJobTemplate_AddItem(t *testing.T) {
 	template, _ := NewJobTemplate("Test Template", "Description", []TemplateItem{
 		{ItemID: "item1", DefaultQuantity: 1},
-	})
+	}, nil)
 
 	newItem := TemplateItem{
 		ItemID:          "item2",
@@ -119,7 +119,7 @@ func TestJobTemplate_RemoveItem(t *testing.T) {
 		{ItemID: "item1", DefaultQuantity: 1},
 		{ItemID: "item2", DefaultQuantity: 2},
 		{ItemID: "item3", DefaultQuantity: 3},
-	})
+	}, nil)
 
 	tests := []struct {
 		name    string
@@ -179,7 +179,7 @@ func TestJobTemplate_RemoveItem(t *testing.T) {
 func TestJobTemplate_Deactivate(t *testing.T) {
 	template, _ := NewJobTemplate("Test Template", "Description", []TemplateItem{
 		{ItemID: "item1", DefaultQuantity: 1},
-	})
+	}, nil)
 
 	if !template.IsActive {
 		t.Error("expected template to be active initially")
@@ -195,7 +195,7 @@ func TestJobTemplate_Deactivate(t *testing.T) {
 func TestJobTemplate_Activate(t *testing.T) {
 	template, _ := NewJobTemplate("Test Template", "Description", []TemplateItem{
 		{ItemID: "item1", DefaultQuantity: 1},
-	})
+	}, nil)
 
 	template.Deactivate()
 	
@@ -208,4 +208,140 @@ func TestJobTemplate_Activate(t *testing.T) {
 	if !template.IsActive {
 		t.Error("expected template to be active after activation")
 	}
-}
\ No newline at end of file
+}
+func TestJobTemplate_FirstPhase(t *testing.T) {
+	template, _ := NewJobTemplate("Test Template", "Description", []TemplateItem{
+		{ItemID: "item1", DefaultQuantity: 1},
+	}, nil)
+
+	if template.FirstPhase() != nil {
+		t.Error("expected no first phase for a template without phases")
+	}
+
+	template.Phases = []TemplatePhase{
+		{ID: "trim", Name: "Trim", Order: 3},
+		{ID: "rough", Name: "Rough", Order: 1},
+		{ID: "service", Name: "Service", Order: 2},
+	}
+
+	first := template.FirstPhase()
+	if first == nil || first.ID != "rough" {
+		t.Errorf("expected first phase rough, got %v", first)
+	}
+}
+
+func TestJobTemplate_ReplacePhases(t *testing.T) {
+	newTemplate := func() *JobTemplate {
+		template, _ := NewJobTemplate("Test Template", "Description", []TemplateItem{
+			{ItemID: "item1", DefaultQuantity: 1},
+		}, nil)
+		template.Phases = []TemplatePhase{
+			{ID: "rough", TemplateID: template.ID, Name: "Rough", Order: 1},
+			{ID: "trim", TemplateID: template.ID, Name: "Trim", Order: 2},
+			{ID: "final", TemplateID: template.ID, Name: "Final", Order: 3},
+		}
+		return template
+	}
+
+	t.Run("keeps identity of phases sent with their ID", func(t *testing.T) {
+		template := newTemplate()
+		removed, err := template.ReplacePhases([]TemplatePhase{
+			{ID: "trim", Name: "Trim Out", Order: 1},
+			{ID: "rough", Name: "Rough In", Order: 2},
+			{Name: "Inspection", Order: 3},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(removed) != 1 || removed[0] != "final" {
+			t.Errorf("expected final to be removed, got %v", removed)
+		}
+		if template.Phases[0].ID != "trim" || template.Phases[0].Name != "Trim Out" {
+			t.Errorf("expected trim to be kept and renamed, got %+v", template.Phases[0])
+		}
+		if template.Phases[1].ID != "rough" {
+			t.Errorf("expected rough to be kept, got %+v", template.Phases[1])
+		}
+		added := template.Phases[2]
+		if added.ID == "" || added.ID == "final" || added.TemplateID != template.ID {
+			t.Errorf("expected new phase to get a fresh ID on this template, got %+v", added)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		phases []TemplatePhase
+		errMsg string
+	}{
+		{
+			name:   "unknown phase ID",
+			phases: []TemplatePhase{{ID: "other", Name: "Other", Order: 1}},
+			errMsg: "phase other does not belong to this template",
+		},
+		{
+			name: "duplicate phase ID",
+			phases: []TemplatePhase{
+				{ID: "rough", Name: "Rough", Order: 1},
+				{ID: "rough", Name: "Rough", Order: 2},
+			},
+			errMsg: "phase rough is listed more than once",
+		},
+		{
+			name: "duplicate order",
+			phases: []TemplatePhase{
+				{ID: "rough", Name: "Rough", Order: 1},
+				{Name: "New", Order: 1},
+			},
+			errMsg: "more than one phase has order 1",
+		},
+		{
+			name:   "missing name",
+			phases: []TemplatePhase{{ID: "rough", Order: 1}},
+			errMsg: "phase name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := newTemplate()
+			_, err := template.ReplacePhases(tt.phases)
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("expected error %q, got %v", tt.errMsg, err)
+			}
+			if len(template.Phases) != 3 {
+				t.Errorf("expected phases to be unchanged after a failed edit, got %d", len(template.Phases))
+			}
+		})
+	}
+}
+
+func TestJobTemplate_CheckPhaseReassignments(t *testing.T) {
+	template, _ := NewJobTemplate("Test Template", "Description", []TemplateItem{
+		{ItemID: "item1", DefaultQuantity: 1},
+	}, nil)
+	template.Phases = []TemplatePhase{
+		{ID: "rough", Name: "Rough", Order: 1},
+		{ID: "trim", Name: "Trim", Order: 2},
+	}
+	removed, _ := template.ReplacePhases([]TemplatePhase{{ID: "rough", Name: "Rough", Order: 1}})
+
+	tests := []struct {
+		name          string
+		reassignments map[string]string
+		wantErr       bool
+	}{
+		{name: "no reassignments", reassignments: nil},
+		{name: "removed phase to kept phase", reassignments: map[string]string{"trim": "rough"}},
+		{name: "phase that is not removed", reassignments: map[string]string{"rough": "rough"}, wantErr: true},
+		{name: "target that is removed", reassignments: map[string]string{"trim": "trim"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := template.CheckPhaseReassignments(removed, tt.reassignments)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
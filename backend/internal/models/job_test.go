@@ -190,21 +190,348 @@ func TestJob_CalculateTotal(t *testing.T) {
 		{
 			ItemID:   "item1",
 			Quantity: 5,
-			Price:    10.50,
+			Price:    Cents(1050),
 		},
 		{
 			ItemID:   "item2",
 			Quantity: 3,
-			Price:    25.00,
+			Price:    Cents(2500),
 		},
 	}
 	
 	job.Items = items
 	job.CalculateTotal()
 	
-	expectedTotal := (5 * 10.50) + (3 * 25.00) // 52.50 + 75.00 = 127.50
+	expectedTotal := Cents(12750) // 52.50 + 75.00
 	
 	if job.TotalAmount != expectedTotal {
-		t.Errorf("expected total amount %.2f but got %.2f", expectedTotal, job.TotalAmount)
+		t.Errorf("expected total amount %s but got %s", expectedTotal, job.TotalAmount)
 	}
-}
\ No newline at end of file
+	
+	// Line totals must be written back to the job's items
+	if job.Items[0].Total != Cents(5250) || job.Items[1].Total != Cents(7500) {
+		t.Errorf("expected line totals 52.50 and 75.00, got %s and %s", job.Items[0].Total, job.Items[1].Total)
+	}
+}
+func TestNewJobRevision(t *testing.T) {
+	job, _ := NewJob("cust123", "template123", JobStatusScheduled, time.Now().Add(24*time.Hour))
+	job.Items = []JobItem{
+		{ID: "line1", ItemID: "item1", Category: "Devices", Quantity: 1, BudgetedQuantity: 4, Price: Cents(1000)},
+		{ID: "line2", ItemID: "item2", Category: "Panels", BudgetedQuantity: 1, Price: Cents(25000)},
+	}
+	markup, _ := NewPriceAdjustment("Devices markup", AdjustmentMarkup, AdjustmentScopeCategory, "", "Devices", 10, 0)
+	job.Adjustments = []PriceAdjustment{*markup}
+	job.ChangeOrders = []ChangeOrder{
+		{Status: ChangeOrderStatusApproved, TotalAmount: Cents(5000)},
+		{Status: ChangeOrderStatusPending, TotalAmount: Cents(9900)},
+	}
+	author := &User{ID: "user1", Name: "Alex"}
+
+	if _, err := NewJobRevision(job, "", "", author); err == nil || err.Error() != "revision name is required" {
+		t.Errorf("expected name error, got %v", err)
+	}
+	if _, err := NewJobRevision(job, "Initial", "", nil); err == nil || err.Error() != "revision author is required" {
+		t.Errorf("expected author error, got %v", err)
+	}
+
+	revision, err := NewJobRevision(job, "Initial", "As bid", author)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The budgeted lines, the markup on them and the approved change order;
+	// installed quantities don't count towards the revision
+	if revision.TotalAmount != Cents(4000+25000+400+5000) {
+		t.Errorf("expected total 344.00, got %s", revision.TotalAmount)
+	}
+	if revision.AuthorID != "user1" || revision.AuthorName != "Alex" {
+		t.Errorf("expected author to be recorded, got %s/%s", revision.AuthorID, revision.AuthorName)
+	}
+
+	// The snapshot must not change when the job does
+	job.Items[0].BudgetedQuantity = 99
+	if revision.Items[0].BudgetedQuantity != 4 {
+		t.Errorf("expected snapshot budgeted quantity 4, got %.2f", revision.Items[0].BudgetedQuantity)
+	}
+}
+
+func TestDiffJobItems(t *testing.T) {
+	from := []JobItem{
+		{ID: "line1", ItemID: "can-light", Name: "Recessed Light", BudgetedQuantity: 12, Price: Cents(4500)},
+		{ID: "line2", ItemID: "outlet", Name: "Outlet", BudgetedQuantity: 20, Price: Cents(1500)},
+		{ID: "line3", ItemID: "switch", Name: "Switch", BudgetedQuantity: 6, Price: Cents(1200)},
+	}
+
+	tests := []struct {
+		name        string
+		to          []JobItem
+		wantAdded   []string
+		wantRemoved []string
+		wantChanged []string
+		wantDelta   Money
+	}{
+		{
+			name:      "identical",
+			to:        from,
+			wantDelta: 0,
+		},
+		{
+			name: "fewer recessed lights",
+			to: []JobItem{
+				{ID: "line1", ItemID: "can-light", Name: "Recessed Light", BudgetedQuantity: 8, Price: Cents(4500)},
+				from[1],
+				from[2],
+			},
+			wantChanged: []string{"line1"},
+			wantDelta:   Cents(-18000),
+		},
+		{
+			name: "installed progress only",
+			to: []JobItem{
+				{ID: "line1", ItemID: "can-light", Name: "Recessed Light", Quantity: 5, BudgetedQuantity: 12, Price: Cents(4500)},
+				from[1],
+				from[2],
+			},
+			wantDelta: 0,
+		},
+		{
+			name: "price change",
+			to: []JobItem{
+				from[0],
+				{ID: "line2", ItemID: "outlet", Name: "Outlet", BudgetedQuantity: 20, Price: Cents(1650)},
+				from[2],
+			},
+			wantChanged: []string{"line2"},
+			wantDelta:   Cents(3000),
+		},
+		{
+			name: "added subpanel and removed switches",
+			to: []JobItem{
+				from[0],
+				from[1],
+				{ID: "line4", ItemID: "subpanel", Name: "Subpanel", BudgetedQuantity: 1, Price: Cents(85000)},
+			},
+			wantAdded:   []string{"line4"},
+			wantRemoved: []string{"line3"},
+			wantDelta:   Cents(85000 - 7200),
+		},
+		{
+			name:        "everything removed",
+			to:          nil,
+			wantRemoved: []string{"line1", "line2", "line3"},
+			wantDelta:   Cents(-(54000 + 30000 + 7200)),
+		},
+	}
+
+	ids := func(changes []LineItemChange) []string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, c.JobItemID)
+		}
+		return out
+	}
+
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffJobItems(from, tt.to)
+
+			if got := ids(diff.Added); !equal(got, tt.wantAdded) {
+				t.Errorf("expected added %v, got %v", tt.wantAdded, got)
+			}
+			if got := ids(diff.Removed); !equal(got, tt.wantRemoved) {
+				t.Errorf("expected removed %v, got %v", tt.wantRemoved, got)
+			}
+			if got := ids(diff.Changed); !equal(got, tt.wantChanged) {
+				t.Errorf("expected changed %v, got %v", tt.wantChanged, got)
+			}
+			if diff.TotalDelta != tt.wantDelta {
+				t.Errorf("expected total delta %s, got %s", tt.wantDelta, diff.TotalDelta)
+			}
+			if diff.FromTotal != Cents(91200) {
+				t.Errorf("expected from total 912.00, got %s", diff.FromTotal)
+			}
+		})
+	}
+
+	t.Run("change details", func(t *testing.T) {
+		to := []JobItem{{ID: "line1", ItemID: "can-light", Name: "Recessed Light", BudgetedQuantity: 8, Price: Cents(5000)}}
+		diff := DiffJobItems(from[:1], to)
+
+		if len(diff.Changed) != 1 {
+			t.Fatalf("expected 1 changed line, got %d", len(diff.Changed))
+		}
+		change := diff.Changed[0]
+		if change.Type != LineItemChanged {
+			t.Errorf("expected type changed, got %s", change.Type)
+		}
+		if change.FromBudgetedQuantity != 12 || change.ToBudgetedQuantity != 8 || change.FromPrice != Cents(4500) || change.ToPrice != Cents(5000) {
+			t.Errorf("unexpected change values: %+v", change)
+		}
+		if change.TotalDelta != Cents(40000-54000) {
+			t.Errorf("expected line delta -140.00, got %s", change.TotalDelta)
+		}
+	})
+}
+
+func TestJob_VarianceReport(t *testing.T) {
+	job, _ := NewJob("cust123", "template123", JobStatusScheduled, time.Now().Add(24*time.Hour))
+	job.Items = []JobItem{
+		{ID: "line1", ItemID: "outlet", Name: "Outlet", Quantity: 24, BudgetedQuantity: 20, Price: Cents(1500)},
+		{ID: "line2", ItemID: "switch", Name: "Switch", Quantity: 5, BudgetedQuantity: 8, Price: Cents(1200)},
+		{ID: "line3", ItemID: "panel", Name: "Panel", Quantity: 1, BudgetedQuantity: 1, Price: Cents(60000)},
+	}
+
+	report := job.VarianceReport()
+
+	tests := []struct {
+		index          int
+		status         VarianceStatus
+		quantity       float64
+		amountVariance Money
+	}{
+		{0, VarianceOver, 4, Cents(6000)},
+		{1, VarianceUnder, -3, Cents(-3600)},
+		{2, VarianceOnBudget, 0, 0},
+	}
+
+	for _, tt := range tests {
+		item := report.Items[tt.index]
+		if item.Status != tt.status {
+			t.Errorf("%s: expected status %s, got %s", item.Name, tt.status, item.Status)
+		}
+		if item.QuantityVariance != tt.quantity {
+			t.Errorf("%s: expected quantity variance %.2f, got %.2f", item.Name, tt.quantity, item.QuantityVariance)
+		}
+		if item.AmountVariance != tt.amountVariance {
+			t.Errorf("%s: expected amount variance %s, got %s", item.Name, tt.amountVariance, item.AmountVariance)
+		}
+	}
+
+	if report.BudgetedTotal != Cents(30000+9600+60000) {
+		t.Errorf("expected budgeted total 996.00, got %s", report.BudgetedTotal)
+	}
+	if report.InstalledTotal != Cents(36000+6000+60000) {
+		t.Errorf("expected installed total 1020.00, got %s", report.InstalledTotal)
+	}
+	if report.AmountVariance != Cents(2400) {
+		t.Errorf("expected amount variance 24.00, got %s", report.AmountVariance)
+	}
+	if report.OverBudget != 1 || report.UnderBudget != 1 {
+		t.Errorf("expected 1 over and 1 under, got %d and %d", report.OverBudget, report.UnderBudget)
+	}
+}
+
+func TestJob_CalculateTotalWithChangeOrders(t *testing.T) {
+	job, _ := NewJob("cust123", "template123", JobStatusScheduled, time.Now().Add(24*time.Hour))
+	job.Items = []JobItem{{ItemID: "item1", Quantity: 10, Price: Cents(1000)}}
+	job.ChangeOrders = []ChangeOrder{
+		{Status: ChangeOrderStatusApproved, TotalAmount: Cents(25000)},
+		{Status: ChangeOrderStatusApproved, TotalAmount: Cents(-4000)},
+		{Status: ChangeOrderStatusPending, TotalAmount: Cents(100000)},
+		{Status: ChangeOrderStatusRejected, TotalAmount: Cents(50000)},
+	}
+
+	job.CalculateTotal()
+
+	if job.TotalAmount != Cents(31000) {
+		t.Errorf("expected only approved change orders to count, got total %s", job.TotalAmount)
+	}
+}
+
+func TestJob_Reprice(t *testing.T) {
+	job := &Job{
+		ID:    "job-1",
+		Items: adjustedJobItems(),
+		Adjustments: []PriceAdjustment{
+			adjustment("a1", "Materials markup", AdjustmentMarkup, AdjustmentScopeCategory, "Materials", 20, 0),
+		},
+	}
+	for i := range job.Items {
+		job.Items[i].ID = "line-" + job.Items[i].ItemID
+	}
+
+	// Labor has no current price, so it keeps its own
+	repricing := job.Reprice(map[string]Money{
+		"outlet": Cents(1500),
+		"wire":   Cents(99),
+		"panel":  Cents(47500),
+	})
+
+	// The markup follows the new material prices: 1717.00 -> 1777.00
+	if repricing.FromTotal != Cents(171700) || repricing.ToTotal != Cents(177700) || repricing.TotalDelta != Cents(6000) {
+		t.Errorf("expected 1717.00 -> 1777.00 (+60.00), got %s -> %s (%s)", repricing.FromTotal, repricing.ToTotal, repricing.TotalDelta)
+	}
+	if job.TotalAmount != repricing.ToTotal {
+		t.Errorf("expected the job total to be repriced, got %s", job.TotalAmount)
+	}
+	if len(repricing.Lines) != 2 || repricing.Lines[0].ItemID != "wire" || repricing.Lines[1].ItemID != "panel" {
+		t.Fatalf("expected the wire and panel lines to change, got %+v", repricing.Lines)
+	}
+	if repricing.Lines[0].FromPrice != Cents(89) || repricing.Lines[0].ToPrice != Cents(99) || repricing.Lines[0].TotalDelta != Cents(2500) {
+		t.Errorf("expected wire 0.89 -> 0.99 (+25.00), got %+v", repricing.Lines[0])
+	}
+}
+
+func TestCanTransitionJobStatus(t *testing.T) {
+	tests := []struct {
+		from JobStatus
+		to   JobStatus
+		want bool
+	}{
+		{JobStatusScheduled, JobStatusInProgress, true},
+		{JobStatusScheduled, JobStatusCancelled, true},
+		{JobStatusScheduled, JobStatusCompleted, false},
+		{JobStatusInProgress, JobStatusCompleted, true},
+		{JobStatusInProgress, JobStatusScheduled, true},
+		{JobStatusInProgress, JobStatusCancelled, true},
+		{JobStatusCompleted, JobStatusScheduled, false},
+		{JobStatusCompleted, JobStatusInProgress, false},
+		{JobStatusCancelled, JobStatusCompleted, false},
+		{JobStatusCancelled, JobStatusScheduled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := CanTransitionJobStatus(tt.from, tt.to); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJob_UpdateStatusGuards(t *testing.T) {
+	job, _ := NewJob("cust123", "template123", JobStatusScheduled, time.Now().Add(24*time.Hour))
+
+	if err := job.UpdateStatus(JobStatusCompleted); err == nil || err.Error() != "cannot change job status from scheduled to completed" {
+		t.Errorf("expected transition error, got %v", err)
+	}
+
+	job.UpdateStatus(JobStatusInProgress)
+	job.PermitRequired = true
+
+	if err := job.UpdateStatus(JobStatusCompleted); err == nil || err.Error() != "a permit number is required to complete this job" {
+		t.Errorf("expected permit error, got %v", err)
+	}
+	if job.Status != JobStatusInProgress {
+		t.Errorf("expected status to stay in progress, got %s", job.Status)
+	}
+
+	job.PermitNumber = "EP-2024-001"
+	if err := job.UpdateStatus(JobStatusCompleted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.EndDate == nil {
+		t.Error("expected end date to be set")
+	}
+}
@@ -0,0 +1,248 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrAssemblyLabor is returned when an assembly's labor hours are set
+// directly; they come from its components
+var ErrAssemblyLabor = errors.New("an assembly's labor hours come from its components")
+
+// LaborRate is what an hour of a crew's time is worth. The default rate prices
+// labor for jobs and templates that don't name one.
+type LaborRate struct {
+	ID         string    `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	HourlyRate Money     `json:"hourlyRate" db:"hourly_rate"`
+	IsDefault  bool      `json:"isDefault" db:"is_default"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// LaborDifficulty scales labor hours for harder work, such as a remodel (1.25)
+// or an attic (1.5) against new construction (1.0)
+type LaborDifficulty struct {
+	ID         string    `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	Multiplier float64   `json:"multiplier" db:"multiplier"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt  time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// JobLabor is how a job's labor is priced. Without a rate the default rate is
+// used, and without a difficulty hours are not scaled. SplitOnInvoice bills
+// the labor in each line on a line of its own when the job is sent to Wave.
+type JobLabor struct {
+	JobID          string    `json:"jobId" db:"job_id"`
+	RateID         string    `json:"rateId,omitempty" db:"labor_rate_id"`
+	DifficultyID   string    `json:"difficultyId,omitempty" db:"difficulty_id"`
+	SplitOnInvoice bool      `json:"splitOnInvoice" db:"split_on_invoice"`
+	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// LaborLine is the labor and materials in one line of a job or template.
+// Price is what the line sells for; labor is part of it, not added to it.
+type LaborLine struct {
+	ItemID       string  `json:"itemId"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	HoursPerUnit float64 `json:"hoursPerUnit"`
+	Hours        float64 `json:"hours"`
+	Labor        Money   `json:"labor"`
+	MaterialCost Money   `json:"materialCost"`
+	Price        Money   `json:"price"`
+}
+
+// LaborEstimate totals the labor in a job or template at a rate and
+// difficulty. The difficulty's multiplier scales the hours, not the rate.
+type LaborEstimate struct {
+	RateID         string      `json:"rateId"`
+	RateName       string      `json:"rateName"`
+	DifficultyID   string      `json:"difficultyId,omitempty"`
+	DifficultyName string      `json:"difficultyName,omitempty"`
+	Multiplier     float64     `json:"multiplier"`
+	HourlyRate     Money       `json:"hourlyRate"`
+	Lines          []LaborLine `json:"lines"`
+	Hours          float64     `json:"hours"`
+	Labor          Money       `json:"labor"`
+	MaterialCost   Money       `json:"materialCost"`
+	Price          Money       `json:"price"`
+}
+
+// NewLaborRate creates a new LaborRate with validation
+func NewLaborRate(name string, hourlyRate Money, isDefault bool) (*LaborRate, error) {
+	rate := &LaborRate{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	if err := rate.Update(name, hourlyRate, isDefault); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// Update changes the rate's fields with validation
+func (r *LaborRate) Update(name string, hourlyRate Money, isDefault bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("labor rate name is required")
+	}
+	if hourlyRate < 0 {
+		return errors.New("hourly rate cannot be negative")
+	}
+
+	r.Name = name
+	r.HourlyRate = hourlyRate
+	r.IsDefault = isDefault
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// NewLaborDifficulty creates a new LaborDifficulty with validation
+func NewLaborDifficulty(name string, multiplier float64) (*LaborDifficulty, error) {
+	difficulty := &LaborDifficulty{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	if err := difficulty.Update(name, multiplier); err != nil {
+		return nil, err
+	}
+	return difficulty, nil
+}
+
+// Update changes the difficulty's fields with validation
+func (d *LaborDifficulty) Update(name string, multiplier float64) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("difficulty name is required")
+	}
+	if multiplier <= 0 {
+		return errors.New("multiplier must be positive")
+	}
+
+	d.Name = name
+	d.Multiplier = multiplier
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+// Hours scales labor hours by the difficulty's multiplier, to four decimal
+// places. A nil difficulty leaves them as they are.
+func (d *LaborDifficulty) Hours(hours float64) float64 {
+	if d == nil {
+		return roundHours(hours)
+	}
+	return roundHours(hours * d.Multiplier)
+}
+
+// LaborCharge is hours of labor at hourlyRate, rounded to the cent. Hours are
+// taken to four decimal places, finer than MulQuantity's hundredths, since
+// labor units such as 0.125 hours per foot are common.
+func LaborCharge(hourlyRate Money, hours float64) Money {
+	return hourlyRate.MulFineQuantity(hours)
+}
+
+// TemplateLaborLines lists the labor in a template's lines at their default
+// quantities, priced from items, the catalog by ID
+func TemplateLaborLines(template *JobTemplate, items map[string]*Item) []LaborLine {
+	lines := make([]LaborLine, 0, len(template.Items))
+	for _, templateItem := range template.Items {
+		item, ok := items[templateItem.ItemID]
+		if !ok {
+			continue
+		}
+		lines = append(lines, LaborLine{
+			ItemID:       item.ID,
+			Name:         item.Name,
+			Quantity:     templateItem.DefaultQuantity,
+			HoursPerUnit: item.LaborHours,
+			MaterialCost: item.UnitCost.MulQuantity(templateItem.DefaultQuantity),
+			Price:        item.UnitPrice.MulQuantity(templateItem.DefaultQuantity),
+		})
+	}
+	return lines
+}
+
+// JobLaborLines lists the labor in a job's lines at their budgeted quantities,
+// then in its approved change orders. Lines keep the price and cost they were
+// added at; labor hours are the items' current ones, from items by ID.
+func JobLaborLines(job *Job, items map[string]*Item) []LaborLine {
+	hoursPerUnit := func(itemID string) float64 {
+		if item, ok := items[itemID]; ok {
+			return item.LaborHours
+		}
+		return 0
+	}
+
+	lines := make([]LaborLine, 0, len(job.Items))
+	for _, item := range job.Items {
+		lines = append(lines, LaborLine{
+			ItemID:       item.ItemID,
+			Name:         item.Name,
+			Quantity:     item.BudgetedQuantity,
+			HoursPerUnit: hoursPerUnit(item.ItemID),
+			MaterialCost: item.Cost.MulQuantity(item.BudgetedQuantity),
+			Price:        item.Price.MulQuantity(item.BudgetedQuantity),
+		})
+	}
+
+	for _, changeOrder := range job.ChangeOrders {
+		if !changeOrder.IsApproved() {
+			continue
+		}
+		for _, item := range changeOrder.Items {
+			lines = append(lines, LaborLine{
+				ItemID:       item.ItemID,
+				Name:         fmt.Sprintf("%s (%s)", item.Name, changeOrder.InvoiceLabel()),
+				Quantity:     item.Quantity,
+				HoursPerUnit: hoursPerUnit(item.ItemID),
+				MaterialCost: item.Cost.MulQuantity(item.Quantity),
+				Price:        item.Total,
+			})
+		}
+	}
+
+	return lines
+}
+
+// EstimateLabor prices the labor in lines at the rate and difficulty, which
+// may be nil, and totals it alongside the lines' materials and prices
+func EstimateLabor(lines []LaborLine, rate *LaborRate, difficulty *LaborDifficulty) *LaborEstimate {
+	estimate := &LaborEstimate{
+		RateID:     rate.ID,
+		RateName:   rate.Name,
+		Multiplier: 1,
+		HourlyRate: rate.HourlyRate,
+		Lines:      make([]LaborLine, 0, len(lines)),
+	}
+	if difficulty != nil {
+		estimate.DifficultyID = difficulty.ID
+		estimate.DifficultyName = difficulty.Name
+		estimate.Multiplier = difficulty.Multiplier
+	}
+
+	for _, line := range lines {
+		line.Hours = difficulty.Hours(line.HoursPerUnit * line.Quantity)
+		line.Labor = LaborCharge(rate.HourlyRate, line.Hours)
+		estimate.Lines = append(estimate.Lines, line)
+
+		estimate.Hours += line.Hours
+		estimate.Labor += line.Labor
+		estimate.MaterialCost += line.MaterialCost
+		estimate.Price += line.Price
+	}
+	estimate.Hours = roundHours(estimate.Hours)
+
+	return estimate
+}
+
+// roundHours rounds hours to four decimal places, as they are stored
+func roundHours(hours float64) float64 {
+	return math.Round(hours*10000) / 10000
+}
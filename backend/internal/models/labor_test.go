@@ -0,0 +1,166 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewLaborRate_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		rateName   string
+		hourlyRate Money
+		errMsg     string
+	}{
+		{"valid", " Journeyman ", Cents(8500), ""},
+		{"missing name", "  ", Cents(8500), "labor rate name is required"},
+		{"negative rate", "Journeyman", Cents(-1), "hourly rate cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := NewLaborRate(tt.rateName, tt.hourlyRate, true)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("expected error %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rate.Name != "Journeyman" || !rate.IsDefault || rate.ID == "" {
+				t.Errorf("unexpected rate %+v", rate)
+			}
+		})
+	}
+}
+
+func TestNewLaborDifficulty_Validation(t *testing.T) {
+	if _, err := NewLaborDifficulty("Attic", 0); err == nil || err.Error() != "multiplier must be positive" {
+		t.Errorf("expected a zero multiplier to be rejected, got %v", err)
+	}
+	if _, err := NewLaborDifficulty("", 1.5); err == nil || err.Error() != "difficulty name is required" {
+		t.Errorf("expected a missing name to be rejected, got %v", err)
+	}
+
+	attic, err := NewLaborDifficulty("Attic", 1.5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hours := attic.Hours(0.35); hours != 0.525 {
+		t.Errorf("expected 0.525 hours in an attic, got %g", hours)
+	}
+
+	var none *LaborDifficulty
+	if hours := none.Hours(0.35); hours != 0.35 {
+		t.Errorf("expected no difficulty to leave 0.35 hours, got %g", hours)
+	}
+}
+
+func TestLaborCharge(t *testing.T) {
+	// 0.125 hours keeps its third decimal: 85.00 x 0.125 = 10.625, rounded up
+	if charge := LaborCharge(Cents(8500), 0.125); charge != Cents(1063) {
+		t.Errorf("expected 10.63, got %s", charge)
+	}
+	if charge := LaborCharge(Cents(8500), 0); charge != 0 {
+		t.Errorf("expected no charge for no hours, got %s", charge)
+	}
+}
+
+func TestItem_UpdateLaborHours(t *testing.T) {
+	item, err := NewItem("Outlet", "each", Cents(1500), "Devices")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := item.Update(map[string]interface{}{"labor_hours": json.Number("0.35")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.LaborHours != 0.35 {
+		t.Errorf("expected 0.35 labor hours, got %g", item.LaborHours)
+	}
+
+	if err := item.Update(map[string]interface{}{"labor_hours": -1.0}); err == nil || err.Error() != "labor hours cannot be negative" {
+		t.Errorf("expected negative hours to be rejected, got %v", err)
+	}
+	if err := item.Update(map[string]interface{}{"labor_hours": true}); err == nil || err.Error() != "labor hours must be a number" {
+		t.Errorf("expected a non-number to be rejected, got %v", err)
+	}
+	if item.LaborHours != 0.35 {
+		t.Errorf("expected rejected updates to leave 0.35 hours, got %g", item.LaborHours)
+	}
+}
+
+func TestEstimateLabor_Template(t *testing.T) {
+	items := map[string]*Item{
+		"outlet": {ID: "outlet", Name: "Outlet", UnitPrice: Cents(1500), UnitCost: Cents(600), LaborHours: 0.35},
+		"wire":   {ID: "wire", Name: "12/2 Wire", UnitPrice: Cents(89), UnitCost: Cents(37), LaborHours: 0.01},
+	}
+	template := &JobTemplate{Items: []TemplateItem{
+		{ItemID: "outlet", DefaultQuantity: 10},
+		{ItemID: "wire", DefaultQuantity: 250},
+		{ItemID: "deleted", DefaultQuantity: 1},
+	}}
+	rate := &LaborRate{ID: "journeyman", Name: "Journeyman", HourlyRate: Cents(8500)}
+	remodel := &LaborDifficulty{ID: "remodel", Name: "Remodel", Multiplier: 1.25}
+
+	estimate := EstimateLabor(TemplateLaborLines(template, items), rate, nil)
+	if len(estimate.Lines) != 2 {
+		t.Fatalf("expected a line per catalog item, got %+v", estimate.Lines)
+	}
+	// 3.5 hours of outlets and 2.5 of wire at 85.00
+	if estimate.Hours != 6 || estimate.Labor != Cents(51000) {
+		t.Errorf("expected 6 hours costing 510.00, got %g costing %s", estimate.Hours, estimate.Labor)
+	}
+	// 10 x 6.00 + 250 x 0.37 of materials; 10 x 15.00 + 250 x 0.89 billed
+	if estimate.MaterialCost != Cents(15250) || estimate.Price != Cents(37250) {
+		t.Errorf("expected 152.50 of materials in 372.50, got %s in %s", estimate.MaterialCost, estimate.Price)
+	}
+
+	// Each line is rounded to the cent: 4.375 hours come to 371.88 and 3.125
+	// to 265.63
+	estimate = EstimateLabor(TemplateLaborLines(template, items), rate, remodel)
+	if estimate.Hours != 7.5 || estimate.Labor != Cents(63751) || estimate.HourlyRate != Cents(8500) {
+		t.Errorf("expected 7.5 hours at 85.00 in a remodel, got %g at %s costing %s", estimate.Hours, estimate.HourlyRate, estimate.Labor)
+	}
+	if estimate.Lines[0].Hours != 4.375 || estimate.DifficultyName != "Remodel" || estimate.Multiplier != 1.25 {
+		t.Errorf("expected the outlets to take 4.375 hours, got %+v", estimate.Lines[0])
+	}
+}
+
+func TestEstimateLabor_Job(t *testing.T) {
+	items := map[string]*Item{
+		"outlet": {ID: "outlet", LaborHours: 0.35},
+	}
+	job := &Job{
+		Items: []JobItem{
+			{ItemID: "outlet", Name: "Outlet", Quantity: 4, BudgetedQuantity: 10, Price: Cents(1500), Cost: Cents(600)},
+			{ItemID: "permit", Name: "Permit", Quantity: 1, BudgetedQuantity: 1, Price: Cents(25000)},
+		},
+		ChangeOrders: []ChangeOrder{
+			{Number: 1, Status: ChangeOrderStatusApproved, Items: []ChangeOrderItem{
+				{ItemID: "outlet", Name: "Outlet", Quantity: 2, Price: Cents(1500), Cost: Cents(600), Total: Cents(3000)},
+			}},
+			{Number: 2, Status: ChangeOrderStatusPending, Items: []ChangeOrderItem{
+				{ItemID: "outlet", Name: "Outlet", Quantity: 5, Price: Cents(1500), Total: Cents(7500)},
+			}},
+		},
+	}
+	rate := &LaborRate{ID: "journeyman", Name: "Journeyman", HourlyRate: Cents(8000)}
+
+	estimate := EstimateLabor(JobLaborLines(job, items), rate, nil)
+	if len(estimate.Lines) != 3 {
+		t.Fatalf("expected two job lines and one approved change order line, got %+v", estimate.Lines)
+	}
+	// Budgeted 10 outlets plus 2 approved: 12 x 0.35 = 4.2 hours
+	if estimate.Hours != 4.2 || estimate.Labor != Cents(33600) {
+		t.Errorf("expected 4.2 hours costing 336.00, got %g costing %s", estimate.Hours, estimate.Labor)
+	}
+	if estimate.Lines[1].Hours != 0 || estimate.Lines[1].Price != Cents(25000) {
+		t.Errorf("expected the permit to carry no labor, got %+v", estimate.Lines[1])
+	}
+	if estimate.MaterialCost != Cents(7200) || estimate.Price != Cents(43000) {
+		t.Errorf("expected 72.00 of materials in 430.00, got %s in %s", estimate.MaterialCost, estimate.Price)
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/masterbrent/electrical-bidding-app/internal/loadcalc"
+)
+
+// JobLoadCalculation is a dwelling service load calculation saved against a
+// job. Result is worked out from the dwelling's loads, not stored.
+type JobLoadCalculation struct {
+	ID        string            `json:"id" db:"id"`
+	JobID     string            `json:"jobId" db:"job_id"`
+	Name      string            `json:"name" db:"name"`
+	Dwelling  loadcalc.Dwelling `json:"dwelling"`
+	Result    *loadcalc.Result  `json:"result"`
+	CreatedAt time.Time         `json:"createdAt" db:"created_at"`
+}
+
+// NewJobLoadCalculation creates a new JobLoadCalculation and works it out.
+// Without a name it is called "Service load calculation".
+func NewJobLoadCalculation(jobID, name string, dwelling loadcalc.Dwelling) (*JobLoadCalculation, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Service load calculation"
+	}
+	if dwelling.Voltage == 0 {
+		dwelling.Voltage = loadcalc.DefaultVoltage
+	}
+
+	calculation := &JobLoadCalculation{
+		ID:        uuid.New().String(),
+		JobID:     jobID,
+		Name:      name,
+		Dwelling:  dwelling,
+		CreatedAt: time.Now(),
+	}
+	if err := calculation.Calculate(); err != nil {
+		return nil, err
+	}
+	return calculation, nil
+}
+
+// Calculate works out the result from the dwelling's loads
+func (c *JobLoadCalculation) Calculate() error {
+	result, err := loadcalc.Calculate(c.Dwelling)
+	if err != nil {
+		return err
+	}
+	c.Result = result
+	return nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/loadcalc"
+)
+
+func TestNewJobLoadCalculation(t *testing.T) {
+	calculation, err := NewJobLoadCalculation("job-1", "  ", loadcalc.Dwelling{
+		SquareFeet: 1500,
+		Cooking:    []loadcalc.Appliance{{Name: "Range", VA: 12000}},
+		Dryers:     []loadcalc.Appliance{{Name: "Dryer", VA: 5500}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calculation.ID == "" || calculation.JobID != "job-1" || calculation.Name != "Service load calculation" {
+		t.Errorf("unexpected calculation %+v", calculation)
+	}
+	if calculation.Dwelling.Voltage != 240 {
+		t.Errorf("expected the voltage to default to 240, got %g", calculation.Dwelling.Voltage)
+	}
+	if calculation.Result == nil || calculation.Result.Amps != 77.5 || calculation.Result.ServiceAmps != 100 {
+		t.Errorf("expected 77.5 A on a 100 A service, got %+v", calculation.Result)
+	}
+
+	if _, err := NewJobLoadCalculation("job-1", "House", loadcalc.Dwelling{}); err == nil || err.Error() != "square feet must be positive" {
+		t.Errorf("expected a dwelling without an area to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in whole cents. Prices and totals are stored as
+// DECIMAL(10,2) in Postgres; keeping them as integer cents in Go means sums are
+// exact and only multiplication by a quantity needs rounding.
+//
+// Rounding is half away from zero to the nearest cent, the same rule as
+// Postgres ROUND(numeric, 2), so totals derived in SQL and in Go agree.
+type Money int64
+
+// Cents returns an amount of n cents
+func Cents(n int64) Money {
+	return Money(n)
+}
+
+// MoneyFromFloat converts a dollar amount to Money, rounding to the nearest
+// cent. Use it only at boundaries that hand over floats; ParseMoney is exact.
+func MoneyFromFloat(dollars float64) Money {
+	return Money(math.Round(dollars * 100))
+}
+
+// ParseMoney parses a decimal dollar amount such as "12.34", "-0.5" or "7".
+// Digits past the cent are rounded half away from zero.
+func ParseMoney(s string) (Money, error) {
+	text := strings.TrimSpace(s)
+	if text == "" {
+		return 0, errors.New("money amount is empty")
+	}
+
+	negative := false
+	switch text[0] {
+	case '-':
+		negative = true
+		text = text[1:]
+	case '+':
+		text = text[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(text, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid money amount %q", s)
+	}
+	for _, digits := range []string{whole, fraction} {
+		for _, r := range digits {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("invalid money amount %q", s)
+			}
+		}
+	}
+
+	var cents int64
+	if whole != "" {
+		dollars, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || dollars > math.MaxInt64/100-1 {
+			return 0, fmt.Errorf("money amount %q is out of range", s)
+		}
+		cents = dollars * 100
+	}
+
+	padded := fraction + "00"
+	cents += int64(padded[0]-'0')*10 + int64(padded[1]-'0')
+	if len(fraction) > 2 && fraction[2] >= '5' {
+		cents++
+	}
+
+	if negative {
+		cents = -cents
+	}
+	return Money(cents), nil
+}
+
+// MulQuantity returns the amount for quantity units at this price, rounded to
+// the nearest cent. Quantities are stored to two decimal places, so the
+// quantity is taken in hundredths and the product is exact before rounding.
+func (m Money) MulQuantity(quantity float64) Money {
+	hundredths := int64(math.Round(quantity * 100))
+	return Money(divRound(int64(m)*hundredths, 100))
+}
+
+// MulFineQuantity is MulQuantity for quantities kept to four decimal places,
+// such as the parts of an assembly or hours of labor, so 0.125 of a unit is
+// not taken as 0.13
+func (m Money) MulFineQuantity(quantity float64) Money {
+	tenThousandths := int64(math.Round(quantity * 10000))
+	return Money(divRound(int64(m)*tenThousandths, 10000))
+}
+
+// Percent returns percent of the amount, rounded to the nearest cent. The
+// percentage is taken to four decimal places, e.g. 8.875.
+func (m Money) Percent(percent float64) Money {
+	tenThousandths := int64(math.Round(percent * 10000))
+	return Money(divRound(int64(m)*tenThousandths, 1000000))
+}
+
+// divRound divides n by d (d > 0) rounding half away from zero
+func divRound(n, d int64) int64 {
+	q, r := n/d, n%d
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= d {
+		if n < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+// Cents returns the amount in whole cents
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount in dollars for display and reporting
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount as a plain decimal with two places, e.g. "-12.05"
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string without going through
+// float64, so 0.1 is exactly 10 cents
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	if strings.ContainsAny(text, "eE") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid money amount %s", data)
+		}
+		*m = MoneyFromFloat(value)
+		return nil
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan reads a DECIMAL column. lib/pq returns numerics as text, which is
+// parsed exactly.
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		parsed, err := ParseMoney(string(value))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case string:
+		parsed, err := ParseMoney(value)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case int64:
+		*m = Money(value * 100)
+	case float64:
+		*m = MoneyFromFloat(value)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+// Value writes the amount as a decimal string so Postgres stores it exactly
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: "12.34", want: Cents(1234)},
+		{input: "7", want: Cents(700)},
+		{input: "0.5", want: Cents(50)},
+		{input: ".05", want: Cents(5)},
+		{input: "-12.05", want: Cents(-1205)},
+		{input: "19.995", want: Cents(2000)},
+		{input: "19.994", want: Cents(1999)},
+		{input: "-0.125", want: Cents(-13)},
+		{input: "", wantErr: true},
+		{input: ".", wantErr: true},
+		{input: "1.2.3", wantErr: true},
+		{input: "$5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoney_MulQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    Money
+		quantity float64
+		want     Money
+	}{
+		{name: "whole quantity", price: Cents(1050), quantity: 5, want: Cents(5250)},
+		{name: "fractional quantity", price: Cents(1999), quantity: 2.5, want: Cents(4998)},
+		{name: "rounds half up", price: Cents(333), quantity: 1.5, want: Cents(500)},
+		{name: "rounds half away from zero", price: Cents(333), quantity: -1.5, want: Cents(-500)},
+		{name: "float quantity is taken in hundredths", price: Cents(10), quantity: 0.29, want: Cents(3)},
+		{name: "zero quantity", price: Cents(4500), quantity: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.MulQuantity(tt.quantity); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoney_MulFineQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    Money
+		quantity float64
+		want     Money
+	}{
+		{name: "eighth of a unit", price: Cents(800), quantity: 0.125, want: Cents(100)},
+		{name: "four decimal places", price: Cents(10000), quantity: 0.0625, want: Cents(625)},
+		{name: "rounds half away from zero", price: Cents(5), quantity: -0.5, want: Cents(-3)},
+		{name: "hundredths match MulQuantity", price: Cents(1999), quantity: 2.5, want: Cents(4998)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.price.MulFineQuantity(tt.quantity); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoney_Percent(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		percent float64
+		want    Money
+	}{
+		{name: "whole percent", amount: Cents(10000), percent: 6, want: Cents(600)},
+		{name: "fractional percent", amount: Cents(12000), percent: 6.25, want: Cents(750)},
+		{name: "rounds half up", amount: Cents(1010), percent: 6.25, want: Cents(63)},
+		{name: "four decimal places", amount: Cents(100000), percent: 4.225, want: Cents(4225)},
+		{name: "negative amount", amount: Cents(-1010), percent: 6.25, want: Cents(-63)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.amount.Percent(tt.percent); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := map[Money]string{
+		Cents(0):      "0.00",
+		Cents(5):      "0.05",
+		Cents(1234):   "12.34",
+		Cents(-1205):  "-12.05",
+		Cents(-5):     "-0.05",
+		Cents(100000): "1000.00",
+	}
+
+	for money, want := range tests {
+		if got := money.String(); got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var line struct {
+		Price Money `json:"price"`
+		Total Money `json:"total"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": 0.1, "total": "19.99"}`), &line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line.Price != Cents(10) || line.Total != Cents(1999) {
+		t.Errorf("expected 0.10 and 19.99, got %s and %s", line.Price, line.Total)
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"price":0.10,"total":19.99}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"price": "ten"}`), &line); err == nil {
+		t.Error("expected error for a non-numeric amount")
+	}
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  interface{}
+		want Money
+	}{
+		{name: "numeric text", src: []byte("1234.56"), want: Cents(123456)},
+		{name: "string", src: "0.07", want: Cents(7)},
+		{name: "integer", src: int64(12), want: Cents(1200)},
+		{name: "float", src: 0.29, want: Cents(29)},
+		{name: "null", src: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money := Cents(99)
+			if err := money.Scan(tt.src); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if money != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, money)
+			}
+		})
+	}
+
+	var money Money
+	if err := money.Scan(true); err == nil {
+		t.Error("expected error scanning a bool")
+	}
+}
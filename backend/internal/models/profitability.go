@@ -0,0 +1,150 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+// CategoryProfit is the revenue, material cost and gross margin of one item
+// category on a job
+type CategoryProfit struct {
+	Category      string  `json:"category"`
+	Revenue       Money   `json:"revenue"`
+	MaterialCost  Money   `json:"materialCost"`
+	GrossMargin   Money   `json:"grossMargin"`
+	MarginPercent float64 `json:"marginPercent"`
+}
+
+// JobProfitability is what a job earns before tax against what its materials
+// cost. Revenue is the installed lines, markups and discounts and approved
+// change orders; material cost is each line's unit cost when it was added
+// times its installed quantity. Job-wide markups and discounts belong to no
+// category, so they are reported on their own.
+type JobProfitability struct {
+	JobID          string           `json:"jobId"`
+	TemplateID     string           `json:"templateId"`
+	Status         JobStatus        `json:"status"`
+	Revenue        Money            `json:"revenue"`
+	MaterialCost   Money            `json:"materialCost"`
+	GrossMargin    Money            `json:"grossMargin"`
+	MarginPercent  float64          `json:"marginPercent"`
+	Categories     []CategoryProfit `json:"categories"`
+	JobAdjustments Money            `json:"jobAdjustments"`
+}
+
+// TemplateProfitability sums the profitability of the jobs built from one
+// template
+type TemplateProfitability struct {
+	TemplateID    string  `json:"templateId"`
+	TemplateName  string  `json:"templateName"`
+	Jobs          int     `json:"jobs"`
+	Revenue       Money   `json:"revenue"`
+	MaterialCost  Money   `json:"materialCost"`
+	GrossMargin   Money   `json:"grossMargin"`
+	MarginPercent float64 `json:"marginPercent"`
+}
+
+// Profitability reports the job's revenue, material cost and gross margin,
+// overall and per category. Change order lines have no category of their own,
+// so itemCategories gives the category of each item.
+func (j *Job) Profitability(itemCategories map[string]string) *JobProfitability {
+	report := &JobProfitability{
+		JobID:      j.ID,
+		TemplateID: j.TemplateID,
+		Status:     j.Status,
+	}
+
+	categories := make(map[string]*CategoryProfit)
+	add := func(category string, revenue, cost Money) {
+		profit, ok := categories[category]
+		if !ok {
+			profit = &CategoryProfit{Category: category}
+			categories[category] = profit
+		}
+		profit.Revenue += revenue
+		profit.MaterialCost += cost
+		report.Revenue += revenue
+		report.MaterialCost += cost
+	}
+
+	for _, item := range j.Items {
+		category := item.Category
+		if category == "" {
+			category = itemCategories[item.ItemID]
+		}
+		add(category, item.Price.MulQuantity(item.Quantity), item.Cost.MulQuantity(item.Quantity))
+	}
+
+	for _, line := range ApplyAdjustments(j.Items, j.Adjustments) {
+		if line.Scope == AdjustmentScopeJob {
+			report.JobAdjustments += line.Amount
+			report.Revenue += line.Amount
+			continue
+		}
+		add(line.Category, line.Amount, 0)
+	}
+
+	for _, changeOrder := range j.ChangeOrders {
+		if !changeOrder.IsApproved() {
+			continue
+		}
+		for _, item := range changeOrder.Items {
+			add(itemCategories[item.ItemID], item.Total, item.Cost.MulQuantity(item.Quantity))
+		}
+	}
+
+	report.GrossMargin = report.Revenue - report.MaterialCost
+	report.MarginPercent = marginPercent(report.GrossMargin, report.Revenue)
+
+	report.Categories = make([]CategoryProfit, 0, len(categories))
+	for _, profit := range categories {
+		profit.GrossMargin = profit.Revenue - profit.MaterialCost
+		profit.MarginPercent = marginPercent(profit.GrossMargin, profit.Revenue)
+		report.Categories = append(report.Categories, *profit)
+	}
+	sort.Slice(report.Categories, func(a, b int) bool {
+		return report.Categories[a].Category < report.Categories[b].Category
+	})
+
+	return report
+}
+
+// SummarizeTemplateProfitability groups job reports by template, best margin
+// first. Template names are left for the caller to fill in.
+func SummarizeTemplateProfitability(jobs []*JobProfitability) []TemplateProfitability {
+	templates := make(map[string]*TemplateProfitability)
+	for _, job := range jobs {
+		summary, ok := templates[job.TemplateID]
+		if !ok {
+			summary = &TemplateProfitability{TemplateID: job.TemplateID}
+			templates[job.TemplateID] = summary
+		}
+		summary.Jobs++
+		summary.Revenue += job.Revenue
+		summary.MaterialCost += job.MaterialCost
+	}
+
+	summaries := make([]TemplateProfitability, 0, len(templates))
+	for _, summary := range templates {
+		summary.GrossMargin = summary.Revenue - summary.MaterialCost
+		summary.MarginPercent = marginPercent(summary.GrossMargin, summary.Revenue)
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(a, b int) bool {
+		if summaries[a].MarginPercent != summaries[b].MarginPercent {
+			return summaries[a].MarginPercent > summaries[b].MarginPercent
+		}
+		return summaries[a].TemplateID < summaries[b].TemplateID
+	})
+
+	return summaries
+}
+
+// marginPercent is margin as a percent of revenue, to two decimal places, or
+// 0 when there is no revenue
+func marginPercent(margin, revenue Money) float64 {
+	if revenue == 0 {
+		return 0
+	}
+	return math.Round(float64(margin)/float64(revenue)*10000) / 100
+}
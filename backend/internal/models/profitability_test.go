@@ -0,0 +1,81 @@
+package models
+
+import "testing"
+
+func TestJob_Profitability(t *testing.T) {
+	items := adjustedJobItems()
+	costs := map[string]Money{"outlet": Cents(600), "wire": Cents(40), "panel": Cents(30000)}
+	for i := range items {
+		items[i].Cost = costs[items[i].ItemID]
+	}
+
+	job := &Job{
+		ID:         "job-1",
+		TemplateID: "template-1",
+		Items:      items,
+		Adjustments: []PriceAdjustment{
+			adjustment("a1", "Materials markup", AdjustmentMarkup, AdjustmentScopeCategory, "Materials", 20, 0),
+			adjustment("a2", "Spring special", AdjustmentDiscount, AdjustmentScopeJob, "", 0, Cents(5000)),
+		},
+		ChangeOrders: []ChangeOrder{
+			{Status: ChangeOrderStatusApproved, Items: []ChangeOrderItem{
+				{ItemID: "wire", Quantity: 100, Price: Cents(89), Cost: Cents(40), Total: Cents(8900)},
+			}},
+			{Status: ChangeOrderStatusPending, Items: []ChangeOrderItem{
+				{ItemID: "panel", Quantity: 1, Price: Cents(45000), Cost: Cents(30000), Total: Cents(45000)},
+			}},
+		},
+	}
+
+	report := job.Profitability(map[string]string{"wire": "Materials", "panel": "Materials"})
+
+	// 1582.50 of lines + 134.50 markup - 50.00 discount + 89.00 change order
+	if report.Revenue != Cents(175600) || report.MaterialCost != Cents(50000) || report.GrossMargin != Cents(125600) {
+		t.Errorf("expected 1756.00 - 500.00 = 1256.00, got %s - %s = %s", report.Revenue, report.MaterialCost, report.GrossMargin)
+	}
+	if report.MarginPercent != 71.53 {
+		t.Errorf("expected a 71.53%% margin, got %v", report.MarginPercent)
+	}
+	if report.JobAdjustments != Cents(-5000) {
+		t.Errorf("expected -50.00 of job adjustments, got %s", report.JobAdjustments)
+	}
+
+	want := []CategoryProfit{
+		{Category: "Devices", Revenue: Cents(15000), MaterialCost: Cents(6000), GrossMargin: Cents(9000), MarginPercent: 60},
+		{Category: "Labor", Revenue: Cents(76000), GrossMargin: Cents(76000), MarginPercent: 100},
+		{Category: "Materials", Revenue: Cents(89600), MaterialCost: Cents(44000), GrossMargin: Cents(45600), MarginPercent: 50.89},
+	}
+	if len(report.Categories) != len(want) {
+		t.Fatalf("expected %d categories, got %+v", len(want), report.Categories)
+	}
+	for i, w := range want {
+		if report.Categories[i] != w {
+			t.Errorf("category %d: expected %+v, got %+v", i, w, report.Categories[i])
+		}
+	}
+}
+
+func TestSummarizeTemplateProfitability(t *testing.T) {
+	jobs := []*JobProfitability{
+		{TemplateID: "service-upgrade", Revenue: Cents(200000), MaterialCost: Cents(150000)},
+		{TemplateID: "remodel", Revenue: Cents(100000), MaterialCost: Cents(40000)},
+		{TemplateID: "service-upgrade", Revenue: Cents(100000), MaterialCost: Cents(60000)},
+		{TemplateID: "empty"},
+	}
+
+	summaries := SummarizeTemplateProfitability(jobs)
+
+	want := []TemplateProfitability{
+		{TemplateID: "remodel", Jobs: 1, Revenue: Cents(100000), MaterialCost: Cents(40000), GrossMargin: Cents(60000), MarginPercent: 60},
+		{TemplateID: "service-upgrade", Jobs: 2, Revenue: Cents(300000), MaterialCost: Cents(210000), GrossMargin: Cents(90000), MarginPercent: 30},
+		{TemplateID: "empty", Jobs: 1},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("expected %d templates, got %+v", len(want), summaries)
+	}
+	for i, w := range want {
+		if summaries[i] != w {
+			t.Errorf("template %d: expected %+v, got %+v", i, w, summaries[i])
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package models
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Supplier is a supply house we buy items from
+type Supplier struct {
+	ID            string    `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name"`
+	Email         string    `json:"email,omitempty" db:"email"`
+	Phone         string    `json:"phone,omitempty" db:"phone"`
+	AccountNumber string    `json:"accountNumber,omitempty" db:"account_number"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt     time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// SupplierItem links a supplier's SKU to one of our items
+type SupplierItem struct {
+	ID         string    `json:"id" db:"id"`
+	SupplierID string    `json:"supplierId" db:"supplier_id"`
+	SKU        string    `json:"sku" db:"sku"`
+	ItemID     string    `json:"itemId" db:"item_id"`
+	ItemName   string    `json:"itemName,omitempty" db:"item_name"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// SupplierPriceImport is a price file a supplier sent, staged until its costs
+// are applied to our items. Prices are only loaded for a single import.
+type SupplierPriceImport struct {
+	ID         string          `json:"id" db:"id"`
+	SupplierID string          `json:"supplierId" db:"supplier_id"`
+	FileName   string          `json:"fileName,omitempty" db:"file_name"`
+	RowCount   int             `json:"rowCount" db:"row_count"`
+	ImportedAt time.Time       `json:"importedAt" db:"imported_at"`
+	AppliedAt  *time.Time      `json:"appliedAt,omitempty" db:"applied_at"`
+	Prices     []SupplierPrice `json:"prices,omitempty"`
+}
+
+// SupplierPrice is one staged row of a price file
+type SupplierPrice struct {
+	SKU         string `json:"sku" db:"sku"`
+	Description string `json:"description,omitempty" db:"description"`
+	Unit        string `json:"unit,omitempty" db:"unit"`
+	Cost        Money  `json:"cost" db:"cost"`
+}
+
+// SupplierPriceRowError is a row of a price file that could not be staged
+type SupplierPriceRowError struct {
+	Line  int    `json:"line"`
+	SKU   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
+
+// SupplierCostChange is a linked item whose supplier cost moved between two
+// price files. DerivedPrice is the price the markup rule gives, when one is
+// used.
+type SupplierCostChange struct {
+	ItemID        string  `json:"itemId"`
+	ItemName      string  `json:"itemName"`
+	SKU           string  `json:"sku"`
+	PreviousCost  Money   `json:"previousCost"`
+	NewCost       Money   `json:"newCost"`
+	Change        Money   `json:"change"`
+	ChangePercent float64 `json:"changePercent"`
+	UnitPrice     Money   `json:"unitPrice"`
+	DerivedPrice  *Money  `json:"derivedPrice,omitempty"`
+}
+
+// supplierPriceColumns are the header names price files use for each column
+var supplierPriceColumns = map[string][]string{
+	"sku":         {"sku", "item", "item number", "part number", "part", "catalog number"},
+	"description": {"description", "desc", "item description"},
+	"unit":        {"unit", "uom", "unit of measure"},
+	"cost":        {"cost", "price", "net price", "net", "unit cost", "your price"},
+}
+
+// NewSupplier creates a new Supplier with validation
+func NewSupplier(name, email, phone, accountNumber string) (*Supplier, error) {
+	now := time.Now()
+	supplier := &Supplier{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+	}
+	if err := supplier.Update(name, email, phone, accountNumber); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// Update changes the supplier's fields with validation
+func (s *Supplier) Update(name, email, phone, accountNumber string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("supplier name is required")
+	}
+	email = strings.TrimSpace(email)
+	if email != "" && !emailRegex.MatchString(email) {
+		return errors.New("invalid email format")
+	}
+
+	s.Name = name
+	s.Email = email
+	s.Phone = strings.TrimSpace(phone)
+	s.AccountNumber = strings.TrimSpace(accountNumber)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// NewSupplierItem links a supplier SKU to an item
+func NewSupplierItem(supplierID, sku, itemID string) (*SupplierItem, error) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil, errors.New("SKU is required")
+	}
+	if itemID == "" {
+		return nil, errors.New("item ID is required")
+	}
+
+	return &SupplierItem{
+		ID:         uuid.New().String(),
+		SupplierID: supplierID,
+		SKU:        sku,
+		ItemID:     itemID,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// NewSupplierPriceImport stages the prices read from a supplier's file
+func NewSupplierPriceImport(supplierID, fileName string, prices []SupplierPrice) *SupplierPriceImport {
+	return &SupplierPriceImport{
+		ID:         uuid.New().String(),
+		SupplierID: supplierID,
+		FileName:   fileName,
+		RowCount:   len(prices),
+		ImportedAt: time.Now(),
+		Prices:     prices,
+	}
+}
+
+// ParseSupplierPriceFile reads a supplier's CSV price file. Supply houses name
+// their columns differently, so common names for the SKU, description, unit
+// and cost columns are all recognised; SKU and cost are required. Rows that
+// cannot be staged are returned as row errors. It returns an error, as a
+// *CSVError, only when the file itself cannot be read.
+func ParseSupplierPriceFile(r io.Reader) ([]SupplierPrice, []SupplierPriceRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, &CSVError{errors.New("the file is empty")}
+	}
+	if err != nil {
+		return nil, nil, &CSVError{fmt.Errorf("failed to read the header: %w", err)}
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for column, names := range supplierPriceColumns {
+			for _, candidate := range names {
+				if _, seen := columns[column]; name == candidate && !seen {
+					columns[column] = i
+				}
+			}
+		}
+	}
+	for _, column := range []string{"sku", "cost"} {
+		if _, ok := columns[column]; !ok {
+			return nil, nil, &CSVError{fmt.Errorf("no %s column; name it one of %s", column, strings.Join(supplierPriceColumns[column], ", "))}
+		}
+	}
+
+	prices := []SupplierPrice{}
+	rowErrors := []SupplierPriceRowError{}
+	seenOn := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, &CSVError{fmt.Errorf("failed to read the file: %w", err)}
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		price := SupplierPrice{
+			SKU:         value("sku"),
+			Description: value("description"),
+			Unit:        value("unit"),
+		}
+		rowError := func(msg string) {
+			rowErrors = append(rowErrors, SupplierPriceRowError{Line: line, SKU: price.SKU, Error: msg})
+		}
+
+		if price.SKU == "" {
+			if strings.TrimSpace(strings.Join(record, "")) != "" {
+				rowError("SKU is required")
+			}
+			continue
+		}
+		if previous, ok := seenOn[price.SKU]; ok {
+			rowError(fmt.Sprintf("SKU already priced on line %d", previous))
+			continue
+		}
+		cost, err := ParseMoney(strings.ReplaceAll(strings.TrimPrefix(value("cost"), "$"), ",", ""))
+		if err != nil {
+			rowError("cost must be a number")
+			continue
+		}
+		if cost < 0 {
+			rowError("cost cannot be negative")
+			continue
+		}
+		price.Cost = cost
+
+		seenOn[price.SKU] = line
+		prices = append(prices, price)
+	}
+
+	return prices, rowErrors, nil
+}
+
+// DerivePrice is the price that marks a cost up by markupPercent
+func DerivePrice(cost Money, markupPercent float64) Money {
+	return cost + cost.Percent(markupPercent)
+}
+
+// SupplierCostChanges compares the costs of linked SKUs in a price file with
+// the supplier's previous file and reports the items whose cost moved by more
+// than thresholdPercent either way. SKUs that are new in the file or were
+// free before have no percent change and are left out. When markupPercent is
+// given each change carries the price the markup gives. Changes are sorted by
+// the size of the move, largest first.
+func SupplierCostChanges(previous, current []SupplierPrice, links []SupplierItem, items map[string]*Item, thresholdPercent float64, markupPercent *float64) []SupplierCostChange {
+	previousCosts := make(map[string]Money, len(previous))
+	for _, price := range previous {
+		previousCosts[price.SKU] = price.Cost
+	}
+	itemsBySKU := make(map[string]*Item, len(links))
+	for _, link := range links {
+		if item, ok := items[link.ItemID]; ok {
+			itemsBySKU[link.SKU] = item
+		}
+	}
+
+	changes := []SupplierCostChange{}
+	for _, price := range current {
+		item, linked := itemsBySKU[price.SKU]
+		before, priced := previousCosts[price.SKU]
+		if !linked || !priced || before == 0 {
+			continue
+		}
+
+		change := price.Cost - before
+		percent := math.Round(float64(change)/float64(before)*10000) / 100
+		if change == 0 || math.Abs(percent) <= thresholdPercent {
+			continue
+		}
+
+		costChange := SupplierCostChange{
+			ItemID:        item.ID,
+			ItemName:      item.Name,
+			SKU:           price.SKU,
+			PreviousCost:  before,
+			NewCost:       price.Cost,
+			Change:        change,
+			ChangePercent: percent,
+			UnitPrice:     item.UnitPrice,
+		}
+		if markupPercent != nil {
+			derived := DerivePrice(price.Cost, *markupPercent)
+			costChange.DerivedPrice = &derived
+		}
+		changes = append(changes, costChange)
+	}
+
+	sort.SliceStable(changes, func(a, b int) bool {
+		return math.Abs(changes[a].ChangePercent) > math.Abs(changes[b].ChangePercent)
+	})
+	return changes
+}
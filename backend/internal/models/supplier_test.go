@@ -0,0 +1,130 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSupplier(t *testing.T) {
+	supplier, err := NewSupplier("  City Electric Supply ", "orders@ces.example", "555-0100", "A-1234")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if supplier.ID == "" || supplier.Name != "City Electric Supply" {
+		t.Errorf("unexpected supplier %+v", supplier)
+	}
+
+	if _, err := NewSupplier(" ", "", "", ""); err == nil {
+		t.Error("expected an error for a missing name")
+	}
+	if _, err := NewSupplier("CES", "not-an-email", "", ""); err == nil {
+		t.Error("expected an error for a bad email")
+	}
+}
+
+func TestParseSupplierPriceFile(t *testing.T) {
+	file := strings.Join([]string{
+		"Part Number,Desc,UOM,Net Price,List",
+		"BR120,20A single pole breaker,EA,$5.48,9.99",
+		"NM122,12/2 NM-B 250ft,RL,\"1,024.10\",1500",
+		",,,,",             // blank row is skipped
+		",orphan,EA,1,2",   // no SKU
+		"BR120,dup,EA,5,9", // SKU already priced
+		"GFCI,GFCI outlet,EA,call,30",
+		"CREDIT,credit,EA,-1.00,0",
+	}, "\n")
+
+	prices, rowErrors, err := ParseSupplierPriceFile(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantPrices := []SupplierPrice{
+		{SKU: "BR120", Description: "20A single pole breaker", Unit: "EA", Cost: Cents(548)},
+		{SKU: "NM122", Description: "12/2 NM-B 250ft", Unit: "RL", Cost: Cents(102410)},
+	}
+	if !reflect.DeepEqual(prices, wantPrices) {
+		t.Errorf("expected prices %+v, got %+v", wantPrices, prices)
+	}
+
+	wantErrors := []SupplierPriceRowError{
+		{Line: 5, Error: "SKU is required"},
+		{Line: 6, SKU: "BR120", Error: "SKU already priced on line 2"},
+		{Line: 7, SKU: "GFCI", Error: "cost must be a number"},
+		{Line: 8, SKU: "CREDIT", Error: "cost cannot be negative"},
+	}
+	if !reflect.DeepEqual(rowErrors, wantErrors) {
+		t.Errorf("expected row errors %+v, got %+v", wantErrors, rowErrors)
+	}
+}
+
+func TestParseSupplierPriceFile_FileErrors(t *testing.T) {
+	for name, file := range map[string]string{
+		"empty":      "",
+		"no sku":     "description,cost\nBreaker,5.00",
+		"no cost":    "sku,description\nBR120,Breaker",
+		"bad quotes": "sku,cost\n\"BR120,5.00",
+	} {
+		_, _, err := ParseSupplierPriceFile(strings.NewReader(file))
+		var csvErr *CSVError
+		if !errors.As(err, &csvErr) {
+			t.Errorf("%s: expected a CSVError, got %v", name, err)
+		}
+	}
+}
+
+func TestSupplierCostChanges(t *testing.T) {
+	items := map[string]*Item{
+		"breaker": {ID: "breaker", Name: "20A Breaker", UnitPrice: Cents(1200)},
+		"wire":    {ID: "wire", Name: "12/2 Wire", UnitPrice: Cents(89)},
+		"outlet":  {ID: "outlet", Name: "Outlet", UnitPrice: Cents(1500)},
+	}
+	links := []SupplierItem{
+		{SKU: "BR120", ItemID: "breaker"},
+		{SKU: "NM122", ItemID: "wire"},
+		{SKU: "DR15", ItemID: "outlet"},
+		{SKU: "NEW1", ItemID: "outlet"},
+	}
+	previous := []SupplierPrice{
+		{SKU: "BR120", Cost: Cents(500)},
+		{SKU: "NM122", Cost: Cents(40)},
+		{SKU: "DR15", Cost: Cents(200)},
+		{SKU: "UNLINKED", Cost: Cents(100)},
+	}
+	current := []SupplierPrice{
+		{SKU: "BR120", Cost: Cents(548)}, // +9.6%
+		{SKU: "NM122", Cost: Cents(30)},  // -25%
+		{SKU: "DR15", Cost: Cents(204)},  // +2%, under the threshold
+		{SKU: "NEW1", Cost: Cents(100)},  // no previous cost
+		{SKU: "UNLINKED", Cost: Cents(200)},
+	}
+
+	changes := SupplierCostChanges(previous, current, links, items, 5, nil)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	wire, breaker := changes[0], changes[1]
+	if wire.ItemID != "wire" || wire.Change != Cents(-10) || wire.ChangePercent != -25 || wire.DerivedPrice != nil {
+		t.Errorf("unexpected wire change %+v", wire)
+	}
+	if breaker.ItemID != "breaker" || breaker.PreviousCost != Cents(500) || breaker.NewCost != Cents(548) ||
+		breaker.ChangePercent != 9.6 || breaker.UnitPrice != Cents(1200) {
+		t.Errorf("unexpected breaker change %+v", breaker)
+	}
+
+	markup := 50.0
+	changes = SupplierCostChanges(previous, current, links, items, 0, &markup)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes without a threshold, got %+v", changes)
+	}
+	for _, change := range changes {
+		if change.DerivedPrice == nil || *change.DerivedPrice != DerivePrice(change.NewCost, markup) {
+			t.Errorf("expected a derived price on %+v", change)
+		}
+	}
+	if got := DerivePrice(Cents(548), markup); got != Cents(822) {
+		t.Errorf("expected 8.22, got %s", got)
+	}
+}
@@ -0,0 +1,412 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxRate is a sales tax rate for one jurisdiction. A rate with only a state
+// applies to the whole state; a city or zip narrows it. Every rate that applies
+// to an address is charged, so a state rate and a city rate stack.
+//
+// EffectiveFrom is the first day the rate applies; EffectiveTo, when set, is
+// the first day it no longer does.
+type TaxRate struct {
+	ID             string     `json:"id" db:"id"`
+	Name           string     `json:"name" db:"name"`
+	State          string     `json:"state" db:"state"`
+	City           string     `json:"city,omitempty" db:"city"`
+	Zip            string     `json:"zip,omitempty" db:"zip"`
+	Rate           float64    `json:"rate" db:"rate"` // percent, e.g. 6.25
+	WaveSalesTaxID string     `json:"waveSalesTaxId,omitempty" db:"wave_sales_tax_id"`
+	EffectiveFrom  time.Time  `json:"effectiveFrom" db:"effective_from"`
+	EffectiveTo    *time.Time `json:"effectiveTo,omitempty" db:"effective_to"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// TaxAddress is the part of an address that decides which rates apply
+type TaxAddress struct {
+	State string `json:"state"`
+	City  string `json:"city,omitempty"`
+	Zip   string `json:"zip,omitempty"`
+}
+
+// TaxCategory marks an item category as taxable or exempt. Categories without
+// a setting are taxable, so only exempt ones such as labor need to be listed.
+type TaxCategory struct {
+	Category  string    `json:"category" db:"category"`
+	Taxable   bool      `json:"taxable" db:"taxable"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// AppliedTaxRate is one rate charged on a job
+type AppliedTaxRate struct {
+	TaxRateID      string  `json:"taxRateId"`
+	Name           string  `json:"name"`
+	Rate           float64 `json:"rate"`
+	WaveSalesTaxID string  `json:"waveSalesTaxId,omitempty"`
+	Amount         Money   `json:"amount"`
+}
+
+// TaxedLine is one of a job's lines with whether it was taxed. Adjustment
+// lines carry the ID of their markup or discount.
+type TaxedLine struct {
+	ItemID       string `json:"itemId"`
+	AdjustmentID string `json:"adjustmentId,omitempty"`
+	Name         string `json:"name"`
+	Category     string `json:"category,omitempty"`
+	Amount       Money  `json:"amount"`
+	Taxable      bool   `json:"taxable"`
+}
+
+// JobTax is the sales tax on a job's items and approved change orders at its
+// address on a given date
+type JobTax struct {
+	JobID         string           `json:"jobId"`
+	Address       TaxAddress       `json:"address"`
+	Date          time.Time        `json:"date"`
+	Lines         []TaxedLine      `json:"lines"`
+	TaxableAmount Money            `json:"taxableAmount"`
+	ExemptAmount  Money            `json:"exemptAmount"`
+	Rates         []AppliedTaxRate `json:"rates"`
+	TaxAmount     Money            `json:"taxAmount"`
+}
+
+// TaxSettings holds what is needed to tax a job: the rates, which categories
+// are exempt and the category of each item
+type TaxSettings struct {
+	Rates          []TaxRate
+	Categories     []TaxCategory
+	ItemCategories map[string]string
+}
+
+// NewTaxRate creates a new TaxRate with validation
+func NewTaxRate(name, state, city, zip string, rate float64, waveSalesTaxID string, effectiveFrom time.Time, effectiveTo *time.Time) (*TaxRate, error) {
+	now := time.Now()
+	taxRate := &TaxRate{
+		ID:        uuid.New().String(),
+		CreatedAt: now,
+	}
+	if err := taxRate.Update(name, state, city, zip, rate, waveSalesTaxID, effectiveFrom, effectiveTo); err != nil {
+		return nil, err
+	}
+	return taxRate, nil
+}
+
+// Update changes the rate's fields with validation
+func (r *TaxRate) Update(name, state, city, zip string, rate float64, waveSalesTaxID string, effectiveFrom time.Time, effectiveTo *time.Time) error {
+	if name == "" {
+		return errors.New("tax rate name is required")
+	}
+	state = strings.ToUpper(strings.TrimSpace(state))
+	if len(state) != 2 {
+		return errors.New("state must be a two-letter code")
+	}
+	if rate < 0 || rate > 100 {
+		return errors.New("rate must be between 0 and 100 percent")
+	}
+	if effectiveFrom.IsZero() {
+		return errors.New("effective from date is required")
+	}
+	effectiveFrom = taxDate(effectiveFrom)
+	if effectiveTo != nil {
+		to := taxDate(*effectiveTo)
+		if !to.After(effectiveFrom) {
+			return errors.New("effective to date must be after the effective from date")
+		}
+		effectiveTo = &to
+	}
+
+	r.Name = name
+	r.State = state
+	r.City = strings.TrimSpace(city)
+	r.Zip = strings.TrimSpace(zip)
+	r.Rate = rate
+	r.WaveSalesTaxID = strings.TrimSpace(waveSalesTaxID)
+	r.EffectiveFrom = effectiveFrom
+	r.EffectiveTo = effectiveTo
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// EffectiveOn reports whether the rate applies on the given day
+func (r *TaxRate) EffectiveOn(on time.Time) bool {
+	day := taxDate(on)
+	if day.Before(r.EffectiveFrom) {
+		return false
+	}
+	return r.EffectiveTo == nil || day.Before(*r.EffectiveTo)
+}
+
+// AppliesTo reports whether the rate applies to the address on the given day
+func (r *TaxRate) AppliesTo(address TaxAddress, on time.Time) bool {
+	if !strings.EqualFold(r.State, address.State) {
+		return false
+	}
+	if r.City != "" && !strings.EqualFold(r.City, address.City) {
+		return false
+	}
+	if r.Zip != "" && zip5(r.Zip) != zip5(address.Zip) {
+		return false
+	}
+	return r.EffectiveOn(on)
+}
+
+// Overlaps reports whether two rates cover the same jurisdiction for at least
+// one day, which would charge the same tax twice
+func (r *TaxRate) Overlaps(other *TaxRate) bool {
+	if r.ID == other.ID ||
+		!strings.EqualFold(r.State, other.State) ||
+		!strings.EqualFold(r.City, other.City) ||
+		zip5(r.Zip) != zip5(other.Zip) {
+		return false
+	}
+	startsBeforeOtherEnds := other.EffectiveTo == nil || r.EffectiveFrom.Before(*other.EffectiveTo)
+	endsAfterOtherStarts := r.EffectiveTo == nil || other.EffectiveFrom.Before(*r.EffectiveTo)
+	return startsBeforeOtherEnds && endsAfterOtherStarts
+}
+
+// ApplicableTaxRates returns the rates that apply to the address on the given day
+func ApplicableTaxRates(rates []TaxRate, address TaxAddress, on time.Time) []TaxRate {
+	applicable := []TaxRate{}
+	for i := range rates {
+		if rates[i].AppliesTo(address, on) {
+			applicable = append(applicable, rates[i])
+		}
+	}
+	return applicable
+}
+
+// ParseTaxAddress reads the city, state and zip from a one-line address such
+// as "123 Main St, Springfield, IL 62704"
+func ParseTaxAddress(address string) TaxAddress {
+	var parts []string
+	for _, part := range strings.Split(address, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) < 2 {
+		// Without a comma "123 Main St" would read "St" as the state
+		return TaxAddress{}
+	}
+
+	var parsed TaxAddress
+	fields := strings.Fields(parts[len(parts)-1])
+	if n := len(fields); n > 0 && isZip(fields[n-1]) {
+		parsed.Zip = fields[n-1]
+		fields = fields[:n-1]
+	}
+	if n := len(fields); n > 0 && isStateCode(fields[n-1]) {
+		parsed.State = strings.ToUpper(fields[n-1])
+		fields = fields[:n-1]
+	}
+
+	switch {
+	case len(fields) > 0:
+		// "Springfield IL 62704" after the street
+		parsed.City = strings.Join(fields, " ")
+	case len(parts) >= 3:
+		parsed.City = parts[len(parts)-2]
+	case !startsWithDigit(parts[0]):
+		parsed.City = parts[0]
+	}
+	return parsed
+}
+
+// IsTaxableItem reports whether an item's category is taxable
+func (s *TaxSettings) IsTaxableItem(itemID string) bool {
+	return s.IsTaxableCategory(s.ItemCategories[itemID])
+}
+
+// IsTaxableCategory reports whether a category is taxable
+func (s *TaxSettings) IsTaxableCategory(category string) bool {
+	for _, setting := range s.Categories {
+		if strings.EqualFold(setting.Category, category) {
+			return setting.Taxable
+		}
+	}
+	return true
+}
+
+// CalculateJobTax taxes the job's items, adjustments and approved change order
+// lines at the rates that apply to its address on the given day. Line and
+// category adjustments are taxed like the lines they adjust; a job adjustment
+// is split between the taxable and exempt lines in proportion to their
+// amounts. Each rate is charged on the taxable amount and rounded to the cent.
+func (s *TaxSettings) CalculateJobTax(job *Job, on time.Time) *JobTax {
+	tax := &JobTax{
+		JobID:   job.ID,
+		Address: ParseTaxAddress(job.Address),
+		Date:    taxDate(on),
+		Lines:   []TaxedLine{},
+		Rates:   []AppliedTaxRate{},
+	}
+
+	add := func(line TaxedLine) {
+		if line.Taxable {
+			tax.TaxableAmount += line.Amount
+		} else {
+			tax.ExemptAmount += line.Amount
+		}
+		tax.Lines = append(tax.Lines, line)
+	}
+	addLine := func(itemID, name string, amount Money) {
+		add(TaxedLine{
+			ItemID:   itemID,
+			Name:     name,
+			Category: s.ItemCategories[itemID],
+			Amount:   amount,
+			Taxable:  s.IsTaxableItem(itemID),
+		})
+	}
+
+	for _, item := range job.Items {
+		addLine(item.ItemID, item.Name, item.Price.MulQuantity(item.Quantity))
+	}
+
+	var jobAdjustments []AdjustmentLine
+	for _, adjustment := range job.AdjustmentLines {
+		if adjustment.Scope == AdjustmentScopeJob {
+			jobAdjustments = append(jobAdjustments, adjustment)
+			continue
+		}
+		add(TaxedLine{
+			ItemID:       adjustment.ItemID,
+			AdjustmentID: adjustment.AdjustmentID,
+			Name:         adjustment.Name,
+			Category:     adjustment.Category,
+			Amount:       adjustment.Amount,
+			Taxable:      s.IsTaxableCategory(adjustment.Category),
+		})
+	}
+
+	// Job adjustments are shared by the taxable and exempt lines before them
+	taxable, base := tax.TaxableAmount, tax.TaxableAmount+tax.ExemptAmount
+	for _, adjustment := range jobAdjustments {
+		var taxablePart Money
+		if base != 0 {
+			taxablePart = Money(divRound(int64(adjustment.Amount)*int64(taxable), int64(base)))
+		}
+		parts := []TaxedLine{
+			{AdjustmentID: adjustment.AdjustmentID, Name: adjustment.Name, Amount: taxablePart, Taxable: true},
+			{AdjustmentID: adjustment.AdjustmentID, Name: adjustment.Name, Amount: adjustment.Amount - taxablePart},
+		}
+		if taxablePart != 0 && taxablePart != adjustment.Amount {
+			parts[0].Name += " (taxable)"
+			parts[1].Name += " (non-taxable)"
+		}
+		for _, part := range parts {
+			if part.Amount != 0 {
+				add(part)
+			}
+		}
+	}
+
+	for _, changeOrder := range job.ChangeOrders {
+		if !changeOrder.IsApproved() {
+			continue
+		}
+		for _, item := range changeOrder.Items {
+			addLine(item.ItemID, item.Name, item.Total)
+		}
+	}
+
+	for _, rate := range ApplicableTaxRates(s.Rates, tax.Address, on) {
+		amount := tax.TaxableAmount.Percent(rate.Rate)
+		tax.Rates = append(tax.Rates, AppliedTaxRate{
+			TaxRateID:      rate.ID,
+			Name:           rate.Name,
+			Rate:           rate.Rate,
+			WaveSalesTaxID: rate.WaveSalesTaxID,
+			Amount:         amount,
+		})
+		tax.TaxAmount += amount
+	}
+
+	return tax
+}
+
+// AdjustmentLines returns the taxed lines for markups and discounts, with job
+// adjustments split into their taxable and exempt parts
+func (t *JobTax) AdjustmentLines() []TaxedLine {
+	var lines []TaxedLine
+	for _, line := range t.Lines {
+		if line.AdjustmentID != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// WaveSalesTaxIDs lists the Wave sales taxes to put on taxable invoice lines
+func (t *JobTax) WaveSalesTaxIDs() []string {
+	var ids []string
+	for _, rate := range t.Rates {
+		if rate.WaveSalesTaxID != "" {
+			ids = append(ids, rate.WaveSalesTaxID)
+		}
+	}
+	return ids
+}
+
+// WaveTaxAmount is the part of the tax that Wave will charge, i.e. the rates
+// that have a Wave sales tax ID
+func (t *JobTax) WaveTaxAmount() Money {
+	var amount Money
+	for _, rate := range t.Rates {
+		if rate.WaveSalesTaxID != "" {
+			amount += rate.Amount
+		}
+	}
+	return amount
+}
+
+// taxDate reduces a time to its calendar day
+func taxDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// zip5 returns the five-digit part of a zip code
+func zip5(zip string) string {
+	zip = strings.TrimSpace(zip)
+	if len(zip) > 5 {
+		return zip[:5]
+	}
+	return zip
+}
+
+func isZip(s string) bool {
+	if len(s) != 5 && (len(s) != 10 || s[5] != '-') {
+		return false
+	}
+	for i, r := range s {
+		if i == 5 && r == '-' {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isStateCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
@@ -0,0 +1,229 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
+
+func TestParseTaxAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    TaxAddress
+	}{
+		{"123 Main St, Springfield, IL 62704", TaxAddress{State: "IL", City: "Springfield", Zip: "62704"}},
+		{"123 Main St, Springfield IL 62704-1234", TaxAddress{State: "IL", City: "Springfield", Zip: "62704-1234"}},
+		{"Springfield, il", TaxAddress{State: "IL", City: "Springfield"}},
+		{"123 Main St, Apt 4, Austin, TX", TaxAddress{State: "TX", City: "Austin"}},
+		{"123 Main St", TaxAddress{}},
+		{"", TaxAddress{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := ParseTaxAddress(tt.address); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewTaxRate(t *testing.T) {
+	end := date("2026-01-01")
+
+	tests := []struct {
+		name   string
+		state  string
+		rate   float64
+		from   time.Time
+		to     *time.Time
+		errMsg string
+	}{
+		{name: "valid", state: "il", rate: 6.25, from: date("2025-01-01"), to: &end},
+		{name: "bad state", state: "Illinois", rate: 6.25, from: date("2025-01-01"), errMsg: "state must be a two-letter code"},
+		{name: "negative rate", state: "IL", rate: -1, from: date("2025-01-01"), errMsg: "rate must be between 0 and 100 percent"},
+		{name: "no start", state: "IL", rate: 6.25, errMsg: "effective from date is required"},
+		{name: "ends before it starts", state: "IL", rate: 6.25, from: date("2026-01-01"), to: &end, errMsg: "effective to date must be after the effective from date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := NewTaxRate("Illinois", tt.state, "", "", tt.rate, "", tt.from, tt.to)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("expected error %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rate.State != "IL" {
+				t.Errorf("expected state to be upper-cased, got %q", rate.State)
+			}
+		})
+	}
+}
+
+func TestTaxRate_AppliesTo(t *testing.T) {
+	end := date("2026-01-01")
+	state, _ := NewTaxRate("Illinois", "IL", "", "", 6.25, "", date("2025-01-01"), &end)
+	city, _ := NewTaxRate("Springfield", "IL", "springfield", "", 2.5, "", date("2025-01-01"), nil)
+	zip, _ := NewTaxRate("Downtown district", "IL", "", "62701", 0.75, "", date("2025-01-01"), nil)
+
+	springfield := TaxAddress{State: "IL", City: "Springfield", Zip: "62704-1234"}
+	downtown := TaxAddress{State: "IL", City: "Springfield", Zip: "62701"}
+
+	tests := []struct {
+		name    string
+		rate    *TaxRate
+		address TaxAddress
+		on      time.Time
+		want    bool
+	}{
+		{"state rate in the state", state, springfield, date("2025-06-01"), true},
+		{"state rate on its first day", state, springfield, date("2025-01-01"), true},
+		{"state rate before it starts", state, springfield, date("2024-12-31"), false},
+		{"state rate on its end date", state, springfield, date("2026-01-01"), false},
+		{"state rate in another state", state, TaxAddress{State: "MO"}, date("2025-06-01"), false},
+		{"city rate in the city", city, springfield, date("2025-06-01"), true},
+		{"city rate in another city", city, TaxAddress{State: "IL", City: "Chicago"}, date("2025-06-01"), false},
+		{"zip rate in the zip", zip, downtown, date("2025-06-01"), true},
+		{"zip rate in another zip", zip, springfield, date("2025-06-01"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rate.AppliesTo(tt.address, tt.on); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTaxRate_Overlaps(t *testing.T) {
+	end := date("2026-01-01")
+	current, _ := NewTaxRate("Illinois", "IL", "", "", 6.25, "", date("2025-01-01"), &end)
+	next, _ := NewTaxRate("Illinois 2026", "IL", "", "", 6.5, "", date("2026-01-01"), nil)
+	early, _ := NewTaxRate("Illinois mid-year", "IL", "", "", 6.5, "", date("2025-07-01"), nil)
+	city, _ := NewTaxRate("Springfield", "IL", "Springfield", "", 2.5, "", date("2025-01-01"), nil)
+
+	if current.Overlaps(next) {
+		t.Error("expected a rate starting on the old rate's end date not to overlap")
+	}
+	if !current.Overlaps(early) || !early.Overlaps(current) {
+		t.Error("expected rates for the same state on the same days to overlap")
+	}
+	if current.Overlaps(city) {
+		t.Error("expected a state rate and a city rate not to overlap")
+	}
+	if current.Overlaps(current) {
+		t.Error("expected a rate not to overlap itself")
+	}
+}
+
+func TestTaxSettings_CalculateJobTax(t *testing.T) {
+	state, _ := NewTaxRate("Illinois", "IL", "", "", 6.25, "wave-il", date("2025-01-01"), nil)
+	city, _ := NewTaxRate("Springfield", "IL", "Springfield", "", 2.5, "", date("2025-01-01"), nil)
+	other, _ := NewTaxRate("Missouri", "MO", "", "", 4.225, "wave-mo", date("2025-01-01"), nil)
+
+	settings := &TaxSettings{
+		Rates:      []TaxRate{*state, *city, *other},
+		Categories: []TaxCategory{{Category: "labor", Taxable: false}},
+		ItemCategories: map[string]string{
+			"outlet": "Devices",
+			"labor":  "Labor",
+		},
+	}
+
+	job := &Job{
+		ID:      "job1",
+		Address: "123 Main St, Springfield, IL 62704",
+		Items: []JobItem{
+			{ItemID: "outlet", Name: "Outlet", Quantity: 10, Price: Cents(1500)},
+			{ItemID: "labor", Name: "Labor", Quantity: 2.5, Price: Cents(9500)},
+		},
+		ChangeOrders: []ChangeOrder{
+			{Status: ChangeOrderStatusApproved, Items: []ChangeOrderItem{{ItemID: "outlet", Name: "Outlet", Quantity: -2, Total: Cents(-3000)}}},
+			{Status: ChangeOrderStatusPending, Items: []ChangeOrderItem{{ItemID: "outlet", Name: "Outlet", Quantity: 5, Total: Cents(7500)}}},
+		},
+	}
+
+	tax := settings.CalculateJobTax(job, date("2025-06-01"))
+
+	if tax.TaxableAmount != Cents(12000) {
+		t.Errorf("expected taxable amount 120.00, got %s", tax.TaxableAmount)
+	}
+	if tax.ExemptAmount != Cents(23750) {
+		t.Errorf("expected labor to be exempt, got exempt amount %s", tax.ExemptAmount)
+	}
+	if len(tax.Rates) != 2 {
+		t.Fatalf("expected the state and city rates to stack, got %+v", tax.Rates)
+	}
+	if tax.Rates[0].Amount != Cents(750) || tax.Rates[1].Amount != Cents(300) {
+		t.Errorf("expected 7.50 and 3.00, got %s and %s", tax.Rates[0].Amount, tax.Rates[1].Amount)
+	}
+	if tax.TaxAmount != Cents(1050) {
+		t.Errorf("expected tax 10.50, got %s", tax.TaxAmount)
+	}
+	if ids := tax.WaveSalesTaxIDs(); len(ids) != 1 || ids[0] != "wave-il" {
+		t.Errorf("expected only the rate with a Wave ID, got %v", ids)
+	}
+	if tax.WaveTaxAmount() != Cents(750) {
+		t.Errorf("expected Wave to charge 7.50, got %s", tax.WaveTaxAmount())
+	}
+	if len(tax.Lines) != 3 || tax.Lines[1].Taxable {
+		t.Errorf("expected three lines with labor untaxed, got %+v", tax.Lines)
+	}
+}
+
+func TestTaxSettings_CalculateJobTaxSplitsJobAdjustments(t *testing.T) {
+	state, _ := NewTaxRate("Illinois", "IL", "", "", 10, "wave-il", date("2025-01-01"), nil)
+	settings := &TaxSettings{
+		Rates:          []TaxRate{*state},
+		Categories:     []TaxCategory{{Category: "Labor", Taxable: false}},
+		ItemCategories: map[string]string{"outlet": "Devices", "labor": "Labor"},
+	}
+
+	job := &Job{
+		Address: "123 Main St, Springfield, IL 62704",
+		Items: []JobItem{
+			{ItemID: "outlet", Name: "Outlet", Category: "Devices", Quantity: 10, Price: Cents(1000)},
+			{ItemID: "labor", Name: "Labor", Category: "Labor", Quantity: 1, Price: Cents(10000)},
+		},
+		Adjustments: []PriceAdjustment{
+			{ID: "a1", Name: "Labor markup", Type: AdjustmentMarkup, Scope: AdjustmentScopeCategory, Category: "Labor", Percent: 10},
+			{ID: "a2", Name: "Loyal customer", Type: AdjustmentDiscount, Scope: AdjustmentScopeJob, Percent: 10},
+		},
+	}
+	job.CalculateTotal()
+
+	tax := settings.CalculateJobTax(job, date("2025-06-01"))
+
+	// 100.00 of outlets less their 10.00 share of the 21.00 discount
+	if tax.TaxableAmount != Cents(9000) {
+		t.Errorf("expected taxable amount 90.00, got %s", tax.TaxableAmount)
+	}
+	// 100.00 of labor + 10.00 markup less the other 11.00 of the discount
+	if tax.ExemptAmount != Cents(9900) {
+		t.Errorf("expected exempt amount 99.00, got %s", tax.ExemptAmount)
+	}
+	if tax.TaxAmount != Cents(900) {
+		t.Errorf("expected tax 9.00, got %s", tax.TaxAmount)
+	}
+
+	lines := tax.AdjustmentLines()
+	if len(lines) != 3 {
+		t.Fatalf("expected the markup and both parts of the discount, got %+v", lines)
+	}
+	if lines[1].Name != "Loyal customer (taxable)" || !lines[1].Taxable || lines[2].Taxable {
+		t.Errorf("expected the discount split into taxable and non-taxable parts, got %+v", lines[1:])
+	}
+	if tax.TaxableAmount+tax.ExemptAmount != job.TotalAmount {
+		t.Errorf("expected the taxed lines to add up to the job total %s", job.TotalAmount)
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateVersion is an immutable snapshot of a template's items and phases.
+// Every save of a template records a new version, and jobs pin the version
+// they were created from.
+type TemplateVersion struct {
+	ID          string          `json:"id" db:"id"`
+	TemplateID  string          `json:"templateId" db:"template_id"`
+	Number      int             `json:"number" db:"version_number"`
+	Name        string          `json:"name" db:"name"`
+	Description string          `json:"description" db:"description"`
+	Items       []TemplateItem  `json:"items"`
+	Phases      []TemplatePhase `json:"phases"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// TemplateItemChange describes one item that differs between two template versions
+type TemplateItemChange struct {
+	ItemID       string             `json:"itemId"`
+	Type         LineItemChangeType `json:"type"`
+	FromQuantity float64            `json:"fromQuantity"`
+	ToQuantity   float64            `json:"toQuantity"`
+}
+
+// TemplatePhaseChange describes one phase that differs between two template versions
+type TemplatePhaseChange struct {
+	PhaseID   string             `json:"phaseId"`
+	Type      LineItemChangeType `json:"type"`
+	FromName  string             `json:"fromName,omitempty"`
+	ToName    string             `json:"toName,omitempty"`
+	FromOrder int                `json:"fromOrder"`
+	ToOrder   int                `json:"toOrder"`
+}
+
+// TemplateVersionDiff is the difference between two versions of a template
+type TemplateVersionDiff struct {
+	TemplateID    string                `json:"templateId"`
+	FromVersion   int                   `json:"fromVersion"`
+	ToVersion     int                   `json:"toVersion"`
+	NameChanged   bool                  `json:"nameChanged"`
+	AddedItems    []TemplateItemChange  `json:"addedItems"`
+	RemovedItems  []TemplateItemChange  `json:"removedItems"`
+	ChangedItems  []TemplateItemChange  `json:"changedItems"`
+	AddedPhases   []TemplatePhaseChange `json:"addedPhases"`
+	RemovedPhases []TemplatePhaseChange `json:"removedPhases"`
+	ChangedPhases []TemplatePhaseChange `json:"changedPhases"`
+}
+
+// NewTemplateVersion snapshots the template's current state. The version
+// number is assigned when the version is stored.
+func NewTemplateVersion(template *JobTemplate) *TemplateVersion {
+	items := make([]TemplateItem, len(template.Items))
+	copy(items, template.Items)
+	phases := make([]TemplatePhase, len(template.Phases))
+	copy(phases, template.Phases)
+
+	return &TemplateVersion{
+		ID:          uuid.New().String(),
+		TemplateID:  template.ID,
+		Name:        template.Name,
+		Description: template.Description,
+		Items:       items,
+		Phases:      phases,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// DiffTemplateVersions compares two versions of a template. Items are matched
+// by catalog item ID and phases by phase ID. Added and changed entries keep
+// the order of to, removed entries the order of from.
+func DiffTemplateVersions(from, to *TemplateVersion) TemplateVersionDiff {
+	diff := TemplateVersionDiff{
+		TemplateID:    to.TemplateID,
+		FromVersion:   from.Number,
+		ToVersion:     to.Number,
+		NameChanged:   from.Name != to.Name,
+		AddedItems:    []TemplateItemChange{},
+		RemovedItems:  []TemplateItemChange{},
+		ChangedItems:  []TemplateItemChange{},
+		AddedPhases:   []TemplatePhaseChange{},
+		RemovedPhases: []TemplatePhaseChange{},
+		ChangedPhases: []TemplatePhaseChange{},
+	}
+
+	fromItems := make(map[string]TemplateItem, len(from.Items))
+	for _, item := range from.Items {
+		fromItems[item.ItemID] = item
+	}
+	toItems := make(map[string]bool, len(to.Items))
+	for _, item := range to.Items {
+		toItems[item.ItemID] = true
+		previous, ok := fromItems[item.ItemID]
+		switch {
+		case !ok:
+			diff.AddedItems = append(diff.AddedItems, TemplateItemChange{
+				ItemID: item.ItemID, Type: LineItemAdded, ToQuantity: item.DefaultQuantity,
+			})
+		case previous.DefaultQuantity != item.DefaultQuantity:
+			diff.ChangedItems = append(diff.ChangedItems, TemplateItemChange{
+				ItemID: item.ItemID, Type: LineItemChanged,
+				FromQuantity: previous.DefaultQuantity, ToQuantity: item.DefaultQuantity,
+			})
+		}
+	}
+	for _, item := range from.Items {
+		if !toItems[item.ItemID] {
+			diff.RemovedItems = append(diff.RemovedItems, TemplateItemChange{
+				ItemID: item.ItemID, Type: LineItemRemoved, FromQuantity: item.DefaultQuantity,
+			})
+		}
+	}
+
+	fromPhases := make(map[string]TemplatePhase, len(from.Phases))
+	for _, phase := range from.Phases {
+		fromPhases[phase.ID] = phase
+	}
+	toPhases := make(map[string]bool, len(to.Phases))
+	for _, phase := range to.Phases {
+		toPhases[phase.ID] = true
+		previous, ok := fromPhases[phase.ID]
+		switch {
+		case !ok:
+			diff.AddedPhases = append(diff.AddedPhases, TemplatePhaseChange{
+				PhaseID: phase.ID, Type: LineItemAdded, ToName: phase.Name, ToOrder: phase.Order,
+			})
+		case previous.Name != phase.Name || previous.Order != phase.Order || previous.Description != phase.Description:
+			diff.ChangedPhases = append(diff.ChangedPhases, TemplatePhaseChange{
+				PhaseID: phase.ID, Type: LineItemChanged,
+				FromName: previous.Name, ToName: phase.Name,
+				FromOrder: previous.Order, ToOrder: phase.Order,
+			})
+		}
+	}
+	for _, phase := range from.Phases {
+		if !toPhases[phase.ID] {
+			diff.RemovedPhases = append(diff.RemovedPhases, TemplatePhaseChange{
+				PhaseID: phase.ID, Type: LineItemRemoved, FromName: phase.Name, FromOrder: phase.Order,
+			})
+		}
+	}
+
+	return diff
+}
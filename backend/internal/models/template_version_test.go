@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNewTemplateVersion(t *testing.T) {
+	template, _ := NewJobTemplate("Panel Upgrade", "200A service", []TemplateItem{
+		{ItemID: "panel", DefaultQuantity: 1},
+	}, []TemplatePhase{
+		{Name: "Rough", Order: 1},
+	})
+
+	version := NewTemplateVersion(template)
+	if version.TemplateID != template.ID || version.Name != "Panel Upgrade" {
+		t.Errorf("expected version of %s, got %+v", template.ID, version)
+	}
+
+	// Later edits to the template must not reach the snapshot
+	template.Items[0].DefaultQuantity = 2
+	template.Phases[0].Name = "Rough In"
+
+	if version.Items[0].DefaultQuantity != 1 {
+		t.Errorf("expected snapshot quantity 1, got %.2f", version.Items[0].DefaultQuantity)
+	}
+	if version.Phases[0].Name != "Rough" {
+		t.Errorf("expected snapshot phase Rough, got %s", version.Phases[0].Name)
+	}
+}
+
+func TestDiffTemplateVersions(t *testing.T) {
+	from := &TemplateVersion{
+		TemplateID: "tmpl",
+		Number:     1,
+		Name:       "Kitchen Remodel",
+		Items: []TemplateItem{
+			{ItemID: "outlet", DefaultQuantity: 6},
+			{ItemID: "can-light", DefaultQuantity: 8},
+			{ItemID: "switch", DefaultQuantity: 3},
+		},
+		Phases: []TemplatePhase{
+			{ID: "rough", Name: "Rough", Order: 1},
+			{ID: "trim", Name: "Trim", Order: 2},
+		},
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		diff := DiffTemplateVersions(from, from)
+		if diff.NameChanged || len(diff.AddedItems)+len(diff.RemovedItems)+len(diff.ChangedItems) != 0 ||
+			len(diff.AddedPhases)+len(diff.RemovedPhases)+len(diff.ChangedPhases) != 0 {
+			t.Errorf("expected no differences, got %+v", diff)
+		}
+	})
+
+	t.Run("items and phases edited", func(t *testing.T) {
+		to := &TemplateVersion{
+			TemplateID: "tmpl",
+			Number:     2,
+			Name:       "Kitchen Remodel",
+			Items: []TemplateItem{
+				{ItemID: "outlet", DefaultQuantity: 6},
+				{ItemID: "can-light", DefaultQuantity: 10},
+				{ItemID: "gfci", DefaultQuantity: 2},
+			},
+			Phases: []TemplatePhase{
+				{ID: "rough", Name: "Rough In", Order: 1},
+				{ID: "final", Name: "Final", Order: 2},
+			},
+		}
+
+		diff := DiffTemplateVersions(from, to)
+		if diff.FromVersion != 1 || diff.ToVersion != 2 {
+			t.Errorf("expected versions 1 to 2, got %d to %d", diff.FromVersion, diff.ToVersion)
+		}
+		if len(diff.AddedItems) != 1 || diff.AddedItems[0].ItemID != "gfci" {
+			t.Errorf("expected gfci added, got %+v", diff.AddedItems)
+		}
+		if len(diff.RemovedItems) != 1 || diff.RemovedItems[0].ItemID != "switch" {
+			t.Errorf("expected switch removed, got %+v", diff.RemovedItems)
+		}
+		if len(diff.ChangedItems) != 1 || diff.ChangedItems[0].FromQuantity != 8 || diff.ChangedItems[0].ToQuantity != 10 {
+			t.Errorf("expected can-light changed from 8 to 10, got %+v", diff.ChangedItems)
+		}
+		if len(diff.AddedPhases) != 1 || diff.AddedPhases[0].PhaseID != "final" {
+			t.Errorf("expected final phase added, got %+v", diff.AddedPhases)
+		}
+		if len(diff.RemovedPhases) != 1 || diff.RemovedPhases[0].PhaseID != "trim" {
+			t.Errorf("expected trim phase removed, got %+v", diff.RemovedPhases)
+		}
+		if len(diff.ChangedPhases) != 1 || diff.ChangedPhases[0].ToName != "Rough In" {
+			t.Errorf("expected rough phase renamed, got %+v", diff.ChangedPhases)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserRole represents the access level of a user
+type UserRole string
+
+const (
+	UserRoleAdmin UserRole = "admin"
+	UserRoleTech  UserRole = "tech"
+)
+
+// MinPasswordLength is the shortest password a user may set
+const MinPasswordLength = 8
+
+// User represents a person who can sign in to the app
+type User struct {
+	ID           string    `json:"id" db:"id"`
+	Email        string    `json:"email" db:"email"`
+	Name         string    `json:"name" db:"name"`
+	Role         UserRole  `json:"role" db:"role"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	IsActive     bool      `json:"isActive" db:"is_active"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Session represents a signed-in user. The ID is the hash of the bearer token,
+// so the token itself is never stored.
+type Session struct {
+	ID        string    `json:"-" db:"id"`
+	UserID    string    `json:"userId" db:"user_id"`
+	ExpiresAt time.Time `json:"expiresAt" db:"expires_at"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// ValidateUserRole checks if a user role is valid
+func ValidateUserRole(role UserRole) bool {
+	switch role {
+	case UserRoleAdmin, UserRoleTech:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewUser creates a new User with validation and a hashed password
+func NewUser(email, name string, role UserRole, password string) (*User, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return nil, errors.New("user email is required")
+	}
+	if !emailRegex.MatchString(email) {
+		return nil, errors.New("invalid email format")
+	}
+	if !ValidateUserRole(role) {
+		return nil, errors.New("invalid user role")
+	}
+
+	now := time.Now()
+	user := &User{
+		ID:        uuid.New().String(),
+		Email:     email,
+		Name:      strings.TrimSpace(name),
+		Role:      role,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := user.SetPassword(password); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// NormalizeEmail lowercases and trims an email so lookups are case-insensitive
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SetPassword validates and hashes a new password
+func (u *User) SetPassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return errors.New("password must be at least 8 characters")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	u.PasswordHash = string(hash)
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// CheckPassword reports whether password matches the stored hash
+func (u *User) CheckPassword(password string) bool {
+	if u.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) == nil
+}
+
+// SetRole changes the user's role with validation
+func (u *User) SetRole(role UserRole) error {
+	if !ValidateUserRole(role) {
+		return errors.New("invalid user role")
+	}
+	u.Role = role
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsAdmin reports whether the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// IsExpired reports whether the session is no longer valid at the given time
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
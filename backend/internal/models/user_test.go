@@ -0,0 +1,162 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		userName string
+		role     UserRole
+		password string
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "valid admin",
+			email:    "Admin@Bremray.com ",
+			userName: "Admin",
+			role:     UserRoleAdmin,
+			password: "supersecret",
+			wantErr:  false,
+		},
+		{
+			name:     "valid tech",
+			email:    "tech@bremray.com",
+			userName: "Tech",
+			role:     UserRoleTech,
+			password: "supersecret",
+			wantErr:  false,
+		},
+		{
+			name:     "empty email",
+			email:    "",
+			role:     UserRoleTech,
+			password: "supersecret",
+			wantErr:  true,
+			errMsg:   "user email is required",
+		},
+		{
+			name:     "invalid email",
+			email:    "not-an-email",
+			role:     UserRoleTech,
+			password: "supersecret",
+			wantErr:  true,
+			errMsg:   "invalid email format",
+		},
+		{
+			name:     "invalid role",
+			email:    "tech@bremray.com",
+			role:     "owner",
+			password: "supersecret",
+			wantErr:  true,
+			errMsg:   "invalid user role",
+		},
+		{
+			name:     "short password",
+			email:    "tech@bremray.com",
+			role:     UserRoleTech,
+			password: "short",
+			wantErr:  true,
+			errMsg:   "password must be at least 8 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := NewUser(tt.email, tt.userName, tt.role, tt.password)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				} else if err.Error() != tt.errMsg {
+					t.Errorf("expected error message %q but got %q", tt.errMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if user.ID == "" {
+				t.Error("expected ID to be generated")
+			}
+
+			if user.Email != NormalizeEmail(tt.email) {
+				t.Errorf("expected email %q but got %q", NormalizeEmail(tt.email), user.Email)
+			}
+
+			if user.PasswordHash == "" || user.PasswordHash == tt.password {
+				t.Error("expected password to be hashed")
+			}
+
+			if !user.IsActive {
+				t.Error("expected user to be active by default")
+			}
+		})
+	}
+}
+
+func TestUser_CheckPassword(t *testing.T) {
+	user, err := NewUser("tech@bremray.com", "Tech", UserRoleTech, "supersecret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !user.CheckPassword("supersecret") {
+		t.Error("expected correct password to match")
+	}
+
+	if user.CheckPassword("wrongpassword") {
+		t.Error("expected wrong password not to match")
+	}
+
+	if err := user.SetPassword("anothersecret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if user.CheckPassword("supersecret") {
+		t.Error("expected old password not to match after change")
+	}
+
+	if !user.CheckPassword("anothersecret") {
+		t.Error("expected new password to match after change")
+	}
+}
+
+func TestUser_SetRole(t *testing.T) {
+	user, _ := NewUser("tech@bremray.com", "Tech", UserRoleTech, "supersecret")
+
+	if user.IsAdmin() {
+		t.Error("expected tech not to be admin")
+	}
+
+	if err := user.SetRole(UserRoleAdmin); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !user.IsAdmin() {
+		t.Error("expected user to be admin after role change")
+	}
+
+	if err := user.SetRole("owner"); err == nil {
+		t.Error("expected error for invalid role")
+	}
+}
+
+func TestSession_IsExpired(t *testing.T) {
+	now := time.Now()
+	session := Session{ExpiresAt: now.Add(time.Hour)}
+
+	if session.IsExpired(now) {
+		t.Error("expected session not to be expired")
+	}
+
+	if !session.IsExpired(now.Add(2 * time.Hour)) {
+		t.Error("expected session to be expired")
+	}
+}
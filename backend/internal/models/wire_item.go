@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/voltagedrop"
+)
+
+// WireItem links a catalog item to the conductor it sells, so a run sized by
+// the voltage drop calculator can be quoted on a job. Each material and size
+// has at most one item.
+type WireItem struct {
+	ItemID    string               `json:"itemId" db:"item_id"`
+	ItemName  string               `json:"itemName,omitempty" db:"item_name"`
+	Material  voltagedrop.Material `json:"material" db:"material"`
+	Size      string               `json:"size" db:"size"`
+	CreatedAt time.Time            `json:"createdAt" db:"created_at"`
+}
+
+// NewWireItem creates a new WireItem with validation. The size must be a
+// standard conductor made in the material; it is stored as the standard
+// spelling, such as "1/0 AWG".
+func NewWireItem(itemID, material, size string) (*WireItem, error) {
+	if strings.TrimSpace(itemID) == "" {
+		return nil, errors.New("item is required")
+	}
+	parsed, err := voltagedrop.ParseMaterial(material)
+	if err != nil {
+		return nil, err
+	}
+	conductor, ok := voltagedrop.LookupConductor(size)
+	if !ok {
+		return nil, fmt.Errorf("unknown conductor size %q", size)
+	}
+	if conductor.Ampacity(parsed) == 0 {
+		return nil, fmt.Errorf("%s isn't made in %s", conductor.Size, parsed)
+	}
+
+	return &WireItem{
+		ItemID:    itemID,
+		Material:  parsed,
+		Size:      conductor.Size,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// QuoteWire is the job line budgeting footage of a wire item. When it
+// replaces a line of the same item, that line keeps its ID, price and
+// installed quantity and takes footage as its budget; any other line it
+// replaces is dropped for a new one.
+func QuoteWire(jobID string, item *Item, footage float64, replacing *JobItem) JobItem {
+	if replacing != nil && replacing.ItemID == item.ID {
+		line := *replacing
+		line.BudgetedQuantity = footage
+		line.CalculateTotal()
+		return line
+	}
+
+	return BudgetJobItem(jobID, item, footage)
+}
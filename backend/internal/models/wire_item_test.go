@@ -0,0 +1,67 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/voltagedrop"
+)
+
+func TestNewWireItem_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		itemID   string
+		material string
+		size     string
+		errMsg   string
+	}{
+		{"valid", "wire-1", "Cu", " 1/0 awg", ""},
+		{"missing item", " ", "copper", "6 AWG", "item is required"},
+		{"unknown material", "wire-1", "gold", "6 AWG", `unknown conductor material "gold": use copper or aluminum`},
+		{"unknown size", "wire-1", "copper", "5 AWG", `unknown conductor size "5 AWG"`},
+		{"not made", "wire-1", "aluminum", "14 AWG", "14 AWG isn't made in aluminum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire, err := NewWireItem(tt.itemID, tt.material, tt.size)
+			if tt.errMsg != "" {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("expected error %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if wire.Material != voltagedrop.Copper || wire.Size != "1/0 AWG" {
+				t.Errorf("expected 1/0 AWG copper, got %+v", wire)
+			}
+		})
+	}
+}
+
+func TestQuoteWire(t *testing.T) {
+	wire := &Item{ID: "4cu", Name: "4 AWG THHN Copper", Category: "Wire", UnitPrice: Cents(210), UnitCost: Cents(120)}
+
+	line := QuoteWire("job-1", wire, 301, nil)
+	if line.ID == "" || line.JobID != "job-1" || line.ItemID != "4cu" || line.Name != "4 AWG THHN Copper" {
+		t.Errorf("unexpected line %+v", line)
+	}
+	if line.Quantity != 0 || line.BudgetedQuantity != 301 || line.Price != Cents(210) || line.Cost != Cents(120) {
+		t.Errorf("expected 301 ft budgeted and none installed, got %+v", line)
+	}
+
+	// Replacing a line of the same wire keeps what techs have counted
+	existing := &JobItem{ID: "line-1", JobID: "job-1", ItemID: "4cu", Name: "4 AWG THHN Copper", Quantity: 120, BudgetedQuantity: 250, Price: Cents(200)}
+	line = QuoteWire("job-1", wire, 301, existing)
+	if line.ID != "line-1" || line.Quantity != 120 || line.BudgetedQuantity != 301 || line.Price != Cents(200) || line.Total != Cents(24000) {
+		t.Errorf("expected line-1 budgeted 301 ft at its own price, got %+v", line)
+	}
+
+	// Replacing another wire starts a new line
+	undersized := &JobItem{ID: "line-2", JobID: "job-1", ItemID: "6cu", Quantity: 40, BudgetedQuantity: 300}
+	line = QuoteWire("job-1", wire, 301, undersized)
+	if line.ID == "line-2" || line.ItemID != "4cu" || line.Quantity != 0 {
+		t.Errorf("expected a new 4 AWG line, got %+v", line)
+	}
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// AdjustmentRepository defines the interface for default markup and discount
+// database operations. The adjustments on a job are stored with the job.
+type AdjustmentRepository interface {
+	Create(ctx context.Context, adjustment *models.PriceAdjustment) error
+	GetByID(ctx context.Context, id string) (*models.PriceAdjustment, error)
+	Update(ctx context.Context, adjustment *models.PriceAdjustment) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.PriceAdjustment, error)
+	// ListForCustomer returns the defaults for a new job for the customer:
+	// those for every job and those for the customer
+	ListForCustomer(ctx context.Context, customerID string) ([]models.PriceAdjustment, error)
+}
+
+type adjustmentRepository struct {
+	db executor
+}
+
+// NewAdjustmentRepository creates a new adjustment repository
+func NewAdjustmentRepository(db *sql.DB) AdjustmentRepository {
+	return &adjustmentRepository{db: db}
+}
+
+const adjustmentColumns = `
+	id, customer_id, name, type, scope, item_id, category, percent, amount,
+	created_at, updated_at
+`
+
+// Create inserts a default adjustment
+func (r *adjustmentRepository) Create(ctx context.Context, adjustment *models.PriceAdjustment) error {
+	if adjustment == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO price_adjustments (` + adjustmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		adjustment.ID, nullString(adjustment.CustomerID), adjustment.Name, adjustment.Type,
+		adjustment.Scope, adjustment.ItemID, adjustment.Category, adjustment.Percent,
+		adjustment.Amount, adjustment.CreatedAt, adjustment.UpdatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to create adjustment", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a default adjustment by its ID
+func (r *adjustmentRepository) GetByID(ctx context.Context, id string) (*models.PriceAdjustment, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + adjustmentColumns + ` FROM price_adjustments WHERE id = $1`
+
+	adjustment, err := scanAdjustment(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get adjustment", err)
+	}
+
+	return adjustment, nil
+}
+
+// Update saves a default adjustment
+func (r *adjustmentRepository) Update(ctx context.Context, adjustment *models.PriceAdjustment) error {
+	if adjustment == nil || adjustment.ID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		UPDATE price_adjustments SET
+			customer_id = $2, name = $3, type = $4, scope = $5, item_id = $6,
+			category = $7, percent = $8, amount = $9, updated_at = $10
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		adjustment.ID, nullString(adjustment.CustomerID), adjustment.Name, adjustment.Type,
+		adjustment.Scope, adjustment.ItemID, adjustment.Category, adjustment.Percent,
+		adjustment.Amount, adjustment.UpdatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to update adjustment", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a default adjustment. Jobs keep their copies.
+func (r *adjustmentRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM price_adjustments WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete adjustment", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List returns every default adjustment, those for every job first
+func (r *adjustmentRepository) List(ctx context.Context) ([]models.PriceAdjustment, error) {
+	query := `
+		SELECT ` + adjustmentColumns + ` FROM price_adjustments
+		ORDER BY customer_id NULLS FIRST, name
+	`
+
+	return queryAdjustments(ctx, r.db, query)
+}
+
+// ListForCustomer returns the defaults for every job and those for the customer
+func (r *adjustmentRepository) ListForCustomer(ctx context.Context, customerID string) ([]models.PriceAdjustment, error) {
+	query := `
+		SELECT ` + adjustmentColumns + ` FROM price_adjustments
+		WHERE customer_id IS NULL OR customer_id = $1
+		ORDER BY customer_id NULLS FIRST, name
+	`
+
+	return queryAdjustments(ctx, r.db, query, customerID)
+}
+
+// getJobAdjustments loads the adjustments on a job. It is shared with the job
+// repository so loaded jobs carry their adjustments.
+func getJobAdjustments(ctx context.Context, db executor, jobID string) ([]models.PriceAdjustment, error) {
+	query := `
+		SELECT job_id, ` + adjustmentColumns + ` FROM job_adjustments
+		WHERE job_id = $1
+		ORDER BY created_at, name
+	`
+
+	rows, err := db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list job adjustments", err)
+	}
+	defer rows.Close()
+
+	adjustments := make([]models.PriceAdjustment, 0)
+	for rows.Next() {
+		var jobID string
+		adjustment, err := scanAdjustment(rows, &jobID)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan job adjustment", err)
+		}
+		adjustment.JobID = jobID
+		adjustments = append(adjustments, *adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewRepositoryError("failed to iterate job adjustments", err)
+	}
+
+	return adjustments, nil
+}
+
+func queryAdjustments(ctx context.Context, db executor, query string, args ...interface{}) ([]models.PriceAdjustment, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list adjustments", err)
+	}
+	defer rows.Close()
+
+	adjustments := make([]models.PriceAdjustment, 0)
+	for rows.Next() {
+		adjustment, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan adjustment", err)
+		}
+		adjustments = append(adjustments, *adjustment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewRepositoryError("failed to iterate adjustments", err)
+	}
+
+	return adjustments, nil
+}
+
+// scanAdjustment scans adjustmentColumns, after any leading destinations
+func scanAdjustment(row rowScanner, leading ...interface{}) (*models.PriceAdjustment, error) {
+	var adjustment models.PriceAdjustment
+	var customerID sql.NullString
+
+	dest := append(leading,
+		&adjustment.ID, &customerID, &adjustment.Name, &adjustment.Type, &adjustment.Scope,
+		&adjustment.ItemID, &adjustment.Category, &adjustment.Percent, &adjustment.Amount,
+		&adjustment.CreatedAt, &adjustment.UpdatedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	adjustment.CustomerID = customerID.String
+
+	return &adjustment, nil
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
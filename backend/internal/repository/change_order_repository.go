@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// ChangeOrderRepository defines the interface for change order database operations
+type ChangeOrderRepository interface {
+	Create(ctx context.Context, changeOrder *models.ChangeOrder) error
+	GetByID(ctx context.Context, id string) (*models.ChangeOrder, error)
+	GetByJobID(ctx context.Context, jobID string) ([]models.ChangeOrder, error)
+	Update(ctx context.Context, changeOrder *models.ChangeOrder) error
+	UpdateFromStatus(ctx context.Context, changeOrder *models.ChangeOrder, from models.ChangeOrderStatus) error
+	Delete(ctx context.Context, id string) error
+}
+
+type changeOrderRepository struct {
+	db *sql.DB
+}
+
+// NewChangeOrderRepository creates a new change order repository
+func NewChangeOrderRepository(db *sql.DB) ChangeOrderRepository {
+	return &changeOrderRepository{db: db}
+}
+
+const changeOrderColumns = `
+	id, job_id, change_order_number, reason, status, total_amount,
+	signed_by, signed_at, decided_at, created_at, updated_at
+`
+
+// Create inserts a change order and its lines, assigning it the job's next
+// change order number
+func (r *changeOrderRepository) Create(ctx context.Context, changeOrder *models.ChangeOrder) error {
+	if changeOrder == nil {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the job so concurrent change orders get distinct numbers
+	var jobID string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM jobs WHERE id = $1 FOR UPDATE`, changeOrder.JobID).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return NewRepositoryError("failed to lock job", err)
+	}
+
+	var number int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(change_order_number), 0) + 1 FROM change_orders WHERE job_id = $1`,
+		changeOrder.JobID,
+	).Scan(&number)
+	if err != nil {
+		return NewRepositoryError("failed to number change order", err)
+	}
+
+	query := `
+		INSERT INTO change_orders (` + changeOrderColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		changeOrder.ID, changeOrder.JobID, number, changeOrder.Reason, changeOrder.Status,
+		changeOrder.TotalAmount, changeOrder.SignedBy, changeOrder.SignedAt, changeOrder.DecidedAt,
+		changeOrder.CreatedAt, changeOrder.UpdatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to create change order", err)
+	}
+
+	if err := insertChangeOrderItems(ctx, tx, changeOrder); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit change order", err)
+	}
+
+	changeOrder.Number = number
+	return nil
+}
+
+// GetByID retrieves a change order with its lines
+func (r *changeOrderRepository) GetByID(ctx context.Context, id string) (*models.ChangeOrder, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + changeOrderColumns + ` FROM change_orders WHERE id = $1`
+
+	changeOrder, err := scanChangeOrder(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	changeOrder.Items, err = getChangeOrderItems(ctx, r.db, changeOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return changeOrder, nil
+}
+
+// GetByJobID retrieves every change order on a job, oldest first
+func (r *changeOrderRepository) GetByJobID(ctx context.Context, jobID string) ([]models.ChangeOrder, error) {
+	return getChangeOrdersForJob(ctx, r.db, jobID)
+}
+
+// Update saves the change order header and replaces its lines in a single transaction
+func (r *changeOrderRepository) Update(ctx context.Context, changeOrder *models.ChangeOrder) error {
+	return r.update(ctx, changeOrder, "")
+}
+
+// UpdateFromStatus saves the change order only while it is still in the given
+// status and its stored signature, if any, is the one being saved. It returns
+// ErrConflict when another request decided, signed or deleted it first.
+func (r *changeOrderRepository) UpdateFromStatus(ctx context.Context, changeOrder *models.ChangeOrder, from models.ChangeOrderStatus) error {
+	if from == "" {
+		return ErrInvalidInput
+	}
+	return r.update(ctx, changeOrder, from)
+}
+
+// update saves the change order, conditioned on its stored status and
+// signature when from is set
+func (r *changeOrderRepository) update(ctx context.Context, changeOrder *models.ChangeOrder, from models.ChangeOrderStatus) error {
+	if changeOrder == nil || changeOrder.ID == "" {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE change_orders SET
+			reason = $2, status = $3, total_amount = $4, signed_by = $5,
+			signed_at = $6, decided_at = $7, updated_at = $8
+		WHERE id = $1
+	`
+	args := []interface{}{
+		changeOrder.ID, changeOrder.Reason, changeOrder.Status, changeOrder.TotalAmount,
+		changeOrder.SignedBy, changeOrder.SignedAt, changeOrder.DecidedAt, changeOrder.UpdatedAt,
+	}
+	if from != "" {
+		query += ` AND status = $9 AND (signed_at IS NULL OR signed_at = $6)`
+		args = append(args, from)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return NewRepositoryError("failed to update change order", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to get affected rows", err)
+	}
+	if rowsAffected == 0 {
+		if from != "" {
+			return ErrConflict
+		}
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM change_order_items WHERE change_order_id = $1`, changeOrder.ID); err != nil {
+		return NewRepositoryError("failed to clear change order items", err)
+	}
+
+	if err := insertChangeOrderItems(ctx, tx, changeOrder); err != nil {
+		return err
+	}
+
+	// Approving a change order adds it to the job total
+	if err := refreshJobTotal(ctx, tx, changeOrder.JobID); err != nil {
+		return NewRepositoryError("failed to refresh job total", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit change order", err)
+	}
+
+	return nil
+}
+
+// Delete removes a change order and refreshes its job's total; its lines are
+// removed by cascade
+func (r *changeOrderRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	var jobID string
+	err = tx.QueryRowContext(ctx, `DELETE FROM change_orders WHERE id = $1 RETURNING job_id`, id).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return NewRepositoryError("failed to delete change order", err)
+	}
+
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return NewRepositoryError("failed to refresh job total", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit change order", err)
+	}
+
+	return nil
+}
+
+// getChangeOrdersForJob loads every change order on a job with its lines. It is
+// shared with the job repository so loaded jobs carry their change orders.
+func getChangeOrdersForJob(ctx context.Context, db executor, jobID string) ([]models.ChangeOrder, error) {
+	query := `
+		SELECT ` + changeOrderColumns + `
+		FROM change_orders
+		WHERE job_id = $1
+		ORDER BY change_order_number
+	`
+
+	rows, err := db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list change orders", err)
+	}
+	defer rows.Close()
+
+	changeOrders := make([]models.ChangeOrder, 0)
+	for rows.Next() {
+		changeOrder, err := scanChangeOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		changeOrders = append(changeOrders, *changeOrder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewRepositoryError("failed to iterate change orders", err)
+	}
+	rows.Close()
+
+	for i := range changeOrders {
+		changeOrders[i].Items, err = getChangeOrderItems(ctx, db, changeOrders[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return changeOrders, nil
+}
+
+func getChangeOrderItems(ctx context.Context, db executor, changeOrderID string) ([]models.ChangeOrderItem, error) {
+	query := `
+		SELECT id, change_order_id, item_id, name, quantity, price, cost, total
+		FROM change_order_items
+		WHERE change_order_id = $1
+		ORDER BY created_at, name
+	`
+
+	rows, err := db.QueryContext(ctx, query, changeOrderID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to get change order items", err)
+	}
+	defer rows.Close()
+
+	items := make([]models.ChangeOrderItem, 0)
+	for rows.Next() {
+		var item models.ChangeOrderItem
+		if err := rows.Scan(
+			&item.ID, &item.ChangeOrderID, &item.ItemID, &item.Name,
+			&item.Quantity, &item.Price, &item.Cost, &item.Total,
+		); err != nil {
+			return nil, NewRepositoryError("failed to scan change order item", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func insertChangeOrderItems(ctx context.Context, tx executor, changeOrder *models.ChangeOrder) error {
+	query := `
+		INSERT INTO change_order_items (id, change_order_id, item_id, name, quantity, price, cost, total, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+	`
+
+	for _, item := range changeOrder.Items {
+		_, err := tx.ExecContext(ctx, query,
+			item.ID, changeOrder.ID, item.ItemID, item.Name, item.Quantity, item.Price, item.Cost, item.Total,
+		)
+		if err != nil {
+			return NewRepositoryError("failed to add change order item", err)
+		}
+	}
+
+	return nil
+}
+
+func scanChangeOrder(row rowScanner) (*models.ChangeOrder, error) {
+	changeOrder := &models.ChangeOrder{}
+	var signedBy sql.NullString
+
+	err := row.Scan(
+		&changeOrder.ID, &changeOrder.JobID, &changeOrder.Number, &changeOrder.Reason,
+		&changeOrder.Status, &changeOrder.TotalAmount, &signedBy, &changeOrder.SignedAt,
+		&changeOrder.DecidedAt, &changeOrder.CreatedAt, &changeOrder.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to scan change order", err)
+	}
+
+	changeOrder.SignedBy = signedBy.String
+	return changeOrder, nil
+}
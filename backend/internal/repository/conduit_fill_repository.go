@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// ConduitFillRepository defines the interface for the conductor and conduit
+// area reference tables
+type ConduitFillRepository interface {
+	CreateConductor(ctx context.Context, conductor *models.ConductorArea) error
+	GetConductor(ctx context.Context, id string) (*models.ConductorArea, error)
+	UpdateConductor(ctx context.Context, conductor *models.ConductorArea) error
+	DeleteConductor(ctx context.Context, id string) error
+	ListConductors(ctx context.Context) ([]models.ConductorArea, error)
+
+	CreateConduit(ctx context.Context, conduit *models.ConduitSize) error
+	GetConduit(ctx context.Context, id string) (*models.ConduitSize, error)
+	UpdateConduit(ctx context.Context, conduit *models.ConduitSize) error
+	DeleteConduit(ctx context.Context, id string) error
+	ListConduits(ctx context.Context, conduitType string) ([]models.ConduitSize, error)
+}
+
+type conduitFillRepository struct {
+	db executor
+}
+
+// NewConduitFillRepository creates a new conduit fill repository
+func NewConduitFillRepository(db *sql.DB) ConduitFillRepository {
+	return &conduitFillRepository{db: db}
+}
+
+const conductorAreaColumns = `id, insulation, size, area_sq_in, created_at, updated_at`
+
+const conduitSizeSelect = `
+	SELECT c.id, c.conduit_type, c.trade_size, c.area_sq_in,
+		COALESCE(c.item_id, ''), COALESCE(i.name, ''), c.created_at, c.updated_at
+	FROM conduit_sizes c
+	LEFT JOIN items i ON i.id = c.item_id
+`
+
+// CreateConductor inserts a conductor area
+func (r *conduitFillRepository) CreateConductor(ctx context.Context, conductor *models.ConductorArea) error {
+	if conductor == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO conductor_areas (` + conductorAreaColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		conductor.ID, conductor.Insulation, conductor.Size, conductor.AreaSqIn, conductor.CreatedAt, conductor.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to create conductor area", err)
+	}
+
+	return nil
+}
+
+// GetConductor retrieves a conductor area by its ID
+func (r *conduitFillRepository) GetConductor(ctx context.Context, id string) (*models.ConductorArea, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + conductorAreaColumns + ` FROM conductor_areas WHERE id = $1`
+
+	conductor, err := scanConductorArea(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get conductor area", err)
+	}
+
+	return conductor, nil
+}
+
+// UpdateConductor saves a conductor area
+func (r *conduitFillRepository) UpdateConductor(ctx context.Context, conductor *models.ConductorArea) error {
+	if conductor == nil || conductor.ID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		UPDATE conductor_areas
+		SET insulation = $2, size = $3, area_sq_in = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		conductor.ID, conductor.Insulation, conductor.Size, conductor.AreaSqIn, conductor.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to update conductor area", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteConductor removes a conductor area
+func (r *conduitFillRepository) DeleteConductor(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM conductor_areas WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete conductor area", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListConductors returns every conductor area by insulation, smallest first
+func (r *conduitFillRepository) ListConductors(ctx context.Context) ([]models.ConductorArea, error) {
+	query := `SELECT ` + conductorAreaColumns + ` FROM conductor_areas ORDER BY insulation, area_sq_in`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list conductor areas", err)
+	}
+	defer rows.Close()
+
+	conductors := []models.ConductorArea{}
+	for rows.Next() {
+		conductor, err := scanConductorArea(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan conductor area", err)
+		}
+		conductors = append(conductors, *conductor)
+	}
+
+	return conductors, rows.Err()
+}
+
+// CreateConduit inserts a conduit size. An item that doesn't exist returns
+// ErrInvalidInput.
+func (r *conduitFillRepository) CreateConduit(ctx context.Context, conduit *models.ConduitSize) error {
+	if conduit == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO conduit_sizes (id, conduit_type, trade_size, area_sq_in, item_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		conduit.ID, conduit.ConduitType, conduit.TradeSize, conduit.AreaSqIn, conduit.ItemID, conduit.CreatedAt, conduit.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		if isForeignKeyError(err) {
+			return ErrInvalidInput
+		}
+		return NewRepositoryError("failed to create conduit size", err)
+	}
+
+	return nil
+}
+
+// GetConduit retrieves a conduit size by its ID
+func (r *conduitFillRepository) GetConduit(ctx context.Context, id string) (*models.ConduitSize, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	conduit, err := scanConduitSize(r.db.QueryRowContext(ctx, conduitSizeSelect+` WHERE c.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get conduit size", err)
+	}
+
+	return conduit, nil
+}
+
+// UpdateConduit saves a conduit size. An item that doesn't exist returns
+// ErrInvalidInput.
+func (r *conduitFillRepository) UpdateConduit(ctx context.Context, conduit *models.ConduitSize) error {
+	if conduit == nil || conduit.ID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		UPDATE conduit_sizes
+		SET conduit_type = $2, trade_size = $3, area_sq_in = $4, item_id = NULLIF($5, ''), updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		conduit.ID, conduit.ConduitType, conduit.TradeSize, conduit.AreaSqIn, conduit.ItemID, conduit.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		if isForeignKeyError(err) {
+			return ErrInvalidInput
+		}
+		return NewRepositoryError("failed to update conduit size", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteConduit removes a conduit size
+func (r *conduitFillRepository) DeleteConduit(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM conduit_sizes WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete conduit size", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListConduits returns the conduit sizes of conduitType, or of every type
+// when it is empty, by type and smallest first
+func (r *conduitFillRepository) ListConduits(ctx context.Context, conduitType string) ([]models.ConduitSize, error) {
+	query := conduitSizeSelect + `
+		WHERE $1 = '' OR LOWER(c.conduit_type) = LOWER($1)
+		ORDER BY c.conduit_type, c.area_sq_in
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, conduitType)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list conduit sizes", err)
+	}
+	defer rows.Close()
+
+	conduits := []models.ConduitSize{}
+	for rows.Next() {
+		conduit, err := scanConduitSize(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan conduit size", err)
+		}
+		conduits = append(conduits, *conduit)
+	}
+
+	return conduits, rows.Err()
+}
+
+func scanConductorArea(row rowScanner) (*models.ConductorArea, error) {
+	var conductor models.ConductorArea
+	err := row.Scan(&conductor.ID, &conductor.Insulation, &conductor.Size, &conductor.AreaSqIn, &conductor.CreatedAt, &conductor.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &conductor, nil
+}
+
+func scanConduitSize(row rowScanner) (*models.ConduitSize, error) {
+	var conduit models.ConduitSize
+	err := row.Scan(
+		&conduit.ID, &conduit.ConduitType, &conduit.TradeSize, &conduit.AreaSqIn,
+		&conduit.ItemID, &conduit.ItemName, &conduit.CreatedAt, &conduit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &conduit, nil
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// EstimateRepository defines the interface for estimate database operations
+type EstimateRepository interface {
+	Create(ctx context.Context, estimate *models.Estimate) error
+	GetByID(ctx context.Context, id string) (*models.Estimate, error)
+	Update(ctx context.Context, estimate *models.Estimate) error
+	UpdateFromStatus(ctx context.Context, estimate *models.Estimate, from models.EstimateStatus) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, limit, offset int) ([]*models.Estimate, error)
+	GetByCustomerID(ctx context.Context, customerID string) ([]*models.Estimate, error)
+	GetByStatus(ctx context.Context, status models.EstimateStatus) ([]*models.Estimate, error)
+}
+
+type estimateRepository struct {
+	db executor
+}
+
+// NewEstimateRepository creates a new estimate repository
+func NewEstimateRepository(db *sql.DB) EstimateRepository {
+	return &estimateRepository{db: db}
+}
+
+const estimateColumns = `
+	id, customer_id, template_id, address, status, revision, valid_until,
+	total_amount, notes, job_id, sent_at, decided_at, created_at, updated_at
+`
+
+// Create inserts an estimate and its lines in a single transaction
+func (r *estimateRepository) Create(ctx context.Context, estimate *models.Estimate) error {
+	if estimate == nil {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO estimates (` + estimateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		estimate.ID, estimate.CustomerID, estimate.TemplateID, estimate.Address,
+		estimate.Status, estimate.Revision, estimate.ValidUntil, estimate.TotalAmount,
+		estimate.Notes, estimate.JobID, estimate.SentAt, estimate.DecidedAt,
+		estimate.CreatedAt, estimate.UpdatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to create estimate", err)
+	}
+
+	if err := insertEstimateItems(ctx, tx, estimate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit estimate", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an estimate with its lines
+func (r *estimateRepository) GetByID(ctx context.Context, id string) (*models.Estimate, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + estimateColumns + ` FROM estimates WHERE id = $1`
+
+	estimate, err := scanEstimate(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	estimate.Items, err = r.getItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return estimate, nil
+}
+
+// Update saves the estimate header and replaces its lines in a single transaction
+func (r *estimateRepository) Update(ctx context.Context, estimate *models.Estimate) error {
+	return r.update(ctx, estimate, "")
+}
+
+// UpdateFromStatus saves the estimate only while it is still in the given
+// status, returning ErrConflict when another request moved it on first
+func (r *estimateRepository) UpdateFromStatus(ctx context.Context, estimate *models.Estimate, from models.EstimateStatus) error {
+	if from == "" {
+		return ErrInvalidInput
+	}
+	return r.update(ctx, estimate, from)
+}
+
+// update saves the estimate, conditioned on its stored status when from is set
+func (r *estimateRepository) update(ctx context.Context, estimate *models.Estimate, from models.EstimateStatus) error {
+	if estimate == nil || estimate.ID == "" {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE estimates SET
+			customer_id = $2, template_id = $3, address = $4, status = $5,
+			revision = $6, valid_until = $7, total_amount = $8, notes = $9,
+			job_id = $10, sent_at = $11, decided_at = $12, updated_at = $13
+		WHERE id = $1
+	`
+	args := []interface{}{
+		estimate.ID, estimate.CustomerID, estimate.TemplateID, estimate.Address,
+		estimate.Status, estimate.Revision, estimate.ValidUntil, estimate.TotalAmount,
+		estimate.Notes, estimate.JobID, estimate.SentAt, estimate.DecidedAt,
+		estimate.UpdatedAt,
+	}
+	if from != "" {
+		query += ` AND status = $14`
+		args = append(args, from)
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return NewRepositoryError("failed to update estimate", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to get affected rows", err)
+	}
+	if rowsAffected == 0 {
+		if from != "" {
+			return ErrConflict
+		}
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM estimate_items WHERE estimate_id = $1`, estimate.ID); err != nil {
+		return NewRepositoryError("failed to clear estimate items", err)
+	}
+
+	if err := insertEstimateItems(ctx, tx, estimate); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit estimate", err)
+	}
+
+	return nil
+}
+
+// Delete removes an estimate; its lines are removed by cascade
+func (r *estimateRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM estimates WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete estimate", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to get affected rows", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// List retrieves estimates, newest first
+func (r *estimateRepository) List(ctx context.Context, limit, offset int) ([]*models.Estimate, error) {
+	query := `
+		SELECT ` + estimateColumns + `
+		FROM estimates
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.queryEstimates(ctx, query, limit, offset)
+}
+
+// GetByCustomerID retrieves all estimates for a customer
+func (r *estimateRepository) GetByCustomerID(ctx context.Context, customerID string) ([]*models.Estimate, error) {
+	query := `
+		SELECT ` + estimateColumns + `
+		FROM estimates
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.queryEstimates(ctx, query, customerID)
+}
+
+// GetByStatus retrieves all estimates with the given status
+func (r *estimateRepository) GetByStatus(ctx context.Context, status models.EstimateStatus) ([]*models.Estimate, error) {
+	query := `
+		SELECT ` + estimateColumns + `
+		FROM estimates
+		WHERE status = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.queryEstimates(ctx, query, status)
+}
+
+func (r *estimateRepository) queryEstimates(ctx context.Context, query string, args ...interface{}) ([]*models.Estimate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list estimates", err)
+	}
+	defer rows.Close()
+
+	var estimates []*models.Estimate
+	for rows.Next() {
+		estimate, err := scanEstimate(rows)
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, estimate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewRepositoryError("failed to iterate estimates", err)
+	}
+	rows.Close()
+
+	for _, estimate := range estimates {
+		estimate.Items, err = r.getItems(ctx, estimate.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return estimates, nil
+}
+
+func (r *estimateRepository) getItems(ctx context.Context, estimateID string) ([]models.EstimateItem, error) {
+	query := `
+		SELECT id, estimate_id, item_id, name, quantity, price, total
+		FROM estimate_items
+		WHERE estimate_id = $1
+		ORDER BY created_at, name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, estimateID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to get estimate items", err)
+	}
+	defer rows.Close()
+
+	items := []models.EstimateItem{}
+	for rows.Next() {
+		var item models.EstimateItem
+		if err := rows.Scan(
+			&item.ID, &item.EstimateID, &item.ItemID, &item.Name,
+			&item.Quantity, &item.Price, &item.Total,
+		); err != nil {
+			return nil, NewRepositoryError("failed to scan estimate item", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func insertEstimateItems(ctx context.Context, tx executor, estimate *models.Estimate) error {
+	query := `
+		INSERT INTO estimate_items (id, estimate_id, item_id, name, quantity, price, total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`
+
+	for _, item := range estimate.Items {
+		_, err := tx.ExecContext(ctx, query,
+			item.ID, estimate.ID, item.ItemID, item.Name, item.Quantity, item.Price, item.Total,
+		)
+		if err != nil {
+			return NewRepositoryError("failed to add estimate item", err)
+		}
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEstimate(row rowScanner) (*models.Estimate, error) {
+	estimate := &models.Estimate{}
+	var notes sql.NullString
+
+	err := row.Scan(
+		&estimate.ID, &estimate.CustomerID, &estimate.TemplateID, &estimate.Address,
+		&estimate.Status, &estimate.Revision, &estimate.ValidUntil, &estimate.TotalAmount,
+		&notes, &estimate.JobID, &estimate.SentAt, &estimate.DecidedAt,
+		&estimate.CreatedAt, &estimate.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to scan estimate", err)
+	}
+
+	estimate.Notes = notes.String
+	return estimate, nil
+}
@@ -13,6 +13,11 @@ type ItemRepository interface {
 	List(ctx context.Context, filter map[string]interface{}) ([]*models.Item, error)
 	Update(ctx context.Context, id string, updates map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
+	AddCost(ctx context.Context, cost *models.ItemCost) error
+	ListCosts(ctx context.Context, itemID string) ([]models.ItemCost, error)
+	ListPriceHistory(ctx context.Context, itemID string) ([]models.ItemPriceChange, error)
+	AddPriceChange(ctx context.Context, change *models.ItemPriceChange) error
+	SaveAssembly(ctx context.Context, item *models.Item) error
 }
 
 // Errors
@@ -20,6 +25,8 @@ var (
 	ErrNotFound = NewRepositoryError("not found", nil)
 	ErrDuplicate = NewRepositoryError("duplicate entry", nil)
 	ErrInvalidInput = NewRepositoryError("invalid input", nil)
+	ErrInUse = NewRepositoryError("in use", nil)
+	ErrConflict = NewRepositoryError("changed by another request", nil)
 )
 
 // RepositoryError represents a repository-level error
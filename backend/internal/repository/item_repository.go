@@ -3,13 +3,15 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
 )
 
 type itemRepository struct {
-	db *sql.DB
+	db executor
 }
 
 // NewItemRepository creates a new item repository
@@ -17,6 +19,19 @@ func NewItemRepository(db *sql.DB) ItemRepository {
 	return &itemRepository{db: db}
 }
 
+// itemColumns selects an item with its cost: the latest supplier cost already
+// in effect, or the cost set on the item when it has none
+const itemColumns = `
+	id, name, COALESCE(nickname, '') as nickname, unit, unit_price,
+	COALESCE((
+		SELECT c.cost FROM item_costs c
+		WHERE c.item_id = items.id AND c.effective_from <= CURRENT_DATE
+		ORDER BY c.effective_from DESC, c.created_at DESC
+		LIMIT 1
+	), unit_cost) as unit_cost,
+	labor_hours, category, created_at, updated_at
+`
+
 // Create inserts a new item into the database
 func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
 	if item == nil {
@@ -24,8 +39,8 @@ func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
 	}
 
 	query := `
-		INSERT INTO items (id, name, nickname, unit, unit_price, category, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO items (id, name, nickname, unit, unit_price, unit_cost, labor_hours, category, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -34,6 +49,8 @@ func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
 		item.Nickname,
 		item.Unit,
 		item.UnitPrice,
+		item.UnitCost,
+		item.LaborHours,
 		item.Category,
 		item.CreatedAt,
 		item.UpdatedAt,
@@ -52,15 +69,20 @@ func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
 
 // GetByID retrieves an item by its ID
 func (r *itemRepository) GetByID(ctx context.Context, id string) (*models.Item, error) {
+	return r.getItem(ctx, id, false)
+}
+
+// getItem retrieves an item by its ID, locking its row for the rest of the
+// transaction when forUpdate is set
+func (r *itemRepository) getItem(ctx context.Context, id string, forUpdate bool) (*models.Item, error) {
 	if id == "" {
 		return nil, ErrInvalidInput
 	}
 
-	query := `
-		SELECT id, name, COALESCE(nickname, '') as nickname, unit, unit_price, category, created_at, updated_at
-		FROM items
-		WHERE id = $1
-	`
+	query := `SELECT ` + itemColumns + ` FROM items WHERE id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
 
 	var item models.Item
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -69,6 +91,8 @@ func (r *itemRepository) GetByID(ctx context.Context, id string) (*models.Item,
 		&item.Nickname,
 		&item.Unit,
 		&item.UnitPrice,
+		&item.UnitCost,
+		&item.LaborHours,
 		&item.Category,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -81,12 +105,16 @@ func (r *itemRepository) GetByID(ctx context.Context, id string) (*models.Item,
 		return nil, NewRepositoryError("failed to get item", err)
 	}
 
+	if err := r.loadAssemblies(ctx, []*models.Item{&item}); err != nil {
+		return nil, err
+	}
+
 	return &item, nil
 }
 
 // List retrieves items with optional filtering
 func (r *itemRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.Item, error) {
-	query := `SELECT id, name, COALESCE(nickname, '') as nickname, unit, unit_price, category, created_at, updated_at FROM items`
+	query := `SELECT ` + itemColumns + ` FROM items`
 	args := []interface{}{}
 	
 	// Build WHERE clause from filter
@@ -125,6 +153,8 @@ func (r *itemRepository) List(ctx context.Context, filter map[string]interface{}
 			&item.Nickname,
 			&item.Unit,
 			&item.UnitPrice,
+			&item.UnitCost,
+			&item.LaborHours,
 			&item.Category,
 			&item.CreatedAt,
 			&item.UpdatedAt,
@@ -139,39 +169,68 @@ func (r *itemRepository) List(ctx context.Context, filter map[string]interface{}
 		return nil, NewRepositoryError("error iterating items", err)
 	}
 
+	if err := r.loadAssemblies(ctx, items); err != nil {
+		return nil, err
+	}
+
 	return items, nil
 }
 
-// Update updates an existing item
+// Update updates an existing item. A changed price is recorded in the item's
+// price history, along with the new price of each assembly priced from its
+// parts that contains the item. A changed cost is also recorded in the item's cost history,
+// effective today, so it replaces any earlier supplier cost.
 func (r *itemRepository) Update(ctx context.Context, id string, updates map[string]interface{}) error {
 	if id == "" {
 		return ErrInvalidInput
 	}
 
-	// First, get the existing item
-	item, err := r.GetByID(ctx, id)
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+	locked := &itemRepository{db: tx}
+
+	// First, get and lock the existing item, so the previous price recorded
+	// is the one this update replaces
+	item, err := locked.getItem(ctx, id, true)
 	if err != nil {
 		return err
 	}
+	previousPrice, previousCost := item.UnitPrice, item.UnitCost
 
 	// Apply updates to the model (with validation)
 	if err := item.Update(updates); err != nil {
 		return NewRepositoryError("validation failed", err)
 	}
 
+	// Lock and load the assemblies containing the item at their current
+	// prices before its new price is saved
+	var assemblies []*models.Item
+	if item.UnitPrice != previousPrice {
+		assemblies, err = locked.assembliesContaining(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Update in database
 	query := `
 		UPDATE items
-		SET name = $2, nickname = $3, unit = $4, unit_price = $5, category = $6, updated_at = $7
+		SET name = $2, nickname = $3, unit = $4, unit_price = $5, unit_cost = $6, labor_hours = $7,
+			category = $8, updated_at = $9
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		item.ID,
 		item.Name,
 		item.Nickname,
 		item.Unit,
 		item.UnitPrice,
+		item.UnitCost,
+		item.LaborHours,
 		item.Category,
 		item.UpdatedAt,
 	)
@@ -189,6 +248,40 @@ func (r *itemRepository) Update(ctx context.Context, id string, updates map[stri
 		return ErrNotFound
 	}
 
+	if item.UnitPrice != previousPrice {
+		change := models.NewItemPriceChange(item.ID, previousPrice, item.UnitPrice, item.UpdatedAt)
+		if err := insertItemPriceChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	// Assemblies priced from their parts change price with their components
+	for _, assembly := range assemblies {
+		assemblyPrice := assembly.UnitPrice
+		assembly.SetComponentPrice(item.ID, item.UnitPrice)
+		if assembly.UnitPrice == assemblyPrice {
+			continue
+		}
+		change := models.NewItemPriceChange(assembly.ID, assemblyPrice, assembly.UnitPrice, item.UpdatedAt)
+		if err := insertItemPriceChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	if item.UnitCost != previousCost {
+		cost, err := models.NewItemCost(item.ID, "", item.UnitCost, item.UpdatedAt)
+		if err != nil {
+			return NewRepositoryError("validation failed", err)
+		}
+		if err := insertItemCost(ctx, tx, cost); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit item update", err)
+	}
+
 	return nil
 }
 
@@ -202,6 +295,10 @@ func (r *itemRepository) Delete(ctx context.Context, id string) error {
 	
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		// Items in an assembly can't be deleted until it stops using them
+		if isForeignKeyError(err) {
+			return ErrInUse
+		}
 		return NewRepositoryError("failed to delete item", err)
 	}
 
@@ -217,8 +314,279 @@ func (r *itemRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// AddCost records a supplier cost for an item
+func (r *itemRepository) AddCost(ctx context.Context, cost *models.ItemCost) error {
+	if cost == nil {
+		return ErrInvalidInput
+	}
+
+	return insertItemCost(ctx, r.db, cost)
+}
+
+// ListCosts returns an item's cost history, newest first
+func (r *itemRepository) ListCosts(ctx context.Context, itemID string) ([]models.ItemCost, error) {
+	if itemID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT id, item_id, supplier, cost, effective_from, created_at
+		FROM item_costs
+		WHERE item_id = $1
+		ORDER BY effective_from DESC, created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list item costs", err)
+	}
+	defer rows.Close()
+
+	costs := make([]models.ItemCost, 0)
+	for rows.Next() {
+		var cost models.ItemCost
+		err := rows.Scan(&cost.ID, &cost.ItemID, &cost.Supplier, &cost.Cost, &cost.EffectiveFrom, &cost.CreatedAt)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan item cost", err)
+		}
+		costs = append(costs, cost)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, NewRepositoryError("error iterating item costs", err)
+	}
+
+	return costs, nil
+}
+
+// ListPriceHistory returns the changes to an item's price, newest first
+func (r *itemRepository) ListPriceHistory(ctx context.Context, itemID string) ([]models.ItemPriceChange, error) {
+	if itemID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT id, item_id, old_price, new_price, changed_at
+		FROM item_price_history
+		WHERE item_id = $1
+		ORDER BY changed_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list item price history", err)
+	}
+	defer rows.Close()
+
+	changes := make([]models.ItemPriceChange, 0)
+	for rows.Next() {
+		var change models.ItemPriceChange
+		err := rows.Scan(&change.ID, &change.ItemID, &change.OldPrice, &change.NewPrice, &change.ChangedAt)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan item price change", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, NewRepositoryError("error iterating item price history", err)
+	}
+
+	return changes, nil
+}
+
+// AddPriceChange records a change to an item's price that Update can't see,
+// such as an assembly repriced by new components
+func (r *itemRepository) AddPriceChange(ctx context.Context, change *models.ItemPriceChange) error {
+	if change == nil || change.ItemID == "" {
+		return ErrInvalidInput
+	}
+
+	return insertItemPriceChange(ctx, r.db, change)
+}
+
+// assembliesContaining loads and locks the assemblies that have itemID as a
+// component
+func (r *itemRepository) assembliesContaining(ctx context.Context, itemID string) ([]*models.Item, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT assembly_id FROM assembly_items WHERE item_id = $1`, itemID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to find assemblies containing item", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, NewRepositoryError("failed to scan assembly", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, NewRepositoryError("error iterating assemblies", err)
+	}
+	rows.Close()
+
+	assemblies := make([]*models.Item, 0, len(ids))
+	for _, id := range ids {
+		assembly, err := r.getItem(ctx, id, true)
+		if err != nil {
+			return nil, err
+		}
+		assemblies = append(assemblies, assembly)
+	}
+	return assemblies, nil
+}
+
+// SaveAssembly saves an assembly's price override, invoice setting and
+// components, replacing the components it had
+func (r *itemRepository) SaveAssembly(ctx context.Context, item *models.Item) error {
+	if item == nil || !item.IsAssembly() {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO assemblies (item_id, price_override, expand_on_invoice)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (item_id) DO UPDATE SET
+			price_override = EXCLUDED.price_override,
+			expand_on_invoice = EXCLUDED.expand_on_invoice
+	`
+	if _, err := tx.ExecContext(ctx, query, item.ID, item.PriceOverride, item.ExpandOnInvoice); err != nil {
+		return NewRepositoryError("failed to save assembly", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM assembly_items WHERE assembly_id = $1`, item.ID); err != nil {
+		return NewRepositoryError("failed to clear assembly components", err)
+	}
+
+	componentQuery := `
+		INSERT INTO assembly_items (assembly_id, item_id, quantity, sort_order)
+		VALUES ($1, $2, $3, $4)
+	`
+	for i, component := range item.Components {
+		if _, err := tx.ExecContext(ctx, componentQuery, item.ID, component.ItemID, component.Quantity, i); err != nil {
+			return NewRepositoryError("failed to save assembly component", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit assembly", err)
+	}
+
+	return nil
+}
+
+// loadAssemblies fills in the components of the assemblies among items, with
+// each component's current price, cost and labor hours, and prices the
+// assemblies from them
+func (r *itemRepository) loadAssemblies(ctx context.Context, items []*models.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Item, len(items))
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+		ids = append(ids, item.ID)
+	}
+
+	query := `
+		SELECT a.item_id, a.price_override, a.expand_on_invoice,
+			c.item_id, i.name, i.unit, c.quantity, i.unit_price, i.unit_cost, i.labor_hours
+		FROM assemblies a
+		JOIN assembly_items c ON c.assembly_id = a.item_id
+		JOIN (SELECT ` + itemColumns + ` FROM items) i ON i.id = c.item_id
+		WHERE a.item_id = ANY($1)
+		ORDER BY a.item_id, c.sort_order
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return NewRepositoryError("failed to load assemblies", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assemblyID string
+		var priceOverride *models.Money
+		var expandOnInvoice bool
+		var component models.AssemblyComponent
+		err := rows.Scan(
+			&assemblyID, &priceOverride, &expandOnInvoice,
+			&component.ItemID, &component.Name, &component.Unit, &component.Quantity,
+			&component.UnitPrice, &component.UnitCost, &component.LaborHours,
+		)
+		if err != nil {
+			return NewRepositoryError("failed to scan assembly component", err)
+		}
+
+		item := byID[assemblyID]
+		item.PriceOverride = priceOverride
+		item.ExpandOnInvoice = expandOnInvoice
+		item.Components = append(item.Components, component)
+	}
+
+	if err := rows.Err(); err != nil {
+		return NewRepositoryError("error iterating assembly components", err)
+	}
+
+	for _, item := range items {
+		item.PriceAssembly()
+	}
+
+	return nil
+}
+
+func insertItemPriceChange(ctx context.Context, db executor, change *models.ItemPriceChange) error {
+	query := `
+		INSERT INTO item_price_history (id, item_id, old_price, new_price, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		change.ID, change.ItemID, change.OldPrice, change.NewPrice, change.ChangedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to record item price change", err)
+	}
+
+	return nil
+}
+
+func insertItemCost(ctx context.Context, db executor, cost *models.ItemCost) error {
+	query := `
+		INSERT INTO item_costs (id, item_id, supplier, cost, effective_from, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		cost.ID, cost.ItemID, cost.Supplier, cost.Cost, cost.EffectiveFrom, cost.CreatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to add item cost", err)
+	}
+
+	return nil
+}
+
 // Helper function to check for duplicate key errors
 func isDuplicateKeyError(err error) bool {
 	// This is PostgreSQL specific - you might need to adjust for other databases
-	return err != nil && err.Error() == "pq: duplicate key value violates unique constraint"
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyError reports whether err is a row still being referenced
+func isForeignKeyError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
 }
\ No newline at end of file
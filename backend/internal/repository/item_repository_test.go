@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
 )
@@ -42,7 +46,7 @@ func TestItemRepository_Create(t *testing.T) {
 				ID:        "test-id",
 				Name:      "Test Item",
 				Unit:      "each",
-				UnitPrice: 10.00,
+				UnitPrice: models.Cents(1000),
 				Category:  "Test",
 			},
 			wantErr: false,
@@ -72,7 +76,7 @@ func TestItemRepository_Create(t *testing.T) {
 				tt.setup(repo)
 			}
 
-			itemRepo := NewItemRepository(nil) // We'll implement this
+			_ = NewItemRepository(nil) // We'll implement this
 			
 			// For now, let's define the interface
 			if tt.item != nil && !tt.wantErr {
@@ -88,7 +92,7 @@ func TestItemRepository_GetByID(t *testing.T) {
 		ID:        "test-id",
 		Name:      "Test Item",
 		Unit:      "each",
-		UnitPrice: 15.50,
+		UnitPrice: models.Cents(1550),
 		Category:  "Electrical",
 	}
 
@@ -215,7 +219,7 @@ func TestItemRepository_Update(t *testing.T) {
 		ID:        "test-id",
 		Name:      "Original Name",
 		Unit:      "each",
-		UnitPrice: 10.00,
+		UnitPrice: models.Cents(1000),
 		Category:  "Test",
 	}
 
@@ -332,4 +336,27 @@ func TestItemRepository_Delete(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestItemRepository_UpdateLocksItemInTransaction(t *testing.T) {
+	now := time.Now()
+	fake := &fakeDB{locked: map[string][]driver.Value{
+		"FROM items WHERE id": {"outlet", "Outlet", "", "each", "10.00", "4.00", 0.25, "Devices", now, now},
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	err := NewItemRepository(db).Update(context.Background(), "outlet", map[string]interface{}{"unit_price": 12.00})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fake.begins != 1 || fake.commits != 1 {
+		t.Errorf("expected one committed transaction, got %d begins and %d commits", fake.begins, fake.commits)
+	}
+	if len(fake.locks) != 1 || !strings.Contains(fake.locks[0], "FROM items WHERE id") {
+		t.Errorf("expected the item to be read under a row lock, got %q", fake.locks)
+	}
+	if len(fake.execs) != 2 || !strings.Contains(fake.execs[1], "item_price_history") {
+		t.Errorf("expected the update and its price change, got %q", fake.execs)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
 )
 
@@ -22,16 +23,40 @@ type JobRepository interface {
 	AddJobItem(ctx context.Context, jobID string, item *models.JobItem) error
 	GetJobItems(ctx context.Context, jobID string) ([]models.JobItem, error)
 	UpdateJobItem(ctx context.Context, item *models.JobItem) error
+	UpdateJobItemQuantity(ctx context.Context, jobID string, item *models.JobItem) error
 	RemoveJobItem(ctx context.Context, jobID, itemID string) error
 	
+	// Job adjustment operations: the markups and discounts priced into a job
+	AddAdjustment(ctx context.Context, jobID string, adjustment *models.PriceAdjustment) error
+	GetAdjustments(ctx context.Context, jobID string) ([]models.PriceAdjustment, error)
+	RemoveAdjustment(ctx context.Context, jobID, adjustmentID string) error
+	
+	// Job total operations. Totals are kept up to date by the item, adjustment
+	// and change order writes; these find and repair totals that drifted anyway.
+	RefreshTotal(ctx context.Context, jobID string) error
+	FindTotalDrift(ctx context.Context) ([]models.JobTotalDrift, error)
+	
 	// Job photos operations
 	AddPhoto(ctx context.Context, jobID string, photo *models.JobPhoto) error
 	GetPhotos(ctx context.Context, jobID string) ([]models.JobPhoto, error)
 	RemovePhoto(ctx context.Context, jobID, photoID string) error
+	
+	// Job phase operations
+	GetPhases(ctx context.Context, jobID string) ([]models.JobPhase, error)
+	SavePhases(ctx context.Context, jobID string, phases []models.JobPhase) error
+	
+	// Job status history operations
+	AddStatusChange(ctx context.Context, change *models.JobStatusChange) error
+	GetStatusHistory(ctx context.Context, jobID string) ([]models.JobStatusChange, error)
+	
+	// Job revision operations
+	CreateRevision(ctx context.Context, revision *models.JobRevision) error
+	GetRevisions(ctx context.Context, jobID string) ([]models.JobRevision, error)
+	GetRevision(ctx context.Context, jobID string, number int) (*models.JobRevision, error)
 }
 
 type jobRepository struct {
-	db *sql.DB
+	db executor
 }
 
 // NewJobRepository creates a new job repository
@@ -42,15 +67,15 @@ func NewJobRepository(db *sql.DB) JobRepository {
 func (r *jobRepository) Create(ctx context.Context, job *models.Job) error {
 	query := `
 		INSERT INTO jobs (
-			id, customer_id, template_id, address, status,
+			id, customer_id, template_id, template_version, address, status,
 			current_phase_id, scheduled_date, start_date, end_date, permit_required,
 			permit_number, total_amount, notes, wave_invoice_id,
 			wave_invoice_url, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`
 	
 	_, err := r.db.ExecContext(ctx, query,
-		job.ID, job.CustomerID, job.TemplateID, job.Address, job.Status,
+		job.ID, job.CustomerID, job.TemplateID, job.TemplateVersion, job.Address, job.Status,
 		job.CurrentPhaseID, job.ScheduledDate, job.StartDate, job.EndDate, job.PermitRequired,
 		job.PermitNumber, job.TotalAmount, job.Notes, job.WaveInvoiceID,
 		job.WaveInvoiceURL, job.CreatedAt, job.UpdatedAt,
@@ -62,7 +87,7 @@ func (r *jobRepository) Create(ctx context.Context, job *models.Job) error {
 func (r *jobRepository) GetByID(ctx context.Context, id string) (*models.Job, error) {
 	query := `
 		SELECT 
-			id, customer_id, template_id, address, status,
+			id, customer_id, template_id, template_version, address, status,
 			current_phase_id, scheduled_date, start_date, end_date, permit_required,
 			permit_number, total_amount, notes, wave_invoice_id,
 			wave_invoice_url, created_at, updated_at
@@ -72,7 +97,7 @@ func (r *jobRepository) GetByID(ctx context.Context, id string) (*models.Job, er
 	
 	job := &models.Job{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&job.ID, &job.CustomerID, &job.TemplateID, &job.Address, &job.Status,
+		&job.ID, &job.CustomerID, &job.TemplateID, &job.TemplateVersion, &job.Address, &job.Status,
 		&job.CurrentPhaseID, &job.ScheduledDate, &job.StartDate, &job.EndDate, &job.PermitRequired,
 		&job.PermitNumber, &job.TotalAmount, &job.Notes, &job.WaveInvoiceID,
 		&job.WaveInvoiceURL, &job.CreatedAt, &job.UpdatedAt,
@@ -96,6 +121,22 @@ func (r *jobRepository) GetByID(ctx context.Context, id string) (*models.Job, er
 		return nil, err
 	}
 	
+	job.ChangeOrders, err = getChangeOrdersForJob(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	
+	job.Adjustments, err = getJobAdjustments(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	job.AdjustmentLines = models.ApplyAdjustments(job.Items, job.Adjustments)
+	
+	job.Phases, err = r.GetPhases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	
 	return job, nil
 }
 
@@ -104,15 +145,18 @@ func (r *jobRepository) Update(ctx context.Context, job *models.Job) error {
 		UPDATE jobs SET
 			customer_id = $2, template_id = $3, address = $4, status = $5,
 			current_phase_id = $6, scheduled_date = $7, start_date = $8, end_date = $9, permit_required = $10,
-			permit_number = $11, total_amount = $12, notes = $13, wave_invoice_id = $14,
-			wave_invoice_url = $15, updated_at = $16
+			permit_number = $11, notes = $12, wave_invoice_id = $13,
+			wave_invoice_url = $14, updated_at = $15
 		WHERE id = $1
 	`
 	
+	// total_amount is not written here: it is derived from the job's items,
+	// adjustments and approved change orders by refreshJobTotal whenever they
+	// change
 	result, err := r.db.ExecContext(ctx, query,
 		job.ID, job.CustomerID, job.TemplateID, job.Address, job.Status,
 		job.CurrentPhaseID, job.ScheduledDate, job.StartDate, job.EndDate, job.PermitRequired,
-		job.PermitNumber, job.TotalAmount, job.Notes, job.WaveInvoiceID,
+		job.PermitNumber, job.Notes, job.WaveInvoiceID,
 		job.WaveInvoiceURL, job.UpdatedAt,
 	)
 	
@@ -155,7 +199,7 @@ func (r *jobRepository) Delete(ctx context.Context, id string) error {
 func (r *jobRepository) List(ctx context.Context, limit, offset int) ([]*models.Job, error) {
 	query := `
 		SELECT 
-			id, customer_id, template_id, address, status,
+			id, customer_id, template_id, template_version, address, status,
 			current_phase_id, scheduled_date, start_date, end_date, permit_required,
 			permit_number, total_amount, notes, wave_invoice_id,
 			wave_invoice_url, created_at, updated_at
@@ -174,7 +218,7 @@ func (r *jobRepository) List(ctx context.Context, limit, offset int) ([]*models.
 	for rows.Next() {
 		job := &models.Job{}
 		err := rows.Scan(
-			&job.ID, &job.CustomerID, &job.TemplateID, &job.Address, &job.Status,
+			&job.ID, &job.CustomerID, &job.TemplateID, &job.TemplateVersion, &job.Address, &job.Status,
 			&job.CurrentPhaseID, &job.ScheduledDate, &job.StartDate, &job.EndDate, &job.PermitRequired,
 			&job.PermitNumber, &job.TotalAmount, &job.Notes, &job.WaveInvoiceID,
 			&job.WaveInvoiceURL, &job.CreatedAt, &job.UpdatedAt,
@@ -194,6 +238,22 @@ func (r *jobRepository) List(ctx context.Context, limit, offset int) ([]*models.
 			return nil, err
 		}
 		
+		job.ChangeOrders, err = getChangeOrdersForJob(ctx, r.db, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		
+		job.Adjustments, err = getJobAdjustments(ctx, r.db, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		job.AdjustmentLines = models.ApplyAdjustments(job.Items, job.Adjustments)
+		
+		job.Phases, err = r.GetPhases(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		
 		jobs = append(jobs, job)
 	}
 	
@@ -203,7 +263,7 @@ func (r *jobRepository) List(ctx context.Context, limit, offset int) ([]*models.
 func (r *jobRepository) GetByCustomerID(ctx context.Context, customerID string) ([]*models.Job, error) {
 	query := `
 		SELECT 
-			id, customer_id, template_id, address, status,
+			id, customer_id, template_id, template_version, address, status,
 			current_phase_id, scheduled_date, start_date, end_date, permit_required,
 			permit_number, total_amount, notes, wave_invoice_id,
 			wave_invoice_url, created_at, updated_at
@@ -224,7 +284,7 @@ func (r *jobRepository) GetByCustomerID(ctx context.Context, customerID string)
 func (r *jobRepository) GetByStatus(ctx context.Context, status models.JobStatus) ([]*models.Job, error) {
 	query := `
 		SELECT 
-			id, customer_id, template_id, address, status,
+			id, customer_id, template_id, template_version, address, status,
 			current_phase_id, scheduled_date, start_date, end_date, permit_required,
 			permit_number, total_amount, notes, wave_invoice_id,
 			wave_invoice_url, created_at, updated_at
@@ -243,30 +303,51 @@ func (r *jobRepository) GetByStatus(ctx context.Context, status models.JobStatus
 }
 
 // Job Items operations
+
+// AddJobItem inserts a line and refreshes the job total in one transaction
 func (r *jobRepository) AddJobItem(ctx context.Context, jobID string, item *models.JobItem) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	item.CalculateTotal()
 	query := `
-		INSERT INTO job_items (id, job_id, item_id, name, quantity, price, total, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		INSERT INTO job_items (id, job_id, item_id, name, quantity, budgeted_quantity, price, cost, total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
 	
-	_, err := r.db.ExecContext(ctx, query,
-		item.ID, jobID, item.ItemID, item.Name, item.Quantity, item.Price, item.Total,
+	_, err = tx.ExecContext(ctx, query,
+		item.ID, jobID, item.ItemID, item.Name, item.Quantity, item.BudgetedQuantity, item.Price, item.Cost, item.Total,
 	)
+	if err != nil {
+		return err
+	}
 	
-	return err
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
 }
 
 func (r *jobRepository) GetJobItems(ctx context.Context, jobID string) ([]models.JobItem, error) {
+	return getJobItems(ctx, r.db, jobID)
+}
+
+// getJobItems loads a job's lines with each item's nickname and category
+func getJobItems(ctx context.Context, db executor, jobID string) ([]models.JobItem, error) {
 	query := `
-		SELECT ji.id, ji.job_id, ji.item_id, ji.name, ji.quantity, ji.price, ji.total,
-		       COALESCE(i.nickname, '') as nickname
+		SELECT ji.id, ji.job_id, ji.item_id, ji.name, ji.quantity, ji.budgeted_quantity, ji.price, ji.cost, ji.total,
+		       COALESCE(i.nickname, '') as nickname, COALESCE(i.category, '') as category
 		FROM job_items ji
 		LEFT JOIN items i ON ji.item_id = i.id
 		WHERE ji.job_id = $1
 		ORDER BY ji.name
 	`
 	
-	rows, err := r.db.QueryContext(ctx, query, jobID)
+	rows, err := db.QueryContext(ctx, query, jobID)
 	if err != nil {
 		return nil, err
 	}
@@ -277,7 +358,7 @@ func (r *jobRepository) GetJobItems(ctx context.Context, jobID string) ([]models
 		var item models.JobItem
 		err := rows.Scan(
 			&item.ID, &item.JobID, &item.ItemID, &item.Name,
-			&item.Quantity, &item.Price, &item.Total, &item.Nickname,
+			&item.Quantity, &item.BudgetedQuantity, &item.Price, &item.Cost, &item.Total, &item.Nickname, &item.Category,
 		)
 		if err != nil {
 			return nil, err
@@ -285,40 +366,105 @@ func (r *jobRepository) GetJobItems(ctx context.Context, jobID string) ([]models
 		items = append(items, item)
 	}
 	
-	return items, nil
+	return items, rows.Err()
 }
 
+// UpdateJobItem saves a line and refreshes the job total in one transaction
 func (r *jobRepository) UpdateJobItem(ctx context.Context, item *models.JobItem) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	item.CalculateTotal()
 	query := `
 		UPDATE job_items SET
-			quantity = $2, price = $3, total = $4, updated_at = CURRENT_TIMESTAMP
+			quantity = $2, budgeted_quantity = $3, price = $4, total = $5, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1
+		RETURNING job_id
 	`
 	
-	result, err := r.db.ExecContext(ctx, query,
-		item.ID, item.Quantity, item.Price, item.Total,
-	)
+	var jobID string
+	err = tx.QueryRowContext(ctx, query,
+		item.ID, item.Quantity, item.BudgetedQuantity, item.Price, item.Total,
+	).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("job item not found")
+	}
+	if err != nil {
+		return err
+	}
 	
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+// UpdateJobItemQuantity sets the installed quantity of a job's line, leaving
+// its price and budgeted quantity as stored. The line total is recomputed from
+// the stored price under the job row lock, and item is filled from the saved line.
+func (r *jobRepository) UpdateJobItemQuantity(ctx context.Context, jobID string, item *models.JobItem) error {
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 	
-	rowsAffected, err := result.RowsAffected()
+	// Take the lock refreshJobTotal takes before reading the line, so a
+	// concurrent reprice or edit of the job lands either wholly before or after
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM jobs WHERE id = $1 FOR NO KEY UPDATE`, jobID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("job not found")
+	}
 	if err != nil {
 		return err
 	}
 	
-	if rowsAffected == 0 {
+	quantity := item.Quantity
+	err = tx.QueryRowContext(ctx,
+		`SELECT item_id, name, budgeted_quantity, price, cost FROM job_items WHERE id = $1 AND job_id = $2 FOR UPDATE`,
+		item.ID, jobID,
+	).Scan(&item.ItemID, &item.Name, &item.BudgetedQuantity, &item.Price, &item.Cost)
+	if err == sql.ErrNoRows {
 		return fmt.Errorf("job item not found")
 	}
+	if err != nil {
+		return err
+	}
 	
-	return nil
+	item.JobID = jobID
+	item.Quantity = quantity
+	item.CalculateTotal()
+	_, err = tx.ExecContext(ctx,
+		`UPDATE job_items SET quantity = $2, total = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		item.ID, item.Quantity, item.Total,
+	)
+	if err != nil {
+		return err
+	}
+	
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
 }
 
+// RemoveJobItem deletes a line and refreshes the job total in one transaction
 func (r *jobRepository) RemoveJobItem(ctx context.Context, jobID, itemID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
 	query := `DELETE FROM job_items WHERE job_id = $1 AND id = $2`
 	
-	result, err := r.db.ExecContext(ctx, query, jobID, itemID)
+	result, err := tx.ExecContext(ctx, query, jobID, itemID)
 	if err != nil {
 		return err
 	}
@@ -332,7 +478,204 @@ func (r *jobRepository) RemoveJobItem(ctx context.Context, jobID, itemID string)
 		return fmt.Errorf("job item not found")
 	}
 	
-	return nil
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+// Total operations
+
+// computeJobTotal derives a job's total from its lines, its adjustments and
+// its approved change orders
+func computeJobTotal(ctx context.Context, db executor, jobID string) (models.Money, error) {
+	items, err := getJobItems(ctx, db, jobID)
+	if err != nil {
+		return 0, err
+	}
+	
+	adjustments, err := getJobAdjustments(ctx, db, jobID)
+	if err != nil {
+		return 0, err
+	}
+	
+	rows, err := db.QueryContext(ctx,
+		`SELECT total_amount FROM change_orders WHERE job_id = $1 AND status = 'approved'`,
+		jobID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	
+	var total models.Money
+	for rows.Next() {
+		var changeOrderTotal models.Money
+		if err := rows.Scan(&changeOrderTotal); err != nil {
+			return 0, err
+		}
+		total += changeOrderTotal
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	
+	for i := range items {
+		items[i].CalculateTotal()
+		total += items[i].Total
+	}
+	total += models.AdjustmentsTotal(models.ApplyAdjustments(items, adjustments))
+	
+	return total, nil
+}
+
+// refreshJobTotal stores the job's derived total. Every write that changes a
+// job's lines, adjustments or approved change orders calls it in the same
+// transaction.
+func refreshJobTotal(ctx context.Context, db executor, jobID string) error {
+	// Lock the job so concurrent writes to it total one after another, each
+	// seeing the lines the other committed. NO KEY UPDATE doesn't wait on the
+	// key-share locks the foreign keys of the rows just written took, so two
+	// writers can't deadlock here.
+	var id string
+	err := db.QueryRowContext(ctx, `SELECT id FROM jobs WHERE id = $1 FOR NO KEY UPDATE`, jobID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("job not found")
+	}
+	if err != nil {
+		return err
+	}
+	
+	total, err := computeJobTotal(ctx, db, jobID)
+	if err != nil {
+		return err
+	}
+	
+	_, err = db.ExecContext(ctx,
+		`UPDATE jobs SET total_amount = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		jobID, total,
+	)
+	return err
+}
+
+// RefreshTotal recomputes and stores a job's total
+func (r *jobRepository) RefreshTotal(ctx context.Context, jobID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+// FindTotalDrift lists jobs whose stored total differs from the total derived
+// from their items, adjustments and approved change orders
+func (r *jobRepository) FindTotalDrift(ctx context.Context) ([]models.JobTotalDrift, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, total_amount FROM jobs ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	stored := make([]models.JobTotalDrift, 0)
+	for rows.Next() {
+		var drift models.JobTotalDrift
+		if err := rows.Scan(&drift.JobID, &drift.StoredTotal); err != nil {
+			return nil, err
+		}
+		stored = append(stored, drift)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	
+	drifts := make([]models.JobTotalDrift, 0)
+	for _, drift := range stored {
+		drift.ComputedTotal, err = computeJobTotal(ctx, r.db, drift.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if drift.StoredTotal != drift.ComputedTotal {
+			drift.Difference = drift.StoredTotal - drift.ComputedTotal
+			drifts = append(drifts, drift)
+		}
+	}
+	
+	return drifts, nil
+}
+
+// Adjustment operations
+
+// AddAdjustment adds a markup or discount to a job and refreshes the job total
+// in one transaction
+func (r *jobRepository) AddAdjustment(ctx context.Context, jobID string, adjustment *models.PriceAdjustment) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	adjustment.JobID = jobID
+	query := `
+		INSERT INTO job_adjustments (job_id, ` + adjustmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	
+	_, err = tx.ExecContext(ctx, query,
+		jobID, adjustment.ID, nullString(adjustment.CustomerID), adjustment.Name, adjustment.Type,
+		adjustment.Scope, adjustment.ItemID, adjustment.Category, adjustment.Percent,
+		adjustment.Amount, adjustment.CreatedAt, adjustment.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+func (r *jobRepository) GetAdjustments(ctx context.Context, jobID string) ([]models.PriceAdjustment, error) {
+	return getJobAdjustments(ctx, r.db, jobID)
+}
+
+// RemoveAdjustment deletes a job's markup or discount and refreshes the job
+// total in one transaction
+func (r *jobRepository) RemoveAdjustment(ctx context.Context, jobID, adjustmentID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.ExecContext(ctx, `DELETE FROM job_adjustments WHERE job_id = $1 AND id = $2`, jobID, adjustmentID)
+	if err != nil {
+		return err
+	}
+	
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	
+	if rowsAffected == 0 {
+		return fmt.Errorf("job adjustment not found")
+	}
+	
+	if err := refreshJobTotal(ctx, tx, jobID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
 }
 
 // Photo operations
@@ -399,13 +742,293 @@ func (r *jobRepository) RemovePhoto(ctx context.Context, jobID, photoID string)
 	return nil
 }
 
+// Phase operations
+func (r *jobRepository) GetPhases(ctx context.Context, jobID string) ([]models.JobPhase, error) {
+	query := `
+		SELECT phase_id, job_id, name, phase_order, is_completed, started_at, completed_at
+		FROM job_phases
+		WHERE job_id = $1
+		ORDER BY phase_order
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	phases := make([]models.JobPhase, 0)
+	for rows.Next() {
+		var phase models.JobPhase
+		err := rows.Scan(
+			&phase.ID, &phase.JobID, &phase.Name, &phase.Order,
+			&phase.IsCompleted, &phase.StartedAt, &phase.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		phases = append(phases, phase)
+	}
+	
+	return phases, rows.Err()
+}
+
+// SavePhases inserts or updates the job's phase records
+func (r *jobRepository) SavePhases(ctx context.Context, jobID string, phases []models.JobPhase) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	query := `
+		INSERT INTO job_phases (job_id, phase_id, name, phase_order, is_completed, started_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (job_id, phase_id) DO UPDATE SET
+			name = EXCLUDED.name, phase_order = EXCLUDED.phase_order,
+			is_completed = EXCLUDED.is_completed, started_at = EXCLUDED.started_at,
+			completed_at = EXCLUDED.completed_at
+	`
+	
+	for _, phase := range phases {
+		_, err := tx.ExecContext(ctx, query,
+			jobID, phase.ID, phase.Name, phase.Order,
+			phase.IsCompleted, phase.StartedAt, phase.CompletedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	
+	return tx.Commit()
+}
+
+// Status history operations
+func (r *jobRepository) AddStatusChange(ctx context.Context, change *models.JobStatusChange) error {
+	query := `
+		INSERT INTO job_status_history (id, job_id, from_status, to_status, changed_by, changed_by_name, note, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID, change.JobID, change.FromStatus, change.ToStatus,
+		change.ChangedBy, change.ChangedByName, change.Note, change.ChangedAt,
+	)
+	
+	return err
+}
+
+func (r *jobRepository) GetStatusHistory(ctx context.Context, jobID string) ([]models.JobStatusChange, error) {
+	query := `
+		SELECT id, job_id, from_status, to_status, changed_by, changed_by_name, note, changed_at
+		FROM job_status_history
+		WHERE job_id = $1
+		ORDER BY changed_at
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	history := make([]models.JobStatusChange, 0)
+	for rows.Next() {
+		var change models.JobStatusChange
+		var changedBy, changedByName, note sql.NullString
+		err := rows.Scan(
+			&change.ID, &change.JobID, &change.FromStatus, &change.ToStatus,
+			&changedBy, &changedByName, &note, &change.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		change.ChangedBy = changedBy.String
+		change.ChangedByName = changedByName.String
+		change.Note = note.String
+		history = append(history, change)
+	}
+	
+	return history, rows.Err()
+}
+
+// Revision operations
+
+// CreateRevision stores a revision and its line snapshot, assigning it the
+// job's next revision number
+func (r *jobRepository) CreateRevision(ctx context.Context, revision *models.JobRevision) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	// Lock the job so concurrent revisions get distinct numbers
+	var jobID string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM jobs WHERE id = $1 FOR UPDATE`, revision.JobID).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("job not found")
+	}
+	if err != nil {
+		return err
+	}
+	
+	var number int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(revision_number), 0) + 1 FROM job_revisions WHERE job_id = $1`,
+		revision.JobID,
+	).Scan(&number)
+	if err != nil {
+		return err
+	}
+	
+	query := `
+		INSERT INTO job_revisions (id, job_id, revision_number, name, note, author_id, author_name, total_amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	
+	_, err = tx.ExecContext(ctx, query,
+		revision.ID, revision.JobID, number, revision.Name, revision.Note,
+		revision.AuthorID, revision.AuthorName, revision.TotalAmount, revision.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	
+	itemQuery := `
+		INSERT INTO job_revision_items (id, revision_id, job_item_id, item_id, name, quantity, budgeted_quantity, price, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	
+	for _, item := range revision.Items {
+		_, err = tx.ExecContext(ctx, itemQuery,
+			uuid.New().String(), revision.ID, item.ID, item.ItemID, item.Name,
+			item.Quantity, item.BudgetedQuantity, item.Price, item.Total,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	
+	revision.Number = number
+	return nil
+}
+
+func (r *jobRepository) GetRevisions(ctx context.Context, jobID string) ([]models.JobRevision, error) {
+	query := `
+		SELECT id, job_id, revision_number, name, note, author_id, author_name, total_amount, created_at
+		FROM job_revisions
+		WHERE job_id = $1
+		ORDER BY revision_number
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	revisions := make([]models.JobRevision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	
+	for i := range revisions {
+		revisions[i].Items, err = r.getRevisionItems(ctx, &revisions[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	return revisions, nil
+}
+
+func (r *jobRepository) GetRevision(ctx context.Context, jobID string, number int) (*models.JobRevision, error) {
+	query := `
+		SELECT id, job_id, revision_number, name, note, author_id, author_name, total_amount, created_at
+		FROM job_revisions
+		WHERE job_id = $1 AND revision_number = $2
+	`
+	
+	revision, err := scanRevision(r.db.QueryRowContext(ctx, query, jobID, number))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("revision not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	revision.Items, err = r.getRevisionItems(ctx, revision)
+	if err != nil {
+		return nil, err
+	}
+	
+	return revision, nil
+}
+
+func (r *jobRepository) getRevisionItems(ctx context.Context, revision *models.JobRevision) ([]models.JobItem, error) {
+	query := `
+		SELECT job_item_id, item_id, name, quantity, budgeted_quantity, price, total
+		FROM job_revision_items
+		WHERE revision_id = $1
+		ORDER BY name
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, revision.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	items := make([]models.JobItem, 0)
+	for rows.Next() {
+		item := models.JobItem{JobID: revision.JobID}
+		err := rows.Scan(
+			&item.ID, &item.ItemID, &item.Name,
+			&item.Quantity, &item.BudgetedQuantity, &item.Price, &item.Total,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	
+	return items, rows.Err()
+}
+
+func scanRevision(row rowScanner) (*models.JobRevision, error) {
+	revision := &models.JobRevision{}
+	var note sql.NullString
+	err := row.Scan(
+		&revision.ID, &revision.JobID, &revision.Number, &revision.Name, &note,
+		&revision.AuthorID, &revision.AuthorName, &revision.TotalAmount, &revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	revision.Note = note.String
+	return revision, nil
+}
+
 // Helper function to scan job rows
 func (r *jobRepository) scanJobs(ctx context.Context, rows *sql.Rows) ([]*models.Job, error) {
 	jobs := make([]*models.Job, 0)
 	for rows.Next() {
 		job := &models.Job{}
 		err := rows.Scan(
-			&job.ID, &job.CustomerID, &job.TemplateID, &job.Address, &job.Status,
+			&job.ID, &job.CustomerID, &job.TemplateID, &job.TemplateVersion, &job.Address, &job.Status,
 			&job.CurrentPhaseID, &job.ScheduledDate, &job.StartDate, &job.EndDate, &job.PermitRequired,
 			&job.PermitNumber, &job.TotalAmount, &job.Notes, &job.WaveInvoiceID,
 			&job.WaveInvoiceURL, &job.CreatedAt, &job.UpdatedAt,
@@ -425,6 +1048,22 @@ func (r *jobRepository) scanJobs(ctx context.Context, rows *sql.Rows) ([]*models
 			return nil, err
 		}
 		
+		job.ChangeOrders, err = getChangeOrdersForJob(ctx, r.db, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		
+		job.Adjustments, err = getJobAdjustments(ctx, r.db, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		job.AdjustmentLines = models.ApplyAdjustments(job.Items, job.Adjustments)
+		
+		job.Phases, err = r.GetPhases(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		
 		jobs = append(jobs, job)
 	}
 	
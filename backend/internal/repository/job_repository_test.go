@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+func TestJobRepository_ItemWritesRefreshTotal(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, jobs JobRepository) error
+	}{
+		{
+			name: "add item",
+			write: func(ctx context.Context, jobs JobRepository) error {
+				return jobs.AddJobItem(ctx, "job1", &models.JobItem{ID: "line1", Quantity: 3, Price: models.Cents(1250)})
+			},
+		},
+		{
+			name: "remove item",
+			write: func(ctx context.Context, jobs JobRepository) error {
+				return jobs.RemoveJobItem(ctx, "job1", "line1")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{}
+			db := sql.OpenDB(fake)
+			defer db.Close()
+
+			if err := tt.write(context.Background(), NewJobRepository(db)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fake.begins != 1 || fake.commits != 1 {
+				t.Errorf("expected one committed transaction, got %d begins and %d commits", fake.begins, fake.commits)
+			}
+			last := fake.execs[len(fake.execs)-1]
+			if !strings.Contains(last, "UPDATE jobs SET total_amount") {
+				t.Errorf("expected the job total to be refreshed last, got %q", last)
+			}
+			if len(fake.locks) != 1 || !strings.Contains(fake.locks[0], "FROM jobs") {
+				t.Errorf("expected the job to be locked before it is totalled, got %q", fake.locks)
+			}
+		})
+	}
+}
+
+func TestJobRepository_AddJobItemSetsLineTotal(t *testing.T) {
+	db := sql.OpenDB(&fakeDB{})
+	defer db.Close()
+
+	item := &models.JobItem{ID: "line1", Quantity: 3, Price: models.Cents(1250), Total: models.Cents(99900)}
+	if err := NewJobRepository(db).AddJobItem(context.Background(), "job1", item); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if item.Total != models.Cents(3750) {
+		t.Errorf("expected line total 37.50, got %s", item.Total)
+	}
+}
+
+func TestJobRepository_UpdateJobItemQuantityKeepsStoredPrice(t *testing.T) {
+	fake := &fakeDB{locked: map[string][]driver.Value{
+		"FROM job_items": {"outlet", "Outlet", 4.0, "12.50", "3.10"},
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	// The caller's copy was read before a reprice and holds the old price
+	item := &models.JobItem{ID: "line1", Quantity: 3, BudgetedQuantity: 2, Price: models.Cents(999)}
+	if err := NewJobRepository(db).UpdateJobItemQuantity(context.Background(), "job1", item); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if item.Price != models.Cents(1250) || item.BudgetedQuantity != 4 || item.Total != models.Cents(3750) {
+		t.Errorf("expected 3 at the stored 12.50 against a budget of 4, got %+v", item)
+	}
+	if len(fake.locks) != 3 || !strings.Contains(fake.locks[0], "FROM jobs") || !strings.Contains(fake.locks[1], "FROM job_items") {
+		t.Errorf("expected the job, then the line to be locked before the total refresh, got %q", fake.locks)
+	}
+	if len(fake.execs) != 2 || strings.Contains(fake.execs[0], "price") || strings.Contains(fake.execs[0], "budgeted_quantity") {
+		t.Errorf("expected only the quantity and total to be written, got %q", fake.execs)
+	}
+	if fake.commits != 1 {
+		t.Errorf("expected one committed transaction, got %d commits", fake.commits)
+	}
+}
+
+func TestJobRepository_FailedRefreshRollsBackItem(t *testing.T) {
+	fake := &fakeDB{failOn: "UPDATE jobs SET total_amount"}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	err := NewJobRepository(db).AddJobItem(context.Background(), "job1", &models.JobItem{ID: "line1"})
+	if err == nil {
+		t.Fatal("expected the failed total refresh to fail the write")
+	}
+	if fake.commits != 0 || fake.rollbacks != 1 {
+		t.Errorf("expected the item insert to be rolled back, got %d commits and %d rollbacks", fake.commits, fake.rollbacks)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/google/uuid"
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
 )
 
@@ -13,7 +14,7 @@ import (
 type JobTemplateRepository interface {
 	Create(ctx context.Context, template *models.JobTemplate) error
 	GetByID(ctx context.Context, id string) (*models.JobTemplate, error)
-	Update(ctx context.Context, template *models.JobTemplate) error
+	Update(ctx context.Context, template *models.JobTemplate, reassignments map[string]string) error
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, limit, offset int) ([]*models.JobTemplate, error)
 	ListActive(ctx context.Context) ([]*models.JobTemplate, error)
@@ -23,10 +24,18 @@ type JobTemplateRepository interface {
 	GetTemplateItems(ctx context.Context, templateID string) ([]models.TemplateItem, error)
 	UpdateTemplateItem(ctx context.Context, item *models.TemplateItem) error
 	RemoveTemplateItem(ctx context.Context, templateID, itemID string) error
+	
+	// Template version operations
+	ListVersions(ctx context.Context, templateID string) ([]models.TemplateVersion, error)
+	GetVersion(ctx context.Context, templateID string, number int) (*models.TemplateVersion, error)
 }
 
+// ErrPhaseInUse is returned when a template edit would remove a phase that
+// active jobs are still on
+var ErrPhaseInUse = NewRepositoryError("phase in use", nil)
+
 type jobTemplateRepository struct {
-	db *sql.DB
+	db executor
 }
 
 // NewJobTemplateRepository creates a new job template repository
@@ -35,7 +44,7 @@ func NewJobTemplateRepository(db *sql.DB) JobTemplateRepository {
 }
 
 func (r *jobTemplateRepository) Create(ctx context.Context, template *models.JobTemplate) error {
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
@@ -83,12 +92,22 @@ func (r *jobTemplateRepository) Create(ctx context.Context, template *models.Job
 		}
 	}
 	
-	return tx.Commit()
+	version, err := r.recordVersion(ctx, tx, template.ID)
+	if err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	
+	template.Version = version
+	return nil
 }
 
 func (r *jobTemplateRepository) GetByID(ctx context.Context, id string) (*models.JobTemplate, error) {
 	query := `
-		SELECT id, name, description, is_active, created_at, updated_at
+		SELECT id, name, description, version, is_active, created_at, updated_at
 		FROM job_templates
 		WHERE id = $1
 	`
@@ -96,7 +115,7 @@ func (r *jobTemplateRepository) GetByID(ctx context.Context, id string) (*models
 	template := &models.JobTemplate{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&template.ID, &template.Name, &template.Description,
-		&template.IsActive, &template.CreatedAt, &template.UpdatedAt,
+		&template.Version, &template.IsActive, &template.CreatedAt, &template.UpdatedAt,
 	)
 	
 	if err == sql.ErrNoRows {
@@ -123,11 +142,11 @@ func (r *jobTemplateRepository) GetByID(ctx context.Context, id string) (*models
 	return template, nil
 }
 
-func (r *jobTemplateRepository) Update(ctx context.Context, template *models.JobTemplate) error {
+func (r *jobTemplateRepository) Update(ctx context.Context, template *models.JobTemplate, reassignments map[string]string) error {
 	log.Printf("Repository Update called for template %s, phases: %d", template.ID, len(template.Phases))
 	
 	// Start a transaction
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
@@ -161,40 +180,155 @@ func (r *jobTemplateRepository) Update(ctx context.Context, template *models.Job
 	// Always update phases when Phases field is not nil (even if empty)
 	if template.Phases != nil {
 		log.Printf("Updating phases for template %s (count: %d)", template.ID, len(template.Phases))
-		
-		// Delete existing phases
-		_, err = tx.ExecContext(ctx, `DELETE FROM template_phases WHERE template_id = $1`, template.ID)
-		if err != nil {
-			log.Printf("Error deleting phases: %v", err)
+		if err := r.savePhases(ctx, tx, template, reassignments); err != nil {
+			log.Printf("Error updating phases: %v", err)
 			return err
 		}
+	} else {
+		log.Printf("Phases field is nil for template %s, not updating phases", template.ID)
+	}
+	
+	version, err := r.recordVersion(ctx, tx, template.ID)
+	if err != nil {
+		return err
+	}
+	
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	
+	template.Version = version
+	log.Printf("Template %s updated successfully to version %d", template.ID, version)
+	return nil
+}
+
+// savePhases brings the template's stored phases in line with template.Phases.
+// Kept phases are updated in place so jobs referencing them are untouched.
+// Jobs on a removed phase are moved to its reassignment target, which active
+// jobs are recorded as having started; if there is none, active jobs block the
+// removal and finished jobs simply lose their current phase.
+func (r *jobTemplateRepository) savePhases(ctx context.Context, tx executor, template *models.JobTemplate, reassignments map[string]string) error {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, name FROM template_phases WHERE template_id = $1 FOR UPDATE`, template.ID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]string)
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	
+	kept := make(map[string]bool, len(template.Phases))
+	phases := make(map[string]models.TemplatePhase, len(template.Phases))
+	for _, phase := range template.Phases {
+		kept[phase.ID] = true
+		phases[phase.ID] = phase
+	}
+	
+	for id, name := range existing {
+		if kept[id] {
+			continue
+		}
 		
-		// Insert new phases if any
-		for _, phase := range template.Phases {
-			insertQuery := `
-				INSERT INTO template_phases (id, template_id, name, phase_order, description)
-				VALUES ($1, $2, $3, $4, $5)
-			`
-			log.Printf("Inserting phase: ID=%s, Name=%s, Order=%d", phase.ID, phase.Name, phase.Order)
-			_, err = tx.ExecContext(ctx, insertQuery,
-				phase.ID, template.ID, phase.Name, phase.Order, phase.Description,
-			)
+		if to, ok := reassignments[id]; ok {
+			// Active jobs start the phase they are moved to, reopening it if
+			// they had already completed it
+			target := phases[to]
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO job_phases (job_id, phase_id, name, phase_order, is_completed, started_at)
+				SELECT id, $2, $3, $4, false, CURRENT_TIMESTAMP
+				FROM jobs
+				WHERE current_phase_id = $1 AND status IN ($5, $6)
+				ON CONFLICT (job_id, phase_id) DO UPDATE SET
+					is_completed = false, completed_at = NULL,
+					started_at = COALESCE(job_phases.started_at, EXCLUDED.started_at)
+			`, id, to, target.Name, target.Order, models.JobStatusScheduled, models.JobStatusInProgress)
+			if err != nil {
+				return err
+			}
+			
+			_, err = tx.ExecContext(ctx,
+				`UPDATE jobs SET current_phase_id = $2, updated_at = CURRENT_TIMESTAMP WHERE current_phase_id = $1`,
+				id, to)
+			if err != nil {
+				return err
+			}
+		} else {
+			var active int
+			err := tx.QueryRowContext(ctx,
+				`SELECT COUNT(*) FROM jobs WHERE current_phase_id = $1 AND status IN ($2, $3)`,
+				id, models.JobStatusScheduled, models.JobStatusInProgress,
+			).Scan(&active)
+			if err != nil {
+				return err
+			}
+			if active > 0 {
+				return fmt.Errorf("%w: %d active job(s) are on the %s phase; reassign them to another phase first",
+					ErrPhaseInUse, active, name)
+			}
+			
+			_, err = tx.ExecContext(ctx,
+				`UPDATE jobs SET current_phase_id = NULL WHERE current_phase_id = $1`, id)
 			if err != nil {
-				log.Printf("Error inserting phase: %v", err)
 				return err
 			}
 		}
-	} else {
-		log.Printf("Phases field is nil for template %s, not updating phases", template.ID)
+		
+		// Open records for the phase would keep active jobs from completing;
+		// completed ones stay as history
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM job_phases
+			WHERE phase_id = $1 AND NOT is_completed
+			  AND job_id IN (SELECT id FROM jobs WHERE status IN ($2, $3))
+		`, id, models.JobStatusScheduled, models.JobStatusInProgress)
+		if err != nil {
+			return err
+		}
+		
+		if _, err := tx.ExecContext(ctx, `DELETE FROM template_phases WHERE id = $1`, id); err != nil {
+			return err
+		}
 	}
 	
-	err = tx.Commit()
+	// Move kept phases out of the way first so reordering doesn't trip the
+	// unique (template_id, phase_order) constraint
+	_, err = tx.ExecContext(ctx,
+		`UPDATE template_phases SET phase_order = -phase_order - 1 WHERE template_id = $1`, template.ID)
 	if err != nil {
-		log.Printf("Error committing transaction: %v", err)
 		return err
 	}
 	
-	log.Printf("Template %s updated successfully", template.ID)
+	upsertQuery := `
+		INSERT INTO template_phases (id, template_id, name, phase_order, description)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name, phase_order = EXCLUDED.phase_order,
+			description = EXCLUDED.description
+		WHERE template_phases.template_id = EXCLUDED.template_id
+	`
+	for _, phase := range template.Phases {
+		if _, ok := existing[phase.ID]; !ok {
+			log.Printf("Inserting phase: ID=%s, Name=%s, Order=%d", phase.ID, phase.Name, phase.Order)
+		}
+		_, err := tx.ExecContext(ctx, upsertQuery,
+			phase.ID, template.ID, phase.Name, phase.Order, phase.Description,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	
 	return nil
 }
 
@@ -220,7 +354,7 @@ func (r *jobTemplateRepository) Delete(ctx context.Context, id string) error {
 
 func (r *jobTemplateRepository) List(ctx context.Context, limit, offset int) ([]*models.JobTemplate, error) {
 	query := `
-		SELECT id, name, description, is_active, created_at, updated_at
+		SELECT id, name, description, version, is_active, created_at, updated_at
 		FROM job_templates
 		ORDER BY name
 		LIMIT $1 OFFSET $2
@@ -237,7 +371,7 @@ func (r *jobTemplateRepository) List(ctx context.Context, limit, offset int) ([]
 
 func (r *jobTemplateRepository) ListActive(ctx context.Context) ([]*models.JobTemplate, error) {
 	query := `
-		SELECT id, name, description, is_active, created_at, updated_at
+		SELECT id, name, description, version, is_active, created_at, updated_at
 		FROM job_templates
 		WHERE is_active = true
 		ORDER BY name
@@ -254,16 +388,29 @@ func (r *jobTemplateRepository) ListActive(ctx context.Context) ([]*models.JobTe
 
 // Template Items operations
 func (r *jobTemplateRepository) AddTemplateItem(ctx context.Context, templateID string, item *models.TemplateItem) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
 	query := `
 		INSERT INTO template_items (id, template_id, item_id, default_quantity, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`
 	
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		item.ID, templateID, item.ItemID, item.DefaultQuantity,
 	)
+	if err != nil {
+		return err
+	}
 	
-	return err
+	if _, err := r.recordVersion(ctx, tx, templateID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
 }
 
 func (r *jobTemplateRepository) GetTemplateItems(ctx context.Context, templateID string) ([]models.TemplateItem, error) {
@@ -296,13 +443,19 @@ func (r *jobTemplateRepository) GetTemplateItems(ctx context.Context, templateID
 }
 
 func (r *jobTemplateRepository) UpdateTemplateItem(ctx context.Context, item *models.TemplateItem) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
 	query := `
 		UPDATE template_items SET
 			default_quantity = $2, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1
 	`
 	
-	result, err := r.db.ExecContext(ctx, query, item.ID, item.DefaultQuantity)
+	result, err := tx.ExecContext(ctx, query, item.ID, item.DefaultQuantity)
 	if err != nil {
 		return err
 	}
@@ -316,13 +469,23 @@ func (r *jobTemplateRepository) UpdateTemplateItem(ctx context.Context, item *mo
 		return fmt.Errorf("template item not found")
 	}
 	
-	return nil
+	if _, err := r.recordVersion(ctx, tx, item.TemplateID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
 }
 
 func (r *jobTemplateRepository) RemoveTemplateItem(ctx context.Context, templateID, itemID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
 	query := `DELETE FROM template_items WHERE template_id = $1 AND item_id = $2`
 	
-	result, err := r.db.ExecContext(ctx, query, templateID, itemID)
+	result, err := tx.ExecContext(ctx, query, templateID, itemID)
 	if err != nil {
 		return err
 	}
@@ -336,7 +499,11 @@ func (r *jobTemplateRepository) RemoveTemplateItem(ctx context.Context, template
 		return fmt.Errorf("template item not found")
 	}
 	
-	return nil
+	if _, err := r.recordVersion(ctx, tx, templateID); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
 }
 
 // Helper function to scan template rows
@@ -346,7 +513,7 @@ func (r *jobTemplateRepository) scanTemplates(ctx context.Context, rows *sql.Row
 		template := &models.JobTemplate{}
 		err := rows.Scan(
 			&template.ID, &template.Name, &template.Description,
-			&template.IsActive, &template.CreatedAt, &template.UpdatedAt,
+			&template.Version, &template.IsActive, &template.CreatedAt, &template.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -408,3 +575,254 @@ func (r *jobTemplateRepository) GetTemplatePhases(ctx context.Context, templateI
 	log.Printf("Found %d phases for template %s", len(phases), templateID)
 	return phases, nil
 }
+
+// Version operations
+
+// recordVersion snapshots the template's stored state within tx as its next
+// version and returns the new version number. Every write to a template calls
+// it before committing, so versions are never edited after the fact.
+func (r *jobTemplateRepository) recordVersion(ctx context.Context, tx executor, templateID string) (int, error) {
+	template := &models.JobTemplate{ID: templateID}
+	var description sql.NullString
+	
+	// Lock the template so concurrent saves get distinct version numbers
+	err := tx.QueryRowContext(ctx,
+		`SELECT name, description FROM job_templates WHERE id = $1 FOR UPDATE`, templateID,
+	).Scan(&template.Name, &description)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("template not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	template.Description = description.String
+	
+	var number int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version_number), 0) + 1 FROM template_versions WHERE template_id = $1`,
+		templateID,
+	).Scan(&number)
+	if err != nil {
+		return 0, err
+	}
+	
+	template.Items, err = queryTemplateItems(ctx, tx, templateID)
+	if err != nil {
+		return 0, err
+	}
+	template.Phases, err = queryTemplatePhases(ctx, tx, templateID)
+	if err != nil {
+		return 0, err
+	}
+	
+	version := models.NewTemplateVersion(template)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO template_versions (id, template_id, version_number, name, description, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, version.ID, templateID, number, version.Name, version.Description, version.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	
+	for _, item := range version.Items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO template_version_items (id, version_id, template_item_id, item_id, default_quantity)
+			VALUES ($1, $2, $3, $4, $5)
+		`, uuid.New().String(), version.ID, item.ID, item.ItemID, item.DefaultQuantity)
+		if err != nil {
+			return 0, err
+		}
+	}
+	
+	for _, phase := range version.Phases {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO template_version_phases (version_id, phase_id, name, phase_order, description)
+			VALUES ($1, $2, $3, $4, $5)
+		`, version.ID, phase.ID, phase.Name, phase.Order, phase.Description)
+		if err != nil {
+			return 0, err
+		}
+	}
+	
+	_, err = tx.ExecContext(ctx, `UPDATE job_templates SET version = $2 WHERE id = $1`, templateID, number)
+	if err != nil {
+		return 0, err
+	}
+	
+	return number, nil
+}
+
+func (r *jobTemplateRepository) ListVersions(ctx context.Context, templateID string) ([]models.TemplateVersion, error) {
+	query := `
+		SELECT id, template_id, version_number, name, description, created_at
+		FROM template_versions
+		WHERE template_id = $1
+		ORDER BY version_number
+	`
+	
+	rows, err := r.db.QueryContext(ctx, query, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	versions := make([]models.TemplateVersion, 0)
+	for rows.Next() {
+		version, err := scanTemplateVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	
+	for i := range versions {
+		if err := r.loadVersionContents(ctx, &versions[i]); err != nil {
+			return nil, err
+		}
+	}
+	
+	return versions, nil
+}
+
+func (r *jobTemplateRepository) GetVersion(ctx context.Context, templateID string, number int) (*models.TemplateVersion, error) {
+	query := `
+		SELECT id, template_id, version_number, name, description, created_at
+		FROM template_versions
+		WHERE template_id = $1 AND version_number = $2
+	`
+	
+	version, err := scanTemplateVersion(r.db.QueryRowContext(ctx, query, templateID, number))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("template version not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := r.loadVersionContents(ctx, version); err != nil {
+		return nil, err
+	}
+	
+	return version, nil
+}
+
+// loadVersionContents reads a version's item and phase snapshots
+func (r *jobTemplateRepository) loadVersionContents(ctx context.Context, version *models.TemplateVersion) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT template_item_id, item_id, default_quantity
+		FROM template_version_items
+		WHERE version_id = $1
+		ORDER BY template_item_id
+	`, version.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	
+	version.Items = make([]models.TemplateItem, 0)
+	for rows.Next() {
+		item := models.TemplateItem{TemplateID: version.TemplateID}
+		if err := rows.Scan(&item.ID, &item.ItemID, &item.DefaultQuantity); err != nil {
+			return err
+		}
+		version.Items = append(version.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+	
+	phaseRows, err := r.db.QueryContext(ctx, `
+		SELECT phase_id, name, phase_order, description
+		FROM template_version_phases
+		WHERE version_id = $1
+		ORDER BY phase_order
+	`, version.ID)
+	if err != nil {
+		return err
+	}
+	defer phaseRows.Close()
+	
+	version.Phases = make([]models.TemplatePhase, 0)
+	for phaseRows.Next() {
+		phase := models.TemplatePhase{TemplateID: version.TemplateID}
+		var description sql.NullString
+		if err := phaseRows.Scan(&phase.ID, &phase.Name, &phase.Order, &description); err != nil {
+			return err
+		}
+		phase.Description = description.String
+		version.Phases = append(version.Phases, phase)
+	}
+	
+	return phaseRows.Err()
+}
+
+func scanTemplateVersion(row rowScanner) (*models.TemplateVersion, error) {
+	version := &models.TemplateVersion{}
+	var description sql.NullString
+	err := row.Scan(
+		&version.ID, &version.TemplateID, &version.Number,
+		&version.Name, &description, &version.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	version.Description = description.String
+	return version, nil
+}
+
+// queryTemplateItems reads a template's items within a transaction
+func queryTemplateItems(ctx context.Context, tx executor, templateID string) ([]models.TemplateItem, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, template_id, item_id, default_quantity
+		FROM template_items
+		WHERE template_id = $1
+		ORDER BY id
+	`, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	items := make([]models.TemplateItem, 0)
+	for rows.Next() {
+		var item models.TemplateItem
+		if err := rows.Scan(&item.ID, &item.TemplateID, &item.ItemID, &item.DefaultQuantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	
+	return items, rows.Err()
+}
+
+// queryTemplatePhases reads a template's phases within a transaction
+func queryTemplatePhases(ctx context.Context, tx executor, templateID string) ([]models.TemplatePhase, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, template_id, name, phase_order, description
+		FROM template_phases
+		WHERE template_id = $1
+		ORDER BY phase_order
+	`, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	phases := make([]models.TemplatePhase, 0)
+	for rows.Next() {
+		var phase models.TemplatePhase
+		var description sql.NullString
+		if err := rows.Scan(&phase.ID, &phase.TemplateID, &phase.Name, &phase.Order, &description); err != nil {
+			return nil, err
+		}
+		phase.Description = description.String
+		phases = append(phases, phase)
+	}
+	
+	return phases, rows.Err()
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+func TestJobTemplateRepository_ReassignedJobsStartTargetPhase(t *testing.T) {
+	fake := &fakeDB{locked: map[string][]driver.Value{
+		"FROM template_phases": {"trim", "Trim"},
+	}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	template := &models.JobTemplate{
+		ID:     "template1",
+		Phases: []models.TemplatePhase{{ID: "rough", Name: "Rough", Order: 1}},
+	}
+
+	repo := &jobTemplateRepository{db: db}
+	if err := repo.savePhases(context.Background(), db, template, map[string]string{"trim": "rough"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	started, moved := -1, -1
+	for i, exec := range fake.execs {
+		switch {
+		case strings.Contains(exec, "INSERT INTO job_phases"):
+			started = i
+		case strings.Contains(exec, "UPDATE jobs SET current_phase_id = $2"):
+			moved = i
+		}
+	}
+	if started < 0 || moved < 0 || started > moved {
+		t.Errorf("expected the target phase to be started for the jobs before they move, got %q", fake.execs)
+	}
+}
@@ -0,0 +1,423 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// LaborRepository defines the interface for labor rate, difficulty and job
+// labor database operations
+type LaborRepository interface {
+	CreateRate(ctx context.Context, rate *models.LaborRate) error
+	GetRate(ctx context.Context, id string) (*models.LaborRate, error)
+	GetDefaultRate(ctx context.Context) (*models.LaborRate, error)
+	UpdateRate(ctx context.Context, rate *models.LaborRate) error
+	DeleteRate(ctx context.Context, id string) error
+	ListRates(ctx context.Context) ([]models.LaborRate, error)
+
+	CreateDifficulty(ctx context.Context, difficulty *models.LaborDifficulty) error
+	GetDifficulty(ctx context.Context, id string) (*models.LaborDifficulty, error)
+	UpdateDifficulty(ctx context.Context, difficulty *models.LaborDifficulty) error
+	DeleteDifficulty(ctx context.Context, id string) error
+	ListDifficulties(ctx context.Context) ([]models.LaborDifficulty, error)
+
+	GetJobLabor(ctx context.Context, jobID string) (*models.JobLabor, error)
+	SetJobLabor(ctx context.Context, labor *models.JobLabor) error
+}
+
+type laborRepository struct {
+	db executor
+}
+
+// NewLaborRepository creates a new labor repository
+func NewLaborRepository(db *sql.DB) LaborRepository {
+	return &laborRepository{db: db}
+}
+
+const laborRateColumns = `id, name, hourly_rate, is_default, created_at, updated_at`
+
+const laborDifficultyColumns = `id, name, multiplier, created_at, updated_at`
+
+// CreateRate inserts a labor rate. A new default rate replaces the old one.
+func (r *laborRepository) CreateRate(ctx context.Context, rate *models.LaborRate) error {
+	if rate == nil {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := clearDefaultRate(ctx, tx, rate); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO labor_rates (` + laborRateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		rate.ID, rate.Name, rate.HourlyRate, rate.IsDefault, rate.CreatedAt, rate.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to create labor rate", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit labor rate", err)
+	}
+
+	return nil
+}
+
+// GetRate retrieves a labor rate by its ID
+func (r *laborRepository) GetRate(ctx context.Context, id string) (*models.LaborRate, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + laborRateColumns + ` FROM labor_rates WHERE id = $1`
+
+	rate, err := scanLaborRate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get labor rate", err)
+	}
+
+	return rate, nil
+}
+
+// GetDefaultRate retrieves the default labor rate, or ErrNotFound when no
+// rate is the default
+func (r *laborRepository) GetDefaultRate(ctx context.Context) (*models.LaborRate, error) {
+	query := `SELECT ` + laborRateColumns + ` FROM labor_rates WHERE is_default`
+
+	rate, err := scanLaborRate(r.db.QueryRowContext(ctx, query))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get default labor rate", err)
+	}
+
+	return rate, nil
+}
+
+// UpdateRate saves a labor rate. Making it the default replaces the old one.
+func (r *laborRepository) UpdateRate(ctx context.Context, rate *models.LaborRate) error {
+	if rate == nil || rate.ID == "" {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := clearDefaultRate(ctx, tx, rate); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE labor_rates
+		SET name = $2, hourly_rate = $3, is_default = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		rate.ID, rate.Name, rate.HourlyRate, rate.IsDefault, rate.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to update labor rate", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit labor rate", err)
+	}
+
+	return nil
+}
+
+// DeleteRate removes a labor rate. Rates that jobs are priced at return
+// ErrInUse.
+func (r *laborRepository) DeleteRate(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM labor_rates WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return ErrInUse
+		}
+		return NewRepositoryError("failed to delete labor rate", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListRates returns every labor rate by name
+func (r *laborRepository) ListRates(ctx context.Context) ([]models.LaborRate, error) {
+	query := `SELECT ` + laborRateColumns + ` FROM labor_rates ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list labor rates", err)
+	}
+	defer rows.Close()
+
+	rates := []models.LaborRate{}
+	for rows.Next() {
+		rate, err := scanLaborRate(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan labor rate", err)
+		}
+		rates = append(rates, *rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// CreateDifficulty inserts a labor difficulty
+func (r *laborRepository) CreateDifficulty(ctx context.Context, difficulty *models.LaborDifficulty) error {
+	if difficulty == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO labor_difficulties (` + laborDifficultyColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		difficulty.ID, difficulty.Name, difficulty.Multiplier, difficulty.CreatedAt, difficulty.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to create labor difficulty", err)
+	}
+
+	return nil
+}
+
+// GetDifficulty retrieves a labor difficulty by its ID
+func (r *laborRepository) GetDifficulty(ctx context.Context, id string) (*models.LaborDifficulty, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + laborDifficultyColumns + ` FROM labor_difficulties WHERE id = $1`
+
+	difficulty, err := scanLaborDifficulty(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get labor difficulty", err)
+	}
+
+	return difficulty, nil
+}
+
+// UpdateDifficulty saves a labor difficulty
+func (r *laborRepository) UpdateDifficulty(ctx context.Context, difficulty *models.LaborDifficulty) error {
+	if difficulty == nil || difficulty.ID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		UPDATE labor_difficulties
+		SET name = $2, multiplier = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		difficulty.ID, difficulty.Name, difficulty.Multiplier, difficulty.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to update labor difficulty", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteDifficulty removes a labor difficulty. Difficulties that jobs are
+// priced at return ErrInUse.
+func (r *laborRepository) DeleteDifficulty(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM labor_difficulties WHERE id = $1`, id)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return ErrInUse
+		}
+		return NewRepositoryError("failed to delete labor difficulty", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListDifficulties returns every labor difficulty, easiest first
+func (r *laborRepository) ListDifficulties(ctx context.Context) ([]models.LaborDifficulty, error) {
+	query := `SELECT ` + laborDifficultyColumns + ` FROM labor_difficulties ORDER BY multiplier, name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list labor difficulties", err)
+	}
+	defer rows.Close()
+
+	difficulties := []models.LaborDifficulty{}
+	for rows.Next() {
+		difficulty, err := scanLaborDifficulty(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan labor difficulty", err)
+		}
+		difficulties = append(difficulties, *difficulty)
+	}
+
+	return difficulties, rows.Err()
+}
+
+// GetJobLabor retrieves how a job's labor is priced, or ErrNotFound when it
+// has never been set
+func (r *laborRepository) GetJobLabor(ctx context.Context, jobID string) (*models.JobLabor, error) {
+	if jobID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT job_id, COALESCE(labor_rate_id, ''), COALESCE(difficulty_id, ''),
+			split_on_invoice, updated_at
+		FROM job_labor WHERE job_id = $1
+	`
+
+	var labor models.JobLabor
+	err := r.db.QueryRowContext(ctx, query, jobID).Scan(
+		&labor.JobID, &labor.RateID, &labor.DifficultyID, &labor.SplitOnInvoice, &labor.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get job labor", err)
+	}
+
+	return &labor, nil
+}
+
+// SetJobLabor saves how a job's labor is priced. A rate or difficulty that
+// doesn't exist returns ErrInvalidInput.
+func (r *laborRepository) SetJobLabor(ctx context.Context, labor *models.JobLabor) error {
+	if labor == nil || labor.JobID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO job_labor (job_id, labor_rate_id, difficulty_id, split_on_invoice, updated_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5)
+		ON CONFLICT (job_id) DO UPDATE SET
+			labor_rate_id = EXCLUDED.labor_rate_id,
+			difficulty_id = EXCLUDED.difficulty_id,
+			split_on_invoice = EXCLUDED.split_on_invoice,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		labor.JobID, labor.RateID, labor.DifficultyID, labor.SplitOnInvoice, labor.UpdatedAt,
+	)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return ErrInvalidInput
+		}
+		return NewRepositoryError("failed to save job labor", err)
+	}
+
+	return nil
+}
+
+// clearDefaultRate takes the default from every other rate when rate is
+// becoming the default
+func clearDefaultRate(ctx context.Context, db executor, rate *models.LaborRate) error {
+	if !rate.IsDefault {
+		return nil
+	}
+
+	_, err := db.ExecContext(ctx,
+		`UPDATE labor_rates SET is_default = false WHERE is_default AND id <> $1`, rate.ID)
+	if err != nil {
+		return NewRepositoryError("failed to clear default labor rate", err)
+	}
+	return nil
+}
+
+func scanLaborRate(row rowScanner) (*models.LaborRate, error) {
+	var rate models.LaborRate
+	err := row.Scan(&rate.ID, &rate.Name, &rate.HourlyRate, &rate.IsDefault, &rate.CreatedAt, &rate.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func scanLaborDifficulty(row rowScanner) (*models.LaborDifficulty, error) {
+	var difficulty models.LaborDifficulty
+	err := row.Scan(&difficulty.ID, &difficulty.Name, &difficulty.Multiplier, &difficulty.CreatedAt, &difficulty.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &difficulty, nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/masterbrent/electrical-bidding-app/internal/loadcalc"
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// LoadCalculationRepository defines the interface for the load calculations
+// saved against jobs
+type LoadCalculationRepository interface {
+	Create(ctx context.Context, calculation *models.JobLoadCalculation) error
+	GetByID(ctx context.Context, jobID, id string) (*models.JobLoadCalculation, error)
+	ListByJob(ctx context.Context, jobID string) ([]*models.JobLoadCalculation, error)
+	Delete(ctx context.Context, jobID, id string) error
+}
+
+type loadCalculationRepository struct {
+	db executor
+}
+
+// NewLoadCalculationRepository creates a new load calculation repository
+func NewLoadCalculationRepository(db *sql.DB) LoadCalculationRepository {
+	return &loadCalculationRepository{db: db}
+}
+
+const loadCalculationColumns = `id, job_id, name, square_feet, small_appliance_circuits, laundry_circuits, voltage, created_at`
+
+// Create inserts a load calculation and its appliances
+func (r *loadCalculationRepository) Create(ctx context.Context, calculation *models.JobLoadCalculation) error {
+	if calculation == nil {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	dwelling := &calculation.Dwelling
+	query := `
+		INSERT INTO job_load_calculations (` + loadCalculationColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		calculation.ID, calculation.JobID, calculation.Name, dwelling.SquareFeet,
+		dwelling.SmallApplianceCircuits, dwelling.LaundryCircuits, dwelling.Voltage, calculation.CreatedAt,
+	)
+	if err != nil {
+		if isForeignKeyError(err) {
+			return ErrInvalidInput
+		}
+		return NewRepositoryError("failed to create load calculation", err)
+	}
+
+	applianceQuery := `
+		INSERT INTO job_load_calculation_appliances (calculation_id, kind, name, va, motor, sort_order)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, group := range applianceGroups(dwelling) {
+		for i, appliance := range *group.appliances {
+			_, err := tx.ExecContext(ctx, applianceQuery,
+				calculation.ID, group.kind, appliance.Name, appliance.VA, appliance.Motor, i,
+			)
+			if err != nil {
+				return NewRepositoryError("failed to save load calculation appliance", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit load calculation", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves one of a job's load calculations, worked out
+func (r *loadCalculationRepository) GetByID(ctx context.Context, jobID, id string) (*models.JobLoadCalculation, error) {
+	if jobID == "" || id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + loadCalculationColumns + ` FROM job_load_calculations WHERE id = $1 AND job_id = $2`
+
+	calculation, err := scanLoadCalculation(r.db.QueryRowContext(ctx, query, id, jobID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get load calculation", err)
+	}
+
+	if err := r.loadAppliances(ctx, []*models.JobLoadCalculation{calculation}); err != nil {
+		return nil, err
+	}
+
+	return calculation, nil
+}
+
+// ListByJob returns a job's load calculations, worked out, oldest first
+func (r *loadCalculationRepository) ListByJob(ctx context.Context, jobID string) ([]*models.JobLoadCalculation, error) {
+	if jobID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + loadCalculationColumns + ` FROM job_load_calculations WHERE job_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, jobID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list load calculations", err)
+	}
+	defer rows.Close()
+
+	calculations := []*models.JobLoadCalculation{}
+	for rows.Next() {
+		calculation, err := scanLoadCalculation(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan load calculation", err)
+		}
+		calculations = append(calculations, calculation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewRepositoryError("error iterating load calculations", err)
+	}
+
+	if err := r.loadAppliances(ctx, calculations); err != nil {
+		return nil, err
+	}
+
+	return calculations, nil
+}
+
+// Delete removes one of a job's load calculations
+func (r *loadCalculationRepository) Delete(ctx context.Context, jobID, id string) error {
+	if jobID == "" || id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM job_load_calculations WHERE id = $1 AND job_id = $2`, id, jobID)
+	if err != nil {
+		return NewRepositoryError("failed to delete load calculation", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// loadAppliances fills in the appliances of calculations and works each one
+// out
+func (r *loadCalculationRepository) loadAppliances(ctx context.Context, calculations []*models.JobLoadCalculation) error {
+	if len(calculations) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.JobLoadCalculation, len(calculations))
+	ids := make([]string, 0, len(calculations))
+	for _, calculation := range calculations {
+		byID[calculation.ID] = calculation
+		ids = append(ids, calculation.ID)
+	}
+
+	query := `
+		SELECT calculation_id, kind, name, va, motor
+		FROM job_load_calculation_appliances
+		WHERE calculation_id = ANY($1)
+		ORDER BY calculation_id, kind, sort_order
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return NewRepositoryError("failed to load load calculation appliances", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var calculationID, kind string
+		var appliance loadcalc.Appliance
+		if err := rows.Scan(&calculationID, &kind, &appliance.Name, &appliance.VA, &appliance.Motor); err != nil {
+			return NewRepositoryError("failed to scan load calculation appliance", err)
+		}
+
+		for _, group := range applianceGroups(&byID[calculationID].Dwelling) {
+			if group.kind == kind {
+				*group.appliances = append(*group.appliances, appliance)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return NewRepositoryError("error iterating load calculation appliances", err)
+	}
+
+	for _, calculation := range calculations {
+		if err := calculation.Calculate(); err != nil {
+			return NewRepositoryError("failed to work out load calculation", err)
+		}
+	}
+
+	return nil
+}
+
+// applianceGroup is one list of appliances in a dwelling and the kind it is
+// stored as
+type applianceGroup struct {
+	kind       string
+	appliances *[]loadcalc.Appliance
+}
+
+// applianceGroups lists the appliance groups of a dwelling
+func applianceGroups(dwelling *loadcalc.Dwelling) []applianceGroup {
+	return []applianceGroup{
+		{"fixed", &dwelling.FixedAppliances},
+		{"cooking", &dwelling.Cooking},
+		{"dryer", &dwelling.Dryers},
+		{"heating", &dwelling.Heating},
+		{"cooling", &dwelling.Cooling},
+		{"ev_charger", &dwelling.EVChargers},
+	}
+}
+
+func scanLoadCalculation(row rowScanner) (*models.JobLoadCalculation, error) {
+	var calculation models.JobLoadCalculation
+	dwelling := &calculation.Dwelling
+	err := row.Scan(
+		&calculation.ID, &calculation.JobID, &calculation.Name, &dwelling.SquareFeet,
+		&dwelling.SmallApplianceCircuits, &dwelling.LaundryCircuits, &dwelling.Voltage, &calculation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &calculation, nil
+}
@@ -0,0 +1,458 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// SupplierRepository defines the interface for supplier, SKU link and price
+// file database operations
+type SupplierRepository interface {
+	Create(ctx context.Context, supplier *models.Supplier) error
+	GetByID(ctx context.Context, id string) (*models.Supplier, error)
+	List(ctx context.Context) ([]models.Supplier, error)
+	Update(ctx context.Context, supplier *models.Supplier) error
+	Delete(ctx context.Context, id string) error
+
+	AddLink(ctx context.Context, link *models.SupplierItem) error
+	ListLinks(ctx context.Context, supplierID string) ([]models.SupplierItem, error)
+	RemoveLink(ctx context.Context, supplierID, linkID string) error
+
+	CreateImport(ctx context.Context, priceImport *models.SupplierPriceImport) error
+	GetImport(ctx context.Context, supplierID, importID string) (*models.SupplierPriceImport, error)
+	GetPreviousImport(ctx context.Context, priceImport *models.SupplierPriceImport) (*models.SupplierPriceImport, error)
+	ListImports(ctx context.Context, supplierID string) ([]models.SupplierPriceImport, error)
+	MarkImportApplied(ctx context.Context, importID string, appliedAt time.Time) error
+}
+
+type supplierRepository struct {
+	db executor
+}
+
+// NewSupplierRepository creates a new supplier repository
+func NewSupplierRepository(db *sql.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+const supplierColumns = `id, name, email, phone, account_number, created_at, updated_at`
+
+const supplierImportColumns = `id, supplier_id, file_name, row_count, imported_at, applied_at`
+
+// Create inserts a supplier
+func (r *supplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	if supplier == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO suppliers (` + supplierColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		supplier.ID, supplier.Name, supplier.Email, supplier.Phone,
+		supplier.AccountNumber, supplier.CreatedAt, supplier.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to create supplier", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a supplier by its ID
+func (r *supplierRepository) GetByID(ctx context.Context, id string) (*models.Supplier, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + supplierColumns + ` FROM suppliers WHERE id = $1`
+
+	supplier, err := scanSupplier(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get supplier", err)
+	}
+
+	return supplier, nil
+}
+
+// List returns every supplier by name
+func (r *supplierRepository) List(ctx context.Context) ([]models.Supplier, error) {
+	query := `SELECT ` + supplierColumns + ` FROM suppliers ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list suppliers", err)
+	}
+	defer rows.Close()
+
+	suppliers := []models.Supplier{}
+	for rows.Next() {
+		supplier, err := scanSupplier(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan supplier", err)
+		}
+		suppliers = append(suppliers, *supplier)
+	}
+
+	return suppliers, rows.Err()
+}
+
+// Update saves a supplier
+func (r *supplierRepository) Update(ctx context.Context, supplier *models.Supplier) error {
+	if supplier == nil || supplier.ID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		UPDATE suppliers
+		SET name = $2, email = $3, phone = $4, account_number = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		supplier.ID, supplier.Name, supplier.Email, supplier.Phone,
+		supplier.AccountNumber, supplier.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to update supplier", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a supplier with its SKU links and price files
+func (r *supplierRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM suppliers WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete supplier", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// AddLink links a supplier SKU to an item. A SKU links to one item per
+// supplier, so linking it again returns ErrDuplicate.
+func (r *supplierRepository) AddLink(ctx context.Context, link *models.SupplierItem) error {
+	if link == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO supplier_items (id, supplier_id, sku, item_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, link.ID, link.SupplierID, link.SKU, link.ItemID, link.CreatedAt)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to link supplier item", err)
+	}
+
+	return nil
+}
+
+// ListLinks returns a supplier's SKU links with the linked item's name, by SKU
+func (r *supplierRepository) ListLinks(ctx context.Context, supplierID string) ([]models.SupplierItem, error) {
+	if supplierID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT l.id, l.supplier_id, l.sku, l.item_id, i.name, l.created_at
+		FROM supplier_items l
+		JOIN items i ON i.id = l.item_id
+		WHERE l.supplier_id = $1
+		ORDER BY l.sku
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, supplierID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list supplier items", err)
+	}
+	defer rows.Close()
+
+	links := []models.SupplierItem{}
+	for rows.Next() {
+		var link models.SupplierItem
+		err := rows.Scan(&link.ID, &link.SupplierID, &link.SKU, &link.ItemID, &link.ItemName, &link.CreatedAt)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan supplier item", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// RemoveLink removes one of a supplier's SKU links
+func (r *supplierRepository) RemoveLink(ctx context.Context, supplierID, linkID string) error {
+	if supplierID == "" || linkID == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM supplier_items WHERE id = $1 AND supplier_id = $2`, linkID, supplierID)
+	if err != nil {
+		return NewRepositoryError("failed to remove supplier item", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateImport stages a price file with its prices
+func (r *supplierRepository) CreateImport(ctx context.Context, priceImport *models.SupplierPriceImport) error {
+	if priceImport == nil {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO supplier_price_imports (` + supplierImportColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		priceImport.ID, priceImport.SupplierID, priceImport.FileName,
+		priceImport.RowCount, priceImport.ImportedAt, priceImport.AppliedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to create supplier price import", err)
+	}
+
+	priceQuery := `
+		INSERT INTO supplier_prices (import_id, sku, description, unit, cost)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, price := range priceImport.Prices {
+		_, err := tx.ExecContext(ctx, priceQuery, priceImport.ID, price.SKU, price.Description, price.Unit, price.Cost)
+		if err != nil {
+			return NewRepositoryError("failed to stage supplier price", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit supplier price import", err)
+	}
+
+	return nil
+}
+
+// GetImport retrieves one of a supplier's price files with its prices
+func (r *supplierRepository) GetImport(ctx context.Context, supplierID, importID string) (*models.SupplierPriceImport, error) {
+	if supplierID == "" || importID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT ` + supplierImportColumns + ` FROM supplier_price_imports
+		WHERE id = $1 AND supplier_id = $2
+	`
+
+	priceImport, err := scanSupplierImport(r.db.QueryRowContext(ctx, query, importID, supplierID))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get supplier price import", err)
+	}
+
+	if priceImport.Prices, err = r.listPrices(ctx, priceImport.ID); err != nil {
+		return nil, err
+	}
+
+	return priceImport, nil
+}
+
+// GetPreviousImport retrieves, with its prices, the price file the same
+// supplier sent before priceImport, or ErrNotFound for their first
+func (r *supplierRepository) GetPreviousImport(ctx context.Context, priceImport *models.SupplierPriceImport) (*models.SupplierPriceImport, error) {
+	if priceImport == nil {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT ` + supplierImportColumns + ` FROM supplier_price_imports
+		WHERE supplier_id = $1 AND id <> $2 AND imported_at <= $3
+		ORDER BY imported_at DESC
+		LIMIT 1
+	`
+
+	previous, err := scanSupplierImport(r.db.QueryRowContext(ctx, query,
+		priceImport.SupplierID, priceImport.ID, priceImport.ImportedAt))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get previous supplier price import", err)
+	}
+
+	if previous.Prices, err = r.listPrices(ctx, previous.ID); err != nil {
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+// ListImports returns a supplier's price files, newest first, without prices
+func (r *supplierRepository) ListImports(ctx context.Context, supplierID string) ([]models.SupplierPriceImport, error) {
+	if supplierID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT ` + supplierImportColumns + ` FROM supplier_price_imports
+		WHERE supplier_id = $1
+		ORDER BY imported_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, supplierID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list supplier price imports", err)
+	}
+	defer rows.Close()
+
+	imports := []models.SupplierPriceImport{}
+	for rows.Next() {
+		priceImport, err := scanSupplierImport(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan supplier price import", err)
+		}
+		imports = append(imports, *priceImport)
+	}
+
+	return imports, rows.Err()
+}
+
+// MarkImportApplied records that a price file's costs were applied. It
+// returns ErrNotFound if the file is unknown or was already applied.
+func (r *supplierRepository) MarkImportApplied(ctx context.Context, importID string, appliedAt time.Time) error {
+	if importID == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE supplier_price_imports SET applied_at = $2 WHERE id = $1 AND applied_at IS NULL`,
+		importID, appliedAt)
+	if err != nil {
+		return NewRepositoryError("failed to mark supplier price import applied", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// listPrices returns the staged prices of a price file, by SKU
+func (r *supplierRepository) listPrices(ctx context.Context, importID string) ([]models.SupplierPrice, error) {
+	query := `
+		SELECT sku, description, unit, cost FROM supplier_prices
+		WHERE import_id = $1
+		ORDER BY sku
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, importID)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list supplier prices", err)
+	}
+	defer rows.Close()
+
+	prices := []models.SupplierPrice{}
+	for rows.Next() {
+		var price models.SupplierPrice
+		if err := rows.Scan(&price.SKU, &price.Description, &price.Unit, &price.Cost); err != nil {
+			return nil, NewRepositoryError("failed to scan supplier price", err)
+		}
+		prices = append(prices, price)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, NewRepositoryError("error iterating supplier prices", err)
+	}
+
+	return prices, nil
+}
+
+func scanSupplier(row rowScanner) (*models.Supplier, error) {
+	var supplier models.Supplier
+	err := row.Scan(
+		&supplier.ID, &supplier.Name, &supplier.Email, &supplier.Phone,
+		&supplier.AccountNumber, &supplier.CreatedAt, &supplier.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func scanSupplierImport(row rowScanner) (*models.SupplierPriceImport, error) {
+	var priceImport models.SupplierPriceImport
+	var appliedAt sql.NullTime
+
+	err := row.Scan(
+		&priceImport.ID, &priceImport.SupplierID, &priceImport.FileName,
+		&priceImport.RowCount, &priceImport.ImportedAt, &appliedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if appliedAt.Valid {
+		priceImport.AppliedAt = &appliedAt.Time
+	}
+
+	return &priceImport, nil
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// TaxRepository defines the interface for tax rate and category database operations
+type TaxRepository interface {
+	CreateRate(ctx context.Context, rate *models.TaxRate) error
+	GetRate(ctx context.Context, id string) (*models.TaxRate, error)
+	UpdateRate(ctx context.Context, rate *models.TaxRate) error
+	DeleteRate(ctx context.Context, id string) error
+	ListRates(ctx context.Context) ([]models.TaxRate, error)
+
+	ListCategories(ctx context.Context) ([]models.TaxCategory, error)
+	SetCategory(ctx context.Context, category *models.TaxCategory) error
+}
+
+type taxRepository struct {
+	db executor
+}
+
+// NewTaxRepository creates a new tax repository
+func NewTaxRepository(db *sql.DB) TaxRepository {
+	return &taxRepository{db: db}
+}
+
+const taxRateColumns = `
+	id, name, state, city, zip, rate, wave_sales_tax_id,
+	effective_from, effective_to, created_at, updated_at
+`
+
+// CreateRate inserts a tax rate
+func (r *taxRepository) CreateRate(ctx context.Context, rate *models.TaxRate) error {
+	if rate == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO tax_rates (` + taxRateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		rate.ID, rate.Name, rate.State, rate.City, rate.Zip, rate.Rate,
+		rate.WaveSalesTaxID, rate.EffectiveFrom, rate.EffectiveTo,
+		rate.CreatedAt, rate.UpdatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to create tax rate", err)
+	}
+
+	return nil
+}
+
+// GetRate retrieves a tax rate by its ID
+func (r *taxRepository) GetRate(ctx context.Context, id string) (*models.TaxRate, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `SELECT ` + taxRateColumns + ` FROM tax_rates WHERE id = $1`
+
+	rate, err := scanTaxRate(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get tax rate", err)
+	}
+
+	return rate, nil
+}
+
+// UpdateRate saves a tax rate
+func (r *taxRepository) UpdateRate(ctx context.Context, rate *models.TaxRate) error {
+	if rate == nil || rate.ID == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		UPDATE tax_rates SET
+			name = $2, state = $3, city = $4, zip = $5, rate = $6,
+			wave_sales_tax_id = $7, effective_from = $8, effective_to = $9,
+			updated_at = $10
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		rate.ID, rate.Name, rate.State, rate.City, rate.Zip, rate.Rate,
+		rate.WaveSalesTaxID, rate.EffectiveFrom, rate.EffectiveTo, rate.UpdatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to update tax rate", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteRate removes a tax rate
+func (r *taxRepository) DeleteRate(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tax_rates WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete tax rate", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ListRates returns every tax rate, by jurisdiction and then start date
+func (r *taxRepository) ListRates(ctx context.Context) ([]models.TaxRate, error) {
+	query := `
+		SELECT ` + taxRateColumns + ` FROM tax_rates
+		ORDER BY state, city, zip, effective_from
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list tax rates", err)
+	}
+	defer rows.Close()
+
+	rates := []models.TaxRate{}
+	for rows.Next() {
+		rate, err := scanTaxRate(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan tax rate", err)
+		}
+		rates = append(rates, *rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// ListCategories returns the taxability setting of every listed category
+func (r *taxRepository) ListCategories(ctx context.Context) ([]models.TaxCategory, error) {
+	query := `SELECT category, taxable, updated_at FROM tax_categories ORDER BY category`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list tax categories", err)
+	}
+	defer rows.Close()
+
+	categories := []models.TaxCategory{}
+	for rows.Next() {
+		var category models.TaxCategory
+		if err := rows.Scan(&category.Category, &category.Taxable, &category.UpdatedAt); err != nil {
+			return nil, NewRepositoryError("failed to scan tax category", err)
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+// SetCategory records whether a category is taxable
+func (r *taxRepository) SetCategory(ctx context.Context, category *models.TaxCategory) error {
+	if category == nil || category.Category == "" {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO tax_categories (category, taxable, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (category) DO UPDATE SET taxable = EXCLUDED.taxable, updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, category.Category, category.Taxable, category.UpdatedAt); err != nil {
+		return NewRepositoryError("failed to save tax category", err)
+	}
+
+	return nil
+}
+
+func scanTaxRate(row rowScanner) (*models.TaxRate, error) {
+	var rate models.TaxRate
+	var effectiveTo sql.NullTime
+
+	err := row.Scan(
+		&rate.ID, &rate.Name, &rate.State, &rate.City, &rate.Zip, &rate.Rate,
+		&rate.WaveSalesTaxID, &rate.EffectiveFrom, &effectiveTo,
+		&rate.CreatedAt, &rate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if effectiveTo.Valid {
+		rate.EffectiveTo = &effectiveTo.Time
+	}
+
+	return &rate, nil
+}
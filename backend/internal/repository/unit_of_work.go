@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// executor is the part of *sql.DB and *sql.Tx the repositories use, so the
+// same repository code runs on its own or inside a unit of work
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Repositories are repositories bound to one transaction
+type Repositories struct {
+	Jobs      JobRepository
+	Templates JobTemplateRepository
+	Estimates EstimateRepository
+	Items     ItemRepository
+	Suppliers SupplierRepository
+}
+
+// UnitOfWork runs operations that span several rows or repositories so they
+// either all happen or none do
+type UnitOfWork interface {
+	// WithTx calls fn with repositories bound to a new transaction. The
+	// transaction is committed if fn returns nil and rolled back otherwise.
+	// Methods that stream rows while loading their children (the List and
+	// GetBy* queries) should be called outside fn.
+	WithTx(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a unit of work on the database
+func NewUnitOfWork(db *sql.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) WithTx(ctx context.Context, fn func(repos Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	repos := Repositories{
+		Jobs:      &jobRepository{db: tx},
+		Templates: &jobTemplateRepository{db: tx},
+		Estimates: &estimateRepository{db: tx},
+		Items:     &itemRepository{db: tx},
+		Suppliers: &supplierRepository{db: tx},
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// txHandle is the transaction a repository method runs its statements in.
+// When the repository is already bound to a unit of work the method joins that
+// transaction, and committing or rolling back is left to the unit of work.
+type txHandle struct {
+	executor
+	tx *sql.Tx
+}
+
+// beginTx starts a transaction on exec, or joins exec if it already is one
+func beginTx(ctx context.Context, exec executor) (*txHandle, error) {
+	db, ok := exec.(*sql.DB)
+	if !ok {
+		return &txHandle{executor: exec}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txHandle{executor: tx, tx: tx}, nil
+}
+
+func (t *txHandle) Commit() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+func (t *txHandle) Rollback() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Rollback()
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// fakeDB is an in-memory database/sql driver that counts transactions and
+// fails any statement containing failOn. Row-locking queries return the row
+// they lock, or the row in locked keyed by a fragment of the query; every
+// other query returns no rows.
+type fakeDB struct {
+	failOn    string
+	locked    map[string][]driver.Value
+	execs     []string
+	locks     []string
+	begins    int
+	commits   int
+	rollbacks int
+}
+
+func (d *fakeDB) Connect(ctx context.Context) (driver.Conn, error) { return &fakeConn{db: d}, nil }
+func (d *fakeDB) Driver() driver.Driver                            { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.begins++
+	return &fakeTx{db: c.db}, nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (t *fakeTx) Commit() error   { t.db.commits++; return nil }
+func (t *fakeTx) Rollback() error { t.db.rollbacks++; return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.db.failOn != "" && strings.Contains(s.query, s.db.failOn) {
+		return nil, errors.New("injected failure")
+	}
+	s.db.execs = append(s.db.execs, s.query)
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, " FOR ") && len(args) > 0 {
+		s.db.locks = append(s.db.locks, s.query)
+		for fragment, row := range s.db.locked {
+			if strings.Contains(s.query, fragment) {
+				return &fakeRows{rows: [][]driver.Value{row}}, nil
+			}
+		}
+		return &fakeRows{rows: [][]driver.Value{{args[0]}}}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{ rows [][]driver.Value }
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) > 0 {
+		return make([]string, len(r.rows[0]))
+	}
+	return make([]string, 1)
+}
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// createJobWithItems runs the same writes as job creation: the job, its
+// phases, its items and the first status entry
+func createJobWithItems(ctx context.Context, jobs JobRepository, job *models.Job) error {
+	if err := jobs.Create(ctx, job); err != nil {
+		return err
+	}
+	if err := jobs.SavePhases(ctx, job.ID, job.Phases); err != nil {
+		return err
+	}
+	for i := range job.Items {
+		if err := jobs.AddJobItem(ctx, job.ID, &job.Items[i]); err != nil {
+			return err
+		}
+	}
+	return jobs.AddStatusChange(ctx, models.NewJobStatusChange(job.ID, nil, job.Status, nil, "Job created"))
+}
+
+func newTestJob() *models.Job {
+	return &models.Job{
+		ID:     "job1",
+		Status: models.JobStatusScheduled,
+		Phases: []models.JobPhase{{ID: "rough", Name: "Rough", Order: 1}},
+		Items: []models.JobItem{
+			{ID: "line1", ItemID: "outlet", Name: "Outlet"},
+			{ID: "line2", ItemID: "switch", Name: "Switch"},
+		},
+	}
+}
+
+func TestUnitOfWork_WithTx(t *testing.T) {
+	tests := []struct {
+		name          string
+		failOn        string
+		wantErr       bool
+		wantCommits   int
+		wantRollbacks int
+	}{
+		{
+			name:        "all writes succeed",
+			wantCommits: 1,
+		},
+		{
+			name:          "job item insert fails",
+			failOn:        "INSERT INTO job_items",
+			wantErr:       true,
+			wantRollbacks: 1,
+		},
+		{
+			name:          "status history insert fails after items",
+			failOn:        "INSERT INTO job_status_history",
+			wantErr:       true,
+			wantRollbacks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{failOn: tt.failOn}
+			db := sql.OpenDB(fake)
+			defer db.Close()
+
+			err := NewUnitOfWork(db).WithTx(context.Background(), func(repos Repositories) error {
+				return createJobWithItems(context.Background(), repos.Jobs, newTestJob())
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			// SavePhases normally opens its own transaction; inside the unit
+			// of work it must join the outer one
+			if fake.begins != 1 {
+				t.Errorf("expected 1 transaction, got %d", fake.begins)
+			}
+			if fake.commits != tt.wantCommits {
+				t.Errorf("expected %d commits, got %d", tt.wantCommits, fake.commits)
+			}
+			if fake.rollbacks != tt.wantRollbacks {
+				t.Errorf("expected %d rollbacks, got %d", tt.wantRollbacks, fake.rollbacks)
+			}
+		})
+	}
+}
+
+func TestBeginTx_StandaloneRepository(t *testing.T) {
+	fake := &fakeDB{}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	jobs := NewJobRepository(db)
+	if err := jobs.SavePhases(context.Background(), "job1", newTestJob().Phases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fake.begins != 1 || fake.commits != 1 {
+		t.Errorf("expected a repository on its own to commit its own transaction, got %d begins and %d commits",
+			fake.begins, fake.commits)
+	}
+}
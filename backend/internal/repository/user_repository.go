@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+// UserRepository defines the interface for user and session database operations
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*models.User, error)
+	Count(ctx context.Context) (int, error)
+
+	// Session operations
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+	DeleteSession(ctx context.Context, id string) error
+	DeleteUserSessions(ctx context.Context, userID string) error
+}
+
+// ErrLastAdmin is returned when a change would leave no active admin to manage
+// the application
+var ErrLastAdmin = NewRepositoryError("last active admin", nil)
+
+type userRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create inserts a new user
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return ErrInvalidInput
+	}
+
+	query := `
+		INSERT INTO users (id, email, name, password_hash, role, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		user.ID, user.Email, user.Name, user.PasswordHash,
+		user.Role, user.IsActive, user.CreatedAt, user.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to create user", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT id, email, name, password_hash, role, is_active, created_at, updated_at
+		FROM users
+		WHERE id = $1
+	`
+
+	return r.scanUser(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetByEmail retrieves a user by email (case-insensitive)
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	email = models.NormalizeEmail(email)
+	if email == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT id, email, name, password_hash, role, is_active, created_at, updated_at
+		FROM users
+		WHERE email = $1
+	`
+
+	return r.scanUser(r.db.QueryRowContext(ctx, query, email))
+}
+
+// Update saves changes to an existing user. Demoting or deactivating the last
+// active admin returns ErrLastAdmin.
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if !user.IsAdmin() || !user.IsActive {
+		if err := checkNotLastAdmin(ctx, tx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE users SET
+			email = $2, name = $3, password_hash = $4, role = $5, is_active = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		user.ID, user.Email, user.Name, user.PasswordHash,
+		user.Role, user.IsActive, user.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicate
+		}
+		return NewRepositoryError("failed to update user", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check update result", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit user", err)
+	}
+
+	return nil
+}
+
+// Delete removes a user and, by cascade, their sessions. Deleting the last
+// active admin returns ErrLastAdmin.
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkNotLastAdmin(ctx, tx, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return NewRepositoryError("failed to delete user", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit user", err)
+	}
+
+	return nil
+}
+
+// checkNotLastAdmin returns ErrLastAdmin when the user is the only active
+// admin. The active admins stay locked for the rest of the transaction, so
+// two requests can't each remove a different one of the last two.
+func checkNotLastAdmin(ctx context.Context, tx executor, userID string) error {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id FROM users WHERE role = $1 AND is_active ORDER BY id FOR UPDATE`, models.UserRoleAdmin)
+	if err != nil {
+		return NewRepositoryError("failed to lock admins", err)
+	}
+	defer rows.Close()
+
+	var admins int
+	isAdmin := false
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return NewRepositoryError("failed to scan admin", err)
+		}
+		admins++
+		isAdmin = isAdmin || id == userID
+	}
+	if err := rows.Err(); err != nil {
+		return NewRepositoryError("error iterating admins", err)
+	}
+
+	if isAdmin && admins == 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// List returns all users ordered by email
+func (r *userRepository) List(ctx context.Context) ([]*models.User, error) {
+	query := `
+		SELECT id, email, name, password_hash, role, is_active, created_at, updated_at
+		FROM users
+		ORDER BY email
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list users", err)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.Name, &user.PasswordHash,
+			&user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan user", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, NewRepositoryError("error iterating users", err)
+	}
+
+	return users, nil
+}
+
+// Count returns the number of users
+func (r *userRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return 0, NewRepositoryError("failed to count users", err)
+	}
+	return count, nil
+}
+
+// CreateSession inserts a new session
+func (r *userRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO user_sessions (id, user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID, session.UserID, session.ExpiresAt, session.CreatedAt,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to create session", err)
+	}
+
+	return nil
+}
+
+// GetSession retrieves a session by its token hash
+func (r *userRepository) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	query := `
+		SELECT id, user_id, expires_at, created_at
+		FROM user_sessions
+		WHERE id = $1
+	`
+
+	session := &models.Session{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&session.ID, &session.UserID, &session.ExpiresAt, &session.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, NewRepositoryError("failed to get session", err)
+	}
+
+	return session, nil
+}
+
+// DeleteSession removes a single session
+func (r *userRepository) DeleteSession(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_sessions WHERE id = $1`, id); err != nil {
+		return NewRepositoryError("failed to delete session", err)
+	}
+	return nil
+}
+
+// DeleteUserSessions removes every session belonging to a user
+func (r *userRepository) DeleteUserSessions(ctx context.Context, userID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_sessions WHERE user_id = $1`, userID); err != nil {
+		return NewRepositoryError("failed to delete user sessions", err)
+	}
+	return nil
+}
+
+// scanUser scans a single user row
+func (r *userRepository) scanUser(row *sql.Row) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(
+		&user.ID, &user.Email, &user.Name, &user.PasswordHash,
+		&user.Role, &user.IsActive, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, NewRepositoryError("failed to get user", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+func TestUserRepository_KeepsLastActiveAdmin(t *testing.T) {
+	// The only active admin is "owner"
+	newFake := func() *fakeDB {
+		return &fakeDB{locked: map[string][]driver.Value{"FROM users": {"owner"}}}
+	}
+
+	tests := []struct {
+		name    string
+		write   func(ctx context.Context, users UserRepository) error
+		wantErr error
+	}{
+		{
+			name:    "delete the last admin",
+			write:   func(ctx context.Context, users UserRepository) error { return users.Delete(ctx, "owner") },
+			wantErr: ErrLastAdmin,
+		},
+		{
+			name: "demote the last admin",
+			write: func(ctx context.Context, users UserRepository) error {
+				return users.Update(ctx, &models.User{ID: "owner", Role: models.UserRoleTech, IsActive: true})
+			},
+			wantErr: ErrLastAdmin,
+		},
+		{
+			name: "deactivate the last admin",
+			write: func(ctx context.Context, users UserRepository) error {
+				return users.Update(ctx, &models.User{ID: "owner", Role: models.UserRoleAdmin})
+			},
+			wantErr: ErrLastAdmin,
+		},
+		{
+			name:  "delete a tech",
+			write: func(ctx context.Context, users UserRepository) error { return users.Delete(ctx, "tech1") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFake()
+			db := sql.OpenDB(fake)
+			defer db.Close()
+
+			err := tt.write(context.Background(), NewUserRepository(db))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(fake.locks) != 1 {
+				t.Errorf("expected the active admins to be locked, got %q", fake.locks)
+			}
+			if tt.wantErr != nil && (len(fake.execs) != 0 || fake.commits != 0) {
+				t.Errorf("expected nothing to be written, got %q and %d commits", fake.execs, fake.commits)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/voltagedrop"
+)
+
+// WireItemRepository defines the interface for the links between conductor
+// sizes and the catalog items that sell them
+type WireItemRepository interface {
+	Set(ctx context.Context, wire *models.WireItem) error
+	GetBySize(ctx context.Context, material voltagedrop.Material, size string) (*models.WireItem, error)
+	List(ctx context.Context) ([]models.WireItem, error)
+	Delete(ctx context.Context, itemID string) error
+}
+
+type wireItemRepository struct {
+	db executor
+}
+
+// NewWireItemRepository creates a new wire item repository
+func NewWireItemRepository(db *sql.DB) WireItemRepository {
+	return &wireItemRepository{db: db}
+}
+
+const wireItemSelect = `
+	SELECT w.item_id, i.name, w.material, w.size, w.created_at
+	FROM wire_items w
+	JOIN items i ON i.id = w.item_id
+`
+
+// Set links an item to a conductor material and size, replacing the item
+// the size was linked to and the size the item was linked to
+func (r *wireItemRepository) Set(ctx context.Context, wire *models.WireItem) error {
+	if wire == nil || wire.ItemID == "" {
+		return ErrInvalidInput
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return NewRepositoryError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM wire_items WHERE item_id = $1 OR (material = $2 AND size = $3)`,
+		wire.ItemID, wire.Material, wire.Size,
+	)
+	if err != nil {
+		return NewRepositoryError("failed to clear wire item", err)
+	}
+
+	query := `
+		INSERT INTO wire_items (item_id, material, size, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.ExecContext(ctx, query, wire.ItemID, wire.Material, wire.Size, wire.CreatedAt); err != nil {
+		if isForeignKeyError(err) {
+			return ErrNotFound
+		}
+		return NewRepositoryError("failed to save wire item", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return NewRepositoryError("failed to commit wire item", err)
+	}
+
+	return nil
+}
+
+// GetBySize retrieves the item linked to a conductor material and size
+func (r *wireItemRepository) GetBySize(ctx context.Context, material voltagedrop.Material, size string) (*models.WireItem, error) {
+	query := wireItemSelect + ` WHERE w.material = $1 AND w.size = $2`
+
+	wire, err := scanWireItem(r.db.QueryRowContext(ctx, query, material, size))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, NewRepositoryError("failed to get wire item", err)
+	}
+
+	return wire, nil
+}
+
+// List returns every wire item by material and item name
+func (r *wireItemRepository) List(ctx context.Context) ([]models.WireItem, error) {
+	rows, err := r.db.QueryContext(ctx, wireItemSelect+` ORDER BY w.material, i.name`)
+	if err != nil {
+		return nil, NewRepositoryError("failed to list wire items", err)
+	}
+	defer rows.Close()
+
+	wires := []models.WireItem{}
+	for rows.Next() {
+		wire, err := scanWireItem(rows)
+		if err != nil {
+			return nil, NewRepositoryError("failed to scan wire item", err)
+		}
+		wires = append(wires, *wire)
+	}
+
+	return wires, rows.Err()
+}
+
+// Delete unlinks an item from its conductor size
+func (r *wireItemRepository) Delete(ctx context.Context, itemID string) error {
+	if itemID == "" {
+		return ErrInvalidInput
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM wire_items WHERE item_id = $1`, itemID)
+	if err != nil {
+		return NewRepositoryError("failed to delete wire item", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return NewRepositoryError("failed to check delete result", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func scanWireItem(row rowScanner) (*models.WireItem, error) {
+	var wire models.WireItem
+	err := row.Scan(&wire.ItemID, &wire.ItemName, &wire.Material, &wire.Size, &wire.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &wire, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+	"github.com/masterbrent/electrical-bidding-app/internal/repository"
+)
+
+// Authentication errors
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidSession     = errors.New("session is invalid or has expired")
+)
+
+// DefaultSessionTTL is how long a login stays valid when no TTL is configured
+const DefaultSessionTTL = 7 * 24 * time.Hour
+
+// AuthService implements login, logout and session validation
+type AuthService struct {
+	users      repository.UserRepository
+	sessionTTL time.Duration
+	now        func() time.Time
+}
+
+// NewAuthService creates a new auth service
+func NewAuthService(users repository.UserRepository, sessionTTL time.Duration) *AuthService {
+	if sessionTTL <= 0 {
+		sessionTTL = DefaultSessionTTL
+	}
+	return &AuthService{
+		users:      users,
+		sessionTTL: sessionTTL,
+		now:        time.Now,
+	}
+}
+
+// Login checks the credentials and starts a new session. The returned token is
+// only ever handed to the client; the database stores its hash.
+func (s *AuthService) Login(ctx context.Context, email, password string) (string, *models.User, time.Time, error) {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return "", nil, time.Time{}, ErrInvalidCredentials
+		}
+		return "", nil, time.Time{}, err
+	}
+
+	if !user.IsActive || !user.CheckPassword(password) {
+		return "", nil, time.Time{}, ErrInvalidCredentials
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+
+	now := s.now()
+	session := &models.Session{
+		ID:        HashSessionToken(token),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(s.sessionTTL),
+		CreatedAt: now,
+	}
+
+	if err := s.users.CreateSession(ctx, session); err != nil {
+		return "", nil, time.Time{}, err
+	}
+
+	return token, user, session.ExpiresAt, nil
+}
+
+// Logout ends the session identified by token
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.users.DeleteSession(ctx, HashSessionToken(token))
+}
+
+// Authenticate resolves a bearer token to an active user
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+
+	session, err := s.users.GetSession(ctx, HashSessionToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	if session.IsExpired(s.now()) {
+		s.users.DeleteSession(ctx, session.ID)
+		return nil, ErrInvalidSession
+	}
+
+	user, err := s.users.GetByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, ErrInvalidSession
+	}
+
+	return user, nil
+}
+
+// ChangePassword verifies the current password, sets the new one and signs the
+// user out of every other session.
+func (s *AuthService) ChangePassword(ctx context.Context, user *models.User, currentPassword, newPassword string) error {
+	if !user.CheckPassword(currentPassword) {
+		return ErrInvalidCredentials
+	}
+
+	if err := user.SetPassword(newPassword); err != nil {
+		return err
+	}
+
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+
+	return s.users.DeleteUserSessions(ctx, user.ID)
+}
+
+// EnsureAdmin creates an initial admin account when the users table is empty,
+// so a fresh install can be signed in to.
+func (s *AuthService) EnsureAdmin(ctx context.Context, email, password string) error {
+	if email == "" || password == "" {
+		return nil
+	}
+
+	count, err := s.users.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	admin, err := models.NewUser(email, "Administrator", models.UserRoleAdmin, password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.users.Create(ctx, admin); err != nil {
+		return err
+	}
+
+	log.Printf("Created initial admin user %s", admin.Email)
+	return nil
+}
+
+// HashSessionToken returns the value stored in user_sessions.id for a token
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSessionToken generates a random opaque bearer token
+func newSessionToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
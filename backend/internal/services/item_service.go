@@ -2,6 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/masterbrent/electrical-bidding-app/internal/models"
 	"github.com/masterbrent/electrical-bidding-app/internal/repository"
@@ -10,17 +16,19 @@ import (
 // ItemService implements business logic for items
 type ItemService struct {
 	repo repository.ItemRepository
+	uow  repository.UnitOfWork
 }
 
 // NewItemService creates a new item service
-func NewItemService(repo repository.ItemRepository) *ItemService {
+func NewItemService(repo repository.ItemRepository, uow repository.UnitOfWork) *ItemService {
 	return &ItemService{
 		repo: repo,
+		uow:  uow,
 	}
 }
 
 // Create creates a new item
-func (s *ItemService) Create(ctx context.Context, name, unit string, unitPrice float64, category string) (*models.Item, error) {
+func (s *ItemService) Create(ctx context.Context, name, unit string, unitPrice models.Money, category string) (*models.Item, error) {
 	// Create the item with validation
 	item, err := models.NewItem(name, unit, unitPrice, category)
 	if err != nil {
@@ -59,4 +67,201 @@ func (s *ItemService) Update(ctx context.Context, id string, updates map[string]
 // Delete removes an item
 func (s *ItemService) Delete(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
-}
\ No newline at end of file
+}
+
+// CreateAssembly creates an assembly from a draft item. The draft's components
+// need only an item ID and quantity; the rest is filled in from the catalog.
+func (s *ItemService) CreateAssembly(ctx context.Context, draft *models.Item) (*models.Item, error) {
+	components, err := s.assemblyComponents(ctx, draft.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := models.NewAssembly(draft.Name, draft.Unit, draft.Category, components, draft.PriceOverride, draft.ExpandOnInvoice)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	item.Nickname = draft.Nickname
+
+	err = s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Items.Create(ctx, item); err != nil {
+			return err
+		}
+		return repos.Items.SaveAssembly(ctx, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// UpdateAssembly replaces an assembly's details, components, price override
+// and invoice setting with the draft's, recording any change to its price.
+// Jobs keep the price the assembly had when it was added to them.
+func (s *ItemService) UpdateAssembly(ctx context.Context, id string, draft *models.Item) (*models.Item, error) {
+	item, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsAssembly() {
+		return nil, repository.ErrNotFound
+	}
+	previousPrice := item.UnitPrice
+
+	components, err := s.assemblyComponents(ctx, draft.Components)
+	if err != nil {
+		return nil, err
+	}
+	if err := item.SetComponents(components, draft.PriceOverride); err != nil {
+		return nil, invalid(err)
+	}
+	item.ExpandOnInvoice = draft.ExpandOnInvoice
+
+	updates := map[string]interface{}{
+		"name":     draft.Name,
+		"nickname": draft.Nickname,
+		"unit":     draft.Unit,
+		"category": draft.Category,
+	}
+	if err := item.Update(updates); err != nil {
+		return nil, invalid(err)
+	}
+
+	// Update reads the price back from the saved components, so it can't see
+	// the change; record it here
+	err = s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		if err := repos.Items.SaveAssembly(ctx, item); err != nil {
+			return err
+		}
+		if err := repos.Items.Update(ctx, id, updates); err != nil {
+			return err
+		}
+		if item.UnitPrice == previousPrice {
+			return nil
+		}
+		return repos.Items.AddPriceChange(ctx, models.NewItemPriceChange(id, previousPrice, item.UnitPrice, item.UpdatedAt))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// assemblyComponents looks up the items a draft assembly is made of
+func (s *ItemService) assemblyComponents(ctx context.Context, drafts []models.AssemblyComponent) ([]models.AssemblyComponent, error) {
+	components := make([]models.AssemblyComponent, 0, len(drafts))
+	for _, draft := range drafts {
+		item, err := s.repo.GetByID(ctx, draft.ItemID)
+		if errors.Is(err, repository.ErrNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return nil, invalid(fmt.Errorf("component item %q not found", draft.ItemID))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		component, err := models.NewAssemblyComponent(item, draft.Quantity)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+// AddCost records a supplier cost for an item from effectiveFrom onward
+func (s *ItemService) AddCost(ctx context.Context, itemID, supplier string, cost models.Money, effectiveFrom time.Time) (*models.ItemCost, error) {
+	// Make sure the item exists
+	if _, err := s.repo.GetByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	itemCost, err := models.NewItemCost(itemID, supplier, cost, effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddCost(ctx, itemCost); err != nil {
+		return nil, err
+	}
+
+	return itemCost, nil
+}
+
+// ListCosts returns an item's cost history, newest first
+func (s *ItemService) ListCosts(ctx context.Context, itemID string) ([]models.ItemCost, error) {
+	// Make sure the item exists
+	if _, err := s.repo.GetByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListCosts(ctx, itemID)
+}
+
+// ListPriceHistory returns the changes to an item's price, newest first
+func (s *ItemService) ListPriceHistory(ctx context.Context, itemID string) ([]models.ItemPriceChange, error) {
+	// Make sure the item exists
+	if _, err := s.repo.GetByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListPriceHistory(ctx, itemID)
+}
+
+// Export writes the catalog as CSV, sorted by name
+func (s *ItemService) Export(ctx context.Context, w io.Writer) error {
+	items, err := s.repo.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+	return models.WriteItemsCSV(w, items)
+}
+
+// Import plans a catalog CSV against the current items and, when apply is set
+// and every row is valid, creates and updates the items in one transaction
+func (s *ItemService) Import(ctx context.Context, r io.Reader, apply bool) (*models.ItemImport, error) {
+	existing, err := s.repo.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := models.PlanItemImport(r, existing)
+	if err != nil {
+		return nil, err
+	}
+	if !apply || plan.Invalid > 0 {
+		return plan, nil
+	}
+
+	err = s.uow.WithTx(ctx, func(repos repository.Repositories) error {
+		for _, row := range plan.Rows {
+			switch row.Action {
+			case models.ItemImportCreate:
+				if err := repos.Items.Create(ctx, row.Item); err != nil {
+					return err
+				}
+			case models.ItemImportUpdate:
+				if err := repos.Items.Update(ctx, row.ItemID, row.Updates); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Applied = true
+	return plan, nil
+}
+
+// invalid marks err as a problem with what was asked for, rather than a failure
+func invalid(err error) error {
+	return fmt.Errorf("%w: %s", models.ErrValidation, err)
+}
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
 )
 
 const WAVE_API_ENDPOINT = "https://gql.waveapps.com/graphql/public"
@@ -21,13 +24,234 @@ type WaveCredentials struct {
 	BusinessID string
 }
 
-// LineItem represents an invoice line item
+// LineItem represents an invoice line item. Quantity keeps its decimals so
+// feet of wire and hours of labor are billed as counted. SalesTaxIDs are the
+// Wave sales taxes charged on the line.
 type LineItem struct {
-	ProductName string  `json:"productName"`
-	Description string  `json:"description,omitempty"`
-	Quantity    int     `json:"quantity"`
-	Price       float64 `json:"price"`
-	Total       float64 `json:"total"`
+	ItemID      string       `json:"itemId,omitempty"`
+	ProductName string       `json:"productName"`
+	Description string       `json:"description,omitempty"`
+	Quantity    float64      `json:"quantity"`
+	Price       models.Money `json:"price"`
+	Total       models.Money `json:"total"`
+	SalesTaxIDs []string     `json:"salesTaxIds,omitempty"`
+}
+
+// InvoiceTotal is what Wave bills for the line: quantity, to four decimal
+// places as expanded assembly parts carry, times unit price, rounded to the cent
+func (l LineItem) InvoiceTotal() models.Money {
+	return l.Price.MulFineQuantity(l.Quantity)
+}
+
+// ExpandAssemblyLine lists an assembly's invoice line as its parts when the
+// assembly is set to expand on invoices, and returns the line as it is
+// otherwise. Each part is billed at its current price for its quantity per
+// assembly times the line's quantity, and carries the line's sales taxes.
+// Whatever the parts don't add up to, from a price override or prices that
+// moved since the line was added, is billed on a final line under the
+// assembly's name, so the expanded lines always total the original.
+func ExpandAssemblyLine(line LineItem, assembly *models.Item) []LineItem {
+	if assembly == nil || !assembly.IsAssembly() || !assembly.ExpandOnInvoice {
+		return []LineItem{line}
+	}
+
+	description := "Part of " + assembly.Name
+	if line.Description != "" {
+		description = line.Description + " - " + assembly.Name
+	}
+
+	// Credits are listed with negative prices, and so are their parts
+	sign := models.Money(1)
+	if line.Price < 0 {
+		sign = -1
+	}
+
+	lines := make([]LineItem, 0, len(assembly.Components)+1)
+	remainder := line.Total
+	for _, component := range assembly.Components {
+		quantity := math.Round(component.Quantity*line.Quantity*10000) / 10000
+		price := sign * component.UnitPrice
+		total := price.MulFineQuantity(quantity)
+		lines = append(lines, LineItem{
+			ItemID:      component.ItemID,
+			ProductName: component.Name,
+			Description: description,
+			Quantity:    quantity,
+			Price:       price,
+			Total:       total,
+			SalesTaxIDs: line.SalesTaxIDs,
+		})
+		remainder -= total
+	}
+
+	if remainder != 0 {
+		lines = append(lines, LineItem{
+			ItemID:      line.ItemID,
+			ProductName: line.ProductName,
+			Description: "Assembly pricing",
+			Quantity:    1,
+			Price:       remainder,
+			Total:       remainder,
+			SalesTaxIDs: line.SalesTaxIDs,
+		})
+	}
+
+	return lines
+}
+
+// SplitLaborLine bills the labor in a line on a line of its own, after the
+// line's materials: hoursPerUnit hours for each unit of the line at
+// hourlyRate. The labor never takes more than the line's price, and carries
+// the line's sales taxes so splitting it out doesn't change the tax. Whatever
+// rounding leaves over is billed on a final line, so the split lines always
+// total the original.
+func SplitLaborLine(line LineItem, hoursPerUnit float64, hourlyRate models.Money) []LineItem {
+	labor := models.LaborCharge(hourlyRate, hoursPerUnit)
+	if labor <= 0 || line.Price == 0 {
+		return []LineItem{line}
+	}
+
+	// Credits are listed with negative prices, and so is their labor
+	if line.Price < 0 {
+		labor = -labor
+		if labor < line.Price {
+			labor = line.Price
+		}
+	} else if labor > line.Price {
+		labor = line.Price
+	}
+
+	materials := line
+	materials.Price = line.Price - labor
+	materials.Total = materials.InvoiceTotal()
+
+	description := fmt.Sprintf("%s: %g hr each at $%s/hr", line.ProductName, hoursPerUnit, hourlyRate)
+	if line.Description != "" {
+		description = line.Description + " - " + description
+	}
+	laborLine := LineItem{
+		ItemID:      line.ItemID,
+		ProductName: "Labor",
+		Description: description,
+		Quantity:    line.Quantity,
+		Price:       labor,
+		SalesTaxIDs: line.SalesTaxIDs,
+	}
+	laborLine.Total = laborLine.InvoiceTotal()
+
+	lines := []LineItem{materials, laborLine}
+	if remainder := line.Total - materials.Total - laborLine.Total; remainder != 0 {
+		lines = append(lines, LineItem{
+			ItemID:      line.ItemID,
+			ProductName: line.ProductName,
+			Description: "Rounding",
+			Quantity:    1,
+			Price:       remainder,
+			Total:       remainder,
+			SalesTaxIDs: line.SalesTaxIDs,
+		})
+	}
+
+	return lines
+}
+
+// InvoiceLaborLines bills a line for item with its labor split out at
+// hourlyRate and the difficulty, which may be nil. Assemblies set to expand on
+// invoices are expanded first and each part's labor is split from it.
+func InvoiceLaborLines(line LineItem, item *models.Item, hourlyRate models.Money, difficulty *models.LaborDifficulty) []LineItem {
+	if item == nil {
+		return []LineItem{line}
+	}
+	if !item.IsAssembly() || !item.ExpandOnInvoice {
+		return SplitLaborLine(line, difficulty.Hours(item.LaborHours), hourlyRate)
+	}
+
+	hours := make(map[string]float64, len(item.Components))
+	for _, component := range item.Components {
+		hours[component.ItemID] = difficulty.Hours(component.LaborHours)
+	}
+
+	var lines []LineItem
+	for _, part := range ExpandAssemblyLine(line, item) {
+		// The assembly pricing line is not a part and has no labor of its own
+		partHours, ok := hours[part.ItemID]
+		if !ok {
+			lines = append(lines, part)
+			continue
+		}
+		lines = append(lines, SplitLaborLine(part, partHours, hourlyRate)...)
+	}
+	return lines
+}
+
+// LineItemMismatch is a line whose local total differs from what Wave will bill
+type LineItemMismatch struct {
+	ProductName  string       `json:"productName"`
+	Description  string       `json:"description,omitempty"`
+	Quantity     float64      `json:"quantity"`
+	LocalTotal   models.Money `json:"localTotal"`
+	InvoiceTotal models.Money `json:"invoiceTotal"`
+}
+
+// InvoiceReconciliation compares the total Wave bills for an invoice with the
+// job's total here. WaveTotal is the total Wave reported once the invoice was
+// created, when it reported one.
+type InvoiceReconciliation struct {
+	LocalTotal   models.Money       `json:"localTotal"`
+	InvoiceTotal models.Money       `json:"invoiceTotal"`
+	WaveTotal    *models.Money      `json:"waveTotal,omitempty"`
+	Difference   models.Money       `json:"difference"`
+	Lines        []LineItemMismatch `json:"lines,omitempty"`
+}
+
+// ReconcileInvoice checks the lines about to be invoiced, plus the sales tax
+// Wave will charge on them, against the local total
+func ReconcileInvoice(lineItems []LineItem, invoiceTax, localTotal models.Money) InvoiceReconciliation {
+	reconciliation := InvoiceReconciliation{LocalTotal: localTotal, InvoiceTotal: invoiceTax}
+
+	for _, item := range lineItems {
+		invoiceTotal := item.InvoiceTotal()
+		reconciliation.InvoiceTotal += invoiceTotal
+		if invoiceTotal != item.Total {
+			reconciliation.Lines = append(reconciliation.Lines, LineItemMismatch{
+				ProductName:  item.ProductName,
+				Description:  item.Description,
+				Quantity:     item.Quantity,
+				LocalTotal:   item.Total,
+				InvoiceTotal: invoiceTotal,
+			})
+		}
+	}
+
+	reconciliation.Difference = reconciliation.InvoiceTotal - localTotal
+	return reconciliation
+}
+
+// SetWaveTotal records the total Wave reported for the created invoice
+func (r *InvoiceReconciliation) SetWaveTotal(total models.Money) {
+	r.WaveTotal = &total
+	r.Difference = total - r.LocalTotal
+}
+
+// Matches reports whether the invoice bills exactly the local total
+func (r *InvoiceReconciliation) Matches() bool {
+	return r.Difference == 0 && len(r.Lines) == 0
+}
+
+// Warning describes how the invoice differs from the local total, or is empty
+// when they match
+func (r *InvoiceReconciliation) Warning() string {
+	if r.Matches() {
+		return ""
+	}
+	billed := r.InvoiceTotal
+	if r.WaveTotal != nil {
+		billed = *r.WaveTotal
+	}
+	if r.Difference == 0 {
+		return fmt.Sprintf("Wave invoice total %s matches the job total, but %d line(s) differ", billed, len(r.Lines))
+	}
+	return fmt.Sprintf("Wave invoice total %s does not match the job total %s (off by %s)", billed, r.LocalTotal, r.Difference)
 }
 
 // WaveProduct represents a product in Wave
@@ -44,9 +268,10 @@ type WaveCustomer struct {
 
 // WaveInvoice represents a created invoice
 type WaveInvoice struct {
-	ID            string `json:"id"`
-	InvoiceNumber string `json:"invoiceNumber"`
-	ViewURL       string `json:"viewUrl"`
+	ID            string        `json:"id"`
+	InvoiceNumber string        `json:"invoiceNumber"`
+	ViewURL       string        `json:"viewUrl"`
+	Total         *models.Money `json:"total,omitempty"`
 }
 
 // WaveAPIService handles all Wave API interactions
@@ -476,8 +701,8 @@ func (w *WaveAPIService) CreateInvoice(customerID string, lineItems []LineItem,
 		
 		invoiceItem := map[string]interface{}{
 			"productId": productID,
-			"quantity":  item.Quantity,
-			"unitPrice": fmt.Sprintf("%.2f", item.Price),
+			"quantity":  strconv.FormatFloat(item.Quantity, 'f', -1, 64),
+			"unitPrice": item.Price.String(),
 		}
 		
 		// Add description if provided
@@ -485,6 +710,14 @@ func (w *WaveAPIService) CreateInvoice(customerID string, lineItems []LineItem,
 			invoiceItem["description"] = item.Description
 		}
 		
+		if len(item.SalesTaxIDs) > 0 {
+			var taxes []map[string]interface{}
+			for _, salesTaxID := range item.SalesTaxIDs {
+				taxes = append(taxes, map[string]interface{}{"salesTaxId": salesTaxID})
+			}
+			invoiceItem["taxes"] = taxes
+		}
+		
 		invoiceItems = append(invoiceItems, invoiceItem)
 	}
 	
@@ -497,6 +730,9 @@ func (w *WaveAPIService) CreateInvoice(customerID string, lineItems []LineItem,
 					invoiceNumber
 					viewUrl 
 					status 
+					total {
+						value
+					}
 				}
 				didSucceed
 				inputErrors {
@@ -555,11 +791,22 @@ func (w *WaveAPIService) CreateInvoice(customerID string, lineItems []LineItem,
 	invoiceNumber, _ := invoice["invoiceNumber"].(string)
 	viewURL, _ := invoice["viewUrl"].(string)
 	
-	return &WaveInvoice{
+	created := &WaveInvoice{
 		ID:            id,
 		InvoiceNumber: invoiceNumber,
 		ViewURL:       viewURL,
-	}, nil
+	}
+	
+	// Wave reports the total as a decimal string
+	if total, ok := invoice["total"].(map[string]interface{}); ok {
+		if value, ok := total["value"].(string); ok {
+			if amount, err := models.ParseMoney(value); err == nil {
+				created.Total = &amount
+			}
+		}
+	}
+	
+	return created, nil
 }
 
 // GetWaveCredentials gets Wave credentials from environment
@@ -652,7 +899,7 @@ func PrepareJobForWaveInvoice(job map[string]interface{}) (string, []LineItem, e
 			if isCustom {
 				// Custom items - use "Custom Work" as product name
 				customPrice, _ := jobItem["customPrice"].(string)
-				price, _ := strconv.ParseFloat(customPrice, 64)
+				price, _ := models.ParseMoney(customPrice)
 				
 				description := ""
 				if desc, ok := jobItem["customDescription"].(string); ok && desc != "" {
@@ -664,9 +911,9 @@ func PrepareJobForWaveInvoice(job map[string]interface{}) (string, []LineItem, e
 				lineItems = append(lineItems, LineItem{
 					ProductName: "Custom Work",
 					Description: description,
-					Quantity:    int(completedQty),
+					Quantity:    completedQty,
 					Price:       price,
-					Total:       completedQty * price,
+					Total:       price.MulQuantity(completedQty),
 				})
 			} else {
 				// Template items
@@ -677,15 +924,15 @@ func PrepareJobForWaveInvoice(job map[string]interface{}) (string, []LineItem, e
 				
 				name, _ := item["name"].(string)
 				priceStr, _ := item["price"].(string)
-				price, _ := strconv.ParseFloat(priceStr, 64)
+				price, _ := models.ParseMoney(priceStr)
 				description, _ := item["description"].(string)
 				
 				lineItems = append(lineItems, LineItem{
 					ProductName: name,
 					Description: description,
-					Quantity:    int(completedQty),
+					Quantity:    completedQty,
 					Price:       price,
-					Total:       completedQty * price,
+					Total:       price.MulQuantity(completedQty),
 				})
 			}
 		}
@@ -694,7 +941,7 @@ func PrepareJobForWaveInvoice(job map[string]interface{}) (string, []LineItem, e
 	// Add Electrical Permit if required
 	if permitRequired, ok := job["permitRequired"].(bool); ok && permitRequired {
 		// Default permit price is $250 unless specified differently
-		permitPrice := 250.0
+		permitPrice := models.Cents(25000)
 		
 		lineItems = append(lineItems, LineItem{
 			ProductName: "Electrical Permit",
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/masterbrent/electrical-bidding-app/internal/models"
+)
+
+func TestReconcileInvoice(t *testing.T) {
+	lineItems := []LineItem{
+		{ProductName: "12/2 Wire", Quantity: 12.5, Price: models.Cents(89), Total: models.Cents(1113)},
+		{ProductName: "Labor", Quantity: 1.25, Price: models.Cents(9500), Total: models.Cents(11875)},
+	}
+
+	t.Run("matching totals", func(t *testing.T) {
+		reconciliation := ReconcileInvoice(lineItems, 0, models.Cents(12988))
+		if !reconciliation.Matches() {
+			t.Errorf("expected invoice to match, got %+v", reconciliation)
+		}
+		if reconciliation.Warning() != "" {
+			t.Errorf("expected no warning, got %q", reconciliation.Warning())
+		}
+	})
+
+	t.Run("truncated quantity", func(t *testing.T) {
+		truncated := append([]LineItem(nil), lineItems...)
+		truncated[0].Quantity = 12
+
+		reconciliation := ReconcileInvoice(truncated, 0, models.Cents(12988))
+		if reconciliation.Matches() {
+			t.Fatal("expected a mismatch")
+		}
+		if reconciliation.Difference != models.Cents(-45) {
+			t.Errorf("expected difference -0.45, got %s", reconciliation.Difference)
+		}
+		if len(reconciliation.Lines) != 1 || reconciliation.Lines[0].ProductName != "12/2 Wire" {
+			t.Errorf("expected the wire line to be reported, got %+v", reconciliation.Lines)
+		}
+		if reconciliation.Warning() == "" {
+			t.Error("expected a warning")
+		}
+	})
+
+	t.Run("Wave reports a different total", func(t *testing.T) {
+		reconciliation := ReconcileInvoice(lineItems, 0, models.Cents(12988))
+		reconciliation.SetWaveTotal(models.Cents(12989))
+		if reconciliation.Matches() || reconciliation.Difference != models.Cents(1) {
+			t.Errorf("expected a one cent difference, got %+v", reconciliation)
+		}
+	})
+}
+
+func TestReconcileInvoiceWithTax(t *testing.T) {
+	lineItems := []LineItem{
+		{ProductName: "Outlet", Quantity: 10, Price: models.Cents(1500), Total: models.Cents(15000), SalesTaxIDs: []string{"state"}},
+		{ProductName: "Labor", Quantity: 2, Price: models.Cents(9500), Total: models.Cents(19000)},
+	}
+
+	reconciliation := ReconcileInvoice(lineItems, models.Cents(938), models.Cents(34938))
+	if !reconciliation.Matches() {
+		t.Errorf("expected tax to be included in the invoice total, got %+v", reconciliation)
+	}
+
+	// A rate without a Wave sales tax ID is not charged by Wave
+	reconciliation = ReconcileInvoice(lineItems, 0, models.Cents(34938))
+	if reconciliation.Matches() || reconciliation.Difference != models.Cents(-938) {
+		t.Errorf("expected the uncharged tax to be reported, got %+v", reconciliation)
+	}
+}
+
+func TestExpandAssemblyLine(t *testing.T) {
+	override := models.Cents(1800)
+	assembly := &models.Item{
+		ID:   "outlet-install",
+		Name: "Outlet Install",
+		Components: []models.AssemblyComponent{
+			{ItemID: "box", Name: "Box", Quantity: 1, UnitPrice: models.Cents(300)},
+			{ItemID: "wire", Name: "12/2 Wire", Quantity: 12.5, UnitPrice: models.Cents(89)},
+		},
+		PriceOverride: &override,
+	}
+	assembly.PriceAssembly()
+
+	line := LineItem{
+		ItemID:      assembly.ID,
+		ProductName: assembly.Name,
+		Quantity:    3,
+		Price:       assembly.UnitPrice,
+		Total:       assembly.UnitPrice.MulQuantity(3),
+		SalesTaxIDs: []string{"tax-1"},
+	}
+
+	if lines := ExpandAssemblyLine(line, assembly); len(lines) != 1 || lines[0].ProductName != assembly.Name {
+		t.Fatalf("expected the assembly to stay collapsed, got %+v", lines)
+	}
+
+	assembly.ExpandOnInvoice = true
+	lines := ExpandAssemblyLine(line, assembly)
+	if len(lines) != 3 {
+		t.Fatalf("expected two parts and a pricing line, got %+v", lines)
+	}
+	if lines[0].Quantity != 3 || lines[1].Quantity != 37.5 || lines[1].Total != models.Cents(3338) {
+		t.Errorf("expected 3 boxes and 37.5 ft of wire, got %+v", lines[:2])
+	}
+	// 54.00 for three installs less 9.00 of boxes and 33.38 of wire
+	if lines[2].ProductName != assembly.Name || lines[2].Total != models.Cents(1162) {
+		t.Errorf("expected 11.62 of assembly pricing, got %+v", lines[2])
+	}
+	reconciliation := ReconcileInvoice(lines, 0, line.Total)
+	if !reconciliation.Matches() {
+		t.Errorf("expected the expanded lines to bill the line total, got %+v", reconciliation)
+	}
+	for _, expanded := range lines {
+		if len(expanded.SalesTaxIDs) != 1 {
+			t.Errorf("expected %s to be taxed like the assembly", expanded.ProductName)
+		}
+	}
+
+	// Without an override only rounding is left over: the assembly prices
+	// 12.5 ft of wire at 11.13, and two of them bill 25 ft at 22.25
+	assembly.PriceOverride = nil
+	assembly.PriceAssembly()
+	credit := LineItem{ItemID: assembly.ID, ProductName: assembly.Name, Quantity: 2, Price: -assembly.UnitPrice, Total: -assembly.UnitPrice.MulQuantity(2)}
+	lines = ExpandAssemblyLine(credit, assembly)
+	if len(lines) != 3 || lines[0].Price != models.Cents(-300) || lines[2].Total != models.Cents(-1) {
+		t.Fatalf("expected two credited parts and a cent of rounding, got %+v", lines)
+	}
+	if reconciliation := ReconcileInvoice(lines, 0, credit.Total); !reconciliation.Matches() {
+		t.Errorf("expected the credited parts to bill the credit, got %+v", reconciliation)
+	}
+}
+
+func TestExpandAssemblyLine_FractionalPart(t *testing.T) {
+	assembly := &models.Item{
+		ID:   "fixture-install",
+		Name: "Fixture Install",
+		Components: []models.AssemblyComponent{
+			{ItemID: "box", Name: "Box", Quantity: 1, UnitPrice: models.Cents(300)},
+			{ItemID: "connectors", Name: "Wire Connectors (box of 8)", Quantity: 0.125, UnitPrice: models.Cents(800)},
+		},
+		ExpandOnInvoice: true,
+	}
+	assembly.PriceAssembly()
+
+	// An eighth of a box of connectors is 1.00, not 0.13 of a box
+	if assembly.UnitPrice != models.Cents(400) {
+		t.Fatalf("expected the assembly to price at 4.00, got %s", assembly.UnitPrice)
+	}
+
+	line := LineItem{ItemID: assembly.ID, ProductName: assembly.Name, Quantity: 3, Price: assembly.UnitPrice, Total: models.Cents(1200)}
+	lines := ExpandAssemblyLine(line, assembly)
+	if len(lines) != 2 {
+		t.Fatalf("expected two parts and no pricing line, got %+v", lines)
+	}
+	if lines[1].Quantity != 0.375 || lines[1].Total != models.Cents(300) || lines[1].InvoiceTotal() != lines[1].Total {
+		t.Errorf("expected 0.375 boxes of connectors billed at 3.00, got %+v", lines[1])
+	}
+	if reconciliation := ReconcileInvoice(lines, 0, line.Total); !reconciliation.Matches() {
+		t.Errorf("expected the parts to bill the line total, got %+v", reconciliation)
+	}
+}
+
+func TestSplitLaborLine(t *testing.T) {
+	line := LineItem{
+		ItemID:      "outlet",
+		ProductName: "Outlet",
+		Quantity:    3,
+		Price:       models.Cents(4500),
+		Total:       models.Cents(13500),
+		SalesTaxIDs: []string{"tax-1"},
+	}
+
+	if lines := SplitLaborLine(line, 0, models.Cents(8500)); len(lines) != 1 || lines[0].Total != line.Total {
+		t.Fatalf("expected a line without labor to stay whole, got %+v", lines)
+	}
+
+	// 0.35 hours at 85.00 is 29.75 of each 45.00 outlet
+	lines := SplitLaborLine(line, 0.35, models.Cents(8500))
+	if len(lines) != 2 {
+		t.Fatalf("expected materials and labor, got %+v", lines)
+	}
+	if lines[0].ProductName != "Outlet" || lines[0].Price != models.Cents(1525) || lines[0].Total != models.Cents(4575) {
+		t.Errorf("expected 3 outlets at 15.25, got %+v", lines[0])
+	}
+	if lines[1].ProductName != "Labor" || lines[1].Price != models.Cents(2975) || lines[1].Quantity != 3 {
+		t.Errorf("expected 3 x 29.75 of labor, got %+v", lines[1])
+	}
+	if len(lines[1].SalesTaxIDs) != 1 {
+		t.Errorf("expected the labor to be taxed like the line, got %+v", lines[1])
+	}
+	if reconciliation := ReconcileInvoice(lines, 0, line.Total); !reconciliation.Matches() {
+		t.Errorf("expected the split lines to bill the line total, got %+v", reconciliation)
+	}
+
+	// Labor never takes more than the line's price, and credits split into credits
+	credit := LineItem{ItemID: "outlet", ProductName: "Outlet", Quantity: 1, Price: models.Cents(-2000), Total: models.Cents(-2000)}
+	lines = SplitLaborLine(credit, 0.5, models.Cents(8500))
+	if len(lines) != 2 || lines[0].Price != 0 || lines[1].Price != models.Cents(-2000) {
+		t.Errorf("expected the whole credit to be labor, got %+v", lines)
+	}
+
+	// 12.5 ft at 0.79 and at 0.11 both round up, a cent over 12.5 ft at 0.90
+	wire := LineItem{ItemID: "wire", ProductName: "12/2 Wire", Quantity: 12.5, Price: models.Cents(90), Total: models.Cents(1125)}
+	lines = SplitLaborLine(wire, 0.0013, models.Cents(8500))
+	if len(lines) != 3 || lines[2].Description != "Rounding" || lines[2].Total != models.Cents(-1) {
+		t.Errorf("expected a cent of rounding back, got %+v", lines)
+	}
+	if reconciliation := ReconcileInvoice(lines, 0, wire.Total); !reconciliation.Matches() {
+		t.Errorf("expected the split wire to bill the line total, got %+v", reconciliation)
+	}
+}
+
+func TestInvoiceLaborLines(t *testing.T) {
+	assembly := &models.Item{
+		ID:   "outlet-install",
+		Name: "Outlet Install",
+		Components: []models.AssemblyComponent{
+			{ItemID: "box", Name: "Box", Quantity: 1, UnitPrice: models.Cents(3000), LaborHours: 0.25},
+			{ItemID: "wire", Name: "12/2 Wire", Quantity: 10, UnitPrice: models.Cents(100)},
+		},
+	}
+	assembly.PriceAssembly()
+	line := LineItem{ItemID: assembly.ID, ProductName: assembly.Name, Quantity: 2, Price: assembly.UnitPrice, Total: assembly.UnitPrice.MulQuantity(2)}
+	attic := &models.LaborDifficulty{Name: "Attic", Multiplier: 2}
+
+	// A collapsed assembly splits on its own labor: 0.25 hours, doubled in an attic
+	lines := InvoiceLaborLines(line, assembly, models.Cents(4000), attic)
+	if len(lines) != 2 || lines[1].Price != models.Cents(2000) || lines[1].Total != models.Cents(4000) {
+		t.Errorf("expected 2 x 20.00 of labor, got %+v", lines)
+	}
+
+	// An expanded assembly splits each part on its own labor
+	assembly.ExpandOnInvoice = true
+	lines = InvoiceLaborLines(line, assembly, models.Cents(4000), attic)
+	if len(lines) != 3 || lines[0].ProductName != "Box" || lines[1].ProductName != "Labor" || lines[2].ProductName != "12/2 Wire" {
+		t.Fatalf("expected a box, its labor and the wire, got %+v", lines)
+	}
+	if reconciliation := ReconcileInvoice(lines, 0, line.Total); !reconciliation.Matches() {
+		t.Errorf("expected the lines to bill the line total, got %+v", reconciliation)
+	}
+
+	if lines := InvoiceLaborLines(line, nil, models.Cents(4000), nil); len(lines) != 1 {
+		t.Errorf("expected a line with no item to stay whole, got %+v", lines)
+	}
+}
@@ -0,0 +1,228 @@
+// Package voltagedrop sizes the conductors of a feeder or branch-circuit run so
+// its voltage drop stays under a target, using the circular-mil method with
+// the conductor areas of NEC (2020) Chapter 9, Table 8.
+package voltagedrop
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Material is what a conductor is made of
+type Material string
+
+const (
+	Copper   Material = "copper"
+	Aluminum Material = "aluminum"
+)
+
+// ParseMaterial reads a conductor material: copper, cu, aluminum, aluminium or al
+func ParseMaterial(s string) (Material, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "copper", "cu":
+		return Copper, nil
+	case "aluminum", "aluminium", "al":
+		return Aluminum, nil
+	}
+	return "", fmt.Errorf("unknown conductor material %q: use copper or aluminum", s)
+}
+
+// Resistivity is the material's K, its resistance in ohms of a conductor one
+// circular mil in area and one foot long, at 75°C
+func (m Material) Resistivity() float64 {
+	if m == Aluminum {
+		return 21.2
+	}
+	return 12.9
+}
+
+// DefaultMaxDropPercent is the drop a run is sized for when none is given, the
+// 3% the informational notes to 210.19(A) and 215.2(A) recommend for a branch
+// circuit or feeder
+const DefaultMaxDropPercent = 3.0
+
+// Conductor is a standard conductor size, its area and its allowable ampacity
+// at 75°C from Table 310.16, limited by 240.4(D) for the small sizes. An
+// ampacity of 0 means the size isn't made in that material.
+type Conductor struct {
+	Size             string  `json:"size"`
+	CircularMils     float64 `json:"circularMils"`
+	CopperAmpacity   float64 `json:"copperAmpacity"`
+	AluminumAmpacity float64 `json:"aluminumAmpacity"`
+}
+
+// Ampacity is the conductor's ampacity in the material
+func (c Conductor) Ampacity(material Material) float64 {
+	if material == Aluminum {
+		return c.AluminumAmpacity
+	}
+	return c.CopperAmpacity
+}
+
+// Conductors are the standard sizes, smallest first
+var Conductors = []Conductor{
+	{"14 AWG", 4110, 15, 0},
+	{"12 AWG", 6530, 20, 15},
+	{"10 AWG", 10380, 30, 25},
+	{"8 AWG", 16510, 50, 40},
+	{"6 AWG", 26240, 65, 50},
+	{"4 AWG", 41740, 85, 65},
+	{"3 AWG", 52620, 100, 75},
+	{"2 AWG", 66360, 115, 90},
+	{"1 AWG", 83690, 130, 100},
+	{"1/0 AWG", 105600, 150, 120},
+	{"2/0 AWG", 133100, 175, 135},
+	{"3/0 AWG", 167800, 200, 155},
+	{"4/0 AWG", 211600, 230, 180},
+	{"250 kcmil", 250000, 255, 205},
+	{"300 kcmil", 300000, 285, 230},
+	{"350 kcmil", 350000, 310, 250},
+	{"400 kcmil", 400000, 335, 270},
+	{"500 kcmil", 500000, 380, 310},
+	{"600 kcmil", 600000, 420, 340},
+	{"750 kcmil", 750000, 475, 385},
+	{"1000 kcmil", 1000000, 545, 445},
+}
+
+// LookupConductor finds a standard conductor by size, such as "6 AWG" or
+// "250 kcmil"
+func LookupConductor(size string) (Conductor, bool) {
+	for _, conductor := range Conductors {
+		if strings.EqualFold(conductor.Size, strings.TrimSpace(size)) {
+			return conductor, true
+		}
+	}
+	return Conductor{}, false
+}
+
+// Run is a circuit to size: the load it carries, its one-way length and its
+// system. Phase is 1 or 3; MaxDropPercent defaults to 3%.
+type Run struct {
+	Amps           float64  `json:"amps"`
+	LengthFeet     float64  `json:"lengthFeet"`
+	Voltage        float64  `json:"voltage"`
+	Phase          int      `json:"phase"`
+	Material       Material `json:"material"`
+	MaxDropPercent float64  `json:"maxDropPercent"`
+}
+
+// Result is the conductor a run needs. LimitedBy says what sized it: the
+// "voltage drop" or, when the drop would allow a smaller wire than the load
+// can be carried on, the "ampacity".
+type Result struct {
+	Size           string   `json:"size"`
+	Material       Material `json:"material"`
+	CircularMils   float64  `json:"circularMils"`
+	Ampacity       float64  `json:"ampacity"`
+	DropVolts      float64  `json:"dropVolts"`
+	DropPercent    float64  `json:"dropPercent"`
+	MaxDropPercent float64  `json:"maxDropPercent"`
+	LimitedBy      string   `json:"limitedBy"`
+}
+
+// Validate checks the run and fills in its default drop
+func (r *Run) Validate() error {
+	if r.Amps <= 0 {
+		return errors.New("load amps must be positive")
+	}
+	if r.LengthFeet <= 0 {
+		return errors.New("length must be positive")
+	}
+	if r.Voltage <= 0 {
+		return errors.New("voltage must be positive")
+	}
+	if r.Phase != 1 && r.Phase != 3 {
+		return errors.New("phase must be 1 or 3")
+	}
+	material, err := ParseMaterial(string(r.Material))
+	if err != nil {
+		return err
+	}
+	r.Material = material
+	if r.MaxDropPercent < 0 || r.MaxDropPercent >= 100 {
+		return errors.New("maximum drop must be between 0 and 100 percent")
+	}
+	if r.MaxDropPercent == 0 {
+		r.MaxDropPercent = DefaultMaxDropPercent
+	}
+	return nil
+}
+
+// Drop is the voltage drop along a run on a conductor of circularMils:
+// 2KIL/CM on a single-phase circuit, which has two current-carrying
+// conductors, and √3KIL/CM on a three-phase one
+func Drop(run Run, circularMils float64) float64 {
+	multiplier := 2.0
+	if run.Phase == 3 {
+		multiplier = math.Sqrt(3)
+	}
+	return multiplier * run.Material.Resistivity() * run.Amps * run.LengthFeet / circularMils
+}
+
+// Size finds the smallest standard conductor that carries the run's load and
+// keeps its voltage drop within the maximum
+func Size(run Run) (*Result, error) {
+	if err := run.Validate(); err != nil {
+		return nil, err
+	}
+
+	ampacityOK := false
+	for _, conductor := range Conductors {
+		ampacity := conductor.Ampacity(run.Material)
+		if ampacity < run.Amps {
+			continue
+		}
+
+		drop := Drop(run, conductor.CircularMils)
+		percent := drop / run.Voltage * 100
+		if percent > run.MaxDropPercent {
+			ampacityOK = true
+			continue
+		}
+
+		result := &Result{
+			Size:           conductor.Size,
+			Material:       run.Material,
+			CircularMils:   conductor.CircularMils,
+			Ampacity:       ampacity,
+			DropVolts:      round(drop),
+			DropPercent:    round(percent),
+			MaxDropPercent: run.MaxDropPercent,
+			LimitedBy:      "ampacity",
+		}
+		if ampacityOK {
+			result.LimitedBy = "voltage drop"
+		}
+		return result, nil
+	}
+
+	largest := Conductors[len(Conductors)-1]
+	if !ampacityOK && largest.Ampacity(run.Material) < run.Amps {
+		return nil, fmt.Errorf("no single %s conductor carries %g A; run conductors in parallel and size them by hand", run.Material, run.Amps)
+	}
+	return nil, fmt.Errorf("even %s %s drops more than %g%% over %g ft; shorten the run or raise the voltage",
+		largest.Size, run.Material, run.MaxDropPercent, run.LengthFeet)
+}
+
+// Conductors is how many conductors a run pulls when none is given: the two
+// ungrounded conductors of a single-phase circuit or the three of a
+// three-phase one
+func (r Run) Conductors() int {
+	if r.Phase == 3 {
+		return 3
+	}
+	return 2
+}
+
+// Footage is the wire a run takes: its length for each conductor pulled,
+// rounded up to the foot
+func Footage(lengthFeet float64, conductors int) float64 {
+	return math.Ceil(lengthFeet * float64(conductors))
+}
+
+// round rounds to two decimal places
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,135 @@
+package voltagedrop
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		run       Run
+		size      string
+		dropVolts float64
+		percent   float64
+		limitedBy string
+	}{
+		{
+			// 2 x 12.9 x 50 x 150 / 7.2 V needs 26,875 cmil: more than 6 AWG
+			name:      "long 240 V feeder",
+			run:       Run{Amps: 50, LengthFeet: 150, Voltage: 240, Phase: 1, Material: "copper"},
+			size:      "4 AWG",
+			dropVolts: 4.64,
+			percent:   1.93,
+			limitedBy: "voltage drop",
+		},
+		{
+			// 2 x 12.9 x 48 x 200 / 7.2 V needs 34,400 cmil
+			name:      "EV charger in a detached garage",
+			run:       Run{Amps: 48, LengthFeet: 200, Voltage: 240, Phase: 1, Material: "cu"},
+			size:      "4 AWG",
+			dropVolts: 5.93,
+			percent:   2.47,
+			limitedBy: "voltage drop",
+		},
+		{
+			name:      "short run sized by ampacity",
+			run:       Run{Amps: 40, LengthFeet: 20, Voltage: 240, Phase: 1, Material: "copper"},
+			size:      "8 AWG",
+			dropVolts: 1.25,
+			percent:   0.52,
+			limitedBy: "ampacity",
+		},
+		{
+			// √3 x 21.2 x 100 x 400 / 14.4 V needs 102,000 cmil
+			name:      "three-phase aluminum feeder",
+			run:       Run{Amps: 100, LengthFeet: 400, Voltage: 480, Phase: 3, Material: "Aluminium"},
+			size:      "1/0 AWG",
+			dropVolts: 13.91,
+			percent:   2.9,
+			limitedBy: "voltage drop",
+		},
+		{
+			// 2 x 12.9 x 20 x 100 / 1.2 V needs 43,000 cmil
+			name:      "tighter target",
+			run:       Run{Amps: 20, LengthFeet: 100, Voltage: 120, Phase: 1, Material: "copper", MaxDropPercent: 1},
+			size:      "3 AWG",
+			dropVolts: 0.98,
+			percent:   0.82,
+			limitedBy: "voltage drop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Size(tt.run)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Size != tt.size || result.DropVolts != tt.dropVolts || result.DropPercent != tt.percent || result.LimitedBy != tt.limitedBy {
+				t.Errorf("expected %s dropping %gV (%g%%) limited by %s, got %+v", tt.size, tt.dropVolts, tt.percent, tt.limitedBy, result)
+			}
+		})
+	}
+}
+
+func TestSize_Defaults(t *testing.T) {
+	result, err := Size(Run{Amps: 30, LengthFeet: 50, Voltage: 240, Phase: 1, Material: "AL"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Material != Aluminum || result.MaxDropPercent != DefaultMaxDropPercent {
+		t.Errorf("expected aluminum sized for a 3%% drop, got %+v", result)
+	}
+	// 12 AWG aluminum carries only 15 A and 10 AWG 25 A
+	if result.Size != "8 AWG" || result.Ampacity != 40 {
+		t.Errorf("expected 8 AWG aluminum, got %+v", result)
+	}
+}
+
+func TestSize_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    Run
+		errMsg string
+	}{
+		{"no load", Run{LengthFeet: 100, Voltage: 240, Phase: 1, Material: "copper"}, "load amps must be positive"},
+		{"no length", Run{Amps: 20, Voltage: 240, Phase: 1, Material: "copper"}, "length must be positive"},
+		{"no voltage", Run{Amps: 20, LengthFeet: 100, Phase: 1, Material: "copper"}, "voltage must be positive"},
+		{"two phase", Run{Amps: 20, LengthFeet: 100, Voltage: 240, Phase: 2, Material: "copper"}, "phase must be 1 or 3"},
+		{"unknown material", Run{Amps: 20, LengthFeet: 100, Voltage: 240, Phase: 1, Material: "gold"}, `unknown conductor material "gold"`},
+		{"bad target", Run{Amps: 20, LengthFeet: 100, Voltage: 240, Phase: 1, Material: "copper", MaxDropPercent: 100}, "maximum drop must be between 0 and 100 percent"},
+		{"too much current", Run{Amps: 600, LengthFeet: 100, Voltage: 480, Phase: 3, Material: "copper"}, "run conductors in parallel"},
+		{"too long", Run{Amps: 100, LengthFeet: 20000, Voltage: 240, Phase: 1, Material: "copper"}, "even 1000 kcmil copper drops more than 3%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Size(tt.run)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLookupConductor(t *testing.T) {
+	conductor, ok := LookupConductor(" 1/0 awg ")
+	if !ok || conductor.CircularMils != 105600 {
+		t.Errorf("expected 1/0 AWG at 105,600 cmil, got %+v", conductor)
+	}
+	if _, ok := LookupConductor("5 AWG"); ok {
+		t.Error("expected 5 AWG not to be a standard size")
+	}
+}
+
+func TestFootage(t *testing.T) {
+	run := Run{Phase: 1}
+	if footage := Footage(150.2, run.Conductors()); footage != 301 {
+		t.Errorf("expected two conductors over 150.2 ft to take 301 ft, got %g", footage)
+	}
+	run.Phase = 3
+	if footage := Footage(100, run.Conductors()); footage != 300 {
+		t.Errorf("expected three conductors over 100 ft to take 300 ft, got %g", footage)
+	}
+}
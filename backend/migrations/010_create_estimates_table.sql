@@ -0,0 +1,41 @@
+-- Create estimates table
+CREATE TABLE IF NOT EXISTS estimates (
+    id VARCHAR(36) PRIMARY KEY,
+    customer_id VARCHAR(36) NOT NULL,
+    template_id VARCHAR(36) NOT NULL,
+    address TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'sent', 'accepted', 'rejected', 'expired')),
+    revision INTEGER NOT NULL DEFAULT 1,
+    valid_until TIMESTAMP NOT NULL,
+    total_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    notes TEXT,
+    job_id VARCHAR(36),
+    sent_at TIMESTAMP,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (template_id) REFERENCES job_templates(id),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE SET NULL
+);
+
+-- Create estimate_items table
+CREATE TABLE IF NOT EXISTS estimate_items (
+    id VARCHAR(36) PRIMARY KEY,
+    estimate_id VARCHAR(36) NOT NULL,
+    item_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (estimate_id) REFERENCES estimates(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- Create indexes
+CREATE INDEX idx_estimates_customer_id ON estimates(customer_id);
+CREATE INDEX idx_estimates_status ON estimates(status);
+CREATE INDEX idx_estimates_job_id ON estimates(job_id);
+CREATE INDEX idx_estimate_items_estimate_id ON estimate_items(estimate_id);
@@ -0,0 +1,2 @@
+-- Track the planned quantity for each job item alongside the installed quantity
+ALTER TABLE job_items ADD COLUMN IF NOT EXISTS budgeted_quantity DECIMAL(10, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,32 @@
+-- Create job_revisions table: immutable snapshots of a job's line items
+CREATE TABLE IF NOT EXISTS job_revisions (
+    id VARCHAR(36) PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL,
+    revision_number INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    note TEXT,
+    author_id VARCHAR(36) NOT NULL,
+    author_name VARCHAR(255) NOT NULL,
+    total_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    UNIQUE (job_id, revision_number)
+);
+
+-- Create job_revision_items table
+CREATE TABLE IF NOT EXISTS job_revision_items (
+    id VARCHAR(36) PRIMARY KEY,
+    revision_id VARCHAR(36) NOT NULL,
+    job_item_id VARCHAR(36) NOT NULL,
+    item_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    budgeted_quantity DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    price DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    FOREIGN KEY (revision_id) REFERENCES job_revisions(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_job_revisions_job_id ON job_revisions(job_id);
+CREATE INDEX idx_job_revision_items_revision_id ON job_revision_items(revision_id);
@@ -0,0 +1,16 @@
+-- Seed the budget of existing job items from their template's default quantity.
+-- Only jobs none of whose lines have a budget yet predate budgets, so running
+-- this again leaves budgets set or cleared since untouched.
+UPDATE job_items ji
+SET budgeted_quantity = ti.default_quantity
+FROM jobs j, template_items ti
+WHERE ji.job_id = j.id
+  AND ti.template_id = j.template_id
+  AND ti.item_id = ji.item_id
+  AND NOT EXISTS (
+      SELECT 1 FROM job_items budgeted
+      WHERE budgeted.job_id = j.id AND budgeted.budgeted_quantity <> 0
+  );
+
+-- Index job items by catalog item for deletes checking the items foreign key
+CREATE INDEX IF NOT EXISTS idx_job_items_item_id ON job_items(item_id);
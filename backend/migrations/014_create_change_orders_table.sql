@@ -0,0 +1,34 @@
+-- Create change_orders table
+CREATE TABLE IF NOT EXISTS change_orders (
+    id VARCHAR(36) PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL,
+    change_order_number INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'approved', 'rejected')),
+    total_amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    signed_by VARCHAR(255),
+    signed_at TIMESTAMP,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    UNIQUE (job_id, change_order_number)
+);
+
+-- Create change_order_items table; negative quantities remove work
+CREATE TABLE IF NOT EXISTS change_order_items (
+    id VARCHAR(36) PRIMARY KEY,
+    change_order_id VARCHAR(36) NOT NULL,
+    item_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity DECIMAL(10, 2) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    total DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (change_order_id) REFERENCES change_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- Create indexes
+CREATE INDEX idx_change_orders_job_id ON change_orders(job_id);
+CREATE INDEX idx_change_order_items_change_order_id ON change_order_items(change_order_id);
@@ -0,0 +1,15 @@
+-- Create job_status_history table: the timeline of a job's status changes
+CREATE TABLE IF NOT EXISTS job_status_history (
+    id VARCHAR(36) PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    changed_by VARCHAR(36),
+    changed_by_name VARCHAR(255),
+    note TEXT,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+-- Create index for timeline lookups
+CREATE INDEX idx_job_status_history_job_id ON job_status_history(job_id, changed_at);
@@ -0,0 +1,27 @@
+-- Create job_phases table: each job's progress through its template's phases
+CREATE TABLE IF NOT EXISTS job_phases (
+    job_id VARCHAR(36) NOT NULL,
+    phase_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    phase_order INTEGER NOT NULL,
+    is_completed BOOLEAN NOT NULL DEFAULT false,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    PRIMARY KEY (job_id, phase_id),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+-- Create phase records for the jobs that exist when the table is created, from
+-- their template's phases at that time. Phases before the job's current
+-- phase, and every phase of a completed job, are treated as done. Jobs that
+-- already have phase records are skipped, so running this again never brings
+-- later template phase changes to existing jobs.
+INSERT INTO job_phases (job_id, phase_id, name, phase_order, is_completed, started_at)
+SELECT j.id, tp.id, tp.name, tp.phase_order,
+       j.status = 'completed' OR (cur.phase_order IS NOT NULL AND tp.phase_order < cur.phase_order),
+       CASE WHEN tp.id = j.current_phase_id THEN j.start_date END
+FROM jobs j
+JOIN template_phases tp ON tp.template_id = j.template_id
+LEFT JOIN template_phases cur ON cur.id = j.current_phase_id
+WHERE NOT EXISTS (SELECT 1 FROM job_phases jp WHERE jp.job_id = j.id)
+ON CONFLICT (job_id, phase_id) DO NOTHING;
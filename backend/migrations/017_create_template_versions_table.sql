@@ -0,0 +1,62 @@
+-- Track the current version number on each template
+ALTER TABLE job_templates ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- Create template_versions table: immutable snapshots of a template
+CREATE TABLE IF NOT EXISTS template_versions (
+    id VARCHAR(36) PRIMARY KEY,
+    template_id VARCHAR(36) NOT NULL,
+    version_number INTEGER NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (template_id) REFERENCES job_templates(id) ON DELETE CASCADE,
+    UNIQUE (template_id, version_number)
+);
+
+-- Create template_version_items table
+CREATE TABLE IF NOT EXISTS template_version_items (
+    id VARCHAR(36) PRIMARY KEY,
+    version_id VARCHAR(36) NOT NULL,
+    template_item_id VARCHAR(36) NOT NULL,
+    item_id VARCHAR(36) NOT NULL,
+    default_quantity DECIMAL(10, 2) NOT NULL,
+    FOREIGN KEY (version_id) REFERENCES template_versions(id) ON DELETE CASCADE
+);
+
+-- Create template_version_phases table; phase_id is the template phase's ID
+-- at the time of the snapshot
+CREATE TABLE IF NOT EXISTS template_version_phases (
+    version_id VARCHAR(36) NOT NULL,
+    phase_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    phase_order INTEGER NOT NULL,
+    description TEXT,
+    PRIMARY KEY (version_id, phase_id),
+    FOREIGN KEY (version_id) REFERENCES template_versions(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_template_versions_template_id ON template_versions(template_id);
+CREATE INDEX idx_template_version_items_version_id ON template_version_items(version_id);
+
+-- Pin each job to the template version it was created from
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS template_version INTEGER NOT NULL DEFAULT 1;
+
+-- Record the current state of existing templates as version 1
+INSERT INTO template_versions (id, template_id, version_number, name, description, created_at)
+SELECT gen_random_uuid()::text, t.id, 1, t.name, t.description, t.updated_at
+FROM job_templates t
+ON CONFLICT (template_id, version_number) DO NOTHING;
+
+INSERT INTO template_version_items (id, version_id, template_item_id, item_id, default_quantity)
+SELECT gen_random_uuid()::text, v.id, ti.id, ti.item_id, ti.default_quantity
+FROM template_versions v
+JOIN template_items ti ON ti.template_id = v.template_id
+WHERE v.version_number = 1;
+
+INSERT INTO template_version_phases (version_id, phase_id, name, phase_order, description)
+SELECT v.id, tp.id, tp.name, tp.phase_order, tp.description
+FROM template_versions v
+JOIN template_phases tp ON tp.template_id = v.template_id
+WHERE v.version_number = 1
+ON CONFLICT (version_id, phase_id) DO NOTHING;
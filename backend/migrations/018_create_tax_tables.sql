@@ -0,0 +1,31 @@
+-- Create tax_rates table: sales tax rates by jurisdiction with effective dates.
+-- effective_to is the first day a rate no longer applies.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    state VARCHAR(2) NOT NULL,
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    zip VARCHAR(10) NOT NULL DEFAULT '',
+    rate DECIMAL(7, 4) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    wave_sales_tax_id VARCHAR(255) NOT NULL DEFAULT '',
+    effective_from DATE NOT NULL,
+    effective_to DATE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+-- Create tax_categories table: item categories that are taxable or exempt.
+-- Categories not listed are taxable.
+CREATE TABLE IF NOT EXISTS tax_categories (
+    category VARCHAR(100) PRIMARY KEY,
+    taxable BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Labor is not taxable
+INSERT INTO tax_categories (category, taxable) VALUES ('Labor', false)
+ON CONFLICT (category) DO NOTHING;
+
+-- Create indexes
+CREATE INDEX idx_tax_rates_state ON tax_rates(state);
@@ -0,0 +1,38 @@
+-- Create price_adjustments table: default markups and discounts copied onto new
+-- jobs. Rows without a customer apply to every job; rows with one apply to that
+-- customer's jobs. Each has either a percent or a fixed amount.
+CREATE TABLE IF NOT EXISTS price_adjustments (
+    id VARCHAR(36) PRIMARY KEY,
+    customer_id VARCHAR(36),
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('markup', 'discount')),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('line', 'category', 'job')),
+    item_id VARCHAR(36) NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    percent DECIMAL(7, 4) NOT NULL DEFAULT 0 CHECK (percent >= 0),
+    amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE CASCADE
+);
+
+-- Create job_adjustments table: the markups and discounts priced into a job
+CREATE TABLE IF NOT EXISTS job_adjustments (
+    id VARCHAR(36) PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL,
+    customer_id VARCHAR(36),
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('markup', 'discount')),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('line', 'category', 'job')),
+    item_id VARCHAR(36) NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    percent DECIMAL(7, 4) NOT NULL DEFAULT 0 CHECK (percent >= 0),
+    amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_price_adjustments_customer_id ON price_adjustments(customer_id);
+CREATE INDEX idx_job_adjustments_job_id ON job_adjustments(job_id);
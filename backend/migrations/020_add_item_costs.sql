@@ -0,0 +1,22 @@
+-- Track what each item costs us alongside what we sell it for
+ALTER TABLE items ADD COLUMN IF NOT EXISTS unit_cost DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Create item_costs table: supplier costs with the day each takes effect. An
+-- item costs the latest entry already in effect, or its unit_cost without one.
+CREATE TABLE IF NOT EXISTS item_costs (
+    id VARCHAR(36) PRIMARY KEY,
+    item_id VARCHAR(36) NOT NULL,
+    supplier VARCHAR(255) NOT NULL DEFAULT '',
+    cost DECIMAL(10, 2) NOT NULL CHECK (cost >= 0),
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE
+);
+
+-- Job and change order lines keep the unit cost from when they were added,
+-- like they keep the price. Lines added before costs were tracked cost 0.
+ALTER TABLE job_items ADD COLUMN IF NOT EXISTS cost DECIMAL(10, 2) NOT NULL DEFAULT 0;
+ALTER TABLE change_order_items ADD COLUMN IF NOT EXISTS cost DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Create indexes
+CREATE INDEX idx_item_costs_item_id ON item_costs(item_id, effective_from);
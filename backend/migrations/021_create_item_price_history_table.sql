@@ -0,0 +1,12 @@
+-- Create item_price_history table: every change to an item's unit price
+CREATE TABLE IF NOT EXISTS item_price_history (
+    id VARCHAR(36) PRIMARY KEY,
+    item_id VARCHAR(36) NOT NULL,
+    old_price DECIMAL(10, 2) NOT NULL,
+    new_price DECIMAL(10, 2) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_item_price_history_item_id ON item_price_history(item_id, changed_at);
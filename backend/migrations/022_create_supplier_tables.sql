@@ -0,0 +1,49 @@
+-- Create suppliers table: the supply houses we buy from
+CREATE TABLE IF NOT EXISTS suppliers (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    account_number VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create supplier_items table: which of our items a supplier SKU is
+CREATE TABLE IF NOT EXISTS supplier_items (
+    id VARCHAR(36) PRIMARY KEY,
+    supplier_id VARCHAR(36) NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    item_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    UNIQUE (supplier_id, sku)
+);
+
+-- Create supplier_price_imports table: each price file a supplier sent.
+-- applied_at is set once its costs have been recorded on our items.
+CREATE TABLE IF NOT EXISTS supplier_price_imports (
+    id VARCHAR(36) PRIMARY KEY,
+    supplier_id VARCHAR(36) NOT NULL,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    row_count INTEGER NOT NULL DEFAULT 0,
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    applied_at TIMESTAMP,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE CASCADE
+);
+
+-- Create supplier_prices table: the staged rows of a price file
+CREATE TABLE IF NOT EXISTS supplier_prices (
+    import_id VARCHAR(36) NOT NULL,
+    sku VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+    cost DECIMAL(10, 2) NOT NULL CHECK (cost >= 0),
+    PRIMARY KEY (import_id, sku),
+    FOREIGN KEY (import_id) REFERENCES supplier_price_imports(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_supplier_items_item_id ON supplier_items(item_id);
+CREATE INDEX idx_supplier_price_imports_supplier_id ON supplier_price_imports(supplier_id, imported_at);
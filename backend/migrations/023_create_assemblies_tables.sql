@@ -0,0 +1,25 @@
+-- Create assemblies table: items sold as a kit of other items, such as an
+-- outlet install. The assembly's own row in items carries its name, unit and
+-- category, so templates and jobs use it like any item. It is priced as the
+-- sum of its parts unless it has a price_override.
+CREATE TABLE IF NOT EXISTS assemblies (
+    item_id VARCHAR(36) PRIMARY KEY,
+    price_override DECIMAL(10, 2) CHECK (price_override >= 0),
+    expand_on_invoice BOOLEAN NOT NULL DEFAULT false,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE
+);
+
+-- Create assembly_items table: how many of each item go into one unit of an
+-- assembly. Items cannot be deleted while an assembly uses them.
+CREATE TABLE IF NOT EXISTS assembly_items (
+    assembly_id VARCHAR(36) NOT NULL,
+    item_id VARCHAR(36) NOT NULL,
+    quantity DECIMAL(10, 4) NOT NULL CHECK (quantity > 0),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (assembly_id, item_id),
+    FOREIGN KEY (assembly_id) REFERENCES assemblies(item_id) ON DELETE CASCADE,
+    FOREIGN KEY (item_id) REFERENCES items(id)
+);
+
+-- Create indexes
+CREATE INDEX idx_assembly_items_item_id ON assembly_items(item_id);
@@ -0,0 +1,44 @@
+-- Labor units: the hours it takes to install one unit of an item
+ALTER TABLE items ADD COLUMN IF NOT EXISTS labor_hours DECIMAL(10, 4) NOT NULL DEFAULT 0 CHECK (labor_hours >= 0);
+
+-- Create labor_rates table: what a crew's time is charged at per hour. The
+-- default rate prices labor for jobs and templates that don't name one.
+CREATE TABLE IF NOT EXISTS labor_rates (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    hourly_rate DECIMAL(10, 2) NOT NULL CHECK (hourly_rate >= 0),
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create labor_difficulties table: multipliers on labor hours for harder work
+CREATE TABLE IF NOT EXISTS labor_difficulties (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    multiplier DECIMAL(6, 3) NOT NULL CHECK (multiplier > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO labor_difficulties (id, name, multiplier) VALUES
+    ('6f1c9a52-4b1e-4d3a-9c7e-1a2b3c4d5e01', 'New construction', 1.000),
+    ('6f1c9a52-4b1e-4d3a-9c7e-1a2b3c4d5e02', 'Remodel', 1.250),
+    ('6f1c9a52-4b1e-4d3a-9c7e-1a2b3c4d5e03', 'Attic', 1.500)
+ON CONFLICT (name) DO NOTHING;
+
+-- Create job_labor table: the rate and difficulty a job's labor is priced at,
+-- and whether labor is billed on lines of its own on the job's invoice
+CREATE TABLE IF NOT EXISTS job_labor (
+    job_id VARCHAR(36) PRIMARY KEY,
+    labor_rate_id VARCHAR(36),
+    difficulty_id VARCHAR(36),
+    split_on_invoice BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (labor_rate_id) REFERENCES labor_rates(id),
+    FOREIGN KEY (difficulty_id) REFERENCES labor_difficulties(id)
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_labor_rates_default ON labor_rates(is_default) WHERE is_default;
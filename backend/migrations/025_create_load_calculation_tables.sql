@@ -0,0 +1,30 @@
+-- Create job_load_calculations table: dwelling service load calculations
+-- saved against a job. Only the loads are stored; the calculation is worked
+-- out from them when it is loaded.
+CREATE TABLE IF NOT EXISTS job_load_calculations (
+    id VARCHAR(36) PRIMARY KEY,
+    job_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    square_feet DECIMAL(10, 2) NOT NULL CHECK (square_feet > 0),
+    small_appliance_circuits INTEGER NOT NULL DEFAULT 2 CHECK (small_appliance_circuits >= 0),
+    laundry_circuits INTEGER NOT NULL DEFAULT 1 CHECK (laundry_circuits >= 0),
+    voltage DECIMAL(6, 1) NOT NULL DEFAULT 240 CHECK (voltage > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+-- Create job_load_calculation_appliances table: the appliances and equipment
+-- in a load calculation, by kind, in the order they were entered
+CREATE TABLE IF NOT EXISTS job_load_calculation_appliances (
+    calculation_id VARCHAR(36) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('fixed', 'cooking', 'dryer', 'heating', 'cooling', 'ev_charger')),
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    va DECIMAL(12, 2) NOT NULL CHECK (va > 0),
+    motor BOOLEAN NOT NULL DEFAULT false,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (calculation_id) REFERENCES job_load_calculations(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_job_load_calculations_job_id ON job_load_calculations(job_id);
+CREATE INDEX idx_job_load_calculation_appliances_calculation_id ON job_load_calculation_appliances(calculation_id);
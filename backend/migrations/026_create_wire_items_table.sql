@@ -0,0 +1,10 @@
+-- Create wire_items table: the catalog item that sells each conductor
+-- material and size, so runs sized for voltage drop can be quoted on jobs
+CREATE TABLE IF NOT EXISTS wire_items (
+    item_id VARCHAR(36) PRIMARY KEY,
+    material VARCHAR(20) NOT NULL CHECK (material IN ('copper', 'aluminum')),
+    size VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE CASCADE,
+    UNIQUE (material, size)
+);
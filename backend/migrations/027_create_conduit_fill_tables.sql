@@ -0,0 +1,54 @@
+-- Create conductor_areas table: the cross-sectional area of each insulated
+-- conductor, by insulation type and size (NEC Chapter 9, Table 5)
+CREATE TABLE IF NOT EXISTS conductor_areas (
+    id VARCHAR(36) PRIMARY KEY,
+    insulation VARCHAR(50) NOT NULL,
+    size VARCHAR(20) NOT NULL,
+    area_sq_in DECIMAL(10, 4) NOT NULL CHECK (area_sq_in > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (insulation, size)
+);
+
+-- Create conduit_sizes table: the total internal area of each trade size of
+-- a conduit type (NEC Chapter 9, Table 4), and the catalog item that sells it
+CREATE TABLE IF NOT EXISTS conduit_sizes (
+    id VARCHAR(36) PRIMARY KEY,
+    conduit_type VARCHAR(50) NOT NULL,
+    trade_size VARCHAR(20) NOT NULL,
+    area_sq_in DECIMAL(10, 4) NOT NULL CHECK (area_sq_in > 0),
+    item_id VARCHAR(36),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (item_id) REFERENCES items(id) ON DELETE SET NULL,
+    UNIQUE (conduit_type, trade_size)
+);
+
+-- THHN/THWN-2
+INSERT INTO conductor_areas (id, insulation, size, area_sq_in)
+SELECT gen_random_uuid()::text, 'THHN', size, area
+FROM (VALUES
+    ('14 AWG', 0.0097), ('12 AWG', 0.0133), ('10 AWG', 0.0211), ('8 AWG', 0.0366),
+    ('6 AWG', 0.0507), ('4 AWG', 0.0824), ('3 AWG', 0.0973), ('2 AWG', 0.1158),
+    ('1 AWG', 0.1562), ('1/0 AWG', 0.1855), ('2/0 AWG', 0.2223), ('3/0 AWG', 0.2679),
+    ('4/0 AWG', 0.3237), ('250 kcmil', 0.3970), ('300 kcmil', 0.4608), ('350 kcmil', 0.5242),
+    ('400 kcmil', 0.5863), ('500 kcmil', 0.7073), ('600 kcmil', 0.8676), ('750 kcmil', 1.0496),
+    ('1000 kcmil', 1.3478)
+) AS v(size, area)
+ON CONFLICT (insulation, size) DO NOTHING;
+
+-- EMT and Schedule 40 PVC
+INSERT INTO conduit_sizes (id, conduit_type, trade_size, area_sq_in)
+SELECT gen_random_uuid()::text, conduit_type, trade_size, area
+FROM (VALUES
+    ('EMT', '1/2', 0.304), ('EMT', '3/4', 0.533), ('EMT', '1', 0.864), ('EMT', '1-1/4', 1.496),
+    ('EMT', '1-1/2', 2.036), ('EMT', '2', 3.356), ('EMT', '2-1/2', 5.858), ('EMT', '3', 8.846),
+    ('EMT', '3-1/2', 11.545), ('EMT', '4', 14.753),
+    ('PVC-40', '1/2', 0.285), ('PVC-40', '3/4', 0.508), ('PVC-40', '1', 0.832), ('PVC-40', '1-1/4', 1.453),
+    ('PVC-40', '1-1/2', 1.986), ('PVC-40', '2', 3.291), ('PVC-40', '2-1/2', 4.695), ('PVC-40', '3', 7.268),
+    ('PVC-40', '3-1/2', 9.737), ('PVC-40', '4', 12.554)
+) AS v(conduit_type, trade_size, area)
+ON CONFLICT (conduit_type, trade_size) DO NOTHING;
+
+-- Create indexes
+CREATE INDEX idx_conduit_sizes_item_id ON conduit_sizes(item_id);
@@ -1,6 +1,44 @@
 // API Client for backend communication
 import { API_BASE_URL } from '../config';
 
+const TOKEN_KEY = 'authToken';
+
+// Session token handling; the token is issued by POST /auth/login
+export const authToken = {
+  get: (): string | null =>
+    typeof window !== 'undefined' ? localStorage.getItem(TOKEN_KEY) : null,
+  set: (token: string) => {
+    if (typeof window !== 'undefined') {
+      localStorage.setItem(TOKEN_KEY, token);
+    }
+  },
+  clear: () => {
+    if (typeof window !== 'undefined') {
+      localStorage.removeItem(TOKEN_KEY);
+    }
+  },
+};
+
+// Called when the backend rejects the session so the app can return to login
+let unauthorizedHandler: (() => void) | null = null;
+
+export function onUnauthorized(handler: () => void) {
+  unauthorizedHandler = handler;
+}
+
+// Headers for requests made outside the api client, such as uploads
+export function authHeaders(): Record<string, string> {
+  const token = authToken.get();
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
+
+function jsonHeaders(): Record<string, string> {
+  return {
+    'Content-Type': 'application/json',
+    ...authHeaders(),
+  };
+}
+
 export class ApiError extends Error {
   constructor(public status: number, message: string) {
     super(message);
@@ -10,6 +48,11 @@ export class ApiError extends Error {
 
 async function handleResponse<T>(response: Response): Promise<T> {
   if (!response.ok) {
+    if (response.status === 401 && authToken.get()) {
+      authToken.clear();
+      unauthorizedHandler?.();
+    }
+
     let errorMessage = 'Request failed';
     try {
       // Try to parse as JSON first
@@ -39,9 +82,7 @@ export const api = {
   async get<T>(endpoint: string): Promise<T> {
     const response = await fetch(`${API_BASE_URL}${endpoint}`, {
       method: 'GET',
-      headers: {
-        'Content-Type': 'application/json',
-      },
+      headers: jsonHeaders(),
     });
     return handleResponse<T>(response);
   },
@@ -49,9 +90,7 @@ export const api = {
   async post<T>(endpoint: string, data: any): Promise<T> {
     const response = await fetch(`${API_BASE_URL}${endpoint}`, {
       method: 'POST',
-      headers: {
-        'Content-Type': 'application/json',
-      },
+      headers: jsonHeaders(),
       body: JSON.stringify(data),
     });
     return handleResponse<T>(response);
@@ -60,9 +99,7 @@ export const api = {
   async put<T>(endpoint: string, data: any): Promise<T> {
     const response = await fetch(`${API_BASE_URL}${endpoint}`, {
       method: 'PUT',
-      headers: {
-        'Content-Type': 'application/json',
-      },
+      headers: jsonHeaders(),
       body: JSON.stringify(data),
     });
     return handleResponse<T>(response);
@@ -71,9 +108,7 @@ export const api = {
   async delete<T>(endpoint: string): Promise<T> {
     const response = await fetch(`${API_BASE_URL}${endpoint}`, {
       method: 'DELETE',
-      headers: {
-        'Content-Type': 'application/json',
-      },
+      headers: jsonHeaders(),
     });
     return handleResponse<T>(response);
   },
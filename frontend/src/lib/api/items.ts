@@ -10,6 +10,7 @@ function toFrontendItem(backendItem: any): Item {
     description: backendItem.description,
     unit: backendItem.unit,
     unitPrice: backendItem.unitPrice,
+    unitCost: backendItem.unitCost,
     category: backendItem.category,
     createdAt: new Date(backendItem.createdAt),
     updatedAt: new Date(backendItem.updatedAt),
@@ -24,6 +25,7 @@ function toBackendItem(item: Partial<Item>): any {
   if (item.description !== undefined) backendItem.description = item.description;
   if (item.unit !== undefined) backendItem.unit = item.unit;
   if (item.unitPrice !== undefined) backendItem.unitPrice = item.unitPrice;
+  if (item.unitCost !== undefined) backendItem.unitCost = item.unitCost;
   if (item.category !== undefined) backendItem.category = item.category;
   
   return backendItem;
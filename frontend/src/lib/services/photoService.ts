@@ -1,5 +1,6 @@
 // Photo service for handling photo uploads and downloads
 import { API_BASE_URL } from '../config';
+import { authHeaders } from '../api/client';
 
 export interface UploadedPhoto {
   id: string;
@@ -21,6 +22,7 @@ export class PhotoService {
     try {
       const response = await fetch(`${API_BASE_URL}/jobs/${jobId}/photos`, {
         method: 'POST',
+        headers: authHeaders(),
         body: formData,
       });
       
@@ -50,6 +52,7 @@ export class PhotoService {
       for (const photoId of photoIds) {
         const response = await fetch(`${API_BASE_URL}/jobs/${jobId}/photos/${photoId}`, {
           method: 'DELETE',
+          headers: authHeaders(),
         });
         
         if (!response.ok) {
@@ -1,5 +1,6 @@
 // Service health check utilities
 import { API_BASE_URL } from '../config';
+import { authHeaders } from '../api/client';
 
 export interface ServiceStatus {
   wave: 'connected' | 'disconnected' | 'checking';
@@ -39,6 +40,7 @@ class ServiceHealthService {
     try {
       const response = await fetch(`${API_BASE_URL}/health/wave`, {
         method: 'GET',
+        headers: authHeaders(),
       });
       
       const isConnected = response.ok;
@@ -61,6 +63,7 @@ class ServiceHealthService {
     try {
       const response = await fetch(`${API_BASE_URL}/health/cloudflare`, {
         method: 'GET',
+        headers: authHeaders(),
       });
       
       const isConnected = response.ok;
@@ -1,18 +1,44 @@
 import { API_BASE_URL } from '../config';
+import { authHeaders } from '../api/client';
+
+export interface InvoiceReconciliation {
+  localTotal: number;
+  invoiceTotal: number;
+  waveTotal?: number;
+  difference: number;
+}
+
+// Thrown when the invoice lines don't add up to the job; nothing was billed
+export class InvoiceMismatchError extends Error {
+  constructor(message: string, public reconciliation: InvoiceReconciliation) {
+    super(message);
+    this.name = 'InvoiceMismatchError';
+  }
+}
 
 export class WaveService {
-  static async sendToWave(jobId: string): Promise<{
+  // Pass override to bill an invoice whose lines don't match the job total
+  static async sendToWave(jobId: string, override = false): Promise<{
     invoiceNumber: string;
     invoiceUrl: string;
     message: string;
+    warning?: string;
+    reconciliation: InvoiceReconciliation;
   }> {
     const response = await fetch(`${API_BASE_URL}/jobs/${jobId}/send-to-wave`, {
       method: 'POST',
       headers: {
         'Content-Type': 'application/json',
+        ...authHeaders(),
       },
+      body: JSON.stringify({ override }),
     });
 
+    if (response.status === 409) {
+      const mismatch = await response.json();
+      throw new InvoiceMismatchError(mismatch.error, mismatch.reconciliation);
+    }
+
     if (!response.ok) {
       const errorText = await response.text();
       throw new Error(errorText || 'Failed to send to Wave');
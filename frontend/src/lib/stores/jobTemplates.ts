@@ -84,7 +84,7 @@ function createJobTemplatesStore() {
     },
 
     // Update template
-    async update(id: string, updates: { name?: string; description?: string; isActive?: boolean; phases?: { name: string; order: number; description?: string }[] }) {
+    async update(id: string, updates: { name?: string; description?: string; isActive?: boolean; phases?: { id?: string; name: string; order: number; description?: string }[]; phaseReassignments?: Record<string, string> }) {
       try {
         console.log('Updating template:', id, updates);
         const template = await api.put<JobTemplate>(`/templates/${id}`, updates);
@@ -105,7 +105,7 @@ function createJobsStore() {
     // Update job status
     async updateStatus(jobId: string, status: string) {
       try {
-        const job = await api.put<Job>(`/jobs/${jobId}`, { status });
+        const job = await api.put<Job>(`/jobs/${jobId}/status`, { status });
         update(state => ({
           ...state,
           jobs: state.jobs.map(j => j.id === jobId ? job : j)
@@ -119,6 +119,24 @@ function createJobsStore() {
       }
     },
     
+    // Complete one of the job's phases; the job moves on to its next open phase
+    async completePhase(jobId: string, phaseId: string) {
+      try {
+        const job = await api.post<Job>(`/jobs/${jobId}/phases/${phaseId}/complete`, {});
+        update(state => ({
+          ...state,
+          jobs: state.jobs.map(j => j.id === jobId ? job : j)
+        }));
+        return job;
+      } catch (error) {
+        update(state => ({
+          ...state,
+          error: error instanceof Error ? error.message : 'Failed to complete job phase'
+        }));
+        throw error;
+      }
+    },
+    
     // Generic update method
     async update(jobId: string, updates: Partial<Job>) {
       try {
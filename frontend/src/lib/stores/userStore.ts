@@ -1,40 +1,75 @@
 import { writable, derived } from 'svelte/store';
+import { api, authToken, onUnauthorized } from '../api/client';
 
 type UserRole = 'admin' | 'tech';
 
 interface User {
+  id: string;
   email: string;
+  name: string;
   role: UserRole;
   isViewingAsTech?: boolean; // For admins to toggle tech view
 }
 
+interface AuthUser {
+  id: string;
+  email: string;
+  name: string;
+  role: UserRole;
+  isActive: boolean;
+}
+
+interface LoginResponse {
+  token: string;
+  expiresAt: string;
+  user: AuthUser;
+}
+
 // Create the user store
 function createUserStore() {
   const { subscribe, set, update } = writable<User | null>(null);
 
-  // Check localStorage for saved view preference
-  const savedViewMode = typeof window !== 'undefined' 
-    ? localStorage.getItem('adminViewMode') 
-    : null;
+  // The role always comes from the backend; only the admin view toggle is local
+  const fromAuthUser = (user: AuthUser): User => {
+    // Check localStorage for saved view preference
+    const savedViewMode = typeof window !== 'undefined' 
+      ? localStorage.getItem('adminViewMode') 
+      : null;
+
+    return {
+      id: user.id,
+      email: user.email,
+      name: user.name,
+      role: user.role,
+      isViewingAsTech: user.role === 'admin' && savedViewMode === 'tech'
+    };
+  };
+
+  // An expired or revoked session sends the user back to the login page
+  onUnauthorized(() => set(null));
 
   return {
     subscribe,
-    
-    // Initialize user (this would come from your auth system)
-    init: (email: string) => {
-      // Master admin
-      if (email === 'brenthall@gmail.com') {
-        set({
-          email,
-          role: 'admin',
-          isViewingAsTech: savedViewMode === 'tech'
-        });
-      } else {
-        // Default to tech for now - you'll assign roles separately
-        set({
-          email,
-          role: 'tech'
-        });
+
+    // Sign in and keep the session token for later requests
+    login: async (email: string, password: string) => {
+      const response = await api.post<LoginResponse>('/auth/login', { email, password });
+      authToken.set(response.token);
+      set(fromAuthUser(response.user));
+    },
+
+    // Restore the signed-in user from a saved session token
+    restore: async () => {
+      if (!authToken.get()) {
+        set(null);
+        return;
+      }
+      try {
+        const user = await api.get<AuthUser>('/auth/me');
+        set(fromAuthUser(user));
+      } catch {
+        authToken.clear();
+        set(null);
       }
     },
 
@@ -64,11 +99,20 @@ function createUserStore() {
     },
 
     // Logout
-    logout: () => {
-      if (typeof window !== 'undefined') {
-        localStorage.removeItem('adminViewMode');
+    logout: async () => {
+      try {
+        if (authToken.get()) {
+          await api.post('/auth/logout', {});
+        }
+      } catch (e) {
+        console.error('Failed to sign out:', e);
+      } finally {
+        authToken.clear();
+        if (typeof window !== 'undefined') {
+          localStorage.removeItem('adminViewMode');
+        }
+        set(null);
       }
-      set(null);
     }
   };
 }
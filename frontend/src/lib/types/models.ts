@@ -5,6 +5,7 @@ export interface Item {
   description?: string;
   unit: 'each' | 'ft' | 'hr' | 'lot';
   unitPrice: number;
+  unitCost?: number;  // What the item costs us; admin only
   category?: string;
   createdAt: Date;
   updatedAt: Date;
@@ -12,9 +13,11 @@ export interface Item {
 
 export interface JobPhase {
   id: string;
+  jobId: string;
   name: string;
   order: number;
   isCompleted: boolean;
+  startedAt?: Date;
   completedAt?: Date;
 }
 
@@ -39,6 +42,7 @@ export interface JobTemplate {
   description: string;
   items: JobTemplateItem[];
   phases: TemplatePhase[];
+  version: number;
   isActive: boolean;
   createdAt: Date;
   updatedAt: Date;
@@ -74,9 +78,11 @@ export interface Job {
   id: string;
   customerId: string;
   templateId: string;
+  templateVersion: number;
   address: string;
   status: 'scheduled' | 'in_progress' | 'completed' | 'cancelled';
   currentPhaseId?: string;
+  phases: JobPhase[];
   scheduledDate: Date;
   startDate?: Date;
   endDate?: Date;
@@ -84,6 +90,7 @@ export interface Job {
   permitNumber?: string;
   totalAmount: number;
   items: JobItem[];
+  adjustmentLines?: AdjustmentLine[];
   photos: JobPhoto[];
   notes?: string;
   waveInvoiceId?: string;
@@ -96,6 +103,35 @@ export interface Job {
   currentPhase?: TemplatePhase;
 }
 
+export interface AdjustmentLine {
+  adjustmentId: string;
+  name: string;
+  type: 'markup' | 'discount';
+  scope: 'line' | 'category' | 'job';
+  itemId?: string;
+  category?: string;
+  percent?: number;
+  base: number;
+  amount: number;
+}
+
+export interface AppliedTaxRate {
+  taxRateId: string;
+  name: string;
+  rate: number;
+  waveSalesTaxId?: string;
+  amount: number;
+}
+
+export interface JobTax {
+  jobId: string;
+  date: string;
+  taxableAmount: number;
+  exemptAmount: number;
+  rates: AppliedTaxRate[];
+  taxAmount: number;
+}
+
 export interface Address {
   street: string;
   city: string;
@@ -8,11 +8,25 @@ DB_URL="${DATABASE_URL:-postgres://postgres@localhost/bremray_dev?sslmode=disabl
 echo "Running migrations..."
 echo "Database URL: $DB_URL"
 
-# Function to run a SQL file
+# Record each migration once it is applied so it never runs twice
+psql "$DB_URL" -q -c "CREATE TABLE IF NOT EXISTS schema_migrations (
+    filename VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);" || exit 1
+
+# Function to run a SQL file that hasn't been applied, recording it in the
+# same transaction
 run_sql_file() {
     local file=$1
-    echo "Running migration: $(basename $file)"
-    psql "$DB_URL" -f "$file" 2>&1 | grep -E "(ERROR|NOTICE|CREATE|ALTER|INSERT)" || echo "  ✓ Completed"
+    local name=$(basename "$file")
+    local applied=$(psql "$DB_URL" -tA -c "SELECT 1 FROM schema_migrations WHERE filename = '$name';")
+    if [ "$applied" = "1" ]; then
+        return
+    fi
+
+    echo "Running migration: $name"
+    (cat "$file"; echo; echo "INSERT INTO schema_migrations (filename) VALUES ('$name');") |
+        psql "$DB_URL" -v ON_ERROR_STOP=1 --single-transaction -f - 2>&1 | grep -E "(ERROR|NOTICE|CREATE|ALTER|INSERT)" || echo "  ✓ Completed"
 }
 
 # Get the directory where this script is located